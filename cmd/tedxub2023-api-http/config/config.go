@@ -1,13 +1,702 @@
 package config
 
-import "os"
-
-func BaseConfig() string {
-	return "" +
-		"host=" + os.Getenv("PGHOST") +
-		" user=" + os.Getenv("PGUSER") +
-		" password=" + os.Getenv("PGPASSWORD") +
-		" dbname=" + os.Getenv("PGDATABASE") +
-		" port=" + os.Getenv("PGPORT") +
-		" sslmode=disable"
+import (
+	"fmt"
+	"net"
+	"net/mail"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/joho/godotenv"
+	"github.com/tedxub2023/global/cors"
+	"github.com/tedxub2023/global/ratelimit"
+	"gopkg.in/yaml.v3"
+)
+
+// Config is the application configuration. It is loaded once
+// at startup and passed down to each service and handler.
+type Config struct {
+	Server      ServerConfig
+	Database    DatabaseConfig
+	SMTP        SMTPConfig
+	Midtrans    MidtransConfig
+	Cloudinary  CloudinaryConfig
+	PDF         PDFConfig
+	RateLimit   RateLimitConfig
+	CORS        cors.Policy
+	MainEvent   MainEventConfig
+	Ticket      TicketConfig
+	Transaction TransactionConfig
+
+	// AdminEmail is the committee email address that gets
+	// notified when a buyer uploads a payment proof.
+	AdminEmail string
+
+	// AdminToken is the bearer token required by the
+	// committee-only endpoints. They reject every request
+	// if it is empty.
+	AdminToken string
+
+	// GateToken is the bearer token of the check-in scanners
+	// at the gates. The check-in endpoints accept it as well
+	// as AdminToken.
+	GateToken string
+}
+
+// ServerConfig holds the HTTP server configuration.
+type ServerConfig struct {
+	Address      string
+	Port         int
+	ReadTimeout  time.Duration
+	WriteTimeout time.Duration
+	IdleTimeout  time.Duration
+
+	// ShutdownTimeout is the maximum duration to wait for
+	// in-flight requests and background jobs to finish when
+	// the server is stopped.
+	ShutdownTimeout time.Duration
+
+	// ExportWriteTimeout is the maximum duration to write an
+	// export download, which replaces WriteTimeout for it.
+	ExportWriteTimeout time.Duration
+}
+
+// DatabaseConfig holds the PostgreSQL connection configuration.
+type DatabaseConfig struct {
+	Host     string
+	User     string
+	Password string
+	Name     string
+	Port     int
+	SSLMode  string
+}
+
+// SMTPConfig holds the outgoing mail configuration.
+type SMTPConfig struct {
+	Host         string
+	Port         int
+	SenderName   string
+	AuthEmail    string
+	AuthPassword string
+}
+
+// MidtransConfig holds the payment gateway configuration.
+type MidtransConfig struct {
+	ServerKeyDev  string
+	ServerKeyProd string
+	Production    bool
+
+	// RefundGateway is either "mock", which only logs the
+	// refunds, or "midtrans".
+	RefundGateway string
+}
+
+// CloudinaryConfig holds the blob storage configuration.
+type CloudinaryConfig struct {
+	CloudName string
+	APIKey    string
+	APISecret string
+}
+
+// PDFConfig holds the ticket PDF renderer configuration.
+type PDFConfig struct {
+	URLQRCode          string
+	WkhtmltopdfPath    string
+	UnidocLicenseToken string
+}
+
+// RateLimitConfig holds the rate limit configuration of the
+// public purchase endpoints.
+type RateLimitConfig struct {
+	// Store is either "memory" or "postgres". The postgres
+	// store shares the counters between processes.
+	Store string
+
+	// TrustProxy takes the client IP address from the
+	// X-Real-IP or X-Forwarded-For header. Only enable it
+	// when running behind a reverse proxy.
+	TrustProxy bool
+
+	MainEvents   ratelimit.Rules
+	Transactions ratelimit.Rules
+	Tickets      ratelimit.Rules
+	Upload       ratelimit.Rules
+	Refunds      ratelimit.Rules
+	Holders      ratelimit.Rules
+	Waitlist     ratelimit.Rules
+	Groups       ratelimit.Rules
+	TicketLookup ratelimit.Rules
+	BuyerLogin   ratelimit.Rules
+}
+
+// MainEventConfig holds the mainevent sales configuration.
+type MainEventConfig struct {
+	// EventSlug is the slug of the event the mainevents are
+	// sold for.
+	EventSlug string
+
+	// HolderTransferCutoff is the time after which the
+	// buyers can no longer assign or transfer their tickets.
+	// There is no cutoff if it is zero.
+	HolderTransferCutoff time.Time
+
+	// WaitlistOfferTTL is the duration a waitlist offer holds
+	// the tickets before it goes to the next buyer.
+	WaitlistOfferTTL time.Duration
+
+	// WaitlistClaimURL is the page of the claim links sent
+	// with the waitlist offers.
+	WaitlistClaimURL string
+
+	// GroupMinTiket is the fewest attendees of a group order.
+	GroupMinTiket int
+
+	// GroupMaxTiket and GroupRequireApproval are the group
+	// order policy of the institutions the committee has not
+	// set one for.
+	GroupMaxTiket        int
+	GroupRequireApproval bool
+
+	// GroupPriceTiers maps the fewest attendees of each group
+	// price tier to its ticket price.
+	GroupPriceTiers map[int]int64
+
+	// PurchaseLimitPerIdentity and PurchaseLimitPerEmail are
+	// the most tickets a buyer may buy across all orders, zero
+	// means no limit.
+	PurchaseLimitPerIdentity int
+	PurchaseLimitPerEmail    int
+
+	// AccessibleSeatQuota is the number of the normal sale
+	// seats reserved for the buyers with a disability or an
+	// accommodation.
+	AccessibleSeatQuota int
+
+	// BuyerLoginTTL is the duration a login link emailed to a
+	// buyer is valid, and BuyerSessionTTL the duration of the
+	// session it opens.
+	BuyerLoginTTL   time.Duration
+	BuyerSessionTTL time.Duration
+
+	// BuyerLoginURL is the page of the login links sent to
+	// the buyers.
+	BuyerLoginURL string
+}
+
+// TicketConfig holds the Panggung Swara Insan registration
+// configuration.
+type TicketConfig struct {
+	// EventSlug is the slug of the event the registrations
+	// are for.
+	EventSlug string
+
+	// DrawConfirmationTTL is the duration the winners of the
+	// draw have to confirm their attendance.
+	DrawConfirmationTTL time.Duration
+
+	// DrawConfirmationURL is the page of the confirmation
+	// links sent to the winners.
+	DrawConfirmationURL string
+
+	// LookupCodeTTL is the duration a lookup code emailed to
+	// a registrant is valid, and LookupSessionTTL the
+	// duration of the session it opens.
+	LookupCodeTTL    time.Duration
+	LookupSessionTTL time.Duration
+}
+
+// TransactionConfig holds the Semayam Asa sales
+// configuration.
+type TransactionConfig struct {
+	// EventSlug is the slug of the event the transactions are
+	// sold for.
+	EventSlug string
+}
+
+// ValidationError is returned by Load when one or more
+// configuration values are missing or invalid.
+type ValidationError []string
+
+// Error returns all validation messages joined together.
+func (e ValidationError) Error() string {
+	return "invalid configuration: " + strings.Join(e, "; ")
+}
+
+// Load reads the configuration from the environment.
+//
+// If path is not empty, the file must exist. A file ending in
+// .yaml or .yml is read as a YAML mapping of the same keys as
+// the environment, e.g. "PGHOST: localhost", where a list is
+// joined with commas. Any other file is loaded as a .env file.
+// Otherwise a .env file in the working directory is loaded if
+// present. Variables that are already set in the environment
+// are never overridden by the file.
+func Load(path string) (Config, error) {
+	r := &envReader{}
+
+	switch {
+	case path == "":
+		// .env is optional, ignore the error if it does not exist
+		godotenv.Load()
+	case strings.HasSuffix(path, ".yaml"), strings.HasSuffix(path, ".yml"):
+		file, err := readYAML(path)
+		if err != nil {
+			return Config{}, fmt.Errorf("failed to load config file %s: %s", path, err.Error())
+		}
+		r.file = file
+	default:
+		if err := godotenv.Load(path); err != nil {
+			return Config{}, fmt.Errorf("failed to load config file %s: %s", path, err.Error())
+		}
+	}
+
+	cfg := Config{
+		Server: ServerConfig{
+			Address:         r.string("ADDRESS", ""),
+			Port:            r.int("PORT", 8080),
+			ReadTimeout:     r.duration("SERVER_READ_TIMEOUT", 10*time.Second),
+			WriteTimeout:    r.duration("SERVER_WRITE_TIMEOUT", 15*time.Second),
+			IdleTimeout:     r.duration("SERVER_IDLE_TIMEOUT", 60*time.Second),
+			ShutdownTimeout: r.duration("SERVER_SHUTDOWN_TIMEOUT", 30*time.Second),
+
+			ExportWriteTimeout: r.duration("EXPORT_WRITE_TIMEOUT", 10*time.Minute),
+		},
+		Database: DatabaseConfig{
+			Host:     r.string("PGHOST", ""),
+			User:     r.string("PGUSER", ""),
+			Password: r.string("PGPASSWORD", ""),
+			Name:     r.string("PGDATABASE", ""),
+			Port:     r.int("PGPORT", 5432),
+			SSLMode:  r.string("PGSSLMODE", "disable"),
+		},
+		SMTP: SMTPConfig{
+			Host:         r.string("CONFIG_SMTP_HOST", ""),
+			Port:         r.int("CONFIG_SMTP_PORT", 587),
+			SenderName:   r.string("CONFIG_SENDER_NAME", ""),
+			AuthEmail:    r.string("CONFIG_AUTH_EMAIL", ""),
+			AuthPassword: r.string("CONFIG_AUTH_PASSWORD", ""),
+		},
+		Midtrans: MidtransConfig{
+			ServerKeyDev:  r.string("SERVER_KEY_MIDTRANS_DEV", ""),
+			ServerKeyProd: r.string("SERVER_KEY_MIDTRANS_PROD", ""),
+			Production:    r.bool("MIDTRANS_PRODUCTION", false),
+			RefundGateway: r.string("REFUND_GATEWAY", "mock"),
+		},
+		Cloudinary: CloudinaryConfig{
+			CloudName: r.string("CLOUDINARY_API_NAME", ""),
+			APIKey:    r.string("CLOUDINARY_API_KEY", ""),
+			APISecret: r.string("CLOUDINARY_API_SECRET", ""),
+		},
+		PDF: PDFConfig{
+			URLQRCode:          r.string("URL_QRCODE", ""),
+			WkhtmltopdfPath:    r.string("WKHTMLTOPDF_PATH", ""),
+			UnidocLicenseToken: r.string("UNIDOC_LICENSE_API_KEY", ""),
+		},
+		RateLimit: RateLimitConfig{
+			Store:        r.string("RATE_LIMIT_STORE", "memory"),
+			TrustProxy:   r.bool("RATE_LIMIT_TRUST_PROXY", false),
+			MainEvents:   r.rules("RATE_LIMIT_MAINEVENTS", "ip=20/1m,email=5/1h,identity=5/1h"),
+			Transactions: r.rules("RATE_LIMIT_TRANSACTIONS", "ip=20/1m,email=5/1h,identity=5/1h"),
+			Tickets:      r.rules("RATE_LIMIT_TICKETS", "ip=10/1m,email=3/1h,identity=3/1h"),
+			Upload:       r.rules("RATE_LIMIT_UPLOAD", "ip=10/1m"),
+			Refunds:      r.rules("RATE_LIMIT_REFUNDS", "ip=10/1m"),
+			Holders:      r.rules("RATE_LIMIT_HOLDERS", "ip=10/1m"),
+			Waitlist:     r.rules("RATE_LIMIT_WAITLIST", "ip=10/1m,email=3/1h"),
+			Groups:       r.rules("RATE_LIMIT_GROUPS", "ip=5/1m,email=3/1h"),
+			TicketLookup: r.rules("RATE_LIMIT_TICKET_LOOKUP", "ip=10/1m,email=5/1h,identity=5/1h"),
+			BuyerLogin:   r.rules("RATE_LIMIT_BUYER_LOGIN", "ip=10/1m,email=5/1h"),
+		},
+		CORS: cors.Policy{
+			AllowedOrigins:   r.list("CORS_ALLOWED_ORIGINS", "*"),
+			AllowedMethods:   r.list("CORS_ALLOWED_METHODS", "GET,HEAD,POST,PUT,PATCH,DELETE"),
+			AllowedHeaders:   r.list("CORS_ALLOWED_HEADERS", "Accept,Authorization,Cache-Control,Content-Type,X-Request-ID,X-Requested-With"),
+			ExposedHeaders:   r.list("CORS_EXPOSED_HEADERS", "Retry-After,X-Request-ID"),
+			AllowCredentials: r.bool("CORS_ALLOW_CREDENTIALS", false),
+			MaxAge:           r.duration("CORS_MAX_AGE", 10*time.Minute),
+		},
+		MainEvent: MainEventConfig{
+			EventSlug:            r.string("MAINEVENT_EVENT_SLUG", "memantik-baskara"),
+			HolderTransferCutoff: r.time("HOLDER_TRANSFER_CUTOFF"),
+			WaitlistOfferTTL:     r.duration("WAITLIST_OFFER_TTL", 30*time.Minute),
+			WaitlistClaimURL:     r.string("WAITLIST_CLAIM_URL", "https://tedxuniversitasbrawijaya.com/waitlist"),
+			GroupMinTiket:        r.int("GROUP_MIN_TIKET", 5),
+			GroupMaxTiket:        r.int("GROUP_MAX_TIKET", 50),
+			GroupRequireApproval: r.bool("GROUP_REQUIRE_APPROVAL", true),
+			GroupPriceTiers:      r.priceTiers("GROUP_PRICE_TIERS", "10=75000,25=72000,50=69000"),
+
+			PurchaseLimitPerIdentity: r.int("PURCHASE_LIMIT_PER_IDENTITY", 5),
+			PurchaseLimitPerEmail:    r.int("PURCHASE_LIMIT_PER_EMAIL", 5),
+
+			AccessibleSeatQuota: r.int("ACCESSIBLE_SEAT_QUOTA", 10),
+
+			BuyerLoginTTL:   r.duration("BUYER_LOGIN_TTL", 15*time.Minute),
+			BuyerSessionTTL: r.duration("BUYER_SESSION_TTL", 2*time.Hour),
+			BuyerLoginURL:   r.string("BUYER_LOGIN_URL", "https://tedxuniversitasbrawijaya.com/pesanan"),
+		},
+		Ticket: TicketConfig{
+			EventSlug:           r.string("TICKET_EVENT_SLUG", "panggung-swara-insan"),
+			DrawConfirmationTTL: r.duration("DRAW_CONFIRMATION_TTL", 72*time.Hour),
+			DrawConfirmationURL: r.string("DRAW_CONFIRMATION_URL", "https://tedxuniversitasbrawijaya.com/konfirmasi"),
+			LookupCodeTTL:       r.duration("TICKET_LOOKUP_CODE_TTL", 10*time.Minute),
+			LookupSessionTTL:    r.duration("TICKET_LOOKUP_SESSION_TTL", 30*time.Minute),
+		},
+		Transaction: TransactionConfig{
+			EventSlug: r.string("TRANSACTION_EVENT_SLUG", "semayam-asa"),
+		},
+		AdminEmail: r.string("EMAIL_CEM", ""),
+		AdminToken: r.string("ADMIN_API_TOKEN", ""),
+		GateToken:  r.string("GATE_API_TOKEN", ""),
+	}
+
+	errs := append(r.errs, cfg.validate()...)
+	if len(errs) > 0 {
+		return Config{}, ValidationError(errs)
+	}
+
+	return cfg, nil
+}
+
+// validate validates the loaded configuration and returns
+// the list of problems found.
+func (c Config) validate() []string {
+	var errs []string
+
+	required := map[string]string{
+		"PGHOST":                 c.Database.Host,
+		"PGUSER":                 c.Database.User,
+		"PGDATABASE":             c.Database.Name,
+		"CONFIG_SMTP_HOST":       c.SMTP.Host,
+		"CONFIG_AUTH_EMAIL":      c.SMTP.AuthEmail,
+		"CONFIG_AUTH_PASSWORD":   c.SMTP.AuthPassword,
+		"CLOUDINARY_API_NAME":    c.Cloudinary.CloudName,
+		"CLOUDINARY_API_KEY":     c.Cloudinary.APIKey,
+		"CLOUDINARY_API_SECRET":  c.Cloudinary.APISecret,
+		"URL_QRCODE":             c.PDF.URLQRCode,
+		"UNIDOC_LICENSE_API_KEY": c.PDF.UnidocLicenseToken,
+		"EMAIL_CEM":              c.AdminEmail,
+	}
+	for _, key := range sortedKeys(required) {
+		if required[key] == "" {
+			errs = append(errs, fmt.Sprintf("%s is required", key))
+		}
+	}
+
+	if c.Server.Port <= 0 || c.Server.Port > 65535 {
+		errs = append(errs, "PORT must be between 1 and 65535")
+	}
+
+	timeouts := []struct {
+		key   string
+		value time.Duration
+	}{
+		{"SERVER_READ_TIMEOUT", c.Server.ReadTimeout},
+		{"SERVER_WRITE_TIMEOUT", c.Server.WriteTimeout},
+		{"SERVER_IDLE_TIMEOUT", c.Server.IdleTimeout},
+		{"SERVER_SHUTDOWN_TIMEOUT", c.Server.ShutdownTimeout},
+		{"EXPORT_WRITE_TIMEOUT", c.Server.ExportWriteTimeout},
+		{"WAITLIST_OFFER_TTL", c.MainEvent.WaitlistOfferTTL},
+		{"BUYER_LOGIN_TTL", c.MainEvent.BuyerLoginTTL},
+		{"BUYER_SESSION_TTL", c.MainEvent.BuyerSessionTTL},
+		{"DRAW_CONFIRMATION_TTL", c.Ticket.DrawConfirmationTTL},
+		{"TICKET_LOOKUP_CODE_TTL", c.Ticket.LookupCodeTTL},
+		{"TICKET_LOOKUP_SESSION_TTL", c.Ticket.LookupSessionTTL},
+	}
+	for _, timeout := range timeouts {
+		if timeout.value <= 0 {
+			errs = append(errs, fmt.Sprintf("%s must be positive", timeout.key))
+		}
+	}
+
+	if c.Database.Port <= 0 || c.Database.Port > 65535 {
+		errs = append(errs, "PGPORT must be between 1 and 65535")
+	}
+
+	if c.SMTP.Port <= 0 || c.SMTP.Port > 65535 {
+		errs = append(errs, "CONFIG_SMTP_PORT must be between 1 and 65535")
+	}
+
+	switch c.Database.SSLMode {
+	case "disable", "allow", "prefer", "require", "verify-ca", "verify-full":
+	default:
+		errs = append(errs, fmt.Sprintf("PGSSLMODE %q is not a valid sslmode", c.Database.SSLMode))
+	}
+
+	if c.SMTP.AuthEmail != "" {
+		if _, err := mail.ParseAddress(c.SMTP.AuthEmail); err != nil {
+			errs = append(errs, "CONFIG_AUTH_EMAIL must be a valid email address")
+		}
+	}
+
+	if c.SMTP.SenderName != "" {
+		if _, err := mail.ParseAddress(c.SMTP.SenderName); err != nil {
+			errs = append(errs, "CONFIG_SENDER_NAME must be a valid address, e.g. \"Name <email@domain>\"")
+		}
+	}
+
+	if c.AdminEmail != "" {
+		if _, err := mail.ParseAddress(c.AdminEmail); err != nil {
+			errs = append(errs, "EMAIL_CEM must be a valid email address")
+		}
+	}
+
+	switch c.RateLimit.Store {
+	case "memory", "postgres":
+	default:
+		errs = append(errs, fmt.Sprintf("RATE_LIMIT_STORE %q must be either memory or postgres", c.RateLimit.Store))
+	}
+
+	switch c.Midtrans.RefundGateway {
+	case "mock", "midtrans":
+	default:
+		errs = append(errs, fmt.Sprintf("REFUND_GATEWAY %q must be either mock or midtrans", c.Midtrans.RefundGateway))
+	}
+
+	if err := c.CORS.Validate(); err != nil {
+		errs = append(errs, fmt.Sprintf("CORS: %s", err.Error()))
+	}
+
+	if c.MainEvent.EventSlug == "" {
+		errs = append(errs, "MAINEVENT_EVENT_SLUG must not be empty")
+	}
+
+	if c.Ticket.EventSlug == "" {
+		errs = append(errs, "TICKET_EVENT_SLUG must not be empty")
+	}
+
+	if c.Transaction.EventSlug == "" {
+		errs = append(errs, "TRANSACTION_EVENT_SLUG must not be empty")
+	}
+
+	if u, err := url.Parse(c.MainEvent.WaitlistClaimURL); err != nil || !u.IsAbs() {
+		errs = append(errs, "WAITLIST_CLAIM_URL must be an absolute URL")
+	}
+
+	if u, err := url.Parse(c.MainEvent.BuyerLoginURL); err != nil || !u.IsAbs() {
+		errs = append(errs, "BUYER_LOGIN_URL must be an absolute URL")
+	}
+
+	if u, err := url.Parse(c.Ticket.DrawConfirmationURL); err != nil || !u.IsAbs() {
+		errs = append(errs, "DRAW_CONFIRMATION_URL must be an absolute URL")
+	}
+
+	if c.MainEvent.GroupMinTiket <= 0 {
+		errs = append(errs, "GROUP_MIN_TIKET must be positive")
+	}
+	if c.MainEvent.GroupMaxTiket < c.MainEvent.GroupMinTiket {
+		errs = append(errs, "GROUP_MAX_TIKET must not be less than GROUP_MIN_TIKET")
+	}
+
+	if c.MainEvent.PurchaseLimitPerIdentity < 0 {
+		errs = append(errs, "PURCHASE_LIMIT_PER_IDENTITY must not be negative")
+	}
+	if c.MainEvent.PurchaseLimitPerEmail < 0 {
+		errs = append(errs, "PURCHASE_LIMIT_PER_EMAIL must not be negative")
+	}
+
+	if c.MainEvent.AccessibleSeatQuota < 0 {
+		errs = append(errs, "ACCESSIBLE_SEAT_QUOTA must not be negative")
+	}
+
+	if c.PDF.URLQRCode != "" && strings.Count(c.PDF.URLQRCode, "%") != 2 {
+		errs = append(errs, "URL_QRCODE must contain exactly two format verbs for the order ID and the ticket number")
+	}
+
+	return errs
+}
+
+// DSN returns the PostgreSQL connection URL. The values are
+// escaped, so they may contain spaces, quotes or any other
+// special character.
+func (c DatabaseConfig) DSN() string {
+	u := url.URL{
+		Scheme:   "postgres",
+		User:     url.UserPassword(c.User, c.Password),
+		Host:     net.JoinHostPort(c.Host, strconv.Itoa(c.Port)),
+		Path:     "/" + c.Name,
+		RawQuery: url.Values{"sslmode": {c.SSLMode}}.Encode(),
+	}
+	return u.String()
+}
+
+// Addr returns the address the HTTP server listens on.
+func (c ServerConfig) Addr() string {
+	return fmt.Sprintf("%s:%d", c.Address, c.Port)
+}
+
+// Sender returns the address used in the From header of
+// outgoing emails.
+func (c SMTPConfig) Sender() string {
+	if c.SenderName != "" {
+		return c.SenderName
+	}
+	return c.AuthEmail
+}
+
+// ServerKey returns the server key for the
+// configured Midtrans environment.
+func (c MidtransConfig) ServerKey() string {
+	if c.Production {
+		return c.ServerKeyProd
+	}
+	return c.ServerKeyDev
+}
+
+// envReader reads typed values from the environment, falling
+// back to the values of the config file, and collects parsing
+// errors.
+type envReader struct {
+	file map[string]string
+	errs []string
+}
+
+func (r *envReader) string(key, def string) string {
+	v, ok := os.LookupEnv(key)
+	if !ok {
+		v = r.file[key]
+	}
+	if v = strings.TrimSpace(v); v == "" {
+		return def
+	}
+	return v
+}
+
+func (r *envReader) int(key string, def int) int {
+	v := r.string(key, "")
+	if v == "" {
+		return def
+	}
+
+	i, err := strconv.Atoi(v)
+	if err != nil {
+		r.errs = append(r.errs, fmt.Sprintf("%s must be an integer, got %q", key, v))
+		return def
+	}
+	return i
+}
+
+func (r *envReader) bool(key string, def bool) bool {
+	v := r.string(key, "")
+	if v == "" {
+		return def
+	}
+
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		r.errs = append(r.errs, fmt.Sprintf("%s must be a boolean, got %q", key, v))
+		return def
+	}
+	return b
+}
+
+func (r *envReader) duration(key string, def time.Duration) time.Duration {
+	v := r.string(key, "")
+	if v == "" {
+		return def
+	}
+
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		r.errs = append(r.errs, fmt.Sprintf("%s must be a duration such as 10s, got %q", key, v))
+		return def
+	}
+	return d
+}
+
+func (r *envReader) time(key string) time.Time {
+	v := r.string(key, "")
+	if v == "" {
+		return time.Time{}
+	}
+
+	t, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		r.errs = append(r.errs, fmt.Sprintf("%s must be an RFC 3339 time such as 2023-10-20T00:00:00+07:00, got %q", key, v))
+		return time.Time{}
+	}
+	return t
+}
+
+func (r *envReader) list(key, def string) []string {
+	var list []string
+	for _, v := range strings.Split(r.string(key, def), ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			list = append(list, v)
+		}
+	}
+	return list
+}
+
+func (r *envReader) rules(key, def string) ratelimit.Rules {
+	rules, err := ratelimit.ParseRules(r.string(key, def))
+	if err != nil {
+		r.errs = append(r.errs, fmt.Sprintf("%s: %s", key, err.Error()))
+		return nil
+	}
+	return rules
+}
+
+// priceTiers reads price tiers written as comma-separated
+// <fewest tickets>=<ticket price> pairs, e.g. "10=75000,25=72000".
+func (r *envReader) priceTiers(key, def string) map[int]int64 {
+	tiers := make(map[int]int64)
+	for _, v := range r.list(key, def) {
+		minStr, priceStr, ok := strings.Cut(v, "=")
+		minTiket, errMin := strconv.Atoi(strings.TrimSpace(minStr))
+		price, errPrice := strconv.ParseInt(strings.TrimSpace(priceStr), 10, 64)
+		if !ok || errMin != nil || errPrice != nil || minTiket <= 0 || price <= 0 {
+			r.errs = append(r.errs, fmt.Sprintf("%s must be a list of <fewest tickets>=<ticket price> such as 10=75000, got %q", key, v))
+			return nil
+		}
+		tiers[minTiket] = price
+	}
+	return tiers
+}
+
+// readYAML reads the YAML config file at path into a map of
+// keys to their raw values.
+func readYAML(path string) (map[string]string, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var nodes map[string]yaml.Node
+	if err := yaml.Unmarshal(b, &nodes); err != nil {
+		return nil, err
+	}
+
+	file := make(map[string]string, len(nodes))
+	for key, node := range nodes {
+		switch node.Kind {
+		case yaml.ScalarNode:
+			if node.Tag != "!!null" {
+				file[key] = node.Value
+			}
+		case yaml.SequenceNode:
+			values := make([]string, 0, len(node.Content))
+			for _, item := range node.Content {
+				if item.Kind != yaml.ScalarNode {
+					return nil, fmt.Errorf("%s must be a list of values", key)
+				}
+				values = append(values, item.Value)
+			}
+			file[key] = strings.Join(values, ",")
+		default:
+			return nil, fmt.Errorf("%s must be a value or a list of values", key)
+		}
+	}
+	return file, nil
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
 }
@@ -1,21 +1,15 @@
 package main
 
 import (
+	"flag"
 	"os"
-	"sync"
 
-	"github.com/joho/godotenv"
 	"github.com/tedxub2023/cmd/tedxub2023-api-http/server"
 )
 
 func main() {
-	godotenv.Load()
+	configPath := flag.String("config", "", "path to an optional .env or YAML config file")
+	flag.Parse()
 
-	wg := sync.WaitGroup{}
-	wg.Add(1)
-	go func() {
-		os.Exit(server.Run())
-		defer wg.Done()
-	}()
-	wg.Wait()
+	os.Exit(server.Run(*configPath))
 }
@@ -1,18 +1,32 @@
 package server
 
 import (
+	"context"
 	"fmt"
-	"log"
 	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
+	"github.com/tedxub2023/internal/event"
+	eventhttphandler "github.com/tedxub2023/internal/event/handler/http"
+	eventservice "github.com/tedxub2023/internal/event/service"
+	eventpgstore "github.com/tedxub2023/internal/event/store/postgresql"
+	"github.com/tedxub2023/internal/export"
+	exporthttphandler "github.com/tedxub2023/internal/export/handler/http"
+	exportservice "github.com/tedxub2023/internal/export/service"
+	exportpgstore "github.com/tedxub2023/internal/export/store/postgresql"
 	"github.com/tedxub2023/internal/mainevent"
 	maineventhttphandler "github.com/tedxub2023/internal/mainevent/handler/http"
 	maineventservice "github.com/tedxub2023/internal/mainevent/service"
 	maineventpgstore "github.com/tedxub2023/internal/mainevent/store/postgresql"
 	merchhttphandler "github.com/tedxub2023/internal/merch/handler/http"
 	ourTeamhttphandler "github.com/tedxub2023/internal/ourteam/handler/http"
+	"github.com/tedxub2023/internal/promo"
+	promohttphandler "github.com/tedxub2023/internal/promo/handler/http"
+	promoservice "github.com/tedxub2023/internal/promo/service"
+	promopgstore "github.com/tedxub2023/internal/promo/store/postgresql"
 	"github.com/tedxub2023/internal/ticket"
 	tickethttphandler "github.com/tedxub2023/internal/ticket/handler/http"
 	ticketservice "github.com/tedxub2023/internal/ticket/service"
@@ -23,10 +37,22 @@ import (
 	transactionpgstore "github.com/tedxub2023/internal/transaction/store/postgresql"
 	uploadhttphandler "github.com/tedxub2023/internal/upload/handler/http"
 
+	"github.com/cloudinary/cloudinary-go/v2"
 	"github.com/gorilla/mux"
 	"github.com/jmoiron/sqlx"
 	_ "github.com/lib/pq"
+	"github.com/midtrans/midtrans-go"
+	"github.com/robfig/cron/v3"
 	"github.com/tedxub2023/cmd/tedxub2023-api-http/config"
+	"github.com/tedxub2023/global/auth"
+	"github.com/tedxub2023/global/cors"
+	"github.com/tedxub2023/global/health"
+	"github.com/tedxub2023/global/helper"
+	"github.com/tedxub2023/global/logger"
+	"github.com/tedxub2023/global/metrics"
+	"github.com/tedxub2023/global/payment"
+	"github.com/tedxub2023/global/ratelimit"
+	"github.com/tedxub2023/global/worker"
 )
 
 // Following constants are the possible exit code returned
@@ -35,13 +61,22 @@ const (
 	CodeSuccess = iota
 	CodeBadConfig
 	CodeFailServeHTTP
+	CodeFailShutdown
 )
 
-// Run creates a server and starts the server.
+// Run loads the configuration from the environment and the
+// given optional config file, creates a server and starts the
+// server.
 //
 // Run returns a status code suitable for os.Exit() argument.
-func Run() int {
-	s, err := new()
+func Run(configPath string) int {
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		logger.Error(context.Background(), "failed to load config", err)
+		return CodeBadConfig
+	}
+
+	s, err := new(cfg)
 	if err != nil {
 		return CodeBadConfig
 	}
@@ -51,10 +86,30 @@ func Run() int {
 
 // server is the long-runnning application.
 type server struct {
-	srv      *http.Server
-	handlers []handler
+	srv             *http.Server
+	db              *sqlx.DB
+	workers         *worker.Group
+	handlers        []handler
+	readyChecks     []health.Check
+	cors            cors.Policy
+	limiter         *ratelimit.Limiter
+	admin           *auth.Guard
+	gate            *auth.Guard
+	shutdownTimeout time.Duration
 }
 
+// apiPrefix is the path prefix of all API endpoints.
+const apiPrefix = "/api/v1"
+
+// readinessTimeout is the maximum duration of each readiness
+// check.
+const readinessTimeout = 2 * time.Second
+
+// blobStorageCheckTTL is the duration the result of the blob
+// storage readiness check is reused, the Cloudinary Admin API
+// is rate limited per hour.
+const blobStorageCheckTTL = 5 * time.Minute
+
 // handler provides mechanism to start HTTP handler. All HTTP
 // handlers must implements this interface.
 type handler interface {
@@ -62,33 +117,201 @@ type handler interface {
 }
 
 // new creates and returns a new server.
-func new() (*server, error) {
+func new(cfg config.Config) (*server, error) {
 	s := &server{
 		srv: &http.Server{
-			ReadTimeout:  10 * time.Second,
-			WriteTimeout: 10 * time.Second,
+			Addr:         cfg.Server.Addr(),
+			ReadTimeout:  cfg.Server.ReadTimeout,
+			WriteTimeout: cfg.Server.WriteTimeout,
+			IdleTimeout:  cfg.Server.IdleTimeout,
+
+			// the handlers of long downloads extend their
+			// write deadline through the connection
+			ConnContext: helper.ConnContext,
 		},
+		workers:         worker.New(),
+		cors:            cfg.CORS,
+		shutdownTimeout: cfg.Server.ShutdownTimeout,
 	}
 
 	// connect to dabatabase
-	db, err := sqlx.Connect("postgres", config.BaseConfig())
+	db, err := sqlx.Connect("postgres", cfg.Database.DSN())
 	if err != nil {
-		log.Printf("[tedxub2023-api-http] failed to connect database: %s\n", err.Error())
+		logger.Error(context.Background(), "failed to connect database", err)
 		return nil, fmt.Errorf("failed to connect database: %s", err.Error())
 	}
+	s.db = db
+
+	// configure payment gateway
+	midtrans.ServerKey = cfg.Midtrans.ServerKey()
+	midtrans.Environment = midtrans.Sandbox
+	if cfg.Midtrans.Production {
+		midtrans.Environment = midtrans.Production
+	}
+
+	// initialize blob storage client
+	cld, err := cloudinary.NewFromParams(cfg.Cloudinary.CloudName, cfg.Cloudinary.APIKey, cfg.Cloudinary.APISecret)
+	if err != nil {
+		logger.Error(context.Background(), "failed to initialize cloudinary client", err)
+		return nil, fmt.Errorf("failed to initialize cloudinary client: %s", err.Error())
+	}
+
+	mailConfig := ticketservice.MailConfig{
+		Host:     cfg.SMTP.Host,
+		Port:     cfg.SMTP.Port,
+		Username: cfg.SMTP.AuthEmail,
+		Password: cfg.SMTP.AuthPassword,
+		Sender:   cfg.SMTP.Sender(),
+	}
+
+	pdfConfig := helper.PDFConfig{
+		URLQRCode:          cfg.PDF.URLQRCode,
+		WkhtmltopdfPath:    cfg.PDF.WkhtmltopdfPath,
+		UnidocLicenseToken: cfg.PDF.UnidocLicenseToken,
+	}
+
+	// register the dependencies checked by readiness endpoint
+	wkhtmltopdfPath := cfg.PDF.WkhtmltopdfPath
+	if wkhtmltopdfPath == "" {
+		wkhtmltopdfPath = "wkhtmltopdf"
+	}
+	s.readyChecks = []health.Check{
+		health.Postgres("postgres", db),
+		health.TCP("smtp", fmt.Sprintf("%s:%d", cfg.SMTP.Host, cfg.SMTP.Port)),
+		health.Cached(health.Check{
+			Name: "blob_storage",
+			Func: func(ctx context.Context) error {
+				_, err := cld.Admin.Ping(ctx)
+				return err
+			},
+		}, blobStorageCheckTTL),
+		health.Templates("templates", "global/template/*.html"),
+		health.All("pdf_renderer",
+			health.Executable("wkhtmltopdf", wkhtmltopdfPath),
+			health.WritableDir("storage", "global/storage"),
+			health.WritableDir("storage_ted", "global/storage/ted"),
+		),
+	}
+
+	// initialize rate limiter of the public purchase endpoints
+	{
+		var store ratelimit.Store
+		switch cfg.RateLimit.Store {
+		case "postgres":
+			store = ratelimit.NewPostgresStore(db)
+
+			scheduler := cron.New()
+			scheduler.AddFunc("@hourly", func() {
+				ctx := logger.NewContext(context.Background(), logger.Fields{"cron": "delete expired rate limit"})
+				if err := ratelimit.DeleteExpired(ctx, db); err != nil {
+					logger.Error(ctx, "failed to delete expired rate limit counters", err)
+				}
+			})
+			s.workers.AddScheduler(scheduler)
+		default:
+			store = ratelimit.NewMemoryStore()
+		}
+
+		s.limiter = ratelimit.New(store, cfg.RateLimit.TrustProxy)
+
+		post := []string{http.MethodPost}
+		s.limiter.Handle(apiPrefix+maineventhttphandler.HandlerMainEvents.URL, ratelimit.Policy{Methods: post, Rules: cfg.RateLimit.MainEvents})
+		s.limiter.Handle(apiPrefix+transactionhttphandler.HandlerTransactions.URL, ratelimit.Policy{Methods: post, Rules: cfg.RateLimit.Transactions})
+		s.limiter.Handle(apiPrefix+tickethttphandler.HandlerTickets.URL, ratelimit.Policy{Methods: post, Rules: cfg.RateLimit.Tickets})
+		s.limiter.Handle(apiPrefix+uploadhttphandler.HandlerUpload.URL, ratelimit.Policy{Methods: post, Rules: cfg.RateLimit.Upload})
+		s.limiter.Handle(apiPrefix+maineventhttphandler.HandlerMainEventRefunds.URL, ratelimit.Policy{Methods: post, Rules: cfg.RateLimit.Refunds})
+		s.limiter.Handle(apiPrefix+maineventhttphandler.HandlerWaitlist.URL, ratelimit.Policy{Methods: post, Rules: cfg.RateLimit.Waitlist})
+		s.limiter.Handle(apiPrefix+maineventhttphandler.HandlerGroups.URL, ratelimit.Policy{Methods: post, Rules: cfg.RateLimit.Groups})
+		s.limiter.Handle(apiPrefix+tickethttphandler.HandlerLookup.URL, ratelimit.Policy{Methods: post, Rules: cfg.RateLimit.TicketLookup})
+		s.limiter.Handle(apiPrefix+tickethttphandler.HandlerLookupVerify.URL, ratelimit.Policy{Methods: post, Rules: cfg.RateLimit.TicketLookup})
+		s.limiter.Handle(apiPrefix+maineventhttphandler.HandlerBuyerLogin.URL, ratelimit.Policy{Methods: post, Rules: cfg.RateLimit.BuyerLogin})
+		s.limiter.Handle(apiPrefix+maineventhttphandler.HandlerBuyerLoginVerify.URL, ratelimit.Policy{Methods: post, Rules: cfg.RateLimit.BuyerLogin})
+		s.limiter.Handle(apiPrefix+maineventhttphandler.HandlerMainEventHolders.URL, ratelimit.Policy{Methods: []string{http.MethodPut}, Rules: cfg.RateLimit.Holders})
+	}
+
+	// protect the committee-only endpoints
+	{
+		if cfg.AdminToken == "" {
+			logger.Warn(context.Background(), "ADMIN_API_TOKEN is not set, the admin endpoints reject every request")
+		}
+
+		s.admin = auth.New(cfg.AdminToken)
+		s.admin.Protect(apiPrefix+maineventhttphandler.HandlerMainEvents.URL, http.MethodGet)
+		s.admin.Protect(apiPrefix+maineventhttphandler.HandlerMainEvent.URL, http.MethodPatch)
+		s.admin.Protect(apiPrefix+transactionhttphandler.HandlerTransactions.URL, http.MethodGet)
+		s.admin.Protect(apiPrefix+transactionhttphandler.HandlerTransaction.URL, http.MethodPatch)
+		s.admin.Protect(apiPrefix + maineventhttphandler.HandlerRefunds.URL)
+		s.admin.Protect(apiPrefix + maineventhttphandler.HandlerRefund.URL)
+		s.admin.Protect(apiPrefix+maineventhttphandler.HandlerGroups.URL, http.MethodGet)
+		s.admin.Protect(apiPrefix + maineventhttphandler.HandlerGroup.URL)
+		s.admin.Protect(apiPrefix + maineventhttphandler.HandlerInstitutions.URL)
+		s.admin.Protect(apiPrefix + maineventhttphandler.HandlerInstitution.URL)
+		s.admin.Protect(apiPrefix + maineventhttphandler.HandlerMainEventAccommodation.URL)
+		s.admin.Protect(apiPrefix + maineventhttphandler.HandlerAccommodationReport.URL)
+		s.admin.Protect(apiPrefix+maineventhttphandler.HandlerSeats.URL, http.MethodPut)
+		s.admin.Protect(apiPrefix + maineventhttphandler.HandlerComplimentaryImport.URL)
+		s.admin.Protect(apiPrefix + promohttphandler.HandlerPromos.URL)
+		s.admin.Protect(apiPrefix + promohttphandler.HandlerPromo.URL)
+		s.admin.Protect(apiPrefix + promohttphandler.HandlerPromoReport.URL)
+		s.admin.Protect(apiPrefix+eventhttphandler.HandlerEvents.URL, http.MethodPost)
+		s.admin.Protect(apiPrefix+eventhttphandler.HandlerEvent.URL, http.MethodPut)
+		s.admin.Protect(apiPrefix+tickethttphandler.HandlerDraw.URL, http.MethodPost)
+		s.admin.Protect(apiPrefix + tickethttphandler.HandlerDrawPreview.URL)
+		s.admin.Protect(apiPrefix + tickethttphandler.HandlerDrawRun.URL)
+		s.admin.Protect(apiPrefix + exporthttphandler.HandlerExports.URL)
+		s.admin.Protect(apiPrefix + exporthttphandler.HandlerExport.URL)
+
+		// the check-in returns the attendee data, the scanners
+		// at the gates use their own token
+		s.gate = auth.New(cfg.GateToken, cfg.AdminToken)
+		s.gate.Protect(apiPrefix + maineventhttphandler.HandlerCheckIn.URL)
+		s.gate.Protect(apiPrefix + transactionhttphandler.HandlerCheckIn.URL)
+	}
+
+	// initialize payment refunder
+	var refunder payment.Refunder
+	switch cfg.Midtrans.RefundGateway {
+	case "midtrans":
+		refunder = payment.NewMidtrans()
+	default:
+		refunder = payment.NewMock()
+	}
+
+	// initialize event service
+	var eventSvc event.Service
+	{
+		pgStore, err := eventpgstore.New(db)
+		if err != nil {
+			logger.Error(context.Background(), "failed to initialize event postgresql store", err)
+			return nil, fmt.Errorf("failed to initialize event postgresql store: %s", err.Error())
+		}
+
+		eventSvc, err = eventservice.New(pgStore)
+		if err != nil {
+			logger.Error(context.Background(), "failed to initialize event service", err)
+			return nil, fmt.Errorf("failed to initialize event service: %s", err.Error())
+		}
+	}
 
 	// initialize ticket service
 	var ticketSvc ticket.Service
 	{
 		pgStore, err := ticketpgstore.New(db)
 		if err != nil {
-			log.Printf("[tedxub2023-api-http] failed to initialize ticket postgresql store: %s\n", err.Error())
+			logger.Error(context.Background(), "failed to initialize ticket postgresql store", err)
 			return nil, fmt.Errorf("failed to initialize ticket postgresql store: %s", err.Error())
 		}
 
-		ticketSvc, err = ticketservice.New(pgStore)
+		ticketSvc, err = ticketservice.New(pgStore, s.workers, eventSvc, ticketservice.Config{
+			Mail:             mailConfig,
+			EventSlug:        cfg.Ticket.EventSlug,
+			ConfirmationTTL:  cfg.Ticket.DrawConfirmationTTL,
+			ConfirmationURL:  cfg.Ticket.DrawConfirmationURL,
+			LookupCodeTTL:    cfg.Ticket.LookupCodeTTL,
+			LookupSessionTTL: cfg.Ticket.LookupSessionTTL,
+		})
 		if err != nil {
-			log.Printf("[tedxub2023-api-http] failed to initialize ticket service: %s\n", err.Error())
+			logger.Error(context.Background(), "failed to initialize ticket service", err)
 			return nil, fmt.Errorf("failed to initialize ticket service: %s", err.Error())
 		}
 	}
@@ -98,29 +321,90 @@ func new() (*server, error) {
 	{
 		pgStore, err := transactionpgstore.New(db)
 		if err != nil {
-			log.Printf("[tedxub2023-api-http] failed to initialize transaction postgresql store: %s\n", err.Error())
+			logger.Error(context.Background(), "failed to initialize transaction postgresql store", err)
 			return nil, fmt.Errorf("failed to initialize transaction postgresql store: %s", err.Error())
 		}
 
-		transactionSvc, err = transactionservice.New(pgStore)
+		transactionSvc, err = transactionservice.New(pgStore, s.workers, eventSvc, transactionservice.Config{
+			Mail:      mailConfig,
+			PDF:       pdfConfig,
+			EventSlug: cfg.Transaction.EventSlug,
+		})
 		if err != nil {
-			log.Printf("[tedxub2023-api-http] failed to initialize transaction service: %s\n", err.Error())
+			logger.Error(context.Background(), "failed to initialize transaction service", err)
 			return nil, fmt.Errorf("failed to initialize transaction service: %s", err.Error())
 		}
 	}
 
+	// initialize promo service
+	var promoSvc promo.Service
+	{
+		pgStore, err := promopgstore.New(db)
+		if err != nil {
+			logger.Error(context.Background(), "failed to initialize promo postgresql store", err)
+			return nil, fmt.Errorf("failed to initialize promo postgresql store: %s", err.Error())
+		}
+
+		promoSvc, err = promoservice.New(pgStore)
+		if err != nil {
+			logger.Error(context.Background(), "failed to initialize promo service", err)
+			return nil, fmt.Errorf("failed to initialize promo service: %s", err.Error())
+		}
+	}
+
+	// initialize export service
+	var exportSvc export.Service
+	{
+		pgStore, err := exportpgstore.New(db)
+		if err != nil {
+			logger.Error(context.Background(), "failed to initialize export postgresql store", err)
+			return nil, fmt.Errorf("failed to initialize export postgresql store: %s", err.Error())
+		}
+
+		exportSvc, err = exportservice.New(pgStore)
+		if err != nil {
+			logger.Error(context.Background(), "failed to initialize export service", err)
+			return nil, fmt.Errorf("failed to initialize export service: %s", err.Error())
+		}
+	}
+
 	// initialize mainevent service
 	var maineventSvc mainevent.Service
 	{
 		pgStore, err := maineventpgstore.New(db)
 		if err != nil {
-			log.Printf("[tedxub2023-api-http] failed to initialize mainevent postgresql store: %s\n", err.Error())
+			logger.Error(context.Background(), "failed to initialize mainevent postgresql store", err)
 			return nil, fmt.Errorf("failed to initialize mainevent postgresql store: %s", err.Error())
 		}
 
-		maineventSvc, err = maineventservice.New(pgStore)
+		maineventSvc, err = maineventservice.New(pgStore, s.workers, refunder, promoSvc, eventSvc, transactionSvc, maineventservice.Config{
+			Mail:       mailConfig,
+			PDF:        pdfConfig,
+			EventSlug:  cfg.MainEvent.EventSlug,
+			AdminEmail: cfg.AdminEmail,
+
+			HolderTransferCutoff: cfg.MainEvent.HolderTransferCutoff,
+			WaitlistOfferTTL:     cfg.MainEvent.WaitlistOfferTTL,
+			WaitlistClaimURL:     cfg.MainEvent.WaitlistClaimURL,
+
+			GroupMinTiket: cfg.MainEvent.GroupMinTiket,
+			GroupPolicy: mainevent.Institution{
+				MaxTiket:        cfg.MainEvent.GroupMaxTiket,
+				RequireApproval: cfg.MainEvent.GroupRequireApproval,
+			},
+			GroupPriceTiers: cfg.MainEvent.GroupPriceTiers,
+
+			PurchaseLimitPerIdentity: cfg.MainEvent.PurchaseLimitPerIdentity,
+			PurchaseLimitPerEmail:    cfg.MainEvent.PurchaseLimitPerEmail,
+
+			AccessibleSeatQuota: cfg.MainEvent.AccessibleSeatQuota,
+
+			BuyerLoginTTL:   cfg.MainEvent.BuyerLoginTTL,
+			BuyerSessionTTL: cfg.MainEvent.BuyerSessionTTL,
+			BuyerLoginURL:   cfg.MainEvent.BuyerLoginURL,
+		})
 		if err != nil {
-			log.Printf("[tedxub2023-api-http] failed to initialize mainevent service: %s\n", err.Error())
+			logger.Error(context.Background(), "failed to initialize mainevent service", err)
 			return nil, fmt.Errorf("failed to initialize mainevent service: %s", err.Error())
 		}
 	}
@@ -129,11 +413,18 @@ func new() (*server, error) {
 	{
 		identities := []tickethttphandler.HandlerIdentity{
 			tickethttphandler.HandlerTickets,
+			tickethttphandler.HandlerDraw,
+			tickethttphandler.HandlerDrawPreview,
+			tickethttphandler.HandlerDrawRun,
+			tickethttphandler.HandlerDrawConfirmation,
+			tickethttphandler.HandlerLookup,
+			tickethttphandler.HandlerLookupVerify,
+			tickethttphandler.HandlerRegistration,
 		}
 
 		ticketHTTP, err := tickethttphandler.New(ticketSvc, identities)
 		if err != nil {
-			log.Printf("[tedxub2023-api-http] failed to initialize ticket http handlers: %s\n", err.Error())
+			logger.Error(context.Background(), "failed to initialize ticket http handlers", err)
 			return nil, fmt.Errorf("failed to initialize ticket http handlers: %s", err.Error())
 		}
 
@@ -148,7 +439,7 @@ func new() (*server, error) {
 
 		ourTeamHTTP, err := ourTeamhttphandler.New(identities)
 		if err != nil {
-			log.Printf("[tedxub2023-api-http] failed to initialize ourTeam http handlers: %s\n", err.Error())
+			logger.Error(context.Background(), "failed to initialize ourTeam http handlers", err)
 			return nil, fmt.Errorf("failed to initialize ourTeam http handlers: %s", err.Error())
 		}
 
@@ -163,8 +454,8 @@ func new() (*server, error) {
 
 		merchTTP, err := merchhttphandler.New(identities)
 		if err != nil {
-			log.Printf("[tedxub2023-api-http] failed to initialize ourTeam http handlers: %s\n", err.Error())
-			return nil, fmt.Errorf("failed to initialize ourTeam http handlers: %s", err.Error())
+			logger.Error(context.Background(), "failed to initialize merch http handlers", err)
+			return nil, fmt.Errorf("failed to initialize merch http handlers: %s", err.Error())
 		}
 
 		s.handlers = append(s.handlers, merchTTP)
@@ -180,7 +471,7 @@ func new() (*server, error) {
 
 		transactionHTTP, err := transactionhttphandler.New(transactionSvc, identities)
 		if err != nil {
-			log.Printf("[tedxub2023-api-http] failed to initialize transaction http handlers: %s\n", err.Error())
+			logger.Error(context.Background(), "failed to initialize transaction http handlers", err)
 			return nil, fmt.Errorf("failed to initialize transaction http handlers: %s", err.Error())
 		}
 
@@ -193,15 +484,64 @@ func new() (*server, error) {
 			uploadhttphandler.HandlerUpload,
 		}
 
-		uploadHTTP, err := uploadhttphandler.New(identities)
+		uploadHTTP, err := uploadhttphandler.New(cld, identities)
 		if err != nil {
-			log.Printf("[upload-api-http] failed to initialize upload http handlers: %s\n", err.Error())
+			logger.Error(context.Background(), "failed to initialize upload http handlers", err)
 			return nil, fmt.Errorf("failed to initialize upload http handlers: %s", err.Error())
 		}
 
 		s.handlers = append(s.handlers, uploadHTTP)
 	}
 
+	// initialize promo HTTP handler
+	{
+		identities := []promohttphandler.HandlerIdentity{
+			promohttphandler.HandlerPromos,
+			promohttphandler.HandlerPromo,
+			promohttphandler.HandlerPromoReport,
+		}
+
+		promoHTTP, err := promohttphandler.New(promoSvc, identities)
+		if err != nil {
+			logger.Error(context.Background(), "failed to initialize promo http handlers", err)
+			return nil, fmt.Errorf("failed to initialize promo http handlers: %s", err.Error())
+		}
+
+		s.handlers = append(s.handlers, promoHTTP)
+	}
+
+	// initialize event HTTP handler
+	{
+		identities := []eventhttphandler.HandlerIdentity{
+			eventhttphandler.HandlerEvents,
+			eventhttphandler.HandlerEvent,
+		}
+
+		eventHTTP, err := eventhttphandler.New(eventSvc, identities)
+		if err != nil {
+			logger.Error(context.Background(), "failed to initialize event http handlers", err)
+			return nil, fmt.Errorf("failed to initialize event http handlers: %s", err.Error())
+		}
+
+		s.handlers = append(s.handlers, eventHTTP)
+	}
+
+	// initialize export HTTP handler
+	{
+		identities := []exporthttphandler.HandlerIdentity{
+			exporthttphandler.HandlerExports,
+			exporthttphandler.HandlerExport,
+		}
+
+		exportHTTP, err := exporthttphandler.New(exportSvc, cfg.Server.ExportWriteTimeout, identities)
+		if err != nil {
+			logger.Error(context.Background(), "failed to initialize export http handlers", err)
+			return nil, fmt.Errorf("failed to initialize export http handlers: %s", err.Error())
+		}
+
+		s.handlers = append(s.handlers, exportHTTP)
+	}
+
 	// initialize mainevent HTTP handler
 	{
 		identities := []maineventhttphandler.HandlerIdentity{
@@ -209,11 +549,31 @@ func new() (*server, error) {
 			maineventhttphandler.HandlerMainEvents,
 			maineventhttphandler.HandlerCheckIn,
 			maineventhttphandler.HandlerCounter,
+			maineventhttphandler.HandlerMainEventRefunds,
+			maineventhttphandler.HandlerMainEventHolders,
+			maineventhttphandler.HandlerMainEventAccommodation,
+			maineventhttphandler.HandlerAccommodationReport,
+			maineventhttphandler.HandlerWaitlist,
+			maineventhttphandler.HandlerGroups,
+			maineventhttphandler.HandlerGroup,
+			maineventhttphandler.HandlerInstitutions,
+			maineventhttphandler.HandlerInstitution,
+			maineventhttphandler.HandlerSeats,
+			maineventhttphandler.HandlerRefunds,
+			maineventhttphandler.HandlerRefund,
+			maineventhttphandler.HandlerBuyerLogin,
+			maineventhttphandler.HandlerBuyerLoginVerify,
+			maineventhttphandler.HandlerBuyerMainEvents,
+			maineventhttphandler.HandlerBuyerPaymentProof,
+			maineventhttphandler.HandlerBuyerTicket,
+			maineventhttphandler.HandlerBuyerMail,
+			maineventhttphandler.HandlerBuyerTransactions,
+			maineventhttphandler.HandlerComplimentaryImport,
 		}
 
 		maineventHTTP, err := maineventhttphandler.New(maineventSvc, identities)
 		if err != nil {
-			log.Printf("[tedxub2023-api-http] failed to initialize mainevent http handlers: %s\n", err.Error())
+			logger.Error(context.Background(), "failed to initialize mainevent http handlers", err)
 			return nil, fmt.Errorf("failed to initialize mainevent http handlers: %s", err.Error())
 		}
 
@@ -224,45 +584,92 @@ func new() (*server, error) {
 
 // start starts the given server.
 func (s *server) start() int {
-	log.Println("[tedxub2023-api-http] starting server...")
+	logger.Info(context.Background(), "starting server")
 
 	// create multiplexer object
 	rootMux := mux.NewRouter()
-	appMux := rootMux.PathPrefix("/api/v1").Subrouter()
+	appMux := rootMux.PathPrefix(apiPrefix).Subrouter()
 
 	// starts handlers
 	for _, h := range s.handlers {
 		if err := h.Start(appMux); err != nil {
-			log.Printf("[tedxub2023-api-http] failed to start handler: %s\n", err.Error())
+			logger.Error(context.Background(), "failed to start handler", err)
 			return CodeFailServeHTTP
 		}
 	}
 
-	// endpoint checker
-	appMux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
-		fmt.Fprint(w, "Hello world! Auto Deploy On, INPO 60 RIBUNYA CAIRINN DONGG!!! @tedxub2023")
-	})
+	// health endpoints, /health is kept for the existing
+	// uptime checkers
+	appMux.Handle("/health", health.LiveHandler()).Methods(http.MethodGet)
+	appMux.Handle("/health/live", health.LiveHandler()).Methods(http.MethodGet)
+	appMux.Handle("/health/ready", health.ReadyHandler(readinessTimeout, s.readyChecks...)).Methods(http.MethodGet)
+
+	// metrics endpoint for Prometheus scraper
+	rootMux.Handle("/metrics", metrics.Handler())
 
 	// use middlewares to app mux only
-	appMux.Use(corsMiddleware)
+	appMux.Use(logger.Middleware)
+	appMux.Use(metrics.Middleware)
+	appMux.Use(s.limiter.Middleware)
+	appMux.Use(s.admin.Middleware)
+	appMux.Use(s.gate.Middleware)
+
+	// CORS wraps the root router so that preflight requests
+	// are answered for every route
+	s.srv.Handler = s.cors.Handler(rootMux)
 
 	// listen and serve
-	log.Printf("[tedxub2023-api-http] Server is running at %s:%s", os.Getenv("ADDRESS"), os.Getenv("PORT"))
-	log.Fatal(http.ListenAndServe(fmt.Sprintf("%s:%s", os.Getenv("ADDRESS"), os.Getenv("PORT")), rootMux))
+	errChan := make(chan error, 1)
+	go func() {
+		logger.Info(context.Background(), "server is running", logger.Fields{"addr": s.srv.Addr})
+		if err := s.srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			errChan <- err
+		}
+	}()
+
+	// wait until the server fails or a termination signal is
+	// received
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(sigChan)
+
+	select {
+	case err := <-errChan:
+		logger.Error(context.Background(), "failed to serve http", err)
+		s.workers.Shutdown(context.Background())
+		return CodeFailServeHTTP
+	case sig := <-sigChan:
+		logger.Info(context.Background(), "shutting down server", logger.Fields{"signal": sig.String()})
+	}
 
-	return CodeSuccess
+	return s.stop()
 }
 
-func corsMiddleware(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Access-Control-Allow-Origin", "*")
-		w.Header().Add("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With")
-		w.Header().Add("Access-Control-Allow-Credentials", "true")
-		w.Header().Add("Access-Control-Allow-Methods", "POST, HEAD, PATCH, OPTIONS, GET, PUT, DELETE")
-		if r.Method == "OPTIONS" {
-			w.WriteHeader(http.StatusNoContent)
-			return
-		}
-		next.ServeHTTP(w, r)
-	})
+// stop drains in-flight requests, waits for the background
+// jobs to finish and closes the database connections, within
+// the configured shutdown timeout.
+func (s *server) stop() int {
+	ctx, cancel := context.WithTimeout(context.Background(), s.shutdownTimeout)
+	defer cancel()
+
+	code := CodeSuccess
+
+	if err := s.srv.Shutdown(ctx); err != nil {
+		logger.Error(context.Background(), "failed to drain http requests", err)
+		code = CodeFailShutdown
+	}
+
+	if err := s.workers.Shutdown(ctx); err != nil {
+		logger.Error(context.Background(), "failed to wait for background jobs", err)
+		code = CodeFailShutdown
+	}
+
+	if err := s.db.Close(); err != nil {
+		logger.Error(context.Background(), "failed to close database", err)
+		code = CodeFailShutdown
+	}
+
+	logger.Info(context.Background(), "server stopped")
+
+	return code
 }
@@ -0,0 +1,120 @@
+// Command tedxub2023-export writes an export of the orders to
+// a file or the standard output, e.g.
+//
+//	tedxub2023-export -config .env -dataset mainevent_attendees -format xlsx -o peserta.xlsx
+//
+// It reads the same configuration as the API server.
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq"
+	"github.com/tedxub2023/cmd/tedxub2023-api-http/config"
+	"github.com/tedxub2023/internal/export"
+	exportservice "github.com/tedxub2023/internal/export/service"
+	exportpgstore "github.com/tedxub2023/internal/export/store/postgresql"
+)
+
+func main() {
+	configPath := flag.String("config", "", "path to an optional .env or YAML config file")
+	dataset := flag.String("dataset", "", "dataset to export, one of "+datasetNames())
+	format := flag.String("format", string(export.FormatCSV), "file format, csv or xlsx")
+	columns := flag.String("columns", "", "comma separated keys of the exported columns, all by default")
+	eventSlug := flag.String("event", "", "export only the orders of the event with this slug")
+	status := flag.String("status", "", "export only the orders with this payment status")
+	output := flag.String("o", "", "path of the output file, the standard output by default")
+	flag.Parse()
+
+	req := export.Request{
+		Dataset: export.Dataset(*dataset),
+		Format:  export.Format(strings.ToLower(*format)),
+		Filter: export.Filter{
+			EventSlug: *eventSlug,
+			Status:    *status,
+		},
+	}
+	for _, c := range strings.Split(*columns, ",") {
+		if c = strings.TrimSpace(c); c != "" {
+			req.Columns = append(req.Columns, c)
+		}
+	}
+
+	if err := run(*configPath, req, *output); err != nil {
+		fmt.Fprintf(os.Stderr, "export failed: %s\n", err.Error())
+		os.Exit(1)
+	}
+}
+
+func run(configPath string, req export.Request, output string) (err error) {
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		return err
+	}
+
+	db, err := sqlx.Connect("postgres", cfg.Database.DSN())
+	if err != nil {
+		return fmt.Errorf("failed to connect database: %s", err.Error())
+	}
+	defer db.Close()
+
+	pgStore, err := exportpgstore.New(db)
+	if err != nil {
+		return err
+	}
+	exportSvc, err := exportservice.New(pgStore)
+	if err != nil {
+		return err
+	}
+
+	// write to the standard output unless a file is given,
+	// a failed export does not leave a partial file behind
+	var w io.Writer = os.Stdout
+	if output != "" {
+		f, err := os.Create(output)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			errClose := f.Close()
+			if err == nil {
+				err = errClose
+			}
+			if err != nil {
+				os.Remove(output)
+			}
+		}()
+		w = f
+	}
+	bw := bufio.NewWriter(w)
+
+	// stop the export on interrupt
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	err = exportSvc.Export(ctx, req, bw)
+	if err != nil {
+		return err
+	}
+
+	return bw.Flush()
+}
+
+// datasetNames returns the names of the datasets, separated by
+// commas.
+func datasetNames() string {
+	names := make([]string, 0, len(export.DatasetList))
+	for _, d := range export.DatasetList {
+		names = append(names, string(d))
+	}
+	return strings.Join(names, ", ")
+}
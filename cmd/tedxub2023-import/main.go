@@ -0,0 +1,119 @@
+// Command tedxub2023-import imports the complimentary orders of
+// a CSV file through the admin endpoint of a running service,
+// e.g.
+//
+//	tedxub2023-import -config .env -api https://api.tedxuniversitasbrawijaya.com/api/v1 pembicara.csv
+//
+// The tickets are issued and emailed by the service. The
+// admin token is read from -token or ADMIN_API_TOKEN.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/joho/godotenv"
+)
+
+// importPath is the path of the import endpoint under the API
+// prefix.
+const importPath = "/mainevents/complimentary/import"
+
+// importResponse is the response of the import endpoint.
+type importResponse struct {
+	Data []struct {
+		OrderID    string   `json:"order_id"`
+		Nama       string   `json:"nama"`
+		Email      string   `json:"email"`
+		NomorTiket []string `json:"nomor_tiket"`
+	} `json:"data"`
+	Meta []struct {
+		Baris int    `json:"baris"`
+		Error string `json:"error"`
+	} `json:"meta"`
+	Errors []string `json:"errors"`
+}
+
+func main() {
+	configPath := flag.String("config", "", "path to an optional .env config file")
+	api := flag.String("api", "http://localhost:8080/api/v1", "base URL of the API")
+	token := flag.String("token", "", "admin token, ADMIN_API_TOKEN by default")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: %s [flags] <file.csv>\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if *configPath != "" {
+		if err := godotenv.Load(*configPath); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to load config file %s: %s\n", *configPath, err.Error())
+			os.Exit(1)
+		}
+	}
+	if *token == "" {
+		*token = os.Getenv("ADMIN_API_TOKEN")
+	}
+
+	if err := run(strings.TrimSuffix(*api, "/")+importPath, *token, flag.Arg(0)); err != nil {
+		fmt.Fprintf(os.Stderr, "import failed: %s\n", err.Error())
+		os.Exit(1)
+	}
+}
+
+func run(url, token, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	req, err := http.NewRequest(http.MethodPost, url, f)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "text/csv")
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	// the service gives the import up to 30 seconds
+	client := &http.Client{Timeout: time.Minute}
+	res, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return err
+	}
+
+	var result importResponse
+	if err := json.Unmarshal(body, &result); err != nil {
+		return fmt.Errorf("unexpected response %s: %s", res.Status, strings.TrimSpace(string(body)))
+	}
+
+	if res.StatusCode != http.StatusOK {
+		for _, row := range result.Meta {
+			fmt.Fprintf(os.Stderr, "baris %d: %s\n", row.Baris, row.Error)
+		}
+		return fmt.Errorf("%s %s", res.Status, strings.Join(result.Errors, ", "))
+	}
+
+	for _, order := range result.Data {
+		fmt.Printf("%s\t%s\t%s\t%s\n", order.OrderID, order.Nama, order.Email, strings.Join(order.NomorTiket, ","))
+	}
+	fmt.Fprintf(os.Stderr, "%d complimentary orders imported\n", len(result.Data))
+
+	return nil
+}
@@ -0,0 +1,124 @@
+// Package auth protects the committee-only endpoints with
+// shared tokens, e.g. the admin token.
+package auth
+
+import (
+	"crypto/subtle"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/tedxub2023/global/helper"
+	"github.com/tedxub2023/global/logger"
+)
+
+// ErrUnauthorized is written in the response of a request to
+// a protected route without a valid token.
+var ErrUnauthorized = errors.New("UNAUTHORIZED")
+
+// Guard rejects the requests to the protected routes that do
+// not carry one of its tokens in the "Authorization: Bearer"
+// header.
+type Guard struct {
+	tokens []string
+	routes map[string][]string
+}
+
+// New creates a new Guard accepting any of the given tokens,
+// the empty ones are ignored. If there is no token left, all
+// requests to the protected routes are rejected.
+func New(tokens ...string) *Guard {
+	g := &Guard{
+		routes: make(map[string][]string),
+	}
+	for _, token := range tokens {
+		if token != "" {
+			g.tokens = append(g.tokens, token)
+		}
+	}
+	return g
+}
+
+// Protect protects the given methods of the handler served at
+// the given route path template, e.g. "/api/v1/refunds". All
+// methods are protected if none is given.
+func (g *Guard) Protect(pathTemplate string, methods ...string) {
+	g.routes[pathTemplate] = methods
+}
+
+// Middleware rejects the unauthorized requests to the
+// protected routes with 401 Unauthorized.
+func (g *Guard) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		route := mux.CurrentRoute(r)
+		if route == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		tpl, err := route.GetPathTemplate()
+		if err != nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		methods, ok := g.routes[tpl]
+		if !ok || !protects(methods, r.Method) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if !g.authorized(r) {
+			logger.Warn(r.Context(), "unauthorized request to protected route")
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			helper.WriteErrorResponse(w, http.StatusUnauthorized, []string{ErrUnauthorized.Error()})
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// authorized returns whether the request carries one of the
+// tokens.
+func (g *Guard) authorized(r *http.Request) bool {
+	token := BearerToken(r)
+	if token == "" {
+		return false
+	}
+
+	authorized := false
+	for _, t := range g.tokens {
+		if subtle.ConstantTimeCompare([]byte(token), []byte(t)) == 1 {
+			authorized = true
+		}
+	}
+	return authorized
+}
+
+// BearerToken returns the token of the "Authorization:
+// Bearer" header of the given request, or the empty string if
+// there is none.
+func BearerToken(r *http.Request) string {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return ""
+	}
+
+	return strings.TrimSpace(token)
+}
+
+// protects returns whether the given method is one of the
+// protected methods.
+func protects(methods []string, method string) bool {
+	if len(methods) == 0 {
+		return true
+	}
+	for _, m := range methods {
+		if m == method {
+			return true
+		}
+	}
+	return false
+}
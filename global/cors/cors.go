@@ -0,0 +1,211 @@
+package cors
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tedxub2023/global/helper"
+)
+
+var (
+	// ErrOriginNotAllowed is written in the response of a
+	// preflight request from an origin that is not allowed.
+	ErrOriginNotAllowed = errors.New("ORIGIN_NOT_ALLOWED")
+
+	// ErrMethodNotAllowed is written in the response of a
+	// preflight request for a method that is not allowed.
+	ErrMethodNotAllowed = errors.New("METHOD_NOT_ALLOWED")
+
+	// ErrHeaderNotAllowed is written in the response of a
+	// preflight request for a header that is not allowed.
+	ErrHeaderNotAllowed = errors.New("HEADER_NOT_ALLOWED")
+
+	errWildcardCredentials = errors.New("wildcard origin cannot be used together with credentials")
+	errInvalidOrigin       = errors.New("origin pattern may contain at most one wildcard")
+)
+
+// Policy is the CORS policy applied to all routes.
+type Policy struct {
+	// AllowedOrigins are the origins allowed to call the API,
+	// e.g. "https://tedxuniversitasbrawijaya.com". An origin
+	// may contain one wildcard, e.g. "https://*.vercel.app",
+	// and "*" allows any origin.
+	AllowedOrigins []string
+
+	// AllowedMethods are the methods allowed in a cross-origin
+	// request.
+	AllowedMethods []string
+
+	// AllowedHeaders are the request headers allowed in a
+	// cross-origin request, compared case-insensitively.
+	AllowedHeaders []string
+
+	// ExposedHeaders are the response headers readable by the
+	// browser script.
+	ExposedHeaders []string
+
+	// AllowCredentials allows cookies and the Authorization
+	// header to be sent. It cannot be used with "*" origin.
+	AllowCredentials bool
+
+	// MaxAge is the duration the browser may cache the result
+	// of a preflight request.
+	MaxAge time.Duration
+}
+
+// Validate returns an error if the policy is inconsistent.
+func (p Policy) Validate() error {
+	for _, origin := range p.AllowedOrigins {
+		if origin == "*" && p.AllowCredentials {
+			return errWildcardCredentials
+		}
+		if strings.Count(origin, "*") > 1 {
+			return errInvalidOrigin
+		}
+	}
+	return nil
+}
+
+// Handler wraps the given handler with the policy. It must
+// wrap the root router, so that the preflight requests are
+// answered before routing, regardless of the methods the
+// matched route accepts.
+func (p Policy) Handler(next http.Handler) http.Handler {
+	allowedMethods := strings.Join(p.AllowedMethods, ", ")
+	allowedHeaders := strings.Join(p.AllowedHeaders, ", ")
+	exposedHeaders := strings.Join(p.ExposedHeaders, ", ")
+	maxAge := strconv.Itoa(int(p.MaxAge.Seconds()))
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		if origin == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		// the response depends on the origin, caches must
+		// not serve it to another origin
+		w.Header().Add("Vary", "Origin")
+
+		preflight := r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != ""
+		if preflight {
+			w.Header().Add("Vary", "Access-Control-Request-Method")
+			w.Header().Add("Vary", "Access-Control-Request-Headers")
+		}
+
+		allowOrigin, ok := p.allowOrigin(origin)
+		if !ok {
+			if preflight {
+				helper.WriteErrorResponse(w, http.StatusForbidden, []string{ErrOriginNotAllowed.Error()})
+				return
+			}
+
+			// let the request through without CORS headers,
+			// the browser hides the response from the script
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if preflight {
+			if !p.allowMethod(r.Header.Get("Access-Control-Request-Method")) {
+				helper.WriteErrorResponse(w, http.StatusForbidden, []string{ErrMethodNotAllowed.Error()})
+				return
+			}
+			if !p.allowHeaders(r.Header.Get("Access-Control-Request-Headers")) {
+				helper.WriteErrorResponse(w, http.StatusForbidden, []string{ErrHeaderNotAllowed.Error()})
+				return
+			}
+
+			w.Header().Set("Access-Control-Allow-Origin", allowOrigin)
+			w.Header().Set("Access-Control-Allow-Methods", allowedMethods)
+			if allowedHeaders != "" {
+				w.Header().Set("Access-Control-Allow-Headers", allowedHeaders)
+			}
+			if p.AllowCredentials {
+				w.Header().Set("Access-Control-Allow-Credentials", "true")
+			}
+			if p.MaxAge > 0 {
+				w.Header().Set("Access-Control-Max-Age", maxAge)
+			}
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		w.Header().Set("Access-Control-Allow-Origin", allowOrigin)
+		if p.AllowCredentials {
+			w.Header().Set("Access-Control-Allow-Credentials", "true")
+		}
+		if exposedHeaders != "" {
+			w.Header().Set("Access-Control-Expose-Headers", exposedHeaders)
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// allowOrigin returns the value of Access-Control-Allow-Origin
+// header for the given origin, and whether it is allowed.
+func (p Policy) allowOrigin(origin string) (string, bool) {
+	for _, allowed := range p.AllowedOrigins {
+		if allowed == "*" {
+			return "*", true
+		}
+		if matchOrigin(allowed, origin) {
+			return origin, true
+		}
+	}
+	return "", false
+}
+
+// allowMethod returns whether the given method is allowed.
+func (p Policy) allowMethod(method string) bool {
+	for _, allowed := range p.AllowedMethods {
+		if strings.EqualFold(allowed, method) {
+			return true
+		}
+	}
+	return false
+}
+
+// allowHeaders returns whether all the headers in the given
+// comma separated list are allowed.
+func (p Policy) allowHeaders(headers string) bool {
+	for _, header := range strings.Split(headers, ",") {
+		header = strings.TrimSpace(header)
+		if header == "" {
+			continue
+		}
+
+		allowed := false
+		for _, h := range p.AllowedHeaders {
+			if strings.EqualFold(h, header) {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return false
+		}
+	}
+	return true
+}
+
+// matchOrigin returns whether the origin matches the pattern,
+// where the pattern may contain one wildcard matching any
+// non-empty string.
+func matchOrigin(pattern, origin string) bool {
+	pattern = strings.ToLower(pattern)
+	origin = strings.ToLower(origin)
+
+	prefix, suffix, ok := strings.Cut(pattern, "*")
+	if !ok {
+		return pattern == origin
+	}
+
+	return len(origin) > len(prefix)+len(suffix) &&
+		strings.HasPrefix(origin, prefix) &&
+		strings.HasSuffix(origin, suffix)
+}
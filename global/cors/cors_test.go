@@ -0,0 +1,282 @@
+package cors
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestMatchOrigin(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		origin  string
+		want    bool
+	}{
+		{"exact", "https://tedxuniversitasbrawijaya.com", "https://tedxuniversitasbrawijaya.com", true},
+		{"exact case-insensitive", "https://TEDxUniversitasBrawijaya.com", "https://tedxuniversitasbrawijaya.com", true},
+		{"exact other scheme", "https://tedxuniversitasbrawijaya.com", "http://tedxuniversitasbrawijaya.com", false},
+		{"exact other port", "https://tedxuniversitasbrawijaya.com", "https://tedxuniversitasbrawijaya.com:8443", false},
+		{"exact subdomain", "https://tedxuniversitasbrawijaya.com", "https://www.tedxuniversitasbrawijaya.com", false},
+		{"wildcard subdomain", "https://*.vercel.app", "https://tedxub2023.vercel.app", true},
+		{"wildcard nested subdomain", "https://*.vercel.app", "https://preview.tedxub2023.vercel.app", true},
+		{"wildcard empty", "https://*.vercel.app", "https://.vercel.app", false},
+		{"wildcard other suffix", "https://*.vercel.app", "https://tedxub2023.vercel.app.evil.com", false},
+		{"wildcard other scheme", "https://*.vercel.app", "http://tedxub2023.vercel.app", false},
+		{"wildcard port", "http://localhost:*", "http://localhost:3000", true},
+		{"wildcard port other host", "http://localhost:*", "http://localhost.evil.com", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := matchOrigin(tt.pattern, tt.origin); got != tt.want {
+				t.Errorf("matchOrigin(%q, %q) = %v, want %v", tt.pattern, tt.origin, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPolicyAllowOrigin(t *testing.T) {
+	tests := []struct {
+		name       string
+		allowed    []string
+		origin     string
+		wantOrigin string
+		wantOK     bool
+	}{
+		{"exact", []string{"https://tedxuniversitasbrawijaya.com"}, "https://tedxuniversitasbrawijaya.com", "https://tedxuniversitasbrawijaya.com", true},
+		{"wildcard echoes origin", []string{"https://*.vercel.app"}, "https://tedxub2023.vercel.app", "https://tedxub2023.vercel.app", true},
+		{"any", []string{"*"}, "https://example.com", "*", true},
+		{"second pattern", []string{"https://tedxuniversitasbrawijaya.com", "https://*.vercel.app"}, "https://tedxub2023.vercel.app", "https://tedxub2023.vercel.app", true},
+		{"not allowed", []string{"https://tedxuniversitasbrawijaya.com"}, "https://example.com", "", false},
+		{"none allowed", nil, "https://tedxuniversitasbrawijaya.com", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := Policy{AllowedOrigins: tt.allowed}
+			gotOrigin, gotOK := p.allowOrigin(tt.origin)
+			if gotOrigin != tt.wantOrigin || gotOK != tt.wantOK {
+				t.Errorf("allowOrigin(%q) = %q, %v, want %q, %v", tt.origin, gotOrigin, gotOK, tt.wantOrigin, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestPolicyValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  Policy
+		wantErr error
+	}{
+		{"exact with credentials", Policy{AllowedOrigins: []string{"https://tedxuniversitasbrawijaya.com"}, AllowCredentials: true}, nil},
+		{"wildcard pattern with credentials", Policy{AllowedOrigins: []string{"https://*.vercel.app"}, AllowCredentials: true}, nil},
+		{"any without credentials", Policy{AllowedOrigins: []string{"*"}}, nil},
+		{"any with credentials", Policy{AllowedOrigins: []string{"https://tedxuniversitasbrawijaya.com", "*"}, AllowCredentials: true}, errWildcardCredentials},
+		{"two wildcards", Policy{AllowedOrigins: []string{"https://*.*.vercel.app"}}, errInvalidOrigin},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.policy.Validate(); err != tt.wantErr {
+				t.Errorf("Validate() = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPolicyHandler(t *testing.T) {
+	policy := Policy{
+		AllowedOrigins:   []string{"https://tedxuniversitasbrawijaya.com", "https://*.vercel.app"},
+		AllowedMethods:   []string{http.MethodGet, http.MethodPost},
+		AllowedHeaders:   []string{"Content-Type", "Authorization"},
+		ExposedHeaders:   []string{"X-Request-ID"},
+		AllowCredentials: true,
+		MaxAge:           10 * time.Minute,
+	}
+
+	tests := []struct {
+		name          string
+		method        string
+		header        map[string]string
+		wantStatus    int
+		wantNext      bool
+		wantHeader    map[string]string
+		wantVary      []string
+		wantNoHeaders []string
+	}{
+		{
+			name:       "same origin",
+			method:     http.MethodGet,
+			wantStatus: http.StatusOK,
+			wantNext:   true,
+			wantNoHeaders: []string{
+				"Access-Control-Allow-Origin",
+				"Vary",
+			},
+		},
+		{
+			name:       "simple allowed",
+			method:     http.MethodGet,
+			header:     map[string]string{"Origin": "https://tedxuniversitasbrawijaya.com"},
+			wantStatus: http.StatusOK,
+			wantNext:   true,
+			wantHeader: map[string]string{
+				"Access-Control-Allow-Origin":      "https://tedxuniversitasbrawijaya.com",
+				"Access-Control-Allow-Credentials": "true",
+				"Access-Control-Expose-Headers":    "X-Request-ID",
+			},
+			wantVary: []string{"Origin"},
+		},
+		{
+			name:       "simple allowed wildcard",
+			method:     http.MethodPost,
+			header:     map[string]string{"Origin": "https://tedxub2023.vercel.app"},
+			wantStatus: http.StatusOK,
+			wantNext:   true,
+			wantHeader: map[string]string{
+				"Access-Control-Allow-Origin": "https://tedxub2023.vercel.app",
+			},
+			wantVary: []string{"Origin"},
+		},
+		{
+			name:          "simple not allowed",
+			method:        http.MethodGet,
+			header:        map[string]string{"Origin": "https://example.com"},
+			wantStatus:    http.StatusOK,
+			wantNext:      true,
+			wantVary:      []string{"Origin"},
+			wantNoHeaders: []string{"Access-Control-Allow-Origin", "Access-Control-Allow-Credentials"},
+		},
+		{
+			name:   "preflight allowed",
+			method: http.MethodOptions,
+			header: map[string]string{
+				"Origin":                         "https://tedxub2023.vercel.app",
+				"Access-Control-Request-Method":  http.MethodPost,
+				"Access-Control-Request-Headers": "content-type, authorization",
+			},
+			wantStatus: http.StatusNoContent,
+			wantHeader: map[string]string{
+				"Access-Control-Allow-Origin":      "https://tedxub2023.vercel.app",
+				"Access-Control-Allow-Methods":     "GET, POST",
+				"Access-Control-Allow-Headers":     "Content-Type, Authorization",
+				"Access-Control-Allow-Credentials": "true",
+				"Access-Control-Max-Age":           "600",
+			},
+			wantVary: []string{"Origin", "Access-Control-Request-Method", "Access-Control-Request-Headers"},
+		},
+		{
+			name:   "preflight without headers",
+			method: http.MethodOptions,
+			header: map[string]string{
+				"Origin":                        "https://tedxuniversitasbrawijaya.com",
+				"Access-Control-Request-Method": http.MethodGet,
+			},
+			wantStatus: http.StatusNoContent,
+			wantHeader: map[string]string{
+				"Access-Control-Allow-Origin": "https://tedxuniversitasbrawijaya.com",
+			},
+			wantVary: []string{"Origin", "Access-Control-Request-Method", "Access-Control-Request-Headers"},
+		},
+		{
+			name:   "preflight origin not allowed",
+			method: http.MethodOptions,
+			header: map[string]string{
+				"Origin":                        "https://example.com",
+				"Access-Control-Request-Method": http.MethodGet,
+			},
+			wantStatus:    http.StatusForbidden,
+			wantVary:      []string{"Origin", "Access-Control-Request-Method", "Access-Control-Request-Headers"},
+			wantNoHeaders: []string{"Access-Control-Allow-Origin"},
+		},
+		{
+			name:   "preflight method not allowed",
+			method: http.MethodOptions,
+			header: map[string]string{
+				"Origin":                        "https://tedxuniversitasbrawijaya.com",
+				"Access-Control-Request-Method": http.MethodDelete,
+			},
+			wantStatus:    http.StatusForbidden,
+			wantVary:      []string{"Origin", "Access-Control-Request-Method", "Access-Control-Request-Headers"},
+			wantNoHeaders: []string{"Access-Control-Allow-Origin"},
+		},
+		{
+			name:   "preflight header not allowed",
+			method: http.MethodOptions,
+			header: map[string]string{
+				"Origin":                         "https://tedxuniversitasbrawijaya.com",
+				"Access-Control-Request-Method":  http.MethodPost,
+				"Access-Control-Request-Headers": "Content-Type, X-Custom",
+			},
+			wantStatus:    http.StatusForbidden,
+			wantVary:      []string{"Origin", "Access-Control-Request-Method", "Access-Control-Request-Headers"},
+			wantNoHeaders: []string{"Access-Control-Allow-Origin"},
+		},
+		{
+			name:       "options without request method",
+			method:     http.MethodOptions,
+			header:     map[string]string{"Origin": "https://tedxuniversitasbrawijaya.com"},
+			wantStatus: http.StatusOK,
+			wantNext:   true,
+			wantHeader: map[string]string{
+				"Access-Control-Allow-Origin": "https://tedxuniversitasbrawijaya.com",
+			},
+			wantVary: []string{"Origin"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var called bool
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				called = true
+				w.WriteHeader(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(tt.method, "/api/v1/mainevents", nil)
+			for k, v := range tt.header {
+				req.Header.Set(k, v)
+			}
+			rec := httptest.NewRecorder()
+
+			policy.Handler(next).ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if called != tt.wantNext {
+				t.Errorf("next called = %v, want %v", called, tt.wantNext)
+			}
+			for k, v := range tt.wantHeader {
+				if got := rec.Header().Get(k); got != v {
+					t.Errorf("header %s = %q, want %q", k, got, v)
+				}
+			}
+			for _, k := range tt.wantNoHeaders {
+				if got := rec.Header().Values(k); len(got) > 0 {
+					t.Errorf("header %s = %q, want none", k, got)
+				}
+			}
+			if tt.wantVary != nil {
+				got := rec.Header().Values("Vary")
+				if !equalStrings(got, tt.wantVary) {
+					t.Errorf("Vary = %q, want %q", got, tt.wantVary)
+				}
+			}
+		})
+	}
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
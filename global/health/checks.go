@@ -0,0 +1,146 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// Postgres returns a check pinging the given database pool.
+func Postgres(name string, db *sqlx.DB) Check {
+	return Check{
+		Name: name,
+		Func: func(ctx context.Context) error {
+			return db.PingContext(ctx)
+		},
+	}
+}
+
+// TCP returns a check dialing the given address. It is used
+// for the dependencies that have no cheaper way to be checked,
+// e.g. the SMTP server.
+func TCP(name, addr string) Check {
+	return Check{
+		Name: name,
+		Func: func(ctx context.Context) error {
+			var d net.Dialer
+			conn, err := d.DialContext(ctx, "tcp", addr)
+			if err != nil {
+				return err
+			}
+			return conn.Close()
+		},
+	}
+}
+
+// Templates returns a check parsing every template matched by
+// the given glob patterns, so that a broken template is found
+// before a buyer is waiting for the email.
+func Templates(name string, patterns ...string) Check {
+	return Check{
+		Name: name,
+		Func: func(ctx context.Context) error {
+			for _, pattern := range patterns {
+				paths, err := filepath.Glob(pattern)
+				if err != nil {
+					return err
+				}
+				if len(paths) == 0 {
+					return fmt.Errorf("no template matches %s", pattern)
+				}
+
+				for _, path := range paths {
+					if _, err := template.ParseFiles(path); err != nil {
+						return err
+					}
+				}
+			}
+			return nil
+		},
+	}
+}
+
+// Executable returns a check looking up the given executable,
+// either as a path or in the PATH environment variable.
+func Executable(name, file string) Check {
+	return Check{
+		Name: name,
+		Func: func(ctx context.Context) error {
+			_, err := exec.LookPath(file)
+			return err
+		},
+	}
+}
+
+// WritableDir returns a check creating and removing a
+// temporary file in the given directory.
+func WritableDir(name, dir string) Check {
+	return Check{
+		Name: name,
+		Func: func(ctx context.Context) error {
+			f, err := os.CreateTemp(dir, ".health-*")
+			if err != nil {
+				return err
+			}
+			f.Close()
+			return os.Remove(f.Name())
+		},
+	}
+}
+
+// All returns a check that is up only if all the given checks
+// are up, run one after another.
+func All(name string, checks ...Check) Check {
+	return Check{
+		Name: name,
+		Func: func(ctx context.Context) error {
+			for _, check := range checks {
+				if err := check.Func(ctx); err != nil {
+					return fmt.Errorf("%s: %s", check.Name, err.Error())
+				}
+			}
+			return nil
+		},
+	}
+}
+
+// Cached returns a check that runs the given check at most
+// once per ttl and returns its last result in between, for
+// the dependencies whose check is rate limited, e.g. the
+// Cloudinary Admin API. A check cut off by its context is not
+// cached.
+func Cached(check Check, ttl time.Duration) Check {
+	var (
+		mu        sync.Mutex
+		checkedAt time.Time
+		lastErr   error
+	)
+
+	return Check{
+		Name: check.Name,
+		Func: func(ctx context.Context) error {
+			mu.Lock()
+			defer mu.Unlock()
+
+			if !checkedAt.IsZero() && time.Since(checkedAt) < ttl {
+				return lastErr
+			}
+
+			err := check.Func(ctx)
+			if ctx.Err() != nil {
+				return err
+			}
+
+			checkedAt, lastErr = time.Now(), err
+			return err
+		},
+	}
+}
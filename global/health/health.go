@@ -0,0 +1,149 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/tedxub2023/global/helper"
+)
+
+// Followings are the possible status of a check and of the
+// whole report.
+const (
+	StatusUp   = "up"
+	StatusDown = "down"
+)
+
+// Check is a readiness check of a single dependency. Func
+// must return nil if the dependency is usable.
+type Check struct {
+	Name string
+	Func func(ctx context.Context) error
+}
+
+// Result is the result of a single check.
+type Result struct {
+	Status    string  `json:"status"`
+	LatencyMS float64 `json:"latency_ms"`
+	Error     string  `json:"error,omitempty"`
+}
+
+// Report is the result of all the readiness checks. Status
+// is StatusUp only if every check is up.
+type Report struct {
+	Status string            `json:"status"`
+	Checks map[string]Result `json:"checks"`
+}
+
+// Run runs the given checks concurrently, each one bounded
+// by the given timeout, and returns the report.
+func Run(ctx context.Context, timeout time.Duration, checks []Check) Report {
+	report := Report{
+		Status: StatusUp,
+		Checks: make(map[string]Result, len(checks)),
+	}
+
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+	for _, check := range checks {
+		wg.Add(1)
+		go func(check Check) {
+			defer wg.Done()
+
+			result := run(ctx, timeout, check)
+
+			mu.Lock()
+			defer mu.Unlock()
+			report.Checks[check.Name] = result
+			if result.Status != StatusUp {
+				report.Status = StatusDown
+			}
+		}(check)
+	}
+	wg.Wait()
+
+	return report
+}
+
+// run runs a single check and measures its latency.
+func run(ctx context.Context, timeout time.Duration, check Check) Result {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	errChan := make(chan error, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				errChan <- errors.New("check panicked")
+			}
+		}()
+		errChan <- check.Func(ctx)
+	}()
+
+	var err error
+	select {
+	case err = <-errChan:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	result := Result{
+		Status:    StatusUp,
+		LatencyMS: float64(time.Since(start).Microseconds()) / 1000,
+	}
+	if err != nil {
+		result.Status = StatusDown
+		result.Error = err.Error()
+	}
+
+	return result
+}
+
+// LiveHandler returns the liveness handler. It only reports
+// that the process is able to serve HTTP requests and never
+// checks the dependencies, so that a slow dependency does
+// not get the process restarted.
+func LiveHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeReport(w, Report{Status: StatusUp})
+	})
+}
+
+// ReadyHandler returns the readiness handler. It runs the
+// given checks on every request and responds with 503 if any
+// of them is down.
+func ReadyHandler(timeout time.Duration, checks ...Check) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeReport(w, Run(r.Context(), timeout, checks))
+	})
+}
+
+func writeReport(w http.ResponseWriter, report Report) {
+	statusCode := http.StatusOK
+	if report.Status != StatusUp {
+		statusCode = http.StatusServiceUnavailable
+	}
+
+	status := "Success"
+	if statusCode != http.StatusOK {
+		status = http.StatusText(statusCode)
+	}
+
+	resBody, err := json.Marshal(helper.ResponseEnvelope{
+		Status: status,
+		Data:   report,
+	})
+	if err != nil {
+		helper.WriteErrorResponse(w, http.StatusInternalServerError, []string{err.Error()})
+		return
+	}
+
+	helper.WriteResponse(w, resBody, statusCode, helper.JSONContentTypeDecorator)
+}
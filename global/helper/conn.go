@@ -0,0 +1,36 @@
+package helper
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"time"
+)
+
+// connContextKey is the context key of the connection of a
+// request.
+type connContextKey struct{}
+
+// ErrConnNotFound is returned when the connection of a request
+// is not in its context.
+var ErrConnNotFound = errors.New("connection not found")
+
+// ConnContext stores the connection in the context of its
+// requests. It is meant to be the ConnContext of the
+// http.Server, so that the handlers can call SetWriteDeadline.
+func ConnContext(ctx context.Context, c net.Conn) context.Context {
+	return context.WithValue(ctx, connContextKey{}, c)
+}
+
+// SetWriteDeadline overrides the write deadline set by the
+// server WriteTimeout for the response of the given request,
+// e.g. for a long download. The deadline is reset by the
+// server for the next request on the same connection.
+func SetWriteDeadline(r *http.Request, t time.Time) error {
+	c, ok := r.Context().Value(connContextKey{}).(net.Conn)
+	if !ok {
+		return ErrConnNotFound
+	}
+	return c.SetWriteDeadline(t)
+}
@@ -0,0 +1,83 @@
+package helper
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/tedxub2023/internal/event"
+)
+
+const (
+	// DefaultLogoURI is the logo of the events without one.
+	DefaultLogoURI = "https://arcudskzafkijqukfool.supabase.co/storage/v1/object/public/tedxub2023/logo.png"
+
+	// DefaultWarnaUtama is the primary color of the events
+	// without one, the TED red.
+	DefaultWarnaUtama = "#E62B1E"
+)
+
+// bulan are the month names in Indonesian.
+var bulan = [...]string{
+	"Januari", "Februari", "Maret", "April", "Mei", "Juni",
+	"Juli", "Agustus", "September", "Oktober", "November", "Desember",
+}
+
+// EventTanggal returns the days the given event is held in
+// Indonesian, e.g. "3 Desember 2023", or an empty string if
+// they are not set yet.
+func EventTanggal(ev event.Event) string {
+	days := make([]string, 0, len(ev.Tanggal))
+	for _, t := range ev.Tanggal {
+		days = append(days, fmt.Sprintf("%d %s %d", t.Day(), bulan[t.Month()-1], t.Year()))
+	}
+	return strings.Join(days, ", ")
+}
+
+// EventLogoURI returns the logo of the given event.
+func EventLogoURI(ev event.Event) string {
+	if ev.Branding.LogoURI == "" {
+		return DefaultLogoURI
+	}
+	return ev.Branding.LogoURI
+}
+
+// EventWarnaUtama returns the primary color of the given
+// event.
+func EventWarnaUtama(ev event.Event) string {
+	if ev.Branding.WarnaUtama == "" {
+		return DefaultWarnaUtama
+	}
+	return ev.Branding.WarnaUtama
+}
+
+// TemplateEvent is an event as shown in the ticket and mail
+// templates, e.g. {{.Event.Nama}}.
+type TemplateEvent struct {
+	Nama       string
+	Venue      string
+	Tanggal    string
+	LogoURI    string
+	WarnaUtama string
+}
+
+// NewTemplateEvent returns the TemplateEvent of the given
+// event.
+func NewTemplateEvent(ev event.Event) TemplateEvent {
+	return TemplateEvent{
+		Nama:       ev.Nama,
+		Venue:      ev.Venue,
+		Tanggal:    EventTanggal(ev),
+		LogoURI:    EventLogoURI(ev),
+		WarnaUtama: EventWarnaUtama(ev),
+	}
+}
+
+// TemplatePath returns the path of the template file with the
+// given name under global/template, or of the given default
+// file if the name is empty.
+func TemplatePath(name, defaultName string) string {
+	if name == "" {
+		name = defaultName
+	}
+	return "global/template/" + name
+}
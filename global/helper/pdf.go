@@ -3,10 +3,12 @@ package helper
 import (
 	"fmt"
 	"image"
-	"log"
 	"net/http"
-	"os"
+	"strings"
+	"time"
 
+	"github.com/tedxub2023/global/metrics"
+	"github.com/tedxub2023/internal/event"
 	"github.com/tedxub2023/internal/mainevent"
 	"github.com/unidoc/unipdf/v3/common/license"
 	"github.com/unidoc/unipdf/v3/creator"
@@ -28,198 +30,316 @@ func downloadImage(url string) (image.Image, error) {
 	return img, nil
 }
 
-func PDF(tx mainevent.MainEvent) error {
-	err := license.SetMeteredKey(os.Getenv("UNIDOC_LICENSE_API_KEY"))
+// PDFConfig holds the configuration used to render ticket
+// PDFs.
+type PDFConfig struct {
+	// URLQRCode is the check-in URL format encoded in the
+	// ticket QR code. It receives the order ID and the ticket
+	// number.
+	URLQRCode string
+
+	// WkhtmltopdfPath is the directory of the wkhtmltopdf
+	// binary. Empty means it is looked up in PATH.
+	WkhtmltopdfPath string
+
+	// UnidocLicenseToken is the metered license key of
+	// unipdf.
+	UnidocLicenseToken string
+}
+
+// PDF renders the tickets of the given mainevent of the given
+// event into a single PDF, each ticket showing its holder.
+func PDF(cfg PDFConfig, ev event.Event, tx mainevent.MainEvent) error {
+	defer func(start time.Time) {
+		metrics.PDFRenderDuration.WithLabelValues("unipdf").Observe(time.Since(start).Seconds())
+	}(time.Now())
+
+	err := license.SetMeteredKey(cfg.UnidocLicenseToken)
 	if err != nil {
-		panic(err)
+		return err
 	}
 
 	c := creator.New()
 
 	for _, nomorTicket := range tx.NomorTiket {
-
-		// c.SetPageMargins(30, 50, 100, 70)
-
-		helvetica, _ := model.NewStandard14Font("Helvetica")
-
-		img, err := c.NewImageFromFile("global/storage/ted/background.png")
-		if err != nil {
+		if err := drawTicket(c, cfg, ev, tx, tx.HolderOf(nomorTicket)); err != nil {
 			return err
 		}
+	}
 
-		img.ScaleToWidth(612.0)
+	return c.WriteToFile(PDFPath(tx))
+}
 
-		height := 612.0 * img.Height() / img.Width()
-		c.SetPageSize(creator.PageSize{612, height})
-		c.NewPage()
-		img.SetPos(0, 0)
-		c.Draw(img)
+// PDFPath returns the path of the PDF rendered by PDF for the
+// given mainevent.
+func PDFPath(tx mainevent.MainEvent) string {
+	return fmt.Sprintf("global/storage/ted/%s-%s.pdf", tx.Nama, tx.Type.String())
+}
 
-		// getting wrapper
-		p := c.NewParagraph("Tiket Event")
-		p.SetFont(helvetica)
-		p.SetFontSize(14)
-		p.SetLineHeight(1.5)
-		p.SetMargins(-30, 30, 50, 0)
-		p.SetColor(creator.ColorBlack)
-		c.Draw(p)
+// HolderPDF renders the ticket of the given holder of the
+// mainevent of the given event and returns the path of the PDF.
+func HolderPDF(cfg PDFConfig, ev event.Event, tx mainevent.MainEvent, holder mainevent.Holder) (string, error) {
+	defer func(start time.Time) {
+		metrics.PDFRenderDuration.WithLabelValues("unipdf").Observe(time.Since(start).Seconds())
+	}(time.Now())
 
-		p = c.NewParagraph("Memantik Baskara | TEDxUniversitasBrawijaya")
-		p.SetFont(helvetica)
-		p.SetFontSize(14)
-		p.SetMargins(-30, 30, 0, 0)
-		p.SetLineHeight(1.5)
-		p.SetColor(creator.ColorBlack)
-		c.Draw(p)
+	err := license.SetMeteredKey(cfg.UnidocLicenseToken)
+	if err != nil {
+		return "", err
+	}
 
-		p = c.NewParagraph("3 Desember 2023")
-		p.SetFont(helvetica)
-		p.SetFontSize(14)
-		p.SetMargins(-30, 30, 0, 0)
-		p.SetLineHeight(1.5)
-		p.SetColor(creator.ColorBlack)
-		c.Draw(p)
+	c := creator.New()
 
-		// baskara
-		p = c.NewParagraph(nomorTicket)
-		p.SetFont(helvetica)
-		p.SetFontSize(14)
-		p.SetLineHeight(1.5)
-		p.SetMargins(-30, 30, 20, 0)
-		p.SetColor(creator.ColorBlack)
-		c.Draw(p)
+	if err := drawTicket(c, cfg, ev, tx, holder); err != nil {
+		return "", err
+	}
 
-		p = c.NewParagraph("Detail Audiens")
-		p.SetFont(helvetica)
-		p.SetFontSize(14)
-		p.SetMargins(-30, 30, 0, 0)
-		p.SetLineHeight(1.5)
-		p.SetColor(creator.ColorBlack)
-		c.Draw(p)
+	// the ticket number contains a slash
+	path := fmt.Sprintf("global/storage/ted/%s-%s.pdf", tx.OrderID, strings.ReplaceAll(holder.NomorTiket, "/", "-"))
+	return path, c.WriteToFile(path)
+}
 
-		// biodata
-		name := fmt.Sprintf("Nama: %s", tx.Nama)
-		p = c.NewParagraph(name)
-		p.SetFont(helvetica)
-		p.SetFontSize(14)
-		p.SetLineHeight(1.5)
-		p.SetMargins(-30, 30, 20, 0)
-		p.SetColor(creator.ColorBlack)
-		c.Draw(p)
+// drawTicket draws a page of the ticket of the given holder,
+// with the name, days and branding of the given event.
+func drawTicket(c *creator.Creator, cfg PDFConfig, ev event.Event, tx mainevent.MainEvent, holder mainevent.Holder) error {
+	// c.SetPageMargins(30, 50, 100, 70)
 
-		identityNumber := fmt.Sprintf("Nomor Identitas: %s", tx.NomorIdentitas)
-		p = c.NewParagraph(identityNumber)
-		p.SetFont(helvetica)
-		p.SetFontSize(14)
-		p.SetLineHeight(1.5)
-		p.SetMargins(-30, 30, 0, 0)
-		p.SetColor(creator.ColorBlack)
-		c.Draw(p)
+	helvetica, _ := model.NewStandard14Font("Helvetica")
 
-		email := fmt.Sprintf("Email: %s", tx.Email)
-		p = c.NewParagraph(email)
-		p.SetFont(helvetica)
-		p.SetFontSize(14)
-		p.SetLineHeight(1.5)
-		p.SetMargins(-30, 30, 0, 0)
-		p.SetColor(creator.ColorBlack)
-		c.Draw(p)
+	img, err := c.NewImageFromFile("global/storage/ted/background.png")
+	if err != nil {
+		return err
+	}
 
-		phone := fmt.Sprintf("No Telpon: %s", tx.NomorTelepon)
-		p = c.NewParagraph(phone)
-		p.SetFont(helvetica)
-		p.SetFontSize(14)
-		p.SetLineHeight(1.5)
-		p.SetMargins(-30, 30, 0, 0)
-		p.SetColor(creator.ColorBlack)
-		c.Draw(p)
+	img.ScaleToWidth(612.0)
 
-		ticketType := fmt.Sprintf("Jenis Tiket: %s", "Normal Sale")
-		p = c.NewParagraph(ticketType)
-		p.SetFont(helvetica)
-		p.SetFontSize(14)
-		p.SetLineHeight(1.5)
-		p.SetMargins(-30, 30, 0, 0)
-		p.SetColor(creator.ColorBlack)
-		c.Draw(p)
+	height := 612.0 * img.Height() / img.Width()
+	c.SetPageSize(creator.PageSize{612, height})
+	c.NewPage()
+	img.SetPos(0, 0)
+	c.Draw(img)
 
-		// barcode
-		url := fmt.Sprintf(os.Getenv("URL_QRCODE"), tx.ID, nomorTicket)
-		image, err := downloadImage("https://api.qrserver.com/v1/create-qr-code/?size=150x150&data=" + url)
+	// logo of the event, the background already has the
+	// TEDxUB logo
+	if ev.Branding.LogoURI != "" {
+		logoImage, err := downloadImage(ev.Branding.LogoURI)
 		if err != nil {
 			return err
 		}
-		barcode, err := c.NewImageFromGoImage(image)
+		logo, err := c.NewImageFromGoImage(logoImage)
 		if err != nil {
 			return err
 		}
 
-		barcode.ScaleToWidth(170)
-
-		barcode.SetPos(410, 220)
-		c.Draw(barcode)
+		logo.ScaleToHeight(40)
 
-		// tatacara
-		p = c.NewParagraph("Tata Cara Penukaran Tiket")
-		p.SetFont(helvetica)
-		p.SetMargins(-30, -30, 30, 0)
-		p.SetFontSize(14)
-		p.SetLineHeight(1.5)
-		p.SetColor(creator.ColorBlack)
-		p.SetTextAlignment(creator.TextAlignmentJustify)
-		c.Draw(p)
-
-		p = c.NewParagraph("1. Silahkan kunjungi entrance gate dan tunjukan unique barcode yang telah kamu dapatkanuntuk di-scan oleh panitia yang bertugas;")
-		p.SetFont(helvetica)
-		p.SetFontSize(14)
-		p.SetMargins(-30, -30, 0, 0)
-		p.SetLineHeight(1.5)
-		p.SetColor(creator.ColorBlack)
-		p.SetTextAlignment(creator.TextAlignmentJustify)
-		c.Draw(p)
+		logo.SetPos(612-30-logo.Width(), 30)
+		c.Draw(logo)
+	}
 
-		p = c.NewParagraph("2. Setelah unique barcode terverifikasi, kamu akan mendapatkan wristband dan juga TEDx Kit;")
+	// the name of the event is in its primary color
+	warnaUtama := creator.ColorRGBFromHex(EventWarnaUtama(ev))
+
+	// getting wrapper
+	p := c.NewParagraph("Tiket Event")
+	p.SetFont(helvetica)
+	p.SetFontSize(14)
+	p.SetLineHeight(1.5)
+	p.SetMargins(-30, 30, 50, 0)
+	p.SetColor(creator.ColorBlack)
+	c.Draw(p)
+
+	p = c.NewParagraph(fmt.Sprintf("%s | TEDxUniversitasBrawijaya", ev.Nama))
+	p.SetFont(helvetica)
+	p.SetFontSize(14)
+	p.SetMargins(-30, 30, 0, 0)
+	p.SetLineHeight(1.5)
+	p.SetColor(warnaUtama)
+	c.Draw(p)
+
+	schedule := EventTanggal(ev)
+	if ev.Venue != "" {
+		schedule = fmt.Sprintf("%s | %s", schedule, ev.Venue)
+	}
+	p = c.NewParagraph(schedule)
+	p.SetFont(helvetica)
+	p.SetFontSize(14)
+	p.SetMargins(-30, 30, 0, 0)
+	p.SetLineHeight(1.5)
+	p.SetColor(creator.ColorBlack)
+	c.Draw(p)
+
+	// baskara
+	p = c.NewParagraph(holder.NomorTiket)
+	p.SetFont(helvetica)
+	p.SetFontSize(14)
+	p.SetLineHeight(1.5)
+	p.SetMargins(-30, 30, 20, 0)
+	p.SetColor(creator.ColorBlack)
+	c.Draw(p)
+
+	p = c.NewParagraph("Detail Audiens")
+	p.SetFont(helvetica)
+	p.SetFontSize(14)
+	p.SetMargins(-30, 30, 0, 0)
+	p.SetLineHeight(1.5)
+	p.SetColor(creator.ColorBlack)
+	c.Draw(p)
+
+	// biodata of the holder, the phone number is the buyer's
+	name := fmt.Sprintf("Nama: %s", holder.Nama)
+	p = c.NewParagraph(name)
+	p.SetFont(helvetica)
+	p.SetFontSize(14)
+	p.SetLineHeight(1.5)
+	p.SetMargins(-30, 30, 20, 0)
+	p.SetColor(creator.ColorBlack)
+	c.Draw(p)
+
+	identityNumber := fmt.Sprintf("Nomor Identitas: %s", holder.NomorIdentitas)
+	p = c.NewParagraph(identityNumber)
+	p.SetFont(helvetica)
+	p.SetFontSize(14)
+	p.SetLineHeight(1.5)
+	p.SetMargins(-30, 30, 0, 0)
+	p.SetColor(creator.ColorBlack)
+	c.Draw(p)
+
+	email := fmt.Sprintf("Email: %s", holder.Email)
+	p = c.NewParagraph(email)
+	p.SetFont(helvetica)
+	p.SetFontSize(14)
+	p.SetLineHeight(1.5)
+	p.SetMargins(-30, 30, 0, 0)
+	p.SetColor(creator.ColorBlack)
+	c.Draw(p)
+
+	if strings.EqualFold(holder.Email, tx.Email) {
+		phone := fmt.Sprintf("No Telpon: %s", tx.NomorTelepon)
+		p = c.NewParagraph(phone)
 		p.SetFont(helvetica)
 		p.SetFontSize(14)
 		p.SetLineHeight(1.5)
+		p.SetMargins(-30, 30, 0, 0)
 		p.SetColor(creator.ColorBlack)
-		p.SetMargins(-30, -30, 0, 0)
-		p.SetTextAlignment(creator.TextAlignmentJustify)
 		c.Draw(p)
+	}
 
-		p = c.NewParagraph("3. Panitia yang bertugas akan mengarahkanmu untuk menempati kursi yang telah tersedia;")
+	ticketTypeName := "Normal Sale"
+	if tx.Type == mainevent.TypeComplimentary {
+		ticketTypeName = "Complimentary"
+	}
+	ticketType := fmt.Sprintf("Jenis Tiket: %s", ticketTypeName)
+	p = c.NewParagraph(ticketType)
+	p.SetFont(helvetica)
+	p.SetFontSize(14)
+	p.SetLineHeight(1.5)
+	p.SetMargins(-30, 30, 0, 0)
+	p.SetColor(creator.ColorBlack)
+	c.Draw(p)
+
+	// the seat of the ticket, the staff direct the attendee to
+	// it
+	if seat := tx.SeatOf(holder.NomorTiket); seat.ID != 0 {
+		p = c.NewParagraph(fmt.Sprintf("Kursi: %s", seat.Label()))
 		p.SetFont(helvetica)
 		p.SetFontSize(14)
 		p.SetLineHeight(1.5)
+		p.SetMargins(-30, 30, 0, 0)
 		p.SetColor(creator.ColorBlack)
-		p.SetMargins(-30, -30, 0, 0)
-		p.SetTextAlignment(creator.TextAlignmentJustify)
 		c.Draw(p)
+	}
 
-		p = c.NewParagraph("4. Jika tiket kamu digunakan oleh orang lain, orang tersebut harus menunjukan bukti berupa foto kartu identitas (nama yang tertera pada kartu identitas harus sesuai dengan yang tertera pada e-ticket).")
-		p.SetFont(helvetica)
-		p.SetFontSize(14)
-		p.SetLineHeight(1.5)
-		p.SetColor(creator.ColorBlack)
-		p.SetMargins(-30, -30, 0, 0)
-		p.SetTextAlignment(creator.TextAlignmentJustify)
-		c.Draw(p)
+	// the accommodations of the order, shown to the gate to
+	// guide the attendee to the accessible seats
+	if tx.NeedsAccessibleSeating() {
+		accommodations := make([]string, 0, len(tx.Akomodasi)+1)
+		if tx.Disabilitas != mainevent.DisabilityUnknown && tx.Disabilitas != mainevent.NoneDisability {
+			accommodations = append(accommodations, tx.Disabilitas.String())
+		}
+		for _, a := range tx.Akomodasi {
+			accommodations = append(accommodations, a.Name())
+		}
 
-		p = c.NewParagraph("5. Penukaran tiket hanya dapat dilakukan pada sesi open gate yakni pukul 09.00 - 10.00 WIB. Jika audiens datang setelah sesi tersebut berakhir, maka otomatis tiket yang dimiliki audiens akan hangus dan audiens dilarang untuk memasuki venue acara.")
+		accommodation := fmt.Sprintf("Akomodasi: %s", strings.Join(accommodations, ", "))
+		p = c.NewParagraph(accommodation)
 		p.SetFont(helvetica)
 		p.SetFontSize(14)
 		p.SetLineHeight(1.5)
+		p.SetMargins(-30, 30, 0, 0)
 		p.SetColor(creator.ColorBlack)
-		p.SetMargins(-30, -30, 0, 0)
-		p.SetTextAlignment(creator.TextAlignmentJustify)
 		c.Draw(p)
 	}
 
-	path := fmt.Sprintf("global/storage/ted/%s-%s.pdf", tx.Nama, tx.Type.String())
-	err = c.WriteToFile(path)
+	// barcode
+	url := fmt.Sprintf(cfg.URLQRCode, tx.ID, holder.NomorTiket)
+	image, err := downloadImage("https://api.qrserver.com/v1/create-qr-code/?size=150x150&data=" + url)
+	if err != nil {
+		return err
+	}
+	barcode, err := c.NewImageFromGoImage(image)
 	if err != nil {
-		log.Println("Write file error:", err)
+		return err
 	}
 
+	barcode.ScaleToWidth(170)
+
+	barcode.SetPos(410, 220)
+	c.Draw(barcode)
+
+	// tatacara
+	p = c.NewParagraph("Tata Cara Penukaran Tiket")
+	p.SetFont(helvetica)
+	p.SetMargins(-30, -30, 30, 0)
+	p.SetFontSize(14)
+	p.SetLineHeight(1.5)
+	p.SetColor(creator.ColorBlack)
+	p.SetTextAlignment(creator.TextAlignmentJustify)
+	c.Draw(p)
+
+	p = c.NewParagraph("1. Silahkan kunjungi entrance gate dan tunjukan unique barcode yang telah kamu dapatkanuntuk di-scan oleh panitia yang bertugas;")
+	p.SetFont(helvetica)
+	p.SetFontSize(14)
+	p.SetMargins(-30, -30, 0, 0)
+	p.SetLineHeight(1.5)
+	p.SetColor(creator.ColorBlack)
+	p.SetTextAlignment(creator.TextAlignmentJustify)
+	c.Draw(p)
+
+	p = c.NewParagraph("2. Setelah unique barcode terverifikasi, kamu akan mendapatkan wristband dan juga TEDx Kit;")
+	p.SetFont(helvetica)
+	p.SetFontSize(14)
+	p.SetLineHeight(1.5)
+	p.SetColor(creator.ColorBlack)
+	p.SetMargins(-30, -30, 0, 0)
+	p.SetTextAlignment(creator.TextAlignmentJustify)
+	c.Draw(p)
+
+	p = c.NewParagraph("3. Panitia yang bertugas akan mengarahkanmu untuk menempati kursi yang tertera pada e-ticket;")
+	p.SetFont(helvetica)
+	p.SetFontSize(14)
+	p.SetLineHeight(1.5)
+	p.SetColor(creator.ColorBlack)
+	p.SetMargins(-30, -30, 0, 0)
+	p.SetTextAlignment(creator.TextAlignmentJustify)
+	c.Draw(p)
+
+	p = c.NewParagraph("4. Tiket hanya dapat digunakan oleh pemegang tiket yang tertera pada e-ticket dan wajib menunjukan kartu identitas (nama yang tertera pada kartu identitas harus sesuai dengan yang tertera pada e-ticket). Pembeli dapat memindahkan tiket kepada orang lain sebelum batas waktu pemindahan tiket.")
+	p.SetFont(helvetica)
+	p.SetFontSize(14)
+	p.SetLineHeight(1.5)
+	p.SetColor(creator.ColorBlack)
+	p.SetMargins(-30, -30, 0, 0)
+	p.SetTextAlignment(creator.TextAlignmentJustify)
+	c.Draw(p)
+
+	p = c.NewParagraph("5. Penukaran tiket hanya dapat dilakukan pada sesi open gate yakni pukul 09.00 - 10.00 WIB. Jika audiens datang setelah sesi tersebut berakhir, maka otomatis tiket yang dimiliki audiens akan hangus dan audiens dilarang untuk memasuki venue acara.")
+	p.SetFont(helvetica)
+	p.SetFontSize(14)
+	p.SetLineHeight(1.5)
+	p.SetColor(creator.ColorBlack)
+	p.SetMargins(-30, -30, 0, 0)
+	p.SetTextAlignment(creator.TextAlignmentJustify)
+	c.Draw(p)
 	return nil
 }
@@ -11,6 +11,7 @@ import (
 // response.
 type ResponseEnvelope struct {
 	Data   interface{} `json:"data,omitempty"`
+	Meta   interface{} `json:"meta,omitempty"`
 	Errors []string    `json:"errors,omitempty"`
 	Status string      `json:"status,omitempty"`
 }
@@ -71,8 +72,19 @@ func WriteResponse(w http.ResponseWriter, body []byte, statusCode int, decorator
 //   - statusCode: HTTP status code.
 //   - errs: List of errror messages.
 func WriteErrorResponse(w http.ResponseWriter, statusCode int, errs []string) {
+	WriteErrorResponseWithMeta(w, statusCode, errs, nil)
+}
+
+// WriteErrorResponseWithMeta writes HTTP response based on
+// the given arguments:
+//   - w: Response writer object.
+//   - statusCode: HTTP status code.
+//   - errs: List of errror messages.
+//   - meta: Details of the errors, omitted if nil.
+func WriteErrorResponseWithMeta(w http.ResponseWriter, statusCode int, errs []string, meta interface{}) {
 	// construct response object
 	response := ResponseEnvelope{
+		Meta:   meta,
 		Errors: errs,
 		Status: http.StatusText(statusCode),
 	}
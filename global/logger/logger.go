@@ -0,0 +1,170 @@
+package logger
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Fields is a set of key-value pairs attached to a log
+// entry.
+type Fields map[string]interface{}
+
+// Followings are the known log levels.
+const (
+	LevelInfo  = "info"
+	LevelWarn  = "warn"
+	LevelError = "error"
+)
+
+// modulePrefix is trimmed from the caller function name to
+// keep the log entries short.
+const modulePrefix = "github.com/tedxub2023/"
+
+var (
+	outMu sync.Mutex
+	out   io.Writer = os.Stderr
+)
+
+// SetOutput sets the destination of the log entries.
+func SetOutput(w io.Writer) {
+	outMu.Lock()
+	defer outMu.Unlock()
+	out = w
+}
+
+// ctxKey is the context key type of this package.
+type ctxKey struct{}
+
+// ctxFields holds the fields attached to a context. It is
+// shared by all the contexts derived from the context it was
+// attached to, so that fields added deep in a service are
+// visible in the request log written by the middleware.
+type ctxFields struct {
+	mu     sync.RWMutex
+	fields Fields
+}
+
+// NewContext returns a copy of the given context carrying
+// the given fields in addition to the fields of the parent
+// context.
+func NewContext(ctx context.Context, fields Fields) context.Context {
+	merged := Fields{}
+	for k, v := range FromContext(ctx) {
+		merged[k] = v
+	}
+	for k, v := range fields {
+		merged[k] = v
+	}
+
+	return context.WithValue(ctx, ctxKey{}, &ctxFields{fields: merged})
+}
+
+// FromContext returns a copy of the fields carried by the
+// given context.
+func FromContext(ctx context.Context) Fields {
+	cf, ok := ctx.Value(ctxKey{}).(*ctxFields)
+	if !ok {
+		return nil
+	}
+
+	cf.mu.RLock()
+	defer cf.mu.RUnlock()
+
+	fields := make(Fields, len(cf.fields))
+	for k, v := range cf.fields {
+		fields[k] = v
+	}
+	return fields
+}
+
+// AddFields adds the given fields to the context in place.
+// It is a no-op if the context was not created by
+// NewContext.
+func AddFields(ctx context.Context, fields Fields) {
+	cf, ok := ctx.Value(ctxKey{}).(*ctxFields)
+	if !ok {
+		return
+	}
+
+	cf.mu.Lock()
+	defer cf.mu.Unlock()
+
+	for k, v := range fields {
+		cf.fields[k] = v
+	}
+}
+
+// Detach returns a new background context carrying the
+// fields of the given context. It is meant for asynchronous
+// jobs which must outlive the request that started them,
+// such as sending emails, while keeping the request ID.
+func Detach(ctx context.Context) context.Context {
+	return NewContext(context.Background(), FromContext(ctx))
+}
+
+// RequestID returns the request ID carried by the given
+// context, if any.
+func RequestID(ctx context.Context) string {
+	id, _ := FromContext(ctx)[FieldRequestID].(string)
+	return id
+}
+
+// Info writes an info log entry.
+func Info(ctx context.Context, msg string, fields ...Fields) {
+	write(ctx, LevelInfo, msg, nil, fields, true)
+}
+
+// Warn writes a warning log entry.
+func Warn(ctx context.Context, msg string, fields ...Fields) {
+	write(ctx, LevelWarn, msg, nil, fields, true)
+}
+
+// Error writes an error log entry with the given error.
+func Error(ctx context.Context, msg string, err error, fields ...Fields) {
+	write(ctx, LevelError, msg, err, fields, true)
+}
+
+// write writes a log entry as a single JSON line. If
+// withCaller is true, the function that called the exported
+// logging function is added to the entry.
+func write(ctx context.Context, level, msg string, err error, fields []Fields, withCaller bool) {
+	entry := FromContext(ctx)
+	if entry == nil {
+		entry = Fields{}
+	}
+	for _, f := range fields {
+		for k, v := range f {
+			entry[k] = v
+		}
+	}
+
+	entry["time"] = time.Now().Format(time.RFC3339Nano)
+	entry["level"] = level
+	entry["msg"] = msg
+	if err != nil {
+		entry["error"] = err.Error()
+	}
+	if withCaller {
+		if pc, _, _, ok := runtime.Caller(2); ok {
+			if fn := runtime.FuncForPC(pc); fn != nil {
+				entry["caller"] = strings.TrimPrefix(fn.Name(), modulePrefix)
+			}
+		}
+	}
+
+	line, errMarshal := json.Marshal(entry)
+	if errMarshal != nil {
+		line = []byte(fmt.Sprintf(`{"level":"error","msg":"failed to marshal log entry","error":%q}`, errMarshal.Error()))
+	}
+
+	outMu.Lock()
+	defer outMu.Unlock()
+	out.Write(append(line, '\n'))
+}
@@ -0,0 +1,113 @@
+package logger
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// Followings are the well-known field names.
+const (
+	FieldRequestID = "request_id"
+)
+
+// HeaderRequestID is the HTTP header carrying the request ID.
+const HeaderRequestID = "X-Request-ID"
+
+// maxRequestIDLength is the maximum length of a request ID
+// accepted from the client.
+const maxRequestIDLength = 64
+
+// Middleware assigns a request ID to every request and
+// writes one log entry per request with the handler, status
+// code and latency, together with the fields added to the
+// request context by the handlers and services.
+//
+// The request ID is taken from the X-Request-ID request
+// header if present, so that a request can be traced across
+// services, and is echoed in the response header.
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+
+		requestID := r.Header.Get(HeaderRequestID)
+		if !validRequestID(requestID) {
+			requestID = newRequestID()
+		}
+		w.Header().Set(HeaderRequestID, requestID)
+
+		handler := "unmatched"
+		if route := mux.CurrentRoute(r); route != nil {
+			if tpl, err := route.GetPathTemplate(); err == nil {
+				handler = tpl
+			}
+		}
+
+		ctx := NewContext(r.Context(), Fields{
+			FieldRequestID: requestID,
+			"handler":      handler,
+		})
+
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rec, r.WithContext(ctx))
+
+		fields := Fields{
+			"method":      r.Method,
+			"path":        r.URL.Path,
+			"status":      rec.status,
+			"latency_ms":  float64(time.Since(start).Microseconds()) / 1000,
+			"remote_addr": r.RemoteAddr,
+			"user_agent":  r.UserAgent(),
+		}
+
+		switch {
+		case rec.status >= http.StatusInternalServerError:
+			write(ctx, LevelError, "http request", nil, []Fields{fields}, false)
+		case rec.status >= http.StatusBadRequest:
+			write(ctx, LevelWarn, "http request", nil, []Fields{fields}, false)
+		default:
+			write(ctx, LevelInfo, "http request", nil, []Fields{fields}, false)
+		}
+	})
+}
+
+// statusRecorder records the status code written to the
+// wrapped response writer.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+// WriteHeader records the status code and writes it to the
+// wrapped response writer.
+func (r *statusRecorder) WriteHeader(statusCode int) {
+	r.status = statusCode
+	r.ResponseWriter.WriteHeader(statusCode)
+}
+
+// newRequestID returns a new random request ID.
+func newRequestID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return time.Now().Format("20060102150405.000000000")
+	}
+	return hex.EncodeToString(b)
+}
+
+// validRequestID returns whether the request ID sent by the
+// client can be used as is.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+
+	for _, c := range id {
+		if !(c >= 'a' && c <= 'z') && !(c >= 'A' && c <= 'Z') && !(c >= '0' && c <= '9') && c != '-' && c != '_' {
+			return false
+		}
+	}
+	return true
+}
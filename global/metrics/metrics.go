@@ -0,0 +1,196 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// namespace is the prefix of all metric names.
+const namespace = "tedxub"
+
+// Followings are the known event labels.
+const (
+	EventMainEvent   = "mainevent"
+	EventTransaction = "transaction"
+)
+
+// Followings are the HTTP metrics.
+var (
+	// HTTPRequestDuration observes the latency of HTTP
+	// requests per handler.
+	HTTPRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "Latency of HTTP requests per handler.",
+		Buckets:   []float64{.01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10},
+	}, []string{"handler", "method", "code"})
+
+	// HTTPRequestErrors counts HTTP requests responded with
+	// a 4xx or 5xx status code per handler.
+	HTTPRequestErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "request_errors_total",
+		Help:      "HTTP requests responded with a 4xx or 5xx status code per handler.",
+	}, []string{"handler", "method", "code"})
+
+	// HTTPRateLimited counts HTTP requests rejected by the
+	// rate limiter per handler and rule.
+	HTTPRateLimited = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "rate_limited_total",
+		Help:      "HTTP requests rejected by the rate limiter per handler and rule.",
+	}, []string{"handler", "rule"})
+)
+
+// Followings are the business metrics.
+var (
+	// OrdersCreated counts created orders per event and
+	// ticket type.
+	OrdersCreated = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "orders_created_total",
+		Help:      "Created orders per event and ticket type.",
+	}, []string{"event", "type"})
+
+	// TicketsOrdered counts ordered tickets per event and
+	// ticket type.
+	TicketsOrdered = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "tickets_ordered_total",
+		Help:      "Ordered tickets per event and ticket type.",
+	}, []string{"event", "type"})
+
+	// OrderSettlements counts orders whose payment has been
+	// settled per event and ticket type.
+	OrderSettlements = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "order_settlements_total",
+		Help:      "Settled orders per event and ticket type.",
+	}, []string{"event", "type"})
+
+	// OrderExpirations counts unpaid orders deleted after
+	// their payment deadline per event and ticket type.
+	OrderExpirations = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "order_expirations_total",
+		Help:      "Expired unpaid orders per event and ticket type.",
+	}, []string{"event", "type"})
+
+	// TicketsRefunded counts tickets of approved refunds per
+	// event and ticket type.
+	TicketsRefunded = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "tickets_refunded_total",
+		Help:      "Refunded tickets per event and ticket type.",
+	}, []string{"event", "type"})
+
+	// WaitlistEntries counts waitlist entries per event and
+	// status they move into: waiting, offered, claimed or
+	// expired.
+	WaitlistEntries = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "waitlist_entries_total",
+		Help:      "Waitlist entries per event and status they move into.",
+	}, []string{"event", "status"})
+
+	// CheckIns counts checked in tickets per event and gate.
+	CheckIns = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "checkins_total",
+		Help:      "Checked in tickets per event and gate.",
+	}, []string{"event", "gate"})
+
+	// EmailSendFailures counts emails that failed to be sent
+	// per subject.
+	EmailSendFailures = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "email_send_failures_total",
+		Help:      "Emails that failed to be sent per subject.",
+	}, []string{"subject"})
+
+	// PDFRenderDuration observes the duration to render a
+	// ticket PDF per renderer.
+	PDFRenderDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "pdf_render_duration_seconds",
+		Help:      "Duration to render a ticket PDF per renderer.",
+		Buckets:   []float64{.1, .25, .5, 1, 2.5, 5, 10, 30},
+	}, []string{"renderer"})
+)
+
+// Handler returns the HTTP handler exposing the metrics in
+// the Prometheus text format.
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
+
+// Middleware observes the latency and the error count of
+// HTTP requests. Requests are labeled by the URL template of
+// the matched route, which is the URL of the respective
+// HandlerIdentity, so that path parameters do not blow up
+// the cardinality.
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+
+		next.ServeHTTP(rec, r)
+
+		handler := "unmatched"
+		if route := mux.CurrentRoute(r); route != nil {
+			if tpl, err := route.GetPathTemplate(); err == nil {
+				handler = tpl
+			}
+		}
+		code := strconv.Itoa(rec.status)
+
+		HTTPRequestDuration.WithLabelValues(handler, r.Method, code).Observe(time.Since(start).Seconds())
+		if rec.status >= http.StatusBadRequest {
+			HTTPRequestErrors.WithLabelValues(handler, r.Method, code).Inc()
+		}
+	})
+}
+
+// statusRecorder records the status code written to the
+// wrapped response writer.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+// WriteHeader records the status code and writes it to the
+// wrapped response writer.
+func (r *statusRecorder) WriteHeader(statusCode int) {
+	r.status = statusCode
+	r.ResponseWriter.WriteHeader(statusCode)
+}
+
+// maxGateLength is the maximum length of a gate label value.
+const maxGateLength = 32
+
+// GateLabel returns the given gate name if it is a valid
+// label value, or "unknown" otherwise. Gate names come from
+// the check-in request, so they are restricted to short
+// lowercase alphanumeric values to bound the cardinality.
+func GateLabel(gate string) string {
+	if gate == "" || len(gate) > maxGateLength {
+		return "unknown"
+	}
+
+	for _, c := range gate {
+		if !(c >= 'a' && c <= 'z') && !(c >= '0' && c <= '9') && c != '-' && c != '_' {
+			return "unknown"
+		}
+	}
+
+	return gate
+}
@@ -0,0 +1,35 @@
+package payment
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/midtrans/midtrans-go/coreapi"
+)
+
+// midtransRefunder implements Refunder with the Midtrans Core
+// API, using the global server key and environment.
+type midtransRefunder struct{}
+
+// NewMidtrans creates a new Midtrans Refunder.
+func NewMidtrans() Refunder {
+	return midtransRefunder{}
+}
+
+// Refund implements Refunder.
+func (midtransRefunder) Refund(ctx context.Context, req RefundRequest) error {
+	res, err := coreapi.RefundTransaction(req.OrderID, &coreapi.RefundReq{
+		RefundKey: req.RefundKey,
+		Amount:    req.Amount,
+		Reason:    req.Reason,
+	})
+	if err != nil {
+		return err
+	}
+
+	if res.StatusCode != "200" {
+		return fmt.Errorf("%w: %s %s", ErrRefundFailed, res.StatusCode, res.StatusMessage)
+	}
+
+	return nil
+}
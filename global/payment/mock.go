@@ -0,0 +1,26 @@
+package payment
+
+import (
+	"context"
+
+	"github.com/tedxub2023/global/logger"
+)
+
+// mockRefunder implements Refunder without calling the
+// payment gateway, for local development.
+type mockRefunder struct{}
+
+// NewMock creates a new Refunder that only logs the refunds.
+func NewMock() Refunder {
+	return mockRefunder{}
+}
+
+// Refund implements Refunder.
+func (mockRefunder) Refund(ctx context.Context, req RefundRequest) error {
+	logger.Info(ctx, "mock payment refund", logger.Fields{
+		"order_id":   req.OrderID,
+		"refund_key": req.RefundKey,
+		"amount":     req.Amount,
+	})
+	return nil
+}
@@ -0,0 +1,30 @@
+// Package payment refunds the payments made through the
+// payment gateway.
+package payment
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrRefundFailed is returned when the payment gateway does
+// not accept a refund.
+var ErrRefundFailed = errors.New("refund failed")
+
+// RefundRequest is a refund of some amount of a paid order.
+type RefundRequest struct {
+	OrderID string
+
+	// RefundKey identifies the refund, so that a retried
+	// refund is not applied twice by the gateway.
+	RefundKey string
+
+	Amount int64
+	Reason string
+}
+
+// Refunder refunds payments. Implementations must be safe for
+// concurrent use.
+type Refunder interface {
+	Refund(ctx context.Context, req RefundRequest) error
+}
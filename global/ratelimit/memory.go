@@ -0,0 +1,66 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// sweepInterval is the interval between removals of the
+// expired counters of the in-memory store.
+const sweepInterval = time.Minute
+
+// memoryStore implements Store in memory. The counters are
+// not shared between processes and are lost on restart.
+type memoryStore struct {
+	mu        sync.Mutex
+	counters  map[string]*counter
+	lastSweep time.Time
+	timeNow   func() time.Time
+}
+
+type counter struct {
+	count int
+	reset time.Time
+}
+
+// NewMemoryStore creates a new in-memory Store.
+func NewMemoryStore() Store {
+	return &memoryStore{
+		counters: make(map[string]*counter),
+		timeNow:  time.Now,
+	}
+}
+
+// Incr implements Store.
+func (s *memoryStore) Incr(ctx context.Context, key string, window time.Duration) (int, time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.timeNow()
+	s.sweep(now)
+
+	c, ok := s.counters[key]
+	if !ok || !now.Before(c.reset) {
+		c = &counter{reset: now.Add(window)}
+		s.counters[key] = c
+	}
+	c.count++
+
+	return c.count, c.reset, nil
+}
+
+// sweep removes the expired counters so that the store does
+// not grow with every client ever seen.
+func (s *memoryStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < sweepInterval {
+		return
+	}
+	s.lastSweep = now
+
+	for key, c := range s.counters {
+		if !now.Before(c.reset) {
+			delete(s.counters, key)
+		}
+	}
+}
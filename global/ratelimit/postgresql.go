@@ -0,0 +1,83 @@
+package ratelimit
+
+import (
+	"context"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// queryIncr increments the counter of a key, starting a new
+// window if the previous one has ended.
+const queryIncr = `
+	INSERT INTO rate_limit (
+		key,
+		count,
+		reset_time
+	) VALUES (
+		$1,
+		1,
+		$2
+	)
+	ON CONFLICT (key) DO UPDATE SET
+		count = CASE
+			WHEN rate_limit.reset_time <= $3 THEN 1
+			ELSE rate_limit.count + 1
+		END,
+		reset_time = CASE
+			WHEN rate_limit.reset_time <= $3 THEN $2
+			ELSE rate_limit.reset_time
+		END
+	RETURNING
+		count,
+		reset_time
+`
+
+// queryDeleteExpired deletes the counters whose window has
+// ended.
+const queryDeleteExpired = `
+	DELETE FROM
+		rate_limit
+	WHERE
+		reset_time <= $1
+`
+
+// pgStore implements Store in PostgreSQL, so that the
+// counters are shared between processes and survive
+// restarts.
+type pgStore struct {
+	db      *sqlx.DB
+	timeNow func() time.Time
+}
+
+// NewPostgresStore creates a new PostgreSQL Store. It needs
+// the rate_limit table, see migrations directory.
+func NewPostgresStore(db *sqlx.DB) Store {
+	return &pgStore{
+		db:      db,
+		timeNow: time.Now,
+	}
+}
+
+// Incr implements Store.
+func (s *pgStore) Incr(ctx context.Context, key string, window time.Duration) (int, time.Time, error) {
+	now := s.timeNow()
+
+	var (
+		count int
+		reset time.Time
+	)
+	err := s.db.QueryRowxContext(ctx, queryIncr, key, now.Add(window), now).Scan(&count, &reset)
+	if err != nil {
+		return 0, time.Time{}, err
+	}
+
+	return count, reset, nil
+}
+
+// DeleteExpired deletes the counters whose window has ended.
+// It is meant to be run periodically.
+func DeleteExpired(ctx context.Context, db *sqlx.DB) error {
+	_, err := db.ExecContext(ctx, queryDeleteExpired, time.Now())
+	return err
+}
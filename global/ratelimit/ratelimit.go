@@ -0,0 +1,290 @@
+package ratelimit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/tedxub2023/global/helper"
+	"github.com/tedxub2023/global/logger"
+	"github.com/tedxub2023/global/metrics"
+)
+
+// Followings are the known rule keys.
+const (
+	// KeyIP limits the requests per client IP address.
+	KeyIP = "ip"
+
+	// KeyEmail limits the requests per "email" field of
+	// the JSON request body.
+	KeyEmail = "email"
+
+	// KeyIdentity limits the requests per "nomor_identitas"
+	// field of the JSON request body.
+	KeyIdentity = "identity"
+)
+
+// maxBodySize is the maximum size of the request body read
+// to extract the email and identity number.
+const maxBodySize = 1 << 20
+
+var (
+	// ErrTooManyRequests is written in the response when a
+	// request is rejected.
+	ErrTooManyRequests = errors.New("TOO_MANY_REQUESTS")
+
+	errInvalidRule = errors.New("invalid rate limit rule")
+)
+
+// Limit is the maximum number of requests allowed within a
+// window.
+type Limit struct {
+	Requests int
+	Window   time.Duration
+}
+
+// Rule limits the requests sharing the same value of Key.
+type Rule struct {
+	Key   string
+	Limit Limit
+}
+
+// Rules is a list of rules applied together to a handler. A
+// request is rejected if any of the rules is exceeded.
+type Rules []Rule
+
+// ParseRules parses rules written as comma separated
+// key=requests/window pairs, e.g. "ip=20/1m,email=5/1h".
+// The value "off" returns no rules.
+func ParseRules(s string) (Rules, error) {
+	s = strings.TrimSpace(s)
+	if s == "" || s == "off" {
+		return nil, nil
+	}
+
+	var rules Rules
+	for _, part := range strings.Split(s, ",") {
+		key, limit, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			return nil, fmt.Errorf("%w %q: missing key", errInvalidRule, part)
+		}
+
+		switch key {
+		case KeyIP, KeyEmail, KeyIdentity:
+		default:
+			return nil, fmt.Errorf("%w %q: unknown key %s", errInvalidRule, part, key)
+		}
+
+		requests, window, ok := strings.Cut(limit, "/")
+		if !ok {
+			return nil, fmt.Errorf("%w %q: missing window", errInvalidRule, part)
+		}
+
+		n, err := strconv.Atoi(requests)
+		if err != nil || n <= 0 {
+			return nil, fmt.Errorf("%w %q: requests must be a positive integer", errInvalidRule, part)
+		}
+
+		d, err := time.ParseDuration(window)
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("%w %q: window must be a positive duration", errInvalidRule, part)
+		}
+
+		rules = append(rules, Rule{
+			Key:   key,
+			Limit: Limit{Requests: n, Window: d},
+		})
+	}
+
+	return rules, nil
+}
+
+// Store counts the requests per key. Implementations must be
+// safe for concurrent use.
+type Store interface {
+	// Incr increments the counter of the given key within
+	// the current window, starting a new window if there is
+	// none, and returns the new count and the time the
+	// window ends.
+	Incr(ctx context.Context, key string, window time.Duration) (int, time.Time, error)
+}
+
+// Policy is the rate limit policy of a handler.
+type Policy struct {
+	// Methods are the HTTP methods the rules apply to. All
+	// methods are limited if it is empty.
+	Methods []string
+	Rules   Rules
+}
+
+// Limiter limits the requests per handler using the
+// policies registered with Handle.
+type Limiter struct {
+	store      Store
+	trustProxy bool
+	policies   map[string]Policy
+}
+
+// New creates a new Limiter. If trustProxy is true, the
+// client IP address is taken from the X-Real-IP or
+// X-Forwarded-For header set by the reverse proxy.
+func New(store Store, trustProxy bool) *Limiter {
+	return &Limiter{
+		store:      store,
+		trustProxy: trustProxy,
+		policies:   make(map[string]Policy),
+	}
+}
+
+// Handle registers the policy of the handler served at the
+// given route path template, e.g. "/api/v1/mainevents".
+func (l *Limiter) Handle(pathTemplate string, policy Policy) {
+	l.policies[pathTemplate] = policy
+}
+
+// Middleware rejects the requests exceeding the policy of
+// the matched route with 429 Too Many Requests.
+//
+// The requests are let through if the store fails, so that
+// a broken store does not stop the ticket sales.
+func (l *Limiter) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		route := mux.CurrentRoute(r)
+		if route == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		tpl, err := route.GetPathTemplate()
+		if err != nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		policy, ok := l.policies[tpl]
+		if !ok || !policy.applies(r.Method) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		values := l.values(r, policy.Rules)
+		for _, rule := range policy.Rules {
+			value := values[rule.Key]
+			if value == "" {
+				continue
+			}
+
+			key := strings.Join([]string{tpl, rule.Key, value}, ":")
+			count, reset, err := l.store.Incr(r.Context(), key, rule.Limit.Window)
+			if err != nil {
+				logger.Error(r.Context(), "failed to increment rate limit counter", err, logger.Fields{"rule": rule.Key})
+				continue
+			}
+
+			if count > rule.Limit.Requests {
+				metrics.HTTPRateLimited.WithLabelValues(tpl, rule.Key).Inc()
+				logger.Warn(r.Context(), "request is rate limited", logger.Fields{"rule": rule.Key})
+
+				retryAfter := int(math.Ceil(time.Until(reset).Seconds()))
+				if retryAfter < 1 {
+					retryAfter = 1
+				}
+				w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+				helper.WriteErrorResponse(w, http.StatusTooManyRequests, []string{ErrTooManyRequests.Error()})
+				return
+			}
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// applies returns whether the policy applies to the given
+// method.
+func (p Policy) applies(method string) bool {
+	if len(p.Methods) == 0 {
+		return true
+	}
+	for _, m := range p.Methods {
+		if m == method {
+			return true
+		}
+	}
+	return false
+}
+
+// values returns the values of the keys used by the given
+// rules. The request body is restored after being read.
+func (l *Limiter) values(r *http.Request, rules Rules) map[string]string {
+	values := make(map[string]string)
+
+	var needBody bool
+	for _, rule := range rules {
+		switch rule.Key {
+		case KeyIP:
+			values[KeyIP] = l.clientIP(r)
+		case KeyEmail, KeyIdentity:
+			needBody = true
+		}
+	}
+
+	// the handlers decode the body as JSON regardless of the
+	// Content-Type, so does the limiter
+	if !needBody || r.Body == nil || strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/") {
+		return values
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxBodySize))
+	r.Body.Close()
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return values
+	}
+
+	var fields struct {
+		Email          string `json:"email"`
+		NomorIdentitas string `json:"nomor_identitas"`
+	}
+	if err := json.Unmarshal(body, &fields); err != nil {
+		return values
+	}
+
+	values[KeyEmail] = strings.ToLower(strings.TrimSpace(fields.Email))
+	values[KeyIdentity] = strings.TrimSpace(fields.NomorIdentitas)
+
+	return values
+}
+
+// clientIP returns the IP address of the client.
+func (l *Limiter) clientIP(r *http.Request) string {
+	if l.trustProxy {
+		if ip := strings.TrimSpace(r.Header.Get("X-Real-IP")); ip != "" {
+			return ip
+		}
+
+		// the last address is the one added by our proxy,
+		// the others are set by the client
+		if xff := r.Header.Get("X-Forwarded-For"); xff != "" {
+			parts := strings.Split(xff, ",")
+			if ip := strings.TrimSpace(parts[len(parts)-1]); ip != "" {
+				return ip
+			}
+		}
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
@@ -0,0 +1,111 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/robfig/cron/v3"
+	"github.com/tedxub2023/global/logger"
+)
+
+// Group runs background jobs, such as sending emails and
+// rendering PDFs, and keeps track of them so that the server
+// can wait for them to finish before exiting.
+type Group struct {
+	mu         sync.Mutex
+	wg         sync.WaitGroup
+	closed     bool
+	schedulers []*cron.Cron
+}
+
+// New returns a new Group.
+func New() *Group {
+	return &Group{}
+}
+
+// Go runs the given job in a new goroutine. The returned
+// error, if any, is logged together with the job name.
+//
+// The job receives a context detached from the cancellation
+// of the given context, since it usually outlives the request
+// that started it, but carrying its log fields so that the
+// job logs can be traced back to the request.
+//
+// Once Shutdown has been called, the job is run synchronously
+// in the caller goroutine instead, so that it is not lost.
+func (g *Group) Go(ctx context.Context, name string, job func(ctx context.Context) error) {
+	ctx = logger.NewContext(logger.Detach(ctx), logger.Fields{"job": name})
+
+	g.mu.Lock()
+	if g.closed {
+		g.mu.Unlock()
+		run(ctx, job)
+		return
+	}
+	g.wg.Add(1)
+	g.mu.Unlock()
+
+	go func() {
+		defer g.wg.Done()
+		run(ctx, job)
+	}()
+}
+
+// AddScheduler starts the given cron scheduler and registers
+// it to be stopped on Shutdown. Shutdown waits for the
+// running cron jobs to finish.
+func (g *Group) AddScheduler(c *cron.Cron) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.closed {
+		return
+	}
+
+	g.schedulers = append(g.schedulers, c)
+	c.Start()
+}
+
+// Shutdown stops all registered schedulers and waits until
+// every running job has finished, or until the given context
+// is done, in which case the context error is returned.
+func (g *Group) Shutdown(ctx context.Context) error {
+	g.mu.Lock()
+	g.closed = true
+	schedulers := g.schedulers
+	g.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		for _, c := range schedulers {
+			<-c.Stop().Done()
+		}
+		g.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// run runs the given job and logs the returned error or
+// recovered panic.
+func run(ctx context.Context, job func(ctx context.Context) error) {
+	defer func() {
+		if r := recover(); r != nil {
+			logger.Error(ctx, "background job panicked", fmt.Errorf("%v", r))
+		}
+	}()
+
+	if err := job(ctx); err != nil {
+		logger.Error(ctx, "background job failed", err)
+		return
+	}
+
+	logger.Info(ctx, "background job finished")
+}
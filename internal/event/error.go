@@ -0,0 +1,44 @@
+package event
+
+import "errors"
+
+// Followings are the known errors returned from event.
+var (
+	// ErrDataNotFound is returned when the wanted data is
+	// not found.
+	ErrDataNotFound = errors.New("data not found")
+
+	// ErrInvalidEventID is returned when the given event id
+	// is invalid.
+	ErrInvalidEventID = errors.New("invalid event id")
+
+	// ErrInvalidEventSlug is returned when the given slug is
+	// empty or contains other than lowercase letters, digits
+	// and dashes.
+	ErrInvalidEventSlug = errors.New("invalid event slug")
+
+	// ErrInvalidEventNama is returned when the given name is
+	// empty.
+	ErrInvalidEventNama = errors.New("invalid event nama")
+
+	// ErrInvalidEventTanggal is returned when the given event
+	// has no days or the same day twice.
+	ErrInvalidEventTanggal = errors.New("invalid event tanggal")
+
+	// ErrInvalidTicketType is returned when the given ticket
+	// types have an empty or repeated code, or a negative
+	// price or quota.
+	ErrInvalidTicketType = errors.New("invalid ticket type")
+
+	// ErrInvalidBranding is returned when the given primary
+	// color is not a hex color code.
+	ErrInvalidBranding = errors.New("invalid branding")
+
+	// ErrInvalidTemplate is returned when the given template
+	// names are not plain file names.
+	ErrInvalidTemplate = errors.New("invalid template")
+
+	// ErrEventSlugExists is returned when the given slug is
+	// already used by another event.
+	ErrEventSlugExists = errors.New("event slug exists")
+)
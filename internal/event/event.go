@@ -0,0 +1,100 @@
+package event
+
+import (
+	"context"
+	"time"
+)
+
+type Service interface {
+	// CreateEvent creates a new event and returns the created
+	// event ID.
+	CreateEvent(ctx context.Context, event Event) (int64, error)
+
+	// GetAllEvents returns all events, the latest first.
+	GetAllEvents(ctx context.Context) ([]Event, error)
+
+	// GetEventByID returns an event with the given event ID.
+	GetEventByID(ctx context.Context, eventID int64) (Event, error)
+
+	// GetEventBySlug returns an event with the given slug.
+	GetEventBySlug(ctx context.Context, slug string) (Event, error)
+
+	// UpdateEvent updates an event and replaces its ticket
+	// types, its slug can not be changed.
+	UpdateEvent(ctx context.Context, event Event) error
+}
+
+// Event is an event of a TEDxUB edition, the orders of each
+// event reference it by ID.
+type Event struct {
+	ID int64
+
+	// Slug identifies the event in the configuration and the
+	// URLs of the frontend, e.g. "memantik-baskara".
+	Slug  string
+	Nama  string
+	Venue string
+
+	// Tanggal are the days the event is held, in order.
+	Tanggal []time.Time
+
+	// TicketTypes are the tickets sold for the event.
+	TicketTypes []TicketType
+
+	Branding  Branding
+	Templates Templates
+
+	CreateTime time.Time
+	UpdateTime time.Time
+}
+
+// TicketType returns the ticket type of the event with the
+// given code, and whether there is one.
+func (e Event) TicketType(kode string) (TicketType, bool) {
+	for _, tt := range e.TicketTypes {
+		if tt.Kode == kode {
+			return tt, true
+		}
+	}
+	return TicketType{}, false
+}
+
+// TicketType is a ticket sold for an event.
+type TicketType struct {
+	// Kode identifies the ticket type within its event, the
+	// mainevent sale phases use their name, e.g.
+	// "normal-sale".
+	Kode string
+	Nama string
+
+	// Harga is the price of a ticket before any discount.
+	Harga int64
+
+	// Kuota is the number of tickets on sale.
+	Kuota int
+}
+
+// Branding is the look of an event on the frontend and the
+// tickets.
+type Branding struct {
+	LogoURI string
+
+	// WarnaUtama is the primary color, as a hex color code
+	// e.g. "#E62B1E".
+	WarnaUtama string
+}
+
+// Templates are the names of the template files of an event
+// under global/template. Empty names use the default
+// templates.
+type Templates struct {
+	// Tiket is the template of the tickets rendered from HTML,
+	// the mainevent tickets are drawn and only use the
+	// branding.
+	Tiket string
+
+	// Email is the template of the email carrying the
+	// tickets, or the registration email of the tickets
+	// package.
+	Email string
+}
@@ -0,0 +1,82 @@
+package http
+
+import (
+	"errors"
+
+	"github.com/tedxub2023/internal/event"
+)
+
+// Followings are the known errors from Event HTTP handlers.
+var (
+	// errBadRequest is returned when the given request is
+	// bad/invalid.
+	errBadRequest = errors.New("BAD_REQUEST")
+
+	// errInternalServer is returned when there is an
+	// unexpected error encountered when processing a request.
+	errInternalServer = errors.New("INTERNAL_SERVER_ERROR")
+
+	// errDataNotFound is returned when the desired data is
+	// not found.
+	errDataNotFound = errors.New("DATA_NOT_FOUND")
+
+	// errMethodNotAllowed is returned when accessing not
+	// allowed HTTP method.
+	errMethodNotAllowed = errors.New("METHOD_NOT_ALLOWED")
+
+	// errRequestTimeout is returned when processing time
+	// has reached the timeout limit.
+	errRequestTimeout = errors.New("REQUEST_TIMEOUT")
+
+	// errInvalidEventID is returned when the given event id
+	// is invalid.
+	errInvalidEventID = errors.New("INVALID_EVENT_ID")
+
+	// errInvalidEventSlug is returned when the given slug is
+	// invalid.
+	errInvalidEventSlug = errors.New("INVALID_EVENT_SLUG")
+
+	// errInvalidEventNama is returned when the given name is
+	// invalid.
+	errInvalidEventNama = errors.New("INVALID_EVENT_NAMA")
+
+	// errInvalidEventTanggal is returned when the given days
+	// are invalid.
+	errInvalidEventTanggal = errors.New("INVALID_EVENT_TANGGAL")
+
+	// errInvalidTicketType is returned when the given ticket
+	// types are invalid.
+	errInvalidTicketType = errors.New("INVALID_TICKET_TYPE")
+
+	// errInvalidBranding is returned when the given branding
+	// is invalid.
+	errInvalidBranding = errors.New("INVALID_BRANDING")
+
+	// errInvalidTemplate is returned when the given template
+	// names are invalid.
+	errInvalidTemplate = errors.New("INVALID_TEMPLATE")
+
+	// errEventSlugExists is returned when the given slug is
+	// taken.
+	errEventSlugExists = errors.New("EVENT_SLUG_EXISTS")
+)
+
+var (
+	// mapHTTPError maps service error into HTTP error that
+	// categorize as bad request error.
+	//
+	// Internal server error-related should not be mapped
+	// here, and the handler should just return `errInternal`
+	// as the error instead
+	mapHTTPError = map[error]error{
+		event.ErrDataNotFound:        errDataNotFound,
+		event.ErrInvalidEventID:      errInvalidEventID,
+		event.ErrInvalidEventSlug:    errInvalidEventSlug,
+		event.ErrInvalidEventNama:    errInvalidEventNama,
+		event.ErrInvalidEventTanggal: errInvalidEventTanggal,
+		event.ErrInvalidTicketType:   errInvalidTicketType,
+		event.ErrInvalidBranding:     errInvalidBranding,
+		event.ErrInvalidTemplate:     errInvalidTemplate,
+		event.ErrEventSlugExists:     errEventSlugExists,
+	}
+)
@@ -0,0 +1,192 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/tedxub2023/global/helper"
+	"github.com/tedxub2023/global/logger"
+	"github.com/tedxub2023/internal/event"
+)
+
+type eventHandler struct {
+	event event.Service
+}
+
+func (h *eventHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	eventID, err := strconv.ParseInt(vars["id"], 10, 64)
+	if err != nil {
+		logger.Error(r.Context(), "failed to parse event ID", err, logger.Fields{"id": vars["id"]})
+		helper.WriteErrorResponse(w, http.StatusBadRequest, []string{errInvalidEventID.Error()})
+		return
+	}
+	logger.AddFields(r.Context(), logger.Fields{"event_id": eventID})
+
+	switch r.Method {
+	case http.MethodGet:
+		h.handleGetEventByID(w, r, eventID)
+	case http.MethodPut:
+		h.handleUpdateEvent(w, r, eventID)
+	default:
+		helper.WriteErrorResponse(w, http.StatusMethodNotAllowed, []string{errMethodNotAllowed.Error()})
+	}
+}
+
+func (h *eventHandler) handleGetEventByID(w http.ResponseWriter, r *http.Request, eventID int64) {
+	// add timeout to context
+	ctx, cancel := context.WithTimeout(r.Context(), 3000*time.Millisecond)
+	defer cancel()
+
+	var (
+		err        error           // stores error in this handler
+		resBody    []byte          // stores response body to write
+		statusCode = http.StatusOK // stores response status code
+	)
+
+	// write response
+	defer func() {
+		// error
+		if err != nil {
+			logger.Error(ctx, "failed to get event", err)
+			helper.WriteErrorResponse(w, statusCode, []string{err.Error()})
+			return
+		}
+		// success
+		helper.WriteResponse(w, resBody, statusCode, helper.JSONContentTypeDecorator)
+	}()
+
+	// prepare channels for main go routine
+	resChan := make(chan event.Event, 1)
+	errChan := make(chan error, 1)
+
+	go func() {
+		res, err := h.event.GetEventByID(ctx, eventID)
+		if err != nil {
+			// determine error and status code, by default its internal error
+			parsedErr := errInternalServer
+			statusCode = http.StatusInternalServerError
+			if v, ok := mapHTTPError[err]; ok {
+				parsedErr = v
+				statusCode = http.StatusBadRequest
+			}
+
+			// log the actual error if its internal error
+			if statusCode == http.StatusInternalServerError {
+				logger.Error(ctx, "internal error from GetEventByID", err)
+			}
+
+			errChan <- parsedErr
+			return
+		}
+
+		resChan <- res
+	}()
+
+	// wait and handle main go routine
+	select {
+	case <-ctx.Done():
+		statusCode = http.StatusGatewayTimeout
+		err = errRequestTimeout
+	case err = <-errChan:
+	case res := <-resChan:
+		resBody, err = json.Marshal(helper.ResponseEnvelope{
+			Data: formatEvent(res),
+		})
+	}
+}
+
+func (h *eventHandler) handleUpdateEvent(w http.ResponseWriter, r *http.Request, eventID int64) {
+	// add timeout to context
+	ctx, cancel := context.WithTimeout(r.Context(), 3000*time.Millisecond)
+	defer cancel()
+
+	var (
+		err        error           // stores error in this handler
+		resBody    []byte          // stores response body to write
+		statusCode = http.StatusOK // stores response status code
+	)
+
+	// write response
+	defer func() {
+		if err != nil {
+			logger.Error(ctx, "failed to update event", err)
+			helper.WriteErrorResponse(w, statusCode, []string{err.Error()})
+			return
+		}
+		// success
+		helper.WriteResponse(w, resBody, statusCode, helper.JSONContentTypeDecorator)
+	}()
+
+	// prepare channels for main go routine
+	resChan := make(chan int64, 1)
+	errChan := make(chan error, 1)
+
+	go func() {
+		// read body
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			statusCode = http.StatusBadRequest
+			errChan <- errBadRequest
+			return
+		}
+
+		// unmarshall body
+		request := eventHTTP{}
+		err = json.Unmarshal(body, &request)
+		if err != nil {
+			statusCode = http.StatusBadRequest
+			errChan <- errBadRequest
+			return
+		}
+
+		// format HTTP request into service object, the whole
+		// event is replaced
+		reqEvent, err := parseEventFromRequest(request)
+		if err != nil {
+			statusCode = http.StatusBadRequest
+			errChan <- err
+			return
+		}
+		reqEvent.ID = eventID
+
+		err = h.event.UpdateEvent(ctx, reqEvent)
+		if err != nil {
+			// determine error and status code, by default its internal error
+			parsedErr := errInternalServer
+			statusCode = http.StatusInternalServerError
+			if v, ok := mapHTTPError[err]; ok {
+				parsedErr = v
+				statusCode = http.StatusBadRequest
+			}
+
+			// log the actual error if its internal error
+			if statusCode == http.StatusInternalServerError {
+				logger.Error(ctx, "internal error from UpdateEvent", err)
+			}
+
+			errChan <- parsedErr
+			return
+		}
+
+		resChan <- eventID
+	}()
+
+	// wait and handle main go routine
+	select {
+	case <-ctx.Done():
+		statusCode = http.StatusGatewayTimeout
+		err = errRequestTimeout
+	case err = <-errChan:
+	case eventID := <-resChan:
+		resBody, err = json.Marshal(helper.ResponseEnvelope{
+			Status: "Success",
+			Data:   eventID,
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package http
+
+import (
+	"time"
+
+	"github.com/tedxub2023/internal/event"
+)
+
+// tanggalLayout is the layout of the event days.
+const tanggalLayout = "2006-01-02"
+
+// formatEvent formats the given event into the respective
+// HTTP-format object.
+func formatEvent(e event.Event) eventHTTP {
+	tanggal := make([]string, 0, len(e.Tanggal))
+	for _, t := range e.Tanggal {
+		tanggal = append(tanggal, t.Format(tanggalLayout))
+	}
+
+	ticketTypes := make([]ticketTypeHTTP, 0, len(e.TicketTypes))
+	for _, tt := range e.TicketTypes {
+		ticketTypes = append(ticketTypes, ticketTypeHTTP{
+			Kode:  tt.Kode,
+			Nama:  tt.Nama,
+			Harga: tt.Harga,
+			Kuota: tt.Kuota,
+		})
+	}
+
+	result := eventHTTP{
+		ID:         &e.ID,
+		Slug:       &e.Slug,
+		Nama:       &e.Nama,
+		Venue:      &e.Venue,
+		Tanggal:    &tanggal,
+		JenisTiket: &ticketTypes,
+		Branding: &brandingHTTP{
+			LogoURI:    e.Branding.LogoURI,
+			WarnaUtama: e.Branding.WarnaUtama,
+		},
+		Template: &templateHTTP{
+			Tiket: e.Templates.Tiket,
+			Email: e.Templates.Email,
+		},
+		CreateTime: &e.CreateTime,
+	}
+
+	if !e.UpdateTime.IsZero() {
+		result.UpdateTime = &e.UpdateTime
+	}
+
+	return result
+}
+
+// parseEventFromRequest returns event from the given HTTP
+// request object.
+func parseEventFromRequest(eh eventHTTP) (event.Event, error) {
+	result := event.Event{}
+
+	if eh.Slug != nil {
+		result.Slug = *eh.Slug
+	}
+
+	if eh.Nama != nil {
+		result.Nama = *eh.Nama
+	}
+
+	if eh.Venue != nil {
+		result.Venue = *eh.Venue
+	}
+
+	if eh.Tanggal != nil {
+		for _, s := range *eh.Tanggal {
+			t, err := time.Parse(tanggalLayout, s)
+			if err != nil {
+				return event.Event{}, errInvalidEventTanggal
+			}
+			result.Tanggal = append(result.Tanggal, t)
+		}
+	}
+
+	if eh.JenisTiket != nil {
+		for _, tt := range *eh.JenisTiket {
+			result.TicketTypes = append(result.TicketTypes, event.TicketType{
+				Kode:  tt.Kode,
+				Nama:  tt.Nama,
+				Harga: tt.Harga,
+				Kuota: tt.Kuota,
+			})
+		}
+	}
+
+	if eh.Branding != nil {
+		result.Branding = event.Branding{
+			LogoURI:    eh.Branding.LogoURI,
+			WarnaUtama: eh.Branding.WarnaUtama,
+		}
+	}
+
+	if eh.Template != nil {
+		result.Templates = event.Templates{
+			Tiket: eh.Template.Tiket,
+			Email: eh.Template.Email,
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,134 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/tedxub2023/internal/event"
+)
+
+var (
+	errUnknownConfig = errors.New("unknown config name")
+)
+
+// Handler contains event HTTP-handlers.
+type Handler struct {
+	handlers map[string]*handler
+	event    event.Service
+}
+
+// handler is the HTTP handler wrapper.
+type handler struct {
+	h        http.Handler
+	identity HandlerIdentity
+}
+
+// HandlerIdentity denotes the identity of an HTTP hanlder.
+type HandlerIdentity struct {
+	Name string
+	URL  string
+}
+
+// Followings are the known HTTP handler identities
+var (
+	// HandlerEvents denotes HTTP handler to list the events
+	// and for the committee to create one
+	HandlerEvents = HandlerIdentity{
+		Name: "events",
+		URL:  "/events",
+	}
+
+	// HandlerEvent denotes HTTP handler to get an event and
+	// for the committee to update it
+	HandlerEvent = HandlerIdentity{
+		Name: "event",
+		URL:  "/events/{id:[0-9]+}",
+	}
+)
+
+// New creates a new Handler.
+func New(event event.Service, identities []HandlerIdentity) (*Handler, error) {
+	h := &Handler{
+		handlers: make(map[string]*handler),
+		event:    event,
+	}
+
+	// apply options
+	for _, identity := range identities {
+		if h.handlers == nil {
+			h.handlers = map[string]*handler{}
+		}
+
+		h.handlers[identity.Name] = &handler{
+			identity: identity,
+		}
+
+		handler, err := h.createHTTPHandler(identity.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		h.handlers[identity.Name].h = handler
+	}
+
+	return h, nil
+}
+
+// createHTTPHandler creates a new HTTP handler that
+// implements http.Handler.
+func (h *Handler) createHTTPHandler(configName string) (http.Handler, error) {
+	var httpHandler http.Handler
+	switch configName {
+	case HandlerEvents.Name:
+		httpHandler = &eventsHandler{
+			event: h.event,
+		}
+	case HandlerEvent.Name:
+		httpHandler = &eventHandler{
+			event: h.event,
+		}
+	default:
+		return httpHandler, errUnknownConfig
+	}
+	return httpHandler, nil
+}
+
+// Start starts all HTTP handlers.
+func (h *Handler) Start(multiplexer *mux.Router) error {
+	for _, handler := range h.handlers {
+		multiplexer.Handle(handler.identity.URL, handler.h)
+	}
+	return nil
+}
+
+type eventHTTP struct {
+	ID         *int64            `json:"id"`
+	Slug       *string           `json:"slug"`
+	Nama       *string           `json:"nama"`
+	Venue      *string           `json:"venue"`
+	Tanggal    *[]string         `json:"tanggal"`
+	JenisTiket *[]ticketTypeHTTP `json:"jenis_tiket"`
+	Branding   *brandingHTTP     `json:"branding"`
+	Template   *templateHTTP     `json:"template"`
+	CreateTime *time.Time        `json:"create_time,omitempty"`
+	UpdateTime *time.Time        `json:"update_time,omitempty"`
+}
+
+type ticketTypeHTTP struct {
+	Kode  string `json:"kode"`
+	Nama  string `json:"nama"`
+	Harga int64  `json:"harga"`
+	Kuota int    `json:"kuota"`
+}
+
+type brandingHTTP struct {
+	LogoURI    string `json:"logo_uri"`
+	WarnaUtama string `json:"warna_utama"`
+}
+
+type templateHTTP struct {
+	Tiket string `json:"tiket"`
+	Email string `json:"email"`
+}
@@ -0,0 +1,266 @@
+package service
+
+import (
+	"context"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/tedxub2023/global/logger"
+	"github.com/tedxub2023/internal/event"
+)
+
+var (
+	// slugPattern is the pattern of an event slug.
+	slugPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+	// colorPattern is the pattern of a hex color code.
+	colorPattern = regexp.MustCompile(`^#[0-9A-Fa-f]{6}$`)
+
+	// templatePattern is the pattern of a template file name.
+	templatePattern = regexp.MustCompile(`^[A-Za-z0-9_-]+\.html$`)
+)
+
+func (s *service) CreateEvent(ctx context.Context, reqEvent event.Event) (_ int64, err error) {
+	reqEvent = normalizeEvent(reqEvent)
+
+	// validate field
+	if !slugPattern.MatchString(reqEvent.Slug) {
+		return 0, event.ErrInvalidEventSlug
+	}
+	err = validateEvent(reqEvent)
+	if err != nil {
+		return 0, err
+	}
+
+	pgStoreClient, err := s.pgStore.NewClient(true)
+	if err != nil {
+		return 0, err
+	}
+
+	// rollback just before return if error
+	defer func() {
+		if err != nil {
+			errTx := pgStoreClient.Rollback()
+			if errTx != nil {
+				// return err from rollback
+				err = errTx
+			}
+		}
+	}()
+
+	reqEvent.CreateTime = s.timeNow()
+
+	eventID, err := pgStoreClient.CreateEvent(ctx, reqEvent)
+	if err != nil {
+		return 0, err
+	}
+
+	err = pgStoreClient.ReplaceTicketTypes(ctx, eventID, reqEvent.TicketTypes)
+	if err != nil {
+		return 0, err
+	}
+
+	// commit changes
+	err = pgStoreClient.Commit()
+	if err != nil {
+		return 0, err
+	}
+	logger.Info(ctx, "event created", logger.Fields{"event_id": eventID, "slug": reqEvent.Slug})
+
+	return eventID, nil
+}
+
+func (s *service) GetAllEvents(ctx context.Context) ([]event.Event, error) {
+	// get pg store client without using transaction
+	pgStoreClient, err := s.pgStore.NewClient(false)
+	if err != nil {
+		return nil, err
+	}
+
+	events, err := pgStoreClient.GetAllEvents(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	eventIDs := make([]int64, 0, len(events))
+	for _, e := range events {
+		eventIDs = append(eventIDs, e.ID)
+	}
+
+	ticketTypes, err := pgStoreClient.GetTicketTypes(ctx, eventIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range events {
+		events[i].TicketTypes = ticketTypes[events[i].ID]
+	}
+
+	return events, nil
+}
+
+func (s *service) GetEventByID(ctx context.Context, eventID int64) (event.Event, error) {
+	// validate id
+	if eventID <= 0 {
+		return event.Event{}, event.ErrInvalidEventID
+	}
+
+	// get pg store client without using transaction
+	pgStoreClient, err := s.pgStore.NewClient(false)
+	if err != nil {
+		return event.Event{}, err
+	}
+
+	result, err := pgStoreClient.GetEventByID(ctx, eventID)
+	if err != nil {
+		return event.Event{}, err
+	}
+
+	return withTicketTypes(ctx, pgStoreClient, result)
+}
+
+func (s *service) GetEventBySlug(ctx context.Context, slug string) (event.Event, error) {
+	// get pg store client without using transaction
+	pgStoreClient, err := s.pgStore.NewClient(false)
+	if err != nil {
+		return event.Event{}, err
+	}
+
+	result, err := pgStoreClient.GetEventBySlug(ctx, strings.TrimSpace(slug))
+	if err != nil {
+		return event.Event{}, err
+	}
+
+	return withTicketTypes(ctx, pgStoreClient, result)
+}
+
+func (s *service) UpdateEvent(ctx context.Context, reqEvent event.Event) (err error) {
+	// validate id
+	if reqEvent.ID <= 0 {
+		return event.ErrInvalidEventID
+	}
+
+	reqEvent = normalizeEvent(reqEvent)
+	err = validateEvent(reqEvent)
+	if err != nil {
+		return err
+	}
+
+	pgStoreClient, err := s.pgStore.NewClient(true)
+	if err != nil {
+		return err
+	}
+
+	// rollback just before return if error
+	defer func() {
+		if err != nil {
+			errTx := pgStoreClient.Rollback()
+			if errTx != nil {
+				// return err from rollback
+				err = errTx
+			}
+		}
+	}()
+
+	current, err := pgStoreClient.GetEventByID(ctx, reqEvent.ID)
+	if err != nil {
+		return err
+	}
+
+	// the slug is referenced by the configuration and the
+	// frontend, it is never changed
+	reqEvent.Slug = current.Slug
+	reqEvent.UpdateTime = s.timeNow()
+
+	err = pgStoreClient.UpdateEvent(ctx, reqEvent)
+	if err != nil {
+		return err
+	}
+
+	err = pgStoreClient.ReplaceTicketTypes(ctx, reqEvent.ID, reqEvent.TicketTypes)
+	if err != nil {
+		return err
+	}
+
+	// commit changes
+	return pgStoreClient.Commit()
+}
+
+// withTicketTypes returns the given event with its ticket
+// types.
+func withTicketTypes(ctx context.Context, pgStoreClient PGStoreClient, e event.Event) (event.Event, error) {
+	ticketTypes, err := pgStoreClient.GetTicketTypes(ctx, []int64{e.ID})
+	if err != nil {
+		return event.Event{}, err
+	}
+	e.TicketTypes = ticketTypes[e.ID]
+
+	return e, nil
+}
+
+// normalizeEvent trims the fields of the given event and
+// sorts its days.
+func normalizeEvent(e event.Event) event.Event {
+	e.Slug = strings.ToLower(strings.TrimSpace(e.Slug))
+	e.Nama = strings.TrimSpace(e.Nama)
+	e.Venue = strings.TrimSpace(e.Venue)
+	e.Branding.LogoURI = strings.TrimSpace(e.Branding.LogoURI)
+	e.Branding.WarnaUtama = strings.TrimSpace(e.Branding.WarnaUtama)
+	e.Templates.Tiket = strings.TrimSpace(e.Templates.Tiket)
+	e.Templates.Email = strings.TrimSpace(e.Templates.Email)
+
+	tanggal := make([]time.Time, 0, len(e.Tanggal))
+	for _, t := range e.Tanggal {
+		tanggal = append(tanggal, time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC))
+	}
+	sort.Slice(tanggal, func(i, j int) bool {
+		return tanggal[i].Before(tanggal[j])
+	})
+	e.Tanggal = tanggal
+
+	for i := range e.TicketTypes {
+		e.TicketTypes[i].Kode = strings.TrimSpace(e.TicketTypes[i].Kode)
+		e.TicketTypes[i].Nama = strings.TrimSpace(e.TicketTypes[i].Nama)
+	}
+
+	return e
+}
+
+// validateEvent validates fields of the given event whether
+// its comply the predetermined rules.
+func validateEvent(e event.Event) error {
+	if e.Nama == "" {
+		return event.ErrInvalidEventNama
+	}
+
+	if len(e.Tanggal) == 0 {
+		return event.ErrInvalidEventTanggal
+	}
+	for i := 1; i < len(e.Tanggal); i++ {
+		if e.Tanggal[i].Equal(e.Tanggal[i-1]) {
+			return event.ErrInvalidEventTanggal
+		}
+	}
+
+	kode := make(map[string]struct{}, len(e.TicketTypes))
+	for _, tt := range e.TicketTypes {
+		if _, ok := kode[tt.Kode]; ok || tt.Kode == "" || tt.Nama == "" || tt.Harga < 0 || tt.Kuota < 0 {
+			return event.ErrInvalidTicketType
+		}
+		kode[tt.Kode] = struct{}{}
+	}
+
+	if e.Branding.WarnaUtama != "" && !colorPattern.MatchString(e.Branding.WarnaUtama) {
+		return event.ErrInvalidBranding
+	}
+
+	for _, name := range []string{e.Templates.Tiket, e.Templates.Email} {
+		if name != "" && !templatePattern.MatchString(name) {
+			return event.ErrInvalidTemplate
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,48 @@
+package service
+
+import (
+	"context"
+
+	"github.com/tedxub2023/internal/event"
+)
+
+// PGStore is the PostgreSQL store for event service.
+type PGStore interface {
+	NewClient(useTx bool) (PGStoreClient, error)
+}
+
+type PGStoreClient interface {
+	// Commit commits the transaction.
+	Commit() error
+
+	// Rollback aborts the transaction.
+	Rollback() error
+
+	// CreateEvent creates a new event without its ticket
+	// types and returns the created event ID. It returns
+	// event.ErrEventSlugExists if the slug is taken.
+	CreateEvent(ctx context.Context, event event.Event) (int64, error)
+
+	// GetAllEvents returns all events without their ticket
+	// types, the latest first.
+	GetAllEvents(ctx context.Context) ([]event.Event, error)
+
+	// GetEventByID returns an event with the given event ID
+	// without its ticket types.
+	GetEventByID(ctx context.Context, eventID int64) (event.Event, error)
+
+	// GetEventBySlug returns an event with the given slug
+	// without its ticket types.
+	GetEventBySlug(ctx context.Context, slug string) (event.Event, error)
+
+	// UpdateEvent updates an event without its ticket types.
+	UpdateEvent(ctx context.Context, event event.Event) error
+
+	// GetTicketTypes returns the ticket types of the events
+	// with the given IDs by event ID, in their order.
+	GetTicketTypes(ctx context.Context, eventIDs []int64) (map[int64][]event.TicketType, error)
+
+	// ReplaceTicketTypes replaces the ticket types of the
+	// event with the given ID.
+	ReplaceTicketTypes(ctx context.Context, eventID int64, ticketTypes []event.TicketType) error
+}
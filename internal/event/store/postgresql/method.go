@@ -0,0 +1,235 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/tedxub2023/internal/event"
+)
+
+func (sc *storeClient) CreateEvent(ctx context.Context, reqEvent event.Event) (int64, error) {
+	// construct arguments filled with fields for the query
+	argsKV := map[string]interface{}{
+		"slug":           reqEvent.Slug,
+		"nama":           reqEvent.Nama,
+		"venue":          nullString(reqEvent.Venue),
+		"tanggal":        tanggalArray(reqEvent.Tanggal),
+		"logo_uri":       nullString(reqEvent.Branding.LogoURI),
+		"warna_utama":    nullString(reqEvent.Branding.WarnaUtama),
+		"template_tiket": nullString(reqEvent.Templates.Tiket),
+		"template_email": nullString(reqEvent.Templates.Email),
+		"create_time":    reqEvent.CreateTime,
+	}
+
+	// prepare query
+	query, args, err := sqlx.Named(queryCreateEvent, argsKV)
+	if err != nil {
+		return 0, err
+	}
+	query = sc.q.Rebind(query)
+
+	// execute query
+	var eventID int64
+	err = sc.q.QueryRowxContext(ctx, query, args...).Scan(&eventID)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return 0, event.ErrEventSlugExists
+		}
+		return 0, err
+	}
+
+	return eventID, nil
+}
+
+func (sc *storeClient) GetAllEvents(ctx context.Context) ([]event.Event, error) {
+	query := fmt.Sprintf(queryGetEvent, "ORDER BY e.tanggal[1] DESC NULLS LAST, e.id DESC")
+
+	// query to database
+	rows, err := sc.q.QueryxContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	// read events
+	result := make([]event.Event, 0)
+	for rows.Next() {
+		var row eventDB
+		err = rows.StructScan(&row)
+		if err != nil {
+			return nil, err
+		}
+
+		e, err := row.format()
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, e)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (sc *storeClient) GetEventByID(ctx context.Context, eventID int64) (event.Event, error) {
+	query := fmt.Sprintf(queryGetEvent, "WHERE e.id = $1")
+
+	// query single row
+	var edb eventDB
+	err := sc.q.QueryRowxContext(ctx, query, eventID).StructScan(&edb)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return event.Event{}, event.ErrDataNotFound
+		}
+		return event.Event{}, err
+	}
+
+	return edb.format()
+}
+
+func (sc *storeClient) GetEventBySlug(ctx context.Context, slug string) (event.Event, error) {
+	query := fmt.Sprintf(queryGetEvent, "WHERE e.slug = $1")
+
+	// query single row
+	var edb eventDB
+	err := sc.q.QueryRowxContext(ctx, query, slug).StructScan(&edb)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return event.Event{}, event.ErrDataNotFound
+		}
+		return event.Event{}, err
+	}
+
+	return edb.format()
+}
+
+func (sc *storeClient) UpdateEvent(ctx context.Context, reqEvent event.Event) error {
+	// construct arguments filled with fields for the query
+	argsKV := map[string]interface{}{
+		"nama":           reqEvent.Nama,
+		"venue":          nullString(reqEvent.Venue),
+		"tanggal":        tanggalArray(reqEvent.Tanggal),
+		"logo_uri":       nullString(reqEvent.Branding.LogoURI),
+		"warna_utama":    nullString(reqEvent.Branding.WarnaUtama),
+		"template_tiket": nullString(reqEvent.Templates.Tiket),
+		"template_email": nullString(reqEvent.Templates.Email),
+		"update_time":    reqEvent.UpdateTime,
+		"id":             reqEvent.ID,
+	}
+
+	// prepare query
+	query, args, err := sqlx.Named(queryUpdateEvent, argsKV)
+	if err != nil {
+		return err
+	}
+	query = sc.q.Rebind(query)
+
+	// execute query
+	res, err := sc.q.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return event.ErrDataNotFound
+	}
+
+	return nil
+}
+
+func (sc *storeClient) GetTicketTypes(ctx context.Context, eventIDs []int64) (map[int64][]event.TicketType, error) {
+	result := make(map[int64][]event.TicketType)
+	if len(eventIDs) == 0 {
+		return result, nil
+	}
+
+	// prepare query
+	query, args, err := sqlx.Named(queryGetTicketTypes, map[string]interface{}{
+		"event_ids": eventIDs,
+	})
+	if err != nil {
+		return nil, err
+	}
+	query, args, err = sqlx.In(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	query = sc.q.Rebind(query)
+
+	// query to database
+	rows, err := sc.q.QueryxContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	// read ticket types
+	for rows.Next() {
+		var row ticketTypeDB
+		err = rows.StructScan(&row)
+		if err != nil {
+			return nil, err
+		}
+
+		result[row.EventID] = append(result[row.EventID], row.format())
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (sc *storeClient) ReplaceTicketTypes(ctx context.Context, eventID int64, ticketTypes []event.TicketType) error {
+	// prepare query
+	query, args, err := sqlx.Named(queryDeleteTicketTypes, map[string]interface{}{
+		"event_id": eventID,
+	})
+	if err != nil {
+		return err
+	}
+	query = sc.q.Rebind(query)
+
+	// execute query
+	_, err = sc.q.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	for i, tt := range ticketTypes {
+		// construct arguments filled with fields for the query
+		argsKV := map[string]interface{}{
+			"event_id": eventID,
+			"urutan":   i,
+			"kode":     tt.Kode,
+			"nama":     tt.Nama,
+			"harga":    tt.Harga,
+			"kuota":    tt.Kuota,
+		}
+
+		// prepare query
+		query, args, err := sqlx.Named(queryCreateTicketType, argsKV)
+		if err != nil {
+			return err
+		}
+		query = sc.q.Rebind(query)
+
+		// execute query
+		_, err = sc.q.ExecContext(ctx, query, args...)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,171 @@
+package postgresql
+
+import (
+	"errors"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/tedxub2023/internal/event"
+	"github.com/tedxub2023/internal/event/service"
+)
+
+// tanggalLayout is the layout of the event days as stored.
+const tanggalLayout = "2006-01-02"
+
+var (
+	errInvalidCommit   = errors.New("cannot do commit on non-transactional querier")
+	errInvalidRollback = errors.New("cannot do rollback on non-transactional querier")
+)
+
+// store implements event/service.PGStore
+type store struct {
+	db *sqlx.DB
+}
+
+// storeClient implements event/service.PGStoreClient
+type storeClient struct {
+	q sqlx.ExtContext
+}
+
+// New creates a new store.
+func New(db *sqlx.DB) (*store, error) {
+	s := &store{
+		db: db,
+	}
+
+	return s, nil
+}
+
+func (s *store) NewClient(useTx bool) (service.PGStoreClient, error) {
+	var q sqlx.ExtContext
+
+	// determine what object should be use as querier
+	q = s.db
+	if useTx {
+		var err error
+		q, err = s.db.Beginx()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &storeClient{
+		q: q,
+	}, nil
+}
+
+func (sc *storeClient) Commit() error {
+	if tx, ok := sc.q.(*sqlx.Tx); ok {
+		return tx.Commit()
+	}
+	return errInvalidCommit
+}
+
+func (sc *storeClient) Rollback() error {
+	if tx, ok := sc.q.(*sqlx.Tx); ok {
+		return tx.Rollback()
+	}
+	return errInvalidRollback
+}
+
+type eventDB struct {
+	ID            int64          `db:"id"`
+	Slug          string         `db:"slug"`
+	Nama          string         `db:"nama"`
+	Venue         *string        `db:"venue"`
+	Tanggal       pq.StringArray `db:"tanggal"`
+	LogoURI       *string        `db:"logo_uri"`
+	WarnaUtama    *string        `db:"warna_utama"`
+	TemplateTiket *string        `db:"template_tiket"`
+	TemplateEmail *string        `db:"template_email"`
+	CreateTime    time.Time      `db:"create_time"`
+	UpdateTime    *time.Time     `db:"update_time"`
+}
+
+// format formats database struct into domain struct.
+func (edb *eventDB) format() (event.Event, error) {
+	e := event.Event{
+		ID:         edb.ID,
+		Slug:       edb.Slug,
+		Nama:       edb.Nama,
+		CreateTime: edb.CreateTime,
+	}
+
+	for _, s := range edb.Tanggal {
+		t, err := time.Parse(tanggalLayout, s)
+		if err != nil {
+			return event.Event{}, err
+		}
+		e.Tanggal = append(e.Tanggal, t)
+	}
+
+	if edb.Venue != nil {
+		e.Venue = *edb.Venue
+	}
+
+	if edb.LogoURI != nil {
+		e.Branding.LogoURI = *edb.LogoURI
+	}
+
+	if edb.WarnaUtama != nil {
+		e.Branding.WarnaUtama = *edb.WarnaUtama
+	}
+
+	if edb.TemplateTiket != nil {
+		e.Templates.Tiket = *edb.TemplateTiket
+	}
+
+	if edb.TemplateEmail != nil {
+		e.Templates.Email = *edb.TemplateEmail
+	}
+
+	if edb.UpdateTime != nil {
+		e.UpdateTime = *edb.UpdateTime
+	}
+
+	return e, nil
+}
+
+type ticketTypeDB struct {
+	EventID int64  `db:"event_id"`
+	Kode    string `db:"kode"`
+	Nama    string `db:"nama"`
+	Harga   int64  `db:"harga"`
+	Kuota   int    `db:"kuota"`
+}
+
+// format formats database struct into domain struct.
+func (tdb *ticketTypeDB) format() event.TicketType {
+	return event.TicketType{
+		Kode:  tdb.Kode,
+		Nama:  tdb.Nama,
+		Harga: tdb.Harga,
+		Kuota: tdb.Kuota,
+	}
+}
+
+// tanggalArray returns the given days as a PostgreSQL array.
+func tanggalArray(tanggal []time.Time) pq.StringArray {
+	arr := make(pq.StringArray, 0, len(tanggal))
+	for _, t := range tanggal {
+		arr = append(arr, t.Format(tanggalLayout))
+	}
+	return arr
+}
+
+// nullString returns nil for the empty string, so that it
+// is stored as NULL.
+func nullString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+// isUniqueViolation returns whether the given error is a
+// unique constraint violation.
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23505"
+}
@@ -0,0 +1,105 @@
+package postgresql
+
+const queryCreateEvent = `
+	INSERT INTO
+		event
+	(
+		slug,
+		nama,
+		venue,
+		tanggal,
+		logo_uri,
+		warna_utama,
+		template_tiket,
+		template_email,
+		create_time
+	) VALUES (
+		:slug,
+		:nama,
+		:venue,
+		CAST(:tanggal AS DATE[]),
+		:logo_uri,
+		:warna_utama,
+		:template_tiket,
+		:template_email,
+		:create_time
+	) RETURNING
+		id
+`
+
+const queryGetEvent = `
+	SELECT
+		e.id,
+		e.slug,
+		e.nama,
+		e.venue,
+		CAST(e.tanggal AS TEXT[]) AS tanggal,
+		e.logo_uri,
+		e.warna_utama,
+		e.template_tiket,
+		e.template_email,
+		e.create_time,
+		e.update_time
+	FROM
+		event e
+	%s
+`
+
+const queryUpdateEvent = `
+	UPDATE
+		event
+	SET
+		nama = :nama,
+		venue = :venue,
+		tanggal = CAST(:tanggal AS DATE[]),
+		logo_uri = :logo_uri,
+		warna_utama = :warna_utama,
+		template_tiket = :template_tiket,
+		template_email = :template_email,
+		update_time = :update_time
+	WHERE
+		id = :id
+`
+
+const queryGetTicketTypes = `
+	SELECT
+		t.event_id,
+		t.kode,
+		t.nama,
+		t.harga,
+		t.kuota
+	FROM
+		event_ticket_type t
+	WHERE
+		t.event_id IN (:event_ids)
+	ORDER BY
+		t.event_id,
+		t.urutan
+`
+
+const queryDeleteTicketTypes = `
+	DELETE FROM
+		event_ticket_type
+	WHERE
+		event_id = :event_id
+`
+
+const queryCreateTicketType = `
+	INSERT INTO
+		event_ticket_type
+	(
+		event_id,
+		urutan,
+		kode,
+		nama,
+		harga,
+		kuota
+	) VALUES (
+		:event_id,
+		:urutan,
+		:kode,
+		:nama,
+		:harga,
+		:kuota
+	)
+`
@@ -0,0 +1,22 @@
+package export
+
+import "errors"
+
+// Followings are the known errors returned from export.
+var (
+	// ErrInvalidDataset is returned when the given dataset is
+	// unknown.
+	ErrInvalidDataset = errors.New("invalid dataset")
+
+	// ErrInvalidFormat is returned when the given format is
+	// unknown.
+	ErrInvalidFormat = errors.New("invalid format")
+
+	// ErrInvalidColumn is returned when a given column is not
+	// a column of the dataset or is given twice.
+	ErrInvalidColumn = errors.New("invalid column")
+
+	// ErrInvalidStatus is returned when the given status is
+	// not a payment status of the dataset orders.
+	ErrInvalidStatus = errors.New("invalid status")
+)
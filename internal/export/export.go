@@ -0,0 +1,209 @@
+package export
+
+import (
+	"context"
+	"io"
+)
+
+type Service interface {
+	// Export writes the rows of the requested dataset to w in
+	// the requested format. Rows are written as they are read
+	// from the database, so large exports are never held in
+	// memory.
+	Export(ctx context.Context, req Request, w io.Writer) error
+}
+
+// Request is a request of an export.
+type Request struct {
+	Dataset Dataset
+	Format  Format
+
+	// Columns are the keys of the exported columns, in order.
+	// All columns of the dataset are exported when empty.
+	Columns []string
+
+	Filter Filter
+}
+
+// Filter narrows down the exported rows.
+type Filter struct {
+	// EventSlug is the slug of the event of the orders.
+	EventSlug string
+
+	// Status is the name of the payment status of the
+	// orders, e.g. "settlement".
+	Status string
+}
+
+// Column is a column of a dataset.
+type Column struct {
+	Key   string
+	Title string
+
+	// Numeric tells the column holds numbers, they are
+	// written as numbers in spreadsheets.
+	Numeric bool
+}
+
+// Dataset denotes the data that can be exported.
+type Dataset string
+
+// Followings are the known datasets.
+const (
+	// DatasetMainEventPayments is one row per mainevent
+	// order.
+	DatasetMainEventPayments Dataset = "mainevent_payments"
+
+	// DatasetMainEventAttendees is one row per mainevent
+	// ticket, with its holder, seat and check-in.
+	DatasetMainEventAttendees Dataset = "mainevent_attendees"
+
+	// DatasetTransactionPayments is one row per transaction.
+	DatasetTransactionPayments Dataset = "transaction_payments"
+
+	// DatasetTransactionAttendees is one row per transaction
+	// ticket, with its check-in.
+	DatasetTransactionAttendees Dataset = "transaction_attendees"
+)
+
+var (
+	// DatasetList is a list of valid datasets, in the order
+	// they are listed.
+	DatasetList = []Dataset{
+		DatasetMainEventPayments,
+		DatasetMainEventAttendees,
+		DatasetTransactionPayments,
+		DatasetTransactionAttendees,
+	}
+
+	datasetColumns = map[Dataset][]Column{
+		DatasetMainEventPayments: {
+			{Key: "id", Title: "ID", Numeric: true},
+			{Key: "order_id", Title: "Order ID"},
+			{Key: "event", Title: "Event"},
+			{Key: "nama", Title: "Nama"},
+			{Key: "email", Title: "Email"},
+			{Key: "nomor_telepon", Title: "Nomor Telepon"},
+			{Key: "nomor_identitas", Title: "Nomor Identitas"},
+			{Key: "asal_institusi", Title: "Asal Institusi"},
+			{Key: "disabilitas", Title: "Disabilitas"},
+			{Key: "type", Title: "Tipe"},
+			{Key: "status", Title: "Status"},
+			{Key: "jumlah_tiket", Title: "Jumlah Tiket", Numeric: true},
+			{Key: "total_harga", Title: "Total Harga", Numeric: true},
+			{Key: "kode_promo", Title: "Kode Promo"},
+			{Key: "diskon", Title: "Diskon", Numeric: true},
+			{Key: "image_uri", Title: "Bukti Pembayaran"},
+			{Key: "nomor_tiket", Title: "Nomor Tiket"},
+			{Key: "jumlah_checkin", Title: "Jumlah Check-in", Numeric: true},
+			{Key: "checkin_terakhir", Title: "Check-in Terakhir"},
+			{Key: "create_time", Title: "Waktu Pesan"},
+			{Key: "update_time", Title: "Waktu Update"},
+		},
+		DatasetMainEventAttendees: {
+			{Key: "nomor_tiket", Title: "Nomor Tiket"},
+			{Key: "order_id", Title: "Order ID"},
+			{Key: "event", Title: "Event"},
+			{Key: "nama", Title: "Nama"},
+			{Key: "email", Title: "Email"},
+			{Key: "nomor_identitas", Title: "Nomor Identitas"},
+			{Key: "pemesan", Title: "Pemesan"},
+			{Key: "nomor_telepon", Title: "Nomor Telepon Pemesan"},
+			{Key: "asal_institusi", Title: "Asal Institusi"},
+			{Key: "disabilitas", Title: "Disabilitas"},
+			{Key: "type", Title: "Tipe"},
+			{Key: "status", Title: "Status"},
+			{Key: "kursi", Title: "Kursi"},
+			{Key: "checkin", Title: "Check-in"},
+			{Key: "checkin_time", Title: "Waktu Check-in"},
+			{Key: "refund", Title: "Refund"},
+		},
+		DatasetTransactionPayments: {
+			{Key: "id", Title: "ID", Numeric: true},
+			{Key: "order_id", Title: "Order ID"},
+			{Key: "event", Title: "Event"},
+			{Key: "nama", Title: "Nama"},
+			{Key: "jenis_kelamin", Title: "Jenis Kelamin"},
+			{Key: "email", Title: "Email"},
+			{Key: "nomor_telepon", Title: "Nomor Telepon"},
+			{Key: "nomor_identitas", Title: "Nomor Identitas"},
+			{Key: "asal_institusi", Title: "Asal Institusi"},
+			{Key: "domisili", Title: "Domisili"},
+			{Key: "line_id", Title: "ID Line"},
+			{Key: "instagram", Title: "Instagram"},
+			{Key: "tanggal", Title: "Tanggal"},
+			{Key: "jumlah_tiket", Title: "Jumlah Tiket", Numeric: true},
+			{Key: "total_harga", Title: "Total Harga", Numeric: true},
+			{Key: "status", Title: "Status"},
+			{Key: "image_uri", Title: "Bukti Pembayaran"},
+			{Key: "nomor_tiket", Title: "Nomor Tiket"},
+			{Key: "jumlah_checkin", Title: "Jumlah Check-in", Numeric: true},
+			{Key: "checkin_terakhir", Title: "Check-in Terakhir"},
+			{Key: "create_time", Title: "Waktu Pesan"},
+			{Key: "update_time", Title: "Waktu Update"},
+		},
+		DatasetTransactionAttendees: {
+			{Key: "nomor_tiket", Title: "Nomor Tiket"},
+			{Key: "order_id", Title: "Order ID"},
+			{Key: "event", Title: "Event"},
+			{Key: "nama", Title: "Nama"},
+			{Key: "email", Title: "Email"},
+			{Key: "nomor_telepon", Title: "Nomor Telepon"},
+			{Key: "nomor_identitas", Title: "Nomor Identitas"},
+			{Key: "asal_institusi", Title: "Asal Institusi"},
+			{Key: "tanggal", Title: "Tanggal"},
+			{Key: "status", Title: "Status"},
+			{Key: "checkin", Title: "Check-in"},
+			{Key: "checkin_time", Title: "Waktu Check-in"},
+		},
+	}
+)
+
+// Columns returns all columns of the dataset, in order.
+func (d Dataset) Columns() []Column {
+	return datasetColumns[d]
+}
+
+// Column returns the column of the dataset with the given
+// key, and whether there is one.
+func (d Dataset) Column(key string) (Column, bool) {
+	for _, c := range datasetColumns[d] {
+		if c.Key == key {
+			return c, true
+		}
+	}
+	return Column{}, false
+}
+
+// Format denotes the file format of an export.
+type Format string
+
+// Followings are the known formats.
+const (
+	FormatCSV  Format = "csv"
+	FormatXLSX Format = "xlsx"
+)
+
+var (
+	// FormatList is a list of valid formats.
+	FormatList = map[Format]struct{}{
+		FormatCSV:  {},
+		FormatXLSX: {},
+	}
+
+	formatContentType = map[Format]string{
+		FormatCSV:  "text/csv; charset=utf-8",
+		FormatXLSX: "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+	}
+)
+
+// ContentType returns the MIME type of the format.
+func (f Format) ContentType() string {
+	return formatContentType[f]
+}
+
+// Filename returns the file name of an export of the given
+// dataset in the format.
+func (f Format) Filename(d Dataset) string {
+	return string(d) + "." + string(f)
+}
@@ -0,0 +1,49 @@
+package http
+
+import (
+	"errors"
+
+	"github.com/tedxub2023/internal/export"
+)
+
+// Followings are the known errors from Export HTTP handlers.
+var (
+	// errInternalServer is returned when there is an
+	// unexpected error encountered when processing a request.
+	errInternalServer = errors.New("INTERNAL_SERVER_ERROR")
+
+	// errMethodNotAllowed is returned when accessing not
+	// allowed HTTP method.
+	errMethodNotAllowed = errors.New("METHOD_NOT_ALLOWED")
+
+	// errInvalidDataset is returned when the given dataset is
+	// invalid.
+	errInvalidDataset = errors.New("INVALID_DATASET")
+
+	// errInvalidFormat is returned when the given format is
+	// invalid.
+	errInvalidFormat = errors.New("INVALID_FORMAT")
+
+	// errInvalidColumn is returned when the given columns are
+	// invalid.
+	errInvalidColumn = errors.New("INVALID_COLUMN")
+
+	// errInvalidStatus is returned when the given status is
+	// invalid.
+	errInvalidStatus = errors.New("INVALID_STATUS")
+)
+
+var (
+	// mapHTTPError maps service error into HTTP error that
+	// categorize as bad request error.
+	//
+	// Internal server error-related should not be mapped
+	// here, and the handler should just return `errInternal`
+	// as the error instead
+	mapHTTPError = map[error]error{
+		export.ErrInvalidDataset: errInvalidDataset,
+		export.ErrInvalidFormat:  errInvalidFormat,
+		export.ErrInvalidColumn:  errInvalidColumn,
+		export.ErrInvalidStatus:  errInvalidStatus,
+	}
+)
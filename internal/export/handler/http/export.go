@@ -0,0 +1,116 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/tedxub2023/global/helper"
+	"github.com/tedxub2023/global/logger"
+	"github.com/tedxub2023/internal/export"
+)
+
+type exportHandler struct {
+	export       export.Service
+	writeTimeout time.Duration
+}
+
+func (h *exportHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.handleExport(w, r)
+	default:
+		helper.WriteErrorResponse(w, http.StatusMethodNotAllowed, []string{errMethodNotAllowed.Error()})
+	}
+}
+
+func (h *exportHandler) handleExport(w http.ResponseWriter, r *http.Request) {
+	// the rows are written as they are read, so the export is
+	// bound by its own write timeout instead of the usual
+	// handler timeout and the shorter server write timeout
+	ctx := r.Context()
+	if err := helper.SetWriteDeadline(r, time.Now().Add(h.writeTimeout)); err != nil {
+		logger.Warn(ctx, "failed to extend export write deadline", logger.Fields{"error": err.Error()})
+	}
+
+	req := parseExportRequest(mux.Vars(r)["dataset"], r.URL.Query())
+	logger.AddFields(ctx, logger.Fields{
+		"dataset": req.Dataset,
+		"format":  req.Format,
+	})
+
+	ew := &exportWriter{
+		w:        w,
+		format:   req.Format,
+		filename: req.Format.Filename(req.Dataset),
+	}
+	err := h.export.Export(ctx, req, ew)
+	if err == nil {
+		return
+	}
+
+	// the file is already under way, it can only be cut short
+	if ew.written {
+		logger.Error(ctx, "failed to write export", err)
+		return
+	}
+
+	// determine error and status code, by default its internal error
+	parsedErr := errInternalServer
+	statusCode := http.StatusInternalServerError
+	if v, ok := mapHTTPError[err]; ok {
+		parsedErr = v
+		statusCode = http.StatusBadRequest
+	}
+
+	logger.Error(ctx, "failed to export", err)
+	helper.WriteErrorResponse(w, statusCode, []string{parsedErr.Error()})
+}
+
+// parseExportRequest returns the export request of the given
+// dataset and query, the format is CSV by default.
+func parseExportRequest(dataset string, query url.Values) export.Request {
+	req := export.Request{
+		Dataset: export.Dataset(dataset),
+		Format:  export.FormatCSV,
+		Filter: export.Filter{
+			EventSlug: strings.TrimSpace(query.Get("event")),
+			Status:    strings.TrimSpace(query.Get("status")),
+		},
+	}
+
+	if format := query.Get("format"); format != "" {
+		req.Format = export.Format(strings.ToLower(format))
+	}
+
+	for _, c := range strings.Split(query.Get("columns"), ",") {
+		if c = strings.TrimSpace(c); c != "" {
+			req.Columns = append(req.Columns, c)
+		}
+	}
+
+	return req
+}
+
+// exportWriter writes the headers of an export download right
+// before its first byte, so that an export failing early can
+// still respond with an error.
+type exportWriter struct {
+	w        http.ResponseWriter
+	format   export.Format
+	filename string
+	written  bool
+}
+
+func (ew *exportWriter) Write(p []byte) (int, error) {
+	if !ew.written {
+		ew.written = true
+		ew.w.Header().Set("Content-Type", ew.format.ContentType())
+		ew.w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", ew.filename))
+		ew.w.WriteHeader(http.StatusOK)
+	}
+	return ew.w.Write(p)
+}
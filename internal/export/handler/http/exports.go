@@ -0,0 +1,51 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/tedxub2023/global/helper"
+	"github.com/tedxub2023/global/logger"
+	"github.com/tedxub2023/internal/export"
+)
+
+type exportsHandler struct{}
+
+func (h *exportsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.handleGetAllDatasets(w, r)
+	default:
+		helper.WriteErrorResponse(w, http.StatusMethodNotAllowed, []string{errMethodNotAllowed.Error()})
+	}
+}
+
+func (h *exportsHandler) handleGetAllDatasets(w http.ResponseWriter, r *http.Request) {
+	datasets := make([]datasetHTTP, 0, len(export.DatasetList))
+	for _, d := range export.DatasetList {
+		columns := make([]columnHTTP, 0, len(d.Columns()))
+		for _, c := range d.Columns() {
+			columns = append(columns, columnHTTP{
+				Key:   c.Key,
+				Judul: c.Title,
+			})
+		}
+
+		datasets = append(datasets, datasetHTTP{
+			Dataset: string(d),
+			Kolom:   columns,
+		})
+	}
+
+	resBody, err := json.Marshal(helper.ResponseEnvelope{
+		Status: "Success",
+		Data:   datasets,
+	})
+	if err != nil {
+		logger.Error(r.Context(), "failed to get all datasets", err)
+		helper.WriteErrorResponse(w, http.StatusInternalServerError, []string{errInternalServer.Error()})
+		return
+	}
+
+	helper.WriteResponse(w, resBody, http.StatusOK, helper.JSONContentTypeDecorator)
+}
@@ -0,0 +1,116 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/tedxub2023/internal/export"
+)
+
+var (
+	errUnknownConfig = errors.New("unknown config name")
+)
+
+// Handler contains export HTTP-handlers.
+type Handler struct {
+	handlers     map[string]*handler
+	export       export.Service
+	writeTimeout time.Duration
+}
+
+// handler is the HTTP handler wrapper.
+type handler struct {
+	h        http.Handler
+	identity HandlerIdentity
+}
+
+// HandlerIdentity denotes the identity of an HTTP hanlder.
+type HandlerIdentity struct {
+	Name string
+	URL  string
+}
+
+// Followings are the known HTTP handler identities
+var (
+	// HandlerExports denotes HTTP handler for the committee to
+	// list the datasets that can be exported
+	HandlerExports = HandlerIdentity{
+		Name: "exports",
+		URL:  "/exports",
+	}
+
+	// HandlerExport denotes HTTP handler for the committee to
+	// download an export of a dataset
+	HandlerExport = HandlerIdentity{
+		Name: "export",
+		URL:  "/exports/{dataset:[a-z_]+}",
+	}
+)
+
+// New creates a new Handler. The downloads may take up to the
+// given write timeout instead of the server one.
+func New(export export.Service, writeTimeout time.Duration, identities []HandlerIdentity) (*Handler, error) {
+	h := &Handler{
+		handlers:     make(map[string]*handler),
+		export:       export,
+		writeTimeout: writeTimeout,
+	}
+
+	// apply options
+	for _, identity := range identities {
+		if h.handlers == nil {
+			h.handlers = map[string]*handler{}
+		}
+
+		h.handlers[identity.Name] = &handler{
+			identity: identity,
+		}
+
+		handler, err := h.createHTTPHandler(identity.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		h.handlers[identity.Name].h = handler
+	}
+
+	return h, nil
+}
+
+// createHTTPHandler creates a new HTTP handler that
+// implements http.Handler.
+func (h *Handler) createHTTPHandler(configName string) (http.Handler, error) {
+	var httpHandler http.Handler
+	switch configName {
+	case HandlerExports.Name:
+		httpHandler = &exportsHandler{}
+	case HandlerExport.Name:
+		httpHandler = &exportHandler{
+			export:       h.export,
+			writeTimeout: h.writeTimeout,
+		}
+	default:
+		return httpHandler, errUnknownConfig
+	}
+	return httpHandler, nil
+}
+
+// Start starts all HTTP handlers.
+func (h *Handler) Start(multiplexer *mux.Router) error {
+	for _, handler := range h.handlers {
+		multiplexer.Handle(handler.identity.URL, handler.h)
+	}
+	return nil
+}
+
+type datasetHTTP struct {
+	Dataset string       `json:"dataset"`
+	Kolom   []columnHTTP `json:"kolom"`
+}
+
+type columnHTTP struct {
+	Key   string `json:"key"`
+	Judul string `json:"judul"`
+}
@@ -0,0 +1,61 @@
+package service
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/tedxub2023/internal/export"
+)
+
+// csvWriter implements rowWriter for export.FormatCSV.
+type csvWriter struct {
+	w       *csv.Writer
+	numeric []bool
+}
+
+func newCSVWriter(w io.Writer, columns []export.Column) *csvWriter {
+	numeric := make([]bool, 0, len(columns))
+	for _, c := range columns {
+		numeric = append(numeric, c.Numeric)
+	}
+
+	return &csvWriter{
+		w:       csv.NewWriter(w),
+		numeric: numeric,
+	}
+}
+
+func (cw *csvWriter) Write(row []string) error {
+	record := make([]string, 0, len(row))
+	for i, v := range row {
+		// values typed in by the buyers are escaped so that
+		// spreadsheets do not evaluate them as formulas
+		if !cw.numeric[i] || !isNumber(v) {
+			v = escapeFormula(v)
+		}
+		record = append(record, v)
+	}
+	return cw.w.Write(record)
+}
+
+func (cw *csvWriter) Close() error {
+	cw.w.Flush()
+	return cw.w.Error()
+}
+
+// isNumber returns whether the given value is a number.
+func isNumber(v string) bool {
+	_, err := strconv.ParseFloat(v, 64)
+	return err == nil
+}
+
+// escapeFormula prefixes the given value with a quote if a
+// spreadsheet would read it as a formula.
+func escapeFormula(v string) string {
+	if v != "" && strings.ContainsRune("=+-@\t\r", rune(v[0])) {
+		return "'" + v
+	}
+	return v
+}
@@ -0,0 +1,117 @@
+package service
+
+import (
+	"context"
+	"io"
+
+	"github.com/tedxub2023/internal/export"
+	"github.com/tedxub2023/internal/mainevent"
+	"github.com/tedxub2023/internal/transaction"
+)
+
+func (s *service) Export(ctx context.Context, req export.Request, w io.Writer) error {
+	// validate request
+	if !isValidDataset(req.Dataset) {
+		return export.ErrInvalidDataset
+	}
+	if _, ok := export.FormatList[req.Format]; !ok {
+		return export.ErrInvalidFormat
+	}
+	columns, err := resolveColumns(req.Dataset, req.Columns)
+	if err != nil {
+		return err
+	}
+	if req.Filter.Status != "" && !isValidStatus(req.Dataset, req.Filter.Status) {
+		return export.ErrInvalidStatus
+	}
+
+	pgStoreClient, err := s.pgStore.NewClient(false)
+	if err != nil {
+		return err
+	}
+
+	keys := make([]string, 0, len(columns))
+	for _, c := range columns {
+		keys = append(keys, c.Key)
+	}
+
+	// nothing is written until the first row is read, so a
+	// failing query leaves w untouched
+	var rw rowWriter
+	open := func() error {
+		if rw != nil {
+			return nil
+		}
+		var err error
+		rw, err = newRowWriter(req.Format, w, columns)
+		return err
+	}
+
+	err = pgStoreClient.StreamRows(ctx, req.Dataset, keys, req.Filter, func(row []string) error {
+		if err := open(); err != nil {
+			return err
+		}
+		return rw.Write(row)
+	})
+	if err != nil {
+		return err
+	}
+
+	// an export without rows still has its header
+	err = open()
+	if err != nil {
+		return err
+	}
+
+	return rw.Close()
+}
+
+// isValidDataset returns whether the given dataset is known.
+func isValidDataset(dataset export.Dataset) bool {
+	for _, d := range export.DatasetList {
+		if d == dataset {
+			return true
+		}
+	}
+	return false
+}
+
+// resolveColumns returns the columns of the dataset with the
+// given keys, in order, or all of them if there is no key.
+func resolveColumns(dataset export.Dataset, keys []string) ([]export.Column, error) {
+	if len(keys) == 0 {
+		return dataset.Columns(), nil
+	}
+
+	columns := make([]export.Column, 0, len(keys))
+	seen := make(map[string]struct{}, len(keys))
+	for _, key := range keys {
+		c, ok := dataset.Column(key)
+		if !ok {
+			return nil, export.ErrInvalidColumn
+		}
+		if _, ok := seen[key]; ok {
+			return nil, export.ErrInvalidColumn
+		}
+		seen[key] = struct{}{}
+		columns = append(columns, c)
+	}
+	return columns, nil
+}
+
+// isValidStatus returns whether the given status is a payment
+// status of the orders of the dataset.
+func isValidStatus(dataset export.Dataset, status string) bool {
+	switch dataset {
+	case export.DatasetMainEventPayments, export.DatasetMainEventAttendees:
+		for s := range mainevent.StatusList {
+			if s.String() == status {
+				return true
+			}
+		}
+	case export.DatasetTransactionPayments, export.DatasetTransactionAttendees:
+		_, ok := transaction.StatusList[transaction.Status(status)]
+		return ok
+	}
+	return false
+}
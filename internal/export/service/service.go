@@ -0,0 +1,13 @@
+package service
+
+// service implements export.Service.
+type service struct {
+	pgStore PGStore
+}
+
+// New returns a new service
+func New(pgStore PGStore) (*service, error) {
+	return &service{
+		pgStore: pgStore,
+	}, nil
+}
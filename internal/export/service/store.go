@@ -0,0 +1,27 @@
+package service
+
+import (
+	"context"
+
+	"github.com/tedxub2023/internal/export"
+)
+
+// PGStore is the PostgreSQL store for export service.
+type PGStore interface {
+	NewClient(useTx bool) (PGStoreClient, error)
+}
+
+type PGStoreClient interface {
+	// Commit commits the transaction.
+	Commit() error
+
+	// Rollback aborts the transaction.
+	Rollback() error
+
+	// StreamRows reads the given columns of the rows of the
+	// dataset matching the filter, in order, and calls fn with
+	// each row as it is read. The values are formatted as
+	// text, empty when null. It stops at the first error
+	// returned by fn.
+	StreamRows(ctx context.Context, dataset export.Dataset, columns []string, filter export.Filter, fn func(row []string) error) error
+}
@@ -0,0 +1,42 @@
+package service
+
+import (
+	"io"
+
+	"github.com/tedxub2023/internal/export"
+)
+
+// rowWriter writes the rows of an export in its format.
+type rowWriter interface {
+	// Write writes a row, its values are in the order of the
+	// columns.
+	Write(row []string) error
+
+	// Close writes whatever is left of the file, it does not
+	// close the underlying writer.
+	Close() error
+}
+
+// newRowWriter returns a rowWriter of the given format that
+// has written the header of the given columns to w.
+func newRowWriter(format export.Format, w io.Writer, columns []export.Column) (rowWriter, error) {
+	var rw rowWriter
+	switch format {
+	case export.FormatCSV:
+		rw = newCSVWriter(w, columns)
+	case export.FormatXLSX:
+		var err error
+		rw, err = newXLSXWriter(w, columns)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, export.ErrInvalidFormat
+	}
+
+	header := make([]string, 0, len(columns))
+	for _, c := range columns {
+		header = append(header, c.Title)
+	}
+	return rw, rw.Write(header)
+}
@@ -0,0 +1,153 @@
+package service
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/xml"
+	"io"
+	"strconv"
+
+	"github.com/tedxub2023/internal/export"
+)
+
+// xlsxParts are the parts of a workbook of a single sheet
+// besides the sheet itself.
+var xlsxParts = []struct {
+	name    string
+	content string
+}{
+	{
+		name: "[Content_Types].xml",
+		content: xml.Header + `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+			`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+			`<Default Extension="xml" ContentType="application/xml"/>` +
+			`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+			`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+			`</Types>`,
+	},
+	{
+		name: "_rels/.rels",
+		content: xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+			`</Relationships>`,
+	},
+	{
+		name: "xl/workbook.xml",
+		content: xml.Header + `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+			`<sheets><sheet name="Export" sheetId="1" r:id="rId1"/></sheets>` +
+			`</workbook>`,
+	},
+	{
+		name: "xl/_rels/workbook.xml.rels",
+		content: xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>` +
+			`</Relationships>`,
+	},
+}
+
+// xlsxSheetStart and xlsxSheetEnd enclose the rows of the
+// sheet, the header row is frozen.
+const (
+	xlsxSheetStart = xml.Header + `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+		`<sheetViews><sheetView workbookViewId="0"><pane ySplit="1" topLeftCell="A2" activePane="bottomLeft" state="frozen"/></sheetView></sheetViews>` +
+		`<sheetData>`
+	xlsxSheetEnd = `</sheetData></worksheet>`
+)
+
+// xlsxWriter implements rowWriter for export.FormatXLSX. The
+// sheet is the last part of the file, so its rows are written
+// through as they come.
+type xlsxWriter struct {
+	zw      *zip.Writer
+	sheet   *bufio.Writer
+	numeric []bool
+	rowNum  int
+}
+
+func newXLSXWriter(w io.Writer, columns []export.Column) (*xlsxWriter, error) {
+	zw := zip.NewWriter(w)
+	for _, part := range xlsxParts {
+		f, err := zw.Create(part.name)
+		if err != nil {
+			return nil, err
+		}
+		_, err = io.WriteString(f, part.content)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	f, err := zw.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return nil, err
+	}
+	sheet := bufio.NewWriter(f)
+	_, err = sheet.WriteString(xlsxSheetStart)
+	if err != nil {
+		return nil, err
+	}
+
+	numeric := make([]bool, 0, len(columns))
+	for _, c := range columns {
+		numeric = append(numeric, c.Numeric)
+	}
+
+	return &xlsxWriter{
+		zw:      zw,
+		sheet:   sheet,
+		numeric: numeric,
+	}, nil
+}
+
+func (xw *xlsxWriter) Write(row []string) error {
+	xw.rowNum++
+	rowNum := strconv.Itoa(xw.rowNum)
+
+	xw.sheet.WriteString(`<row r="` + rowNum + `">`)
+	for i, v := range row {
+		if v == "" {
+			continue
+		}
+
+		ref := xlsxColumnName(i) + rowNum
+
+		// the header row is text even on numeric columns
+		if xw.rowNum > 1 && xw.numeric[i] && isNumber(v) {
+			xw.sheet.WriteString(`<c r="` + ref + `"><v>` + v + `</v></c>`)
+			continue
+		}
+
+		xw.sheet.WriteString(`<c r="` + ref + `" t="inlineStr"><is><t xml:space="preserve">`)
+		err := xml.EscapeText(xw.sheet, []byte(v))
+		if err != nil {
+			return err
+		}
+		xw.sheet.WriteString(`</t></is></c>`)
+	}
+	_, err := xw.sheet.WriteString(`</row>`)
+	return err
+}
+
+func (xw *xlsxWriter) Close() error {
+	_, err := xw.sheet.WriteString(xlsxSheetEnd)
+	if err != nil {
+		return err
+	}
+
+	err = xw.sheet.Flush()
+	if err != nil {
+		return err
+	}
+
+	return xw.zw.Close()
+}
+
+// xlsxColumnName returns the name of the column with the given
+// zero-based index, e.g. "A", "Z", "AA".
+func xlsxColumnName(i int) string {
+	name := ""
+	for i++; i > 0; i = (i - 1) / 26 {
+		name = string(rune('A'+(i-1)%26)) + name
+	}
+	return name
+}
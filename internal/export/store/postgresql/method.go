@@ -0,0 +1,113 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/tedxub2023/internal/export"
+	"github.com/tedxub2023/internal/mainevent"
+)
+
+func (sc *storeClient) StreamRows(ctx context.Context, dataset export.Dataset, columns []string, filter export.Filter, fn func(row []string) error) error {
+	baseQuery, ok := datasetQueries[dataset]
+	if !ok {
+		return export.ErrInvalidDataset
+	}
+
+	// select the expression of each column
+	exprs := make([]string, 0, len(columns))
+	for _, c := range columns {
+		expr, ok := datasetColumns[dataset][c]
+		if !ok {
+			return export.ErrInvalidColumn
+		}
+		exprs = append(exprs, expr)
+	}
+
+	// define variables to custom query
+	argsKV := make(map[string]interface{})
+	addConditions := make([]string, 0)
+
+	if filter.EventSlug != "" {
+		addConditions = append(addConditions, "e.slug = :event_slug")
+		argsKV["event_slug"] = filter.EventSlug
+	}
+
+	if filter.Status != "" {
+		switch dataset {
+		case export.DatasetMainEventPayments, export.DatasetMainEventAttendees:
+			status, ok := parseMainEventStatus(filter.Status)
+			if !ok {
+				return export.ErrInvalidStatus
+			}
+			addConditions = append(addConditions, "m.status = :status")
+			argsKV["status"] = status
+		default:
+			addConditions = append(addConditions, "t.status_payment = :status")
+			argsKV["status"] = filter.Status
+		}
+	}
+
+	// construct strings to custom query
+	addCondition := strings.Join(addConditions, " AND ")
+
+	// since the query does not contains "WHERE" yet, need
+	// to add it if needed
+	if len(addConditions) > 0 {
+		addCondition = fmt.Sprintf("WHERE %s", addCondition)
+	}
+	query := fmt.Sprintf(baseQuery, strings.Join(exprs, ",\n\t\t"), addCondition)
+
+	// prepare query
+	query, args, err := sqlx.Named(query, argsKV)
+	if err != nil {
+		return err
+	}
+	query = sc.q.Rebind(query)
+
+	// query to database
+	rows, err := sc.q.QueryxContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	// read and hand over each row as it comes
+	values := make([]sql.NullString, len(columns))
+	dest := make([]interface{}, len(columns))
+	for i := range values {
+		dest[i] = &values[i]
+	}
+	for rows.Next() {
+		err = rows.Scan(dest...)
+		if err != nil {
+			return err
+		}
+
+		row := make([]string, len(columns))
+		for i, v := range values {
+			row[i] = v.String
+		}
+
+		err = fn(row)
+		if err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+// parseMainEventStatus returns the mainevent.Status with the
+// given name, and whether there is one.
+func parseMainEventStatus(name string) (mainevent.Status, bool) {
+	for s := range mainevent.StatusList {
+		if s.String() == name {
+			return s, true
+		}
+	}
+	return mainevent.StatusUnknown, false
+}
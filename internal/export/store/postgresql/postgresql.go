@@ -0,0 +1,223 @@
+package postgresql
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/tedxub2023/internal/export"
+	"github.com/tedxub2023/internal/export/service"
+	"github.com/tedxub2023/internal/mainevent"
+)
+
+var (
+	errInvalidCommit   = errors.New("cannot do commit on non-transactional querier")
+	errInvalidRollback = errors.New("cannot do rollback on non-transactional querier")
+)
+
+// store implements export/service.PGStore
+type store struct {
+	db *sqlx.DB
+}
+
+// storeClient implements export/service.PGStoreClient
+type storeClient struct {
+	q sqlx.ExtContext
+}
+
+// New creates a new store.
+func New(db *sqlx.DB) (*store, error) {
+	s := &store{
+		db: db,
+	}
+
+	return s, nil
+}
+
+func (s *store) NewClient(useTx bool) (service.PGStoreClient, error) {
+	var q sqlx.ExtContext
+
+	// determine what object should be use as querier
+	q = s.db
+	if useTx {
+		var err error
+		q, err = s.db.Beginx()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &storeClient{
+		q: q,
+	}, nil
+}
+
+func (sc *storeClient) Commit() error {
+	if tx, ok := sc.q.(*sqlx.Tx); ok {
+		return tx.Commit()
+	}
+	return errInvalidCommit
+}
+
+func (sc *storeClient) Rollback() error {
+	if tx, ok := sc.q.(*sqlx.Tx); ok {
+		return tx.Rollback()
+	}
+	return errInvalidRollback
+}
+
+// datasetQueries maps each dataset to its query, the aliases
+// of the tables are used by datasetColumns.
+var datasetQueries = map[export.Dataset]string{
+	export.DatasetMainEventPayments:    queryMainEventPayments,
+	export.DatasetMainEventAttendees:   queryMainEventAttendees,
+	export.DatasetTransactionPayments:  queryTransactionPayments,
+	export.DatasetTransactionAttendees: queryTransactionAttendees,
+}
+
+// datasetColumns maps the columns of each dataset to their SQL
+// expressions, all of them are text.
+var datasetColumns = map[export.Dataset]map[string]string{
+	export.DatasetMainEventPayments: {
+		"id":               "m.id::TEXT",
+		"order_id":         "m.order_id",
+		"event":            "e.slug",
+		"nama":             "m.nama",
+		"email":            "m.email",
+		"nomor_telepon":    "m.nomor_telepon",
+		"nomor_identitas":  "m.nomor_identitas",
+		"asal_institusi":   "m.asal_institusi",
+		"disabilitas":      disabilityName("m.disabilitas"),
+		"type":             typeName("m.type"),
+		"status":           statusName("m.status"),
+		"jumlah_tiket":     "m.jumlah_tiket::TEXT",
+		"total_harga":      "m.total_harga::TEXT",
+		"kode_promo":       "m.kode_promo",
+		"diskon":           "m.diskon::TEXT",
+		"image_uri":        "m.image_uri",
+		"nomor_tiket":      "array_to_string(m.nomor_tiket, ', ')",
+		"jumlah_checkin":   "(SELECT COUNT(*) FROM mainevent_checkin c WHERE c.mainevent_id = m.id)::TEXT",
+		"checkin_terakhir": timeText("(SELECT MAX(c.checkin_time) FROM mainevent_checkin c WHERE c.mainevent_id = m.id)"),
+		"create_time":      timeText("m.create_time"),
+		"update_time":      timeText("m.update_time"),
+	},
+	export.DatasetMainEventAttendees: {
+		"nomor_tiket":     "k.nomor_tiket",
+		"order_id":        "m.order_id",
+		"event":           "e.slug",
+		"nama":            "COALESCE(h.nama, m.nama)",
+		"email":           "COALESCE(h.email, m.email)",
+		"nomor_identitas": "COALESCE(h.nomor_identitas, m.nomor_identitas)",
+		"pemesan":         "m.nama",
+		"nomor_telepon":   "m.nomor_telepon",
+		"asal_institusi":  "m.asal_institusi",
+		"disabilitas":     disabilityName("m.disabilitas"),
+		"type":            typeName("m.type"),
+		"status":          statusName("m.status"),
+		"kursi":           "s.section || ', Baris ' || s.baris || ', Kursi ' || s.nomor",
+		"checkin":         yesNo("c.nomor_tiket IS NOT NULL OR k.nomor_tiket = ANY(m.checkin_nomor_tiket)"),
+		"checkin_time":    timeText("c.checkin_time"),
+		"refund":          yesNo("k.nomor_tiket = ANY(m.refund_nomor_tiket)"),
+	},
+	export.DatasetTransactionPayments: {
+		"id":               "t.id::TEXT",
+		"order_id":         "t.order_id",
+		"event":            "e.slug",
+		"nama":             "t.nama",
+		"jenis_kelamin":    "t.jenis_kelamin",
+		"email":            "t.email",
+		"nomor_telepon":    "t.nomor_telepon",
+		"nomor_identitas":  "t.nomor_identitas",
+		"asal_institusi":   "t.asal_institusi",
+		"domisili":         "t.domisili",
+		"line_id":          "t.line_id",
+		"instagram":        "t.instagram",
+		"tanggal":          "to_char(t.tanggal::DATE, 'YYYY-MM-DD')",
+		"jumlah_tiket":     "t.jumlah_tiket::TEXT",
+		"total_harga":      "t.total_harga::TEXT",
+		"status":           "t.status_payment",
+		"image_uri":        "t.image_uri",
+		"nomor_tiket":      "array_to_string(t.nomor_tiket, ', ')",
+		"jumlah_checkin":   "(SELECT COUNT(*) FROM transaction_checkin c WHERE c.transaction_id = t.id)::TEXT",
+		"checkin_terakhir": timeText("(SELECT MAX(c.checkin_time) FROM transaction_checkin c WHERE c.transaction_id = t.id)"),
+		"create_time":      timeText("t.create_time"),
+		"update_time":      timeText("t.update_time"),
+	},
+	export.DatasetTransactionAttendees: {
+		"nomor_tiket":     "k.nomor_tiket",
+		"order_id":        "t.order_id",
+		"event":           "e.slug",
+		"nama":            "t.nama",
+		"email":           "t.email",
+		"nomor_telepon":   "t.nomor_telepon",
+		"nomor_identitas": "t.nomor_identitas",
+		"asal_institusi":  "t.asal_institusi",
+		"tanggal":         "to_char(t.tanggal::DATE, 'YYYY-MM-DD')",
+		"status":          "t.status_payment",
+		"checkin":         yesNo("c.nomor_tiket IS NOT NULL OR k.nomor_tiket = ANY(t.checkin_nomor_tiket)"),
+		"checkin_time":    timeText("c.checkin_time"),
+	},
+}
+
+// timeText returns an SQL expression of the given time in WIB
+// as text.
+func timeText(expr string) string {
+	return fmt.Sprintf("to_char(%s AT TIME ZONE 'Asia/Jakarta', 'YYYY-MM-DD HH24:MI:SS')", expr)
+}
+
+// yesNo returns an SQL expression of the given condition as
+// "Ya" or "Tidak".
+func yesNo(cond string) string {
+	return fmt.Sprintf("CASE WHEN COALESCE(%s, FALSE) THEN 'Ya' ELSE 'Tidak' END", cond)
+}
+
+// statusName returns an SQL expression of the name of the
+// mainevent.Status in the given column.
+func statusName(column string) string {
+	names := make(map[int]string, len(mainevent.StatusList))
+	for s := range mainevent.StatusList {
+		names[s.Value()] = s.String()
+	}
+	return caseName(column, names)
+}
+
+// typeName returns an SQL expression of the name of the
+// mainevent.Type in the given column.
+func typeName(column string) string {
+	names := make(map[int]string, len(mainevent.TypeList))
+	for t := range mainevent.TypeList {
+		names[t.Value()] = t.String()
+	}
+	return caseName(column, names)
+}
+
+// disabilityName returns an SQL expression of the name of the
+// mainevent.Disability in the given column.
+func disabilityName(column string) string {
+	names := make(map[int]string, len(mainevent.DisabilityList))
+	for d := range mainevent.DisabilityList {
+		names[d.Value()] = d.String()
+	}
+	return caseName(column, names)
+}
+
+// caseName returns an SQL expression mapping the values of the
+// given column to their names.
+func caseName(column string, names map[int]string) string {
+	values := make([]int, 0, len(names))
+	for v := range names {
+		values = append(values, v)
+	}
+	sort.Ints(values)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "CASE %s", column)
+	for _, v := range values {
+		fmt.Fprintf(&b, " WHEN %d THEN %s", v, pq.QuoteLiteral(names[v]))
+	}
+	b.WriteString(" END")
+	return b.String()
+}
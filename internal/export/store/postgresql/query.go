@@ -0,0 +1,61 @@
+package postgresql
+
+const queryMainEventPayments = `
+	SELECT
+		%s
+	FROM
+		mainevent m
+	LEFT JOIN
+		event e ON e.id = m.event_id
+	%s
+	ORDER BY
+		m.id
+`
+
+const queryMainEventAttendees = `
+	SELECT
+		%s
+	FROM
+		mainevent m
+	LEFT JOIN
+		event e ON e.id = m.event_id
+	CROSS JOIN LATERAL
+		unnest(m.nomor_tiket) WITH ORDINALITY AS k (nomor_tiket, urutan)
+	LEFT JOIN
+		mainevent_holder h ON h.mainevent_id = m.id AND h.nomor_tiket = k.nomor_tiket
+	LEFT JOIN
+		mainevent_seat s ON s.mainevent_id = m.id AND s.nomor_tiket = k.nomor_tiket
+	LEFT JOIN
+		mainevent_checkin c ON c.mainevent_id = m.id AND c.nomor_tiket = k.nomor_tiket
+	%s
+	ORDER BY
+		m.id, k.urutan
+`
+
+const queryTransactionPayments = `
+	SELECT
+		%s
+	FROM
+		transaction t
+	LEFT JOIN
+		event e ON e.id = t.event_id
+	%s
+	ORDER BY
+		t.id
+`
+
+const queryTransactionAttendees = `
+	SELECT
+		%s
+	FROM
+		transaction t
+	LEFT JOIN
+		event e ON e.id = t.event_id
+	CROSS JOIN LATERAL
+		unnest(t.nomor_tiket) WITH ORDINALITY AS k (nomor_tiket, urutan)
+	LEFT JOIN
+		transaction_checkin c ON c.transaction_id = t.id AND c.nomor_tiket = k.nomor_tiket
+	%s
+	ORDER BY
+		t.id, k.urutan
+`
@@ -0,0 +1,103 @@
+package mainevent
+
+// Accommodation is an accessibility accommodation a buyer
+// needs at the venue.
+type Accommodation string
+
+// Followings are the known accommodations.
+const (
+	// AccommodationCompanion means the attendee comes with a
+	// companion seated next to them.
+	AccommodationCompanion Accommodation = "companion"
+
+	// AccommodationWheelchair means the attendee needs a
+	// wheelchair space.
+	AccommodationWheelchair Accommodation = "wheelchair"
+
+	// AccommodationSignLanguage means the attendee needs a
+	// sign language interpreter in sight.
+	AccommodationSignLanguage Accommodation = "sign_language"
+)
+
+// Followings are the accommodation lists.
+var (
+	// AccommodationList is a list of valid accommodations.
+	AccommodationList = map[Accommodation]struct{}{
+		AccommodationCompanion:    {},
+		AccommodationWheelchair:   {},
+		AccommodationSignLanguage: {},
+	}
+
+	accommodationName = map[Accommodation]string{
+		AccommodationCompanion:    "Pendamping",
+		AccommodationWheelchair:   "Kursi Roda",
+		AccommodationSignLanguage: "Juru Bahasa Isyarat",
+	}
+)
+
+// String returns string representaion of an accommodation.
+func (a Accommodation) String() string {
+	return string(a)
+}
+
+// Name returns the name of an accommodation shown to the
+// attendees and the committee.
+func (a Accommodation) Name() string {
+	return accommodationName[a]
+}
+
+// NeedsAccessibleSeating reports whether the mainevent takes
+// the accessible seats, which are reserved for the buyers
+// with a disability or an accommodation.
+func (m MainEvent) NeedsAccessibleSeating() bool {
+	if m.Disabilitas != DisabilityUnknown && m.Disabilitas != NoneDisability {
+		return true
+	}
+	return len(m.Akomodasi) > 0
+}
+
+// CheckIn is a checked in ticket as shown at the gate.
+type CheckIn struct {
+	Holder Holder
+
+	// Disabilitas, Akomodasi and CatatanAkomodasi are of the
+	// order of the ticket, so that the gate can guide the
+	// attendee to the accessible seats.
+	Disabilitas      Disability
+	Akomodasi        []Accommodation
+	CatatanAkomodasi string
+
+	// Seat is the seat of the ticket, it is zero if the ticket
+	// has no seat.
+	Seat Seat
+}
+
+// AccommodationReport is the report of the accessibility
+// accommodations of the settled mainevents.
+type AccommodationReport struct {
+	// KuotaKursi is the number of reserved accessible seats
+	// of the normal sale, and KursiTerpakai the number of the
+	// normal sale tickets taking them.
+	KuotaKursi    int
+	KursiTerpakai int
+
+	// Disabilitas are the reports of each disability with
+	// orders, in the order of the disability values.
+	Disabilitas []DisabilityReport
+}
+
+// DisabilityReport is the report of the settled mainevents
+// with a disability.
+type DisabilityReport struct {
+	Disabilitas Disability
+	JumlahOrder int
+	JumlahTiket int
+
+	// Akomodasi counts the orders needing each accommodation.
+	Akomodasi map[Accommodation]int
+
+	// Orders are the mainevents of the disability, the orders
+	// without a disability are only reported if they need an
+	// accommodation.
+	Orders []MainEvent
+}
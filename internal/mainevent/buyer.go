@@ -0,0 +1,54 @@
+package mainevent
+
+import "time"
+
+// UnpaidOrderTTL is the duration an unpaid normal sale
+// mainevent holds its tickets, it is deleted once it passes.
+const UnpaidOrderTTL = 6 * time.Minute
+
+// GroupInvoiceTTL is the duration an unpaid group invoice
+// holds its tickets, it is deleted once it passes and its
+// group order expires. The coordinator collects the payment
+// of the attendees meanwhile.
+const GroupInvoiceTTL = 72 * time.Hour
+
+// BuyerLogin is a login link emailed to a buyer to manage
+// their mainevents, and the session it opens once it is
+// followed.
+type BuyerLogin struct {
+	ID    int64
+	Email string
+
+	// Token is the secret of the login link, it is valid
+	// once until ExpireTime. Only its SHA-256 hash in hex,
+	// TokenHash, is stored.
+	Token      string
+	TokenHash  string
+	ExpireTime time.Time
+
+	// SessionToken is the secret of the session opened by
+	// the link, valid until SessionExpireTime. Only its
+	// SessionTokenHash is stored.
+	SessionToken      string
+	SessionTokenHash  string
+	SessionExpireTime time.Time
+
+	CreateTime time.Time
+	UseTime    time.Time
+}
+
+// PaymentDeadline returns the time the mainevent is deleted
+// unless its payment proof is uploaded. It is zero if the
+// mainevent does not expire.
+func (m MainEvent) PaymentDeadline() time.Time {
+	if m.Status != StatusUnpaid {
+		return time.Time{}
+	}
+	switch m.Type {
+	case TypeNormalSale:
+		return m.CreateTime.Add(UnpaidOrderTTL)
+	case TypeGroup:
+		return m.CreateTime.Add(GroupInvoiceTTL)
+	}
+	return time.Time{}
+}
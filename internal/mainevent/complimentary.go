@@ -0,0 +1,170 @@
+package mainevent
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// complimentaryColumns are the columns of a complimentary
+// import and whether they are required.
+var complimentaryColumns = map[string]bool{
+	"nama":              true,
+	"email":             true,
+	"nomor_telepon":     true,
+	"nomor_identitas":   true,
+	"asal_institusi":    true,
+	"jumlah_tiket":      false,
+	"disabilitas":       false,
+	"akomodasi":         false,
+	"catatan_akomodasi": false,
+}
+
+// ReadComplimentaryCSV reads the orders of a complimentary
+// import from the given CSV. Its header row names the columns,
+// in any order: nama, email, nomor_telepon, nomor_identitas
+// and asal_institusi, and optionally jumlah_tiket (1 by
+// default), disabilitas (by name, "Tidak ada" by default),
+// akomodasi (separated by commas) and catatan_akomodasi.
+// Other columns are ignored. The orders are in the order of
+// the rows, the first one being the second row of the file.
+//
+// It returns ErrInvalidImport if the file is not a CSV, a
+// column is missing or there is no order, and ImportError if
+// some rows can not be read.
+func ReadComplimentaryCSV(r io.Reader) ([]MainEvent, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, ErrInvalidImport
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidImport, err.Error())
+	}
+
+	// spreadsheets may start the file with a byte order mark
+	columns := make(map[string]int, len(header))
+	for i, h := range header {
+		h = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(h, "\ufeff")))
+		if _, ok := complimentaryColumns[h]; ok {
+			columns[h] = i
+		}
+	}
+	for c, required := range complimentaryColumns {
+		if _, ok := columns[c]; required && !ok {
+			return nil, ErrInvalidImport
+		}
+	}
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidImport, err.Error())
+	}
+
+	// spreadsheets often leave blank rows at the end, the
+	// blank rows in between are kept so that the rows keep
+	// their number
+	for len(records) > 0 && isEmptyRecord(records[len(records)-1]) {
+		records = records[:len(records)-1]
+	}
+
+	var (
+		orders    []MainEvent
+		rowErrors []ImportRowError
+	)
+	for i, record := range records {
+		record := record
+		get := func(column string) string {
+			i, ok := columns[column]
+			if !ok || i >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[i])
+		}
+
+		order, err := complimentaryOrder(get)
+		if err != nil {
+			rowErrors = append(rowErrors, ImportRowError{Baris: i + 2, Err: err})
+			continue
+		}
+		orders = append(orders, order)
+	}
+
+	if len(rowErrors) > 0 {
+		return nil, ImportError{Rows: rowErrors}
+	}
+	if len(orders) == 0 {
+		return nil, ErrInvalidImport
+	}
+
+	return orders, nil
+}
+
+// complimentaryOrder returns the order of a row of a
+// complimentary import, get returns the value of the row in
+// the given column.
+func complimentaryOrder(get func(column string) string) (MainEvent, error) {
+	order := MainEvent{
+		Nama:             get("nama"),
+		Email:            get("email"),
+		NomorTelepon:     get("nomor_telepon"),
+		NomorIdentitas:   get("nomor_identitas"),
+		AsalInstitusi:    get("asal_institusi"),
+		CatatanAkomodasi: get("catatan_akomodasi"),
+		JumlahTiket:      1,
+		Disabilitas:      NoneDisability,
+		Type:             TypeComplimentary,
+		Status:           StatusSettlement,
+	}
+
+	if v := get("jumlah_tiket"); v != "" {
+		jumlahTiket, err := strconv.Atoi(v)
+		if err != nil {
+			return MainEvent{}, ErrInvalidMainEventJumlahTiket
+		}
+		order.JumlahTiket = jumlahTiket
+	}
+
+	if v := get("disabilitas"); v != "" {
+		disability, err := parseDisabilityName(v)
+		if err != nil {
+			return MainEvent{}, err
+		}
+		order.Disabilitas = disability
+	}
+
+	for _, a := range strings.FieldsFunc(get("akomodasi"), func(r rune) bool { return r == ',' || r == ';' }) {
+		if a = strings.TrimSpace(a); a != "" {
+			order.Akomodasi = append(order.Akomodasi, Accommodation(a))
+		}
+	}
+
+	return order, nil
+}
+
+// parseDisabilityName returns the disability with the given
+// name, ignoring the case.
+func parseDisabilityName(name string) (Disability, error) {
+	for d := range DisabilityList {
+		if strings.EqualFold(d.String(), name) {
+			return d, nil
+		}
+	}
+	return DisabilityUnknown, ErrInvalidMainEventDisability
+}
+
+// isEmptyRecord returns whether all values of the given CSV
+// record are blank.
+func isEmptyRecord(record []string) bool {
+	for _, v := range record {
+		if strings.TrimSpace(v) != "" {
+			return false
+		}
+	}
+	return true
+}
@@ -1,6 +1,9 @@
 package mainevent
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
 
 // Followings are the known errors returned from mainevent.
 var (
@@ -48,6 +51,10 @@ var (
 	// status is invalid.
 	ErrInvalidMainEventStatus = errors.New("invalid main event status")
 
+	// ErrInvalidMainEventDisability is returned when the given
+	// disability is unknown.
+	ErrInvalidMainEventDisability = errors.New("invalid main event disability")
+
 	// errInvalidMainEventImageURI is returned when the given main event
 	// image uri is invalid.
 	ErrInvalidMainEventImageURI = errors.New("invalid main event image uri")
@@ -76,7 +83,216 @@ var (
 	//is sold out
 	ErrTicketSoldOut = errors.New("ticket sold out")
 
+	// ErrInvalidCursor is returned when the given cursor is
+	// malformed or was issued for another sort.
+	ErrInvalidCursor = errors.New("invalid cursor")
+
+	// ErrInvalidSort is returned when the given sort field or
+	// direction is invalid.
+	ErrInvalidSort = errors.New("invalid sort")
+
+	// ErrIllegalStatusTransition is returned when the main
+	// event status may not change into the given status, e.g.
+	// from unpaid directly to settlement.
+	ErrIllegalStatusTransition = errors.New("illegal status transition")
+
+	// ErrConflict is returned when the main event has been
+	// updated by another request since it was read.
+	ErrConflict = errors.New("conflict")
+
+	// ErrTicketRefunded is returned when the given ticket
+	// number has been refunded.
+	ErrTicketRefunded = errors.New("ticket refunded")
+
+	// ErrInvalidRefundID is returned when the given refund id
+	// is invalid.
+	ErrInvalidRefundID = errors.New("invalid refund id")
+
+	// ErrInvalidRefundNomorTiket is returned when the given
+	// ticket numbers to refund are empty, duplicated or not
+	// of the mainevent.
+	ErrInvalidRefundNomorTiket = errors.New("invalid refund nomor tiket")
+
+	// ErrInvalidRefundStatus is returned when the given refund
+	// status is invalid.
+	ErrInvalidRefundStatus = errors.New("invalid refund status")
+
+	// ErrInvalidRefundMethod is returned when the given refund
+	// method is invalid.
+	ErrInvalidRefundMethod = errors.New("invalid refund method")
+
+	// ErrRefundNotAllowed is returned when a refund is
+	// requested for a mainevent that is not settled or is
+	// complimentary, or for a ticket that is checked in,
+	// refunded or already requested to be refunded.
+	ErrRefundNotAllowed = errors.New("refund not allowed")
+
+	// ErrRefundAlreadyProcessed is returned when the given
+	// refund is no longer waiting for approval.
+	ErrRefundAlreadyProcessed = errors.New("refund already processed")
+
+	// ErrInvalidHolder is returned when the given holder has
+	// no name, an invalid email, or a ticket number that is
+	// not of the mainevent or given twice.
+	ErrInvalidHolder = errors.New("invalid holder")
+
+	// ErrHolderChangeNotAllowed is returned when the holders
+	// are assigned to a mainevent that is not settled, or to
+	// a ticket that is checked in or refunded.
+	ErrHolderChangeNotAllowed = errors.New("holder change not allowed")
+
+	// ErrHolderTransferClosed is returned when the holders
+	// are assigned after the transfer cutoff.
+	ErrHolderTransferClosed = errors.New("holder transfer closed")
+
+	// ErrTicketsAvailable is returned when a buyer joins the
+	// waitlist while there are still enough tickets on sale.
+	ErrTicketsAvailable = errors.New("tickets available")
+
+	// ErrWaitlistAlreadyJoined is returned when the given
+	// email is already waiting or holding an offer.
+	ErrWaitlistAlreadyJoined = errors.New("waitlist already joined")
+
+	// ErrInvalidWaitlistToken is returned when the given claim
+	// token is unknown, already claimed, or used with another
+	// email or more tickets than offered.
+	ErrInvalidWaitlistToken = errors.New("invalid waitlist token")
+
+	// ErrWaitlistOfferExpired is returned when the offer of
+	// the given claim token has expired.
+	ErrWaitlistOfferExpired = errors.New("waitlist offer expired")
+
+	// ErrInvalidGroupOrderID is returned when the given group
+	// order id is invalid.
+	ErrInvalidGroupOrderID = errors.New("invalid group order id")
+
+	// ErrInvalidGroupAttendee is returned when an attendee of
+	// the given group order has no name, an invalid email or
+	// nomor identitas, or is given twice.
+	ErrInvalidGroupAttendee = errors.New("invalid group attendee")
+
+	// ErrInvalidGroupSize is returned when the given group
+	// order has fewer attendees than a group order needs, or
+	// more than its institution allows.
+	ErrInvalidGroupSize = errors.New("invalid group size")
+
+	// ErrGroupQuotaExceeded is returned when the given group
+	// order takes the group orders of its institution over
+	// the institution ticket quota.
+	ErrGroupQuotaExceeded = errors.New("group quota exceeded")
+
+	// ErrInvalidGroupStatus is returned when the given group
+	// order status is invalid.
+	ErrInvalidGroupStatus = errors.New("invalid group status")
+
+	// ErrGroupOrderAlreadyProcessed is returned when the given
+	// group order is no longer waiting for approval.
+	ErrGroupOrderAlreadyProcessed = errors.New("group order already processed")
+
+	// ErrInvalidInstitutionID is returned when the given
+	// institution id is invalid.
+	ErrInvalidInstitutionID = errors.New("invalid institution id")
+
+	// ErrInvalidInstitution is returned when the given
+	// institution has no name or invalid limits.
+	ErrInvalidInstitution = errors.New("invalid institution")
+
+	// ErrInstitutionExists is returned when an institution
+	// with the given name already exists.
+	ErrInstitutionExists = errors.New("institution exists")
+
+	// ErrInvalidAccommodation is returned when the given
+	// accommodations are unknown or repeated, or their note is
+	// too long.
+	ErrInvalidAccommodation = errors.New("invalid accommodation")
+
+	// ErrInvalidSeat is returned when the given seats are
+	// repeated or do not match the number of tickets.
+	ErrInvalidSeat = errors.New("invalid seat")
+
+	// ErrSeatNotAvailable is returned when a given seat is
+	// unknown, taken by another order, or accessible while
+	// the buyer does not need accessible seating.
+	ErrSeatNotAvailable = errors.New("seat not available")
+
+	// ErrInvalidSeatLayout is returned when the given seat
+	// layout is empty or has unnamed, repeated or invalid
+	// sections, rows or seats.
+	ErrInvalidSeatLayout = errors.New("invalid seat layout")
+
+	// ErrSeatInUse is returned when the given seat layout
+	// removes a seat held or seated by a mainevent.
+	ErrSeatInUse = errors.New("seat in use")
+
+	// ErrPurchaseLimitReached is returned when the given
+	// order takes the tickets of its buyer over the purchase
+	// limit. The service returns it wrapped in a
+	// PurchaseLimitError.
+	ErrPurchaseLimitReached = errors.New("purchase limit reached")
+
+	// ErrInvalidBuyerLoginToken is returned when the given
+	// login link is unknown, expired or already used.
+	ErrInvalidBuyerLoginToken = errors.New("invalid buyer login token")
+
+	// ErrInvalidBuyerSession is returned when the given buyer
+	// session is unknown or expired.
+	ErrInvalidBuyerSession = errors.New("invalid buyer session")
+
+	// ErrInvalidImport is returned when the given import has
+	// no row or a row that can not be imported. The service
+	// returns the latter wrapped in an ImportError.
+	ErrInvalidImport = errors.New("invalid import")
+
+	// ErrComplimentaryExists is returned when the buyer of the
+	// given complimentary order already has one for the event.
+	ErrComplimentaryExists = errors.New("complimentary order exists")
+
 	//ErrTickeetNotAlreadyAccepted is returned when the given ticket
 	//is not already accepted
 	ErrTicketNotAlreadyAccepted = errors.New("ticket not already accepted")
 )
+
+// PurchaseLimitError is returned when the given order takes
+// the tickets of its buyer, by identity number or by email,
+// over the purchase limit. It matches ErrPurchaseLimitReached
+// with errors.Is.
+type PurchaseLimitError struct {
+	// Remaining is the number of tickets the buyer can still
+	// buy.
+	Remaining int
+}
+
+// Error returns the message of the error.
+func (e PurchaseLimitError) Error() string {
+	return fmt.Sprintf("%s, %d tickets remaining", ErrPurchaseLimitReached, e.Remaining)
+}
+
+// Is reports whether the target is ErrPurchaseLimitReached.
+func (e PurchaseLimitError) Is(target error) bool {
+	return target == ErrPurchaseLimitReached
+}
+
+// ImportError is returned when some rows of the given import
+// can not be imported. It matches ErrInvalidImport with
+// errors.Is.
+type ImportError struct {
+	Rows []ImportRowError
+}
+
+// ImportRowError is the error of a row of an import.
+type ImportRowError struct {
+	// Baris is the number of the row in the imported file,
+	// the header being the first row.
+	Baris int
+	Err   error
+}
+
+// Error returns the message of the error.
+func (e ImportError) Error() string {
+	return fmt.Sprintf("%s, %d invalid rows", ErrInvalidImport, len(e.Rows))
+}
+
+// Is reports whether the target is ErrInvalidImport.
+func (e ImportError) Is(target error) bool {
+	return target == ErrInvalidImport
+}
@@ -0,0 +1,111 @@
+package mainevent
+
+import "time"
+
+// GroupOrder is an order of a campus organisation or other
+// institution, submitted by a coordinator on behalf of the
+// attendees. It is paid with a single invoice, a mainevent of
+// TypeGroup, and each attendee gets an individual ticket once
+// the invoice is settled.
+type GroupOrder struct {
+	ID int64
+
+	// MainEventID is the ID of the invoice, it is zero until
+	// the order is approved.
+	MainEventID int64
+
+	// Nama, NomorIdentitas, Email and NomorTelepon are of the
+	// coordinator.
+	Nama           string
+	NomorIdentitas string
+	AsalInstitusi  string
+	Email          string
+	NomorTelepon   string
+
+	// Attendees are the attendees in the order their tickets
+	// are assigned.
+	Attendees []GroupAttendee
+
+	// HargaTiket is the price of a ticket of the group price
+	// tier of the order.
+	HargaTiket int64
+	TotalHarga int64
+
+	Status GroupStatus
+
+	// Catatan is the note of the committee on the approval or
+	// rejection.
+	Catatan string
+
+	CreateTime time.Time
+	UpdateTime time.Time
+}
+
+// GroupAttendee is an attendee of a group order.
+type GroupAttendee struct {
+	Nama           string
+	Email          string
+	NomorIdentitas string
+}
+
+// GroupStatus denotes status of a group order.
+type GroupStatus string
+
+// Followings are the known group order status.
+const (
+	GroupStatusUnknown GroupStatus = ""
+
+	// GroupStatusRequested means the order waits for the
+	// approval of the committee.
+	GroupStatusRequested GroupStatus = "requested"
+
+	// GroupStatusApproved means the invoice of the order has
+	// been issued.
+	GroupStatusApproved GroupStatus = "approved"
+
+	// GroupStatusRejected means the committee has rejected
+	// the order.
+	GroupStatusRejected GroupStatus = "rejected"
+
+	// GroupStatusExpired means the invoice of the order was
+	// not paid in time and has been deleted.
+	GroupStatusExpired GroupStatus = "expired"
+)
+
+// GroupStatusList is a list of valid group order status.
+var GroupStatusList = map[GroupStatus]struct{}{
+	GroupStatusRequested: {},
+	GroupStatusApproved:  {},
+	GroupStatusRejected:  {},
+	GroupStatusExpired:   {},
+}
+
+// String returns string representaion of a group order status.
+func (s GroupStatus) String() string {
+	return string(s)
+}
+
+// Institution is the group order policy of an institution.
+// The institutions without one follow the default policy.
+type Institution struct {
+	ID int64
+
+	// Nama is matched case-insensitively with the
+	// AsalInstitusi of the group orders.
+	Nama string
+
+	// MaxTiket is the most attendees of a group order, and
+	// KuotaTiket the most tickets of all the group orders of
+	// the institution that are not rejected. Zero KuotaTiket
+	// means no limit.
+	MaxTiket   int
+	KuotaTiket int
+
+	// RequireApproval holds the group orders until the
+	// committee approves them, otherwise their invoices are
+	// issued right away.
+	RequireApproval bool
+
+	CreateTime time.Time
+	UpdateTime time.Time
+}
@@ -0,0 +1,100 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/tedxub2023/global/helper"
+	"github.com/tedxub2023/global/logger"
+	"github.com/tedxub2023/internal/mainevent"
+)
+
+type accommodationReportHandler struct {
+	mainevent mainevent.Service
+}
+
+func (h *accommodationReportHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.handleGetAccommodationReport(w, r)
+	default:
+		helper.WriteErrorResponse(w, http.StatusMethodNotAllowed, []string{errMethodNotAllowed.Error()})
+	}
+}
+
+func (h *accommodationReportHandler) handleGetAccommodationReport(w http.ResponseWriter, r *http.Request) {
+	// add timeout to context
+	ctx, cancel := context.WithTimeout(r.Context(), 3000*time.Millisecond)
+	defer cancel()
+
+	var (
+		err        error           // stores error in this handler
+		resBody    []byte          // stores response body to write
+		statusCode = http.StatusOK // stores response status code
+	)
+
+	// write response
+	defer func() {
+		// error
+		if err != nil {
+			logger.Error(ctx, "failed to get accommodation report", err)
+			helper.WriteErrorResponse(w, statusCode, []string{err.Error()})
+			return
+		}
+		// success
+		helper.WriteResponse(w, resBody, statusCode, helper.JSONContentTypeDecorator)
+	}()
+
+	// prepare channels for main go routine
+	resChan := make(chan mainevent.AccommodationReport, 1)
+	errChan := make(chan error, 1)
+
+	go func() {
+		res, err := h.mainevent.GetAccommodationReport(ctx)
+		if err != nil {
+			// determine error and status code, by default its internal error
+			parsedErr := errInternalServer
+			statusCode = http.StatusInternalServerError
+			if v, ok := mapHTTPError[err]; ok {
+				parsedErr = v
+				statusCode = http.StatusBadRequest
+			}
+
+			// log the actual error if its internal error
+			if statusCode == http.StatusInternalServerError {
+				logger.Error(ctx, "internal error from GetAccommodationReport", err)
+			}
+
+			errChan <- parsedErr
+			return
+		}
+
+		resChan <- res
+	}()
+
+	// wait and handle main go routine
+	select {
+	case <-ctx.Done():
+		statusCode = http.StatusGatewayTimeout
+		err = errRequestTimeout
+	case err = <-errChan:
+	case res := <-resChan:
+		// format the reports of each disability
+		var (
+			reports []disabilityReportHTTP
+			meta    accommodationReportHTTP
+		)
+		reports, meta, err = formatAccommodationReport(res)
+		if err != nil {
+			return
+		}
+
+		// construct response data
+		resBody, err = json.Marshal(helper.ResponseEnvelope{
+			Data: reports,
+			Meta: meta,
+		})
+	}
+}
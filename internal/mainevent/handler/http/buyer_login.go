@@ -0,0 +1,199 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/tedxub2023/global/helper"
+	"github.com/tedxub2023/global/logger"
+	"github.com/tedxub2023/internal/mainevent"
+)
+
+type buyerLoginHandler struct {
+	mainevent mainevent.Service
+}
+
+func (h *buyerLoginHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+		h.handleRequestBuyerLogin(w, r)
+	default:
+		helper.WriteErrorResponse(w, http.StatusMethodNotAllowed, []string{errMethodNotAllowed.Error()})
+	}
+}
+
+func (h *buyerLoginHandler) handleRequestBuyerLogin(w http.ResponseWriter, r *http.Request) {
+	// add timeout to context
+	ctx, cancel := context.WithTimeout(r.Context(), 3000*time.Millisecond)
+	defer cancel()
+
+	var (
+		err        error           // stores error in this handler
+		resBody    []byte          // stores response body to write
+		statusCode = http.StatusOK // stores response status code
+	)
+
+	// write response
+	defer func() {
+		if err != nil {
+			logger.Error(ctx, "failed to request buyer login", err)
+			helper.WriteErrorResponse(w, statusCode, []string{err.Error()})
+			return
+		}
+		// success
+		helper.WriteResponse(w, resBody, statusCode, helper.JSONContentTypeDecorator)
+	}()
+
+	// prepare channels for main go routine
+	resChan := make(chan struct{}, 1)
+	errChan := make(chan error, 1)
+
+	go func() {
+		// read body
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			statusCode = http.StatusBadRequest
+			errChan <- errBadRequest
+			return
+		}
+
+		// unmarshall body
+		request := buyerLoginHTTP{}
+		err = json.Unmarshal(body, &request)
+		if err != nil || request.Email == nil {
+			statusCode = http.StatusBadRequest
+			errChan <- errBadRequest
+			return
+		}
+
+		// the response is the same whether the buyer exists
+		err = h.mainevent.RequestBuyerLogin(ctx, *request.Email)
+		if err != nil {
+			statusCode, err = buyerError(ctx, "RequestBuyerLogin", err)
+			errChan <- err
+			return
+		}
+
+		resChan <- struct{}{}
+	}()
+
+	// wait and handle main go routine
+	select {
+	case <-ctx.Done():
+		statusCode = http.StatusGatewayTimeout
+		err = errRequestTimeout
+	case err = <-errChan:
+	case <-resChan:
+		resBody, err = json.Marshal(helper.ResponseEnvelope{
+			Status: "Success",
+		})
+	}
+}
+
+type buyerLoginVerifyHandler struct {
+	mainevent mainevent.Service
+}
+
+func (h *buyerLoginVerifyHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+		h.handleVerifyBuyerLogin(w, r)
+	default:
+		helper.WriteErrorResponse(w, http.StatusMethodNotAllowed, []string{errMethodNotAllowed.Error()})
+	}
+}
+
+func (h *buyerLoginVerifyHandler) handleVerifyBuyerLogin(w http.ResponseWriter, r *http.Request) {
+	// add timeout to context
+	ctx, cancel := context.WithTimeout(r.Context(), 3000*time.Millisecond)
+	defer cancel()
+
+	var (
+		err        error           // stores error in this handler
+		resBody    []byte          // stores response body to write
+		statusCode = http.StatusOK // stores response status code
+	)
+
+	// write response
+	defer func() {
+		if err != nil {
+			logger.Error(ctx, "failed to verify buyer login", err)
+			helper.WriteErrorResponse(w, statusCode, []string{err.Error()})
+			return
+		}
+		// success
+		helper.WriteResponse(w, resBody, statusCode, helper.JSONContentTypeDecorator)
+	}()
+
+	// prepare channels for main go routine
+	resChan := make(chan mainevent.BuyerLogin, 1)
+	errChan := make(chan error, 1)
+
+	go func() {
+		// read body
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			statusCode = http.StatusBadRequest
+			errChan <- errBadRequest
+			return
+		}
+
+		// unmarshall body
+		request := buyerLoginHTTP{}
+		err = json.Unmarshal(body, &request)
+		if err != nil || request.Token == nil {
+			statusCode = http.StatusBadRequest
+			errChan <- errBadRequest
+			return
+		}
+
+		res, err := h.mainevent.VerifyBuyerLogin(ctx, *request.Token)
+		if err != nil {
+			statusCode, err = buyerError(ctx, "VerifyBuyerLogin", err)
+			errChan <- err
+			return
+		}
+
+		resChan <- res
+	}()
+
+	// wait and handle main go routine
+	select {
+	case <-ctx.Done():
+		statusCode = http.StatusGatewayTimeout
+		err = errRequestTimeout
+	case err = <-errChan:
+	case res := <-resChan:
+		resBody, err = json.Marshal(helper.ResponseEnvelope{
+			Status: "Success",
+			Data: buyerSessionHTTP{
+				Token:      &res.SessionToken,
+				ExpireTime: &res.SessionExpireTime,
+			},
+		})
+	}
+}
+
+// buyerError returns the HTTP status code and error of the
+// given error from the mainevent service method on behalf of
+// a buyer, logging the internal errors.
+func buyerError(ctx context.Context, method string, err error) (int, error) {
+	// determine error and status code, by default its internal error
+	parsedErr, ok := mapHTTPError[err]
+	if !ok {
+		logger.Error(ctx, "internal error from "+method, err)
+		return http.StatusInternalServerError, errInternalServer
+	}
+
+	switch parsedErr {
+	case errInvalidBuyerLoginToken, errInvalidBuyerSession:
+		return http.StatusUnauthorized, parsedErr
+	case errConflict:
+		return http.StatusConflict, parsedErr
+	}
+
+	return http.StatusBadRequest, parsedErr
+}
@@ -0,0 +1,248 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/tedxub2023/global/auth"
+	"github.com/tedxub2023/global/helper"
+	"github.com/tedxub2023/global/logger"
+	"github.com/tedxub2023/internal/mainevent"
+)
+
+type buyerPaymentProofHandler struct {
+	mainevent mainevent.Service
+}
+
+func (h *buyerPaymentProofHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	maineventID, err := strconv.ParseInt(vars["id"], 10, 64)
+	if err != nil {
+		logger.Error(r.Context(), "failed to parse mainevent ID", err, logger.Fields{"id": vars["id"]})
+		helper.WriteErrorResponse(w, http.StatusBadRequest, []string{errInvalidMainEventID.Error()})
+		return
+	}
+	logger.AddFields(r.Context(), logger.Fields{"mainevent_id": maineventID})
+
+	switch r.Method {
+	case http.MethodPut:
+		h.handleUploadBuyerPaymentProof(w, r, maineventID)
+	default:
+		helper.WriteErrorResponse(w, http.StatusMethodNotAllowed, []string{errMethodNotAllowed.Error()})
+	}
+}
+
+func (h *buyerPaymentProofHandler) handleUploadBuyerPaymentProof(w http.ResponseWriter, r *http.Request, maineventID int64) {
+	// add timeout to context
+	ctx, cancel := context.WithTimeout(r.Context(), 5000*time.Millisecond)
+	defer cancel()
+
+	var (
+		err        error           // stores error in this handler
+		resBody    []byte          // stores response body to write
+		statusCode = http.StatusOK // stores response status code
+	)
+
+	// write response
+	defer func() {
+		if err != nil {
+			logger.Error(ctx, "failed to upload buyer payment proof", err)
+			helper.WriteErrorResponse(w, statusCode, []string{err.Error()})
+			return
+		}
+		// success
+		helper.WriteResponse(w, resBody, statusCode, helper.JSONContentTypeDecorator)
+	}()
+
+	// prepare channels for main go routine
+	resChan := make(chan struct{}, 1)
+	errChan := make(chan error, 1)
+
+	go func() {
+		// read body
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			statusCode = http.StatusBadRequest
+			errChan <- errBadRequest
+			return
+		}
+
+		// unmarshall body
+		request := paymentProofHTTP{}
+		err = json.Unmarshal(body, &request)
+		if err != nil || request.ImageURI == nil {
+			statusCode = http.StatusBadRequest
+			errChan <- errBadRequest
+			return
+		}
+
+		err = h.mainevent.UploadBuyerPaymentProof(ctx, auth.BearerToken(r), maineventID, *request.ImageURI)
+		if err != nil {
+			statusCode, err = buyerError(ctx, "UploadBuyerPaymentProof", err)
+			errChan <- err
+			return
+		}
+
+		resChan <- struct{}{}
+	}()
+
+	// wait and handle main go routine
+	select {
+	case <-ctx.Done():
+		statusCode = http.StatusGatewayTimeout
+		err = errRequestTimeout
+	case err = <-errChan:
+	case <-resChan:
+		resBody, err = json.Marshal(helper.ResponseEnvelope{
+			Status: "Success",
+		})
+	}
+}
+
+type buyerTicketHandler struct {
+	mainevent mainevent.Service
+}
+
+func (h *buyerTicketHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	maineventID, err := strconv.ParseInt(vars["id"], 10, 64)
+	if err != nil {
+		logger.Error(r.Context(), "failed to parse mainevent ID", err, logger.Fields{"id": vars["id"]})
+		helper.WriteErrorResponse(w, http.StatusBadRequest, []string{errInvalidMainEventID.Error()})
+		return
+	}
+	logger.AddFields(r.Context(), logger.Fields{"mainevent_id": maineventID})
+
+	switch r.Method {
+	case http.MethodGet:
+		h.handleGetBuyerTicket(w, r, maineventID)
+	default:
+		helper.WriteErrorResponse(w, http.StatusMethodNotAllowed, []string{errMethodNotAllowed.Error()})
+	}
+}
+
+func (h *buyerTicketHandler) handleGetBuyerTicket(w http.ResponseWriter, r *http.Request, maineventID int64) {
+	// add timeout to context, a missing PDF is rendered again
+	ctx, cancel := context.WithTimeout(r.Context(), 15000*time.Millisecond)
+	defer cancel()
+
+	var (
+		err        error           // stores error in this handler
+		resBody    []byte          // stores response body to write
+		statusCode = http.StatusOK // stores response status code
+	)
+
+	// write response
+	defer func() {
+		if err != nil {
+			logger.Error(ctx, "failed to get buyer ticket", err)
+			helper.WriteErrorResponse(w, statusCode, []string{err.Error()})
+			return
+		}
+		// success
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"tiket-%d.pdf\"", maineventID))
+		helper.WriteResponse(w, resBody, statusCode, helper.NewContentTypeDecorator("application/pdf"))
+	}()
+
+	// prepare channels for main go routine
+	resChan := make(chan []byte, 1)
+	errChan := make(chan error, 1)
+
+	go func() {
+		res, err := h.mainevent.GetBuyerTicketPDF(ctx, auth.BearerToken(r), maineventID)
+		if err != nil {
+			statusCode, err = buyerError(ctx, "GetBuyerTicketPDF", err)
+			errChan <- err
+			return
+		}
+
+		resChan <- res
+	}()
+
+	// wait and handle main go routine
+	select {
+	case <-ctx.Done():
+		statusCode = http.StatusGatewayTimeout
+		err = errRequestTimeout
+	case err = <-errChan:
+	case resBody = <-resChan:
+	}
+}
+
+type buyerMailHandler struct {
+	mainevent mainevent.Service
+}
+
+func (h *buyerMailHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	maineventID, err := strconv.ParseInt(vars["id"], 10, 64)
+	if err != nil {
+		logger.Error(r.Context(), "failed to parse mainevent ID", err, logger.Fields{"id": vars["id"]})
+		helper.WriteErrorResponse(w, http.StatusBadRequest, []string{errInvalidMainEventID.Error()})
+		return
+	}
+	logger.AddFields(r.Context(), logger.Fields{"mainevent_id": maineventID})
+
+	switch r.Method {
+	case http.MethodPost:
+		h.handleResendBuyerMail(w, r, maineventID)
+	default:
+		helper.WriteErrorResponse(w, http.StatusMethodNotAllowed, []string{errMethodNotAllowed.Error()})
+	}
+}
+
+func (h *buyerMailHandler) handleResendBuyerMail(w http.ResponseWriter, r *http.Request, maineventID int64) {
+	// add timeout to context
+	ctx, cancel := context.WithTimeout(r.Context(), 3000*time.Millisecond)
+	defer cancel()
+
+	var (
+		err        error           // stores error in this handler
+		resBody    []byte          // stores response body to write
+		statusCode = http.StatusOK // stores response status code
+	)
+
+	// write response
+	defer func() {
+		if err != nil {
+			logger.Error(ctx, "failed to resend buyer mail", err)
+			helper.WriteErrorResponse(w, statusCode, []string{err.Error()})
+			return
+		}
+		// success
+		helper.WriteResponse(w, resBody, statusCode, helper.JSONContentTypeDecorator)
+	}()
+
+	// prepare channels for main go routine
+	resChan := make(chan struct{}, 1)
+	errChan := make(chan error, 1)
+
+	go func() {
+		err := h.mainevent.ResendBuyerMail(ctx, auth.BearerToken(r), maineventID)
+		if err != nil {
+			statusCode, err = buyerError(ctx, "ResendBuyerMail", err)
+			errChan <- err
+			return
+		}
+
+		resChan <- struct{}{}
+	}()
+
+	// wait and handle main go routine
+	select {
+	case <-ctx.Done():
+		statusCode = http.StatusGatewayTimeout
+		err = errRequestTimeout
+	case err = <-errChan:
+	case <-resChan:
+		resBody, err = json.Marshal(helper.ResponseEnvelope{
+			Status: "Success",
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/tedxub2023/global/auth"
+	"github.com/tedxub2023/global/helper"
+	"github.com/tedxub2023/global/logger"
+	"github.com/tedxub2023/internal/mainevent"
+)
+
+type buyerMainEventsHandler struct {
+	mainevent mainevent.Service
+}
+
+func (h *buyerMainEventsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.handleGetBuyerMainEvents(w, r)
+	default:
+		helper.WriteErrorResponse(w, http.StatusMethodNotAllowed, []string{errMethodNotAllowed.Error()})
+	}
+}
+
+func (h *buyerMainEventsHandler) handleGetBuyerMainEvents(w http.ResponseWriter, r *http.Request) {
+	// add timeout to context
+	ctx, cancel := context.WithTimeout(r.Context(), 3000*time.Millisecond)
+	defer cancel()
+
+	var (
+		err        error           // stores error in this handler
+		resBody    []byte          // stores response body to write
+		statusCode = http.StatusOK // stores response status code
+	)
+
+	// write response
+	defer func() {
+		// error
+		if err != nil {
+			logger.Error(ctx, "failed to get buyer mainevents", err)
+			helper.WriteErrorResponse(w, statusCode, []string{err.Error()})
+			return
+		}
+		// success
+		helper.WriteResponse(w, resBody, statusCode, helper.JSONContentTypeDecorator)
+	}()
+
+	// prepare channels for main go routine
+	resChan := make(chan []mainevent.MainEvent, 1)
+	errChan := make(chan error, 1)
+
+	go func() {
+		res, err := h.mainevent.GetBuyerMainEvents(ctx, auth.BearerToken(r))
+		if err != nil {
+			statusCode, err = buyerError(ctx, "GetBuyerMainEvents", err)
+			errChan <- err
+			return
+		}
+
+		resChan <- res
+	}()
+
+	// wait and handle main go routine
+	select {
+	case <-ctx.Done():
+		statusCode = http.StatusGatewayTimeout
+		err = errRequestTimeout
+	case err = <-errChan:
+	case res := <-resChan:
+		// format each mainevents
+		mainevents := make([]mainEventHTTP, 0, len(res))
+		for _, r := range res {
+			var m mainEventHTTP
+			m, err = formatMainEvent(r)
+			if err != nil {
+				return
+			}
+			mainevents = append(mainevents, m)
+		}
+
+		// construct response data
+		resBody, err = json.Marshal(helper.ResponseEnvelope{
+			Data: mainevents,
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/tedxub2023/global/auth"
+	"github.com/tedxub2023/global/helper"
+	"github.com/tedxub2023/global/logger"
+	"github.com/tedxub2023/internal/mainevent"
+	"github.com/tedxub2023/internal/transaction"
+)
+
+type buyerTransactionsHandler struct {
+	mainevent mainevent.Service
+}
+
+func (h *buyerTransactionsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.handleGetBuyerTransactions(w, r)
+	default:
+		helper.WriteErrorResponse(w, http.StatusMethodNotAllowed, []string{errMethodNotAllowed.Error()})
+	}
+}
+
+func (h *buyerTransactionsHandler) handleGetBuyerTransactions(w http.ResponseWriter, r *http.Request) {
+	// add timeout to context
+	ctx, cancel := context.WithTimeout(r.Context(), 3000*time.Millisecond)
+	defer cancel()
+
+	var (
+		err        error           // stores error in this handler
+		resBody    []byte          // stores response body to write
+		statusCode = http.StatusOK // stores response status code
+	)
+
+	// write response
+	defer func() {
+		// error
+		if err != nil {
+			logger.Error(ctx, "failed to get buyer transactions", err)
+			helper.WriteErrorResponse(w, statusCode, []string{err.Error()})
+			return
+		}
+		// success
+		helper.WriteResponse(w, resBody, statusCode, helper.JSONContentTypeDecorator)
+	}()
+
+	// prepare channels for main go routine
+	resChan := make(chan []transaction.Transaction, 1)
+	errChan := make(chan error, 1)
+
+	go func() {
+		res, err := h.mainevent.GetBuyerTransactions(ctx, auth.BearerToken(r))
+		if err != nil {
+			statusCode, err = buyerError(ctx, "GetBuyerTransactions", err)
+			errChan <- err
+			return
+		}
+
+		resChan <- res
+	}()
+
+	// wait and handle main go routine
+	select {
+	case <-ctx.Done():
+		statusCode = http.StatusGatewayTimeout
+		err = errRequestTimeout
+	case err = <-errChan:
+	case res := <-resChan:
+		// format each transactions
+		transactions := make([]buyerTransactionHTTP, 0, len(res))
+		for _, t := range res {
+			transactions = append(transactions, formatBuyerTransaction(t))
+		}
+
+		// construct response data
+		resBody, err = json.Marshal(helper.ResponseEnvelope{
+			Data: transactions,
+		})
+	}
+}
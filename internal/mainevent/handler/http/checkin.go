@@ -3,7 +3,6 @@ package http
 import (
 	"context"
 	"encoding/json"
-	"log"
 	"net/http"
 	"net/url"
 	"strconv"
@@ -11,6 +10,8 @@ import (
 
 	"github.com/gorilla/mux"
 	"github.com/tedxub2023/global/helper"
+	"github.com/tedxub2023/global/logger"
+	"github.com/tedxub2023/global/metrics"
 	"github.com/tedxub2023/internal/mainevent"
 )
 
@@ -22,10 +23,11 @@ func (h *checkInHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	id, err := strconv.ParseInt(vars["id"], 10, 64)
 	if err != nil {
-		log.Printf("[CheckIn HTTP][checkInHandler] Failed to parse mainevent ID. ID: %s. Err: %s\n", vars["id"], err.Error())
+		logger.Error(r.Context(), "failed to parse mainevent ID", err, logger.Fields{"id": vars["id"]})
 		helper.WriteErrorResponse(w, http.StatusBadRequest, []string{errInvalidMainEventID.Error()})
 		return
 	}
+	logger.AddFields(r.Context(), logger.Fields{"mainevent_id": id})
 
 	switch r.Method {
 	case http.MethodPatch:
@@ -41,18 +43,16 @@ func (h *checkInHandler) handleUpdateCheckInStatus(w http.ResponseWriter, r *htt
 
 	var (
 		err        error           // stores error in this handler
-		source     string          // stores request source
 		resBody    []byte          // stores response body to write
 		statusCode = http.StatusOK // stores response status code
-
 	)
 
-	resChan := make(chan string, 1)
+	resChan := make(chan mainevent.CheckIn, 1)
 	errChan := make(chan error, 1)
 
 	defer func() {
 		if err != nil {
-			log.Printf("[CheckIn HTTP][handleUpdateCheckInStatus] Failed to update check in status. maineventID: %v. Source: %s, Err: %s\n", id, source, err.Error())
+			logger.Error(ctx, "failed to update check in status", err)
 			helper.WriteErrorResponse(w, statusCode, []string{err.Error()})
 			return
 		}
@@ -75,9 +75,18 @@ func (h *checkInHandler) handleUpdateCheckInStatus(w http.ResponseWriter, r *htt
 				parsedErr = v
 				statusCode = http.StatusBadRequest
 			}
+
+			// the ticket was scanned at another gate at the
+			// same time, the scan can be retried
+			if parsedErr == errConflict {
+				statusCode = http.StatusConflict
+			}
 			errChan <- parsedErr
 			return
 		}
+
+		metrics.CheckIns.WithLabelValues(metrics.EventMainEvent, metrics.GateLabel(r.URL.Query().Get("gate"))).Inc()
+
 		resChan <- res
 	}()
 
@@ -88,9 +97,13 @@ func (h *checkInHandler) handleUpdateCheckInStatus(w http.ResponseWriter, r *htt
 		return
 	case err = <-errChan:
 	case res := <-resChan:
+		// the ticket number is kept as the data for the
+		// existing scanners, the holder and the accommodations
+		// are shown at the gate
 		resBody, err = json.Marshal(helper.ResponseEnvelope{
 			Status: "Success",
-			Data:   res,
+			Data:   res.Holder.NomorTiket,
+			Meta:   formatCheckIn(res),
 		})
 
 		if err != nil {
@@ -0,0 +1,142 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/tedxub2023/global/helper"
+	"github.com/tedxub2023/global/logger"
+	"github.com/tedxub2023/internal/mainevent"
+)
+
+type complimentaryImportHandler struct {
+	mainevent mainevent.Service
+}
+
+func (h *complimentaryImportHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+		h.handleImportComplimentary(w, r)
+	default:
+		helper.WriteErrorResponse(w, http.StatusMethodNotAllowed, []string{errMethodNotAllowed.Error()})
+	}
+}
+
+func (h *complimentaryImportHandler) handleImportComplimentary(w http.ResponseWriter, r *http.Request) {
+	// add timeout to context, every order of the import is
+	// created and seated at once
+	ctx, cancel := context.WithTimeout(r.Context(), 30000*time.Millisecond)
+	defer cancel()
+
+	var (
+		err        error           // stores error in this handler
+		resBody    []byte          // stores response body to write
+		errMeta    interface{}     // stores details of the error
+		statusCode = http.StatusOK // stores response status code
+	)
+
+	// write response
+	defer func() {
+		if err != nil {
+			logger.Error(ctx, "failed to import complimentary orders", err)
+			helper.WriteErrorResponseWithMeta(w, statusCode, []string{err.Error()}, errMeta)
+			return
+		}
+		// success
+		helper.WriteResponse(w, resBody, statusCode, helper.JSONContentTypeDecorator)
+	}()
+
+	// prepare channels for main go routine
+	resChan := make(chan []mainevent.MainEvent, 1)
+	errChan := make(chan error, 1)
+
+	go func() {
+		// read body, a CSV of the orders
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			statusCode = http.StatusBadRequest
+			errChan <- errBadRequest
+			return
+		}
+
+		orders, err := mainevent.ReadComplimentaryCSV(bytes.NewReader(body))
+		if err == nil {
+			orders, err = h.mainevent.ImportComplimentary(ctx, orders)
+		}
+		if err != nil {
+			// determine error and status code, by default its internal error
+			parsedErr := errInternalServer
+			statusCode = http.StatusInternalServerError
+			var importErr mainevent.ImportError
+			if errors.As(err, &importErr) {
+				parsedErr = errInvalidImport
+				statusCode = http.StatusBadRequest
+				errMeta = formatImportRowErrors(importErr.Rows)
+			} else if errors.Is(err, mainevent.ErrInvalidImport) {
+				parsedErr = errInvalidImport
+				statusCode = http.StatusBadRequest
+			} else if v, ok := mapHTTPError[err]; ok {
+				parsedErr = v
+				statusCode = http.StatusBadRequest
+			}
+
+			// log the actual error if its internal error
+			if statusCode == http.StatusInternalServerError {
+				logger.Error(ctx, "internal error from ImportComplimentary", err)
+			}
+
+			errChan <- parsedErr
+			return
+		}
+
+		resChan <- orders
+	}()
+
+	// wait and handle main go routine
+	select {
+	case <-ctx.Done():
+		statusCode = http.StatusGatewayTimeout
+		err = errRequestTimeout
+	case err = <-errChan:
+	case res := <-resChan:
+		// format each mainevents
+		orders := make([]mainEventHTTP, 0, len(res))
+		for _, m := range res {
+			var order mainEventHTTP
+			order, err = formatMainEvent(m)
+			if err != nil {
+				return
+			}
+			orders = append(orders, order)
+		}
+
+		// construct response data
+		resBody, err = json.Marshal(helper.ResponseEnvelope{
+			Status: "Success",
+			Data:   orders,
+		})
+	}
+}
+
+// formatImportRowErrors formats the given errors of the rows
+// of an import into the respective HTTP-format objects.
+func formatImportRowErrors(rows []mainevent.ImportRowError) []importRowErrorHTTP {
+	result := make([]importRowErrorHTTP, 0, len(rows))
+	for _, row := range rows {
+		parsedErr, ok := mapHTTPError[row.Err]
+		if !ok {
+			parsedErr = errBadRequest
+		}
+
+		result = append(result, importRowErrorHTTP{
+			Baris: row.Baris,
+			Error: parsedErr.Error(),
+		})
+	}
+	return result
+}
@@ -3,12 +3,11 @@ package http
 import (
 	"context"
 	"encoding/json"
-	"log"
 	"net/http"
-	"net/url"
 	"time"
 
 	"github.com/tedxub2023/global/helper"
+	"github.com/tedxub2023/global/logger"
 	"github.com/tedxub2023/internal/mainevent"
 )
 
@@ -32,7 +31,6 @@ func (h *counterHandler) handleGetMainEventCounter(w http.ResponseWriter, r *htt
 
 	var (
 		err        error           // stores error in this handler
-		source     string          // stores request source
 		resBody    []byte          // stores response body to write
 		statusCode = http.StatusOK // stores response status code
 	)
@@ -41,7 +39,7 @@ func (h *counterHandler) handleGetMainEventCounter(w http.ResponseWriter, r *htt
 	defer func() {
 		// error
 		if err != nil {
-			log.Printf("[Main Event HTTP][handleGetMainEventCounter] Failed to get all mainevent. Source: %s, Err: %s\n", source, err.Error())
+			logger.Error(ctx, "failed to get all mainevents", err)
 			helper.WriteErrorResponse(w, statusCode, []string{err.Error()})
 			return
 		}
@@ -50,18 +48,22 @@ func (h *counterHandler) handleGetMainEventCounter(w http.ResponseWriter, r *htt
 	}()
 
 	// prepare channels for main go routine
-	resChan := make(chan int, 1)
+	resChan := make(chan int64, 1)
 	errChan := make(chan error, 1)
 
 	go func() {
-		filter, err := parseGetMainEventsFilter(r.URL.Query())
+		filter, err := parseGetMainEventsFilters(r.URL.Query())
 		if err != nil {
 			statusCode = http.StatusBadRequest
 			errChan <- err
 			return
 		}
 
-		res, err := h.mainevent.GetAllMainEvents(ctx, filter)
+		// only the total is needed, skip reading the rows
+		filter.Cursor = ""
+		filter.Limit = 1
+
+		_, pagination, err := h.mainevent.GetAllMainEvents(ctx, filter)
 		if err != nil {
 			// determine error and status code, by default its internal error
 			parsedErr := errInternalServer
@@ -73,20 +75,14 @@ func (h *counterHandler) handleGetMainEventCounter(w http.ResponseWriter, r *htt
 
 			// log the actual error if its internal error
 			if statusCode == http.StatusInternalServerError {
-				log.Printf("[Match HTTP][handleGetAllGames] Internal error from GetAllMatchs. Err: %s\n", err.Error())
+				logger.Error(ctx, "internal error from GetAllMainEvents", err)
 			}
 
 			errChan <- parsedErr
 			return
 		}
 
-		counter := 0
-
-		for _, t := range res {
-			counter += t.JumlahTiket
-		}
-
-		resChan <- counter
+		resChan <- pagination.TotalTickets
 	}()
 
 	// wait and handle main go routine
@@ -102,20 +98,3 @@ func (h *counterHandler) handleGetMainEventCounter(w http.ResponseWriter, r *htt
 		})
 	}
 }
-
-func parseGetMainEventsFilter(request url.Values) (mainevent.GetAllMainEventsFilter, error) {
-	result := mainevent.GetAllMainEventsFilter{}
-
-	var types mainevent.Type
-	if TypeStr := request.Get("type"); TypeStr != "" {
-		parsedType, err := parseType(TypeStr)
-		if err != nil {
-			return result, errInvalidMainEventType
-		}
-		types = parsedType
-	}
-
-	return mainevent.GetAllMainEventsFilter{
-		Type: types,
-	}, nil
-}
@@ -4,6 +4,7 @@ import (
 	"errors"
 
 	"github.com/tedxub2023/internal/mainevent"
+	"github.com/tedxub2023/internal/promo"
 )
 
 // Followings are the known errors from Main Event HTTP handlers.
@@ -115,6 +116,188 @@ var (
 	// errTicketNotAlreadyAccepted is returned when the given
 	// ticketis not already accepted
 	errTicketNotAlreadyAccepted = errors.New("TICKET_NOT_ALREADY_ACCEPTED")
+
+	// errInvalidCheckInStatus is returned when the given
+	// check in status filter is not a boolean.
+	errInvalidCheckInStatus = errors.New("INVALID_CHECKIN_STATUS")
+
+	// errInvalidDateRange is returned when the given date
+	// range is malformed or empty.
+	errInvalidDateRange = errors.New("INVALID_DATE_RANGE")
+
+	// errInvalidLimit is returned when the given page size is
+	// invalid.
+	errInvalidLimit = errors.New("INVALID_LIMIT")
+
+	// errInvalidCursor is returned when the given cursor is
+	// invalid.
+	errInvalidCursor = errors.New("INVALID_CURSOR")
+
+	// errInvalidSort is returned when the given sort field or
+	// direction is invalid.
+	errInvalidSort = errors.New("INVALID_SORT")
+
+	// errIllegalStatusTransition is returned when the main
+	// event status may not change into the given status.
+	errIllegalStatusTransition = errors.New("ILLEGAL_STATUS_TRANSITION")
+
+	// errConflict is returned when the main event has been
+	// updated by another request since it was read.
+	errConflict = errors.New("CONFLICT")
+
+	// errTicketRefunded is returned when the given ticket
+	// number has been refunded.
+	errTicketRefunded = errors.New("TICKET_REFUNDED")
+
+	// errInvalidRefundID is returned when the given refund id
+	// is invalid.
+	errInvalidRefundID = errors.New("INVALID_REFUND_ID")
+
+	// errInvalidRefundNomorTiket is returned when the given
+	// ticket numbers to refund are invalid.
+	errInvalidRefundNomorTiket = errors.New("INVALID_REFUND_NOMOR_TIKET")
+
+	// errInvalidRefundStatus is returned when the given
+	// refund status is invalid.
+	errInvalidRefundStatus = errors.New("INVALID_REFUND_STATUS")
+
+	// errInvalidRefundMethod is returned when the given
+	// refund method is invalid.
+	errInvalidRefundMethod = errors.New("INVALID_REFUND_METHOD")
+
+	// errRefundNotAllowed is returned when the given tickets
+	// may not be refunded.
+	errRefundNotAllowed = errors.New("REFUND_NOT_ALLOWED")
+
+	// errRefundAlreadyProcessed is returned when the given
+	// refund is no longer waiting for approval.
+	errRefundAlreadyProcessed = errors.New("REFUND_ALREADY_PROCESSED")
+
+	// errInvalidHolder is returned when the given holder is
+	// invalid.
+	errInvalidHolder = errors.New("INVALID_HOLDER")
+
+	// errHolderChangeNotAllowed is returned when the holder
+	// of the given ticket may not be changed.
+	errHolderChangeNotAllowed = errors.New("HOLDER_CHANGE_NOT_ALLOWED")
+
+	// errHolderTransferClosed is returned when the holders
+	// are changed after the transfer cutoff.
+	errHolderTransferClosed = errors.New("HOLDER_TRANSFER_CLOSED")
+
+	// errTicketsAvailable is returned when the waitlist is
+	// joined while the tickets are still on sale.
+	errTicketsAvailable = errors.New("TICKETS_AVAILABLE")
+
+	// errWaitlistAlreadyJoined is returned when the email is
+	// already in the waitlist.
+	errWaitlistAlreadyJoined = errors.New("WAITLIST_ALREADY_JOINED")
+
+	// errInvalidWaitlistToken is returned when the given
+	// waitlist token is invalid.
+	errInvalidWaitlistToken = errors.New("INVALID_WAITLIST_TOKEN")
+
+	// errWaitlistOfferExpired is returned when the waitlist
+	// offer has expired.
+	errWaitlistOfferExpired = errors.New("WAITLIST_OFFER_EXPIRED")
+
+	// errInvalidGroupOrderID is returned when the given group
+	// order id is invalid.
+	errInvalidGroupOrderID = errors.New("INVALID_GROUP_ORDER_ID")
+
+	// errInvalidGroupAttendee is returned when an attendee of
+	// the given group order is invalid.
+	errInvalidGroupAttendee = errors.New("INVALID_GROUP_ATTENDEE")
+
+	// errInvalidGroupSize is returned when the given group
+	// order has too few or too many attendees.
+	errInvalidGroupSize = errors.New("INVALID_GROUP_SIZE")
+
+	// errGroupQuotaExceeded is returned when the institution
+	// has used up its group ticket quota.
+	errGroupQuotaExceeded = errors.New("GROUP_QUOTA_EXCEEDED")
+
+	// errInvalidGroupStatus is returned when the given group
+	// order status is invalid.
+	errInvalidGroupStatus = errors.New("INVALID_GROUP_STATUS")
+
+	// errGroupOrderAlreadyProcessed is returned when the given
+	// group order is no longer waiting for approval.
+	errGroupOrderAlreadyProcessed = errors.New("GROUP_ORDER_ALREADY_PROCESSED")
+
+	// errInvalidInstitutionID is returned when the given
+	// institution id is invalid.
+	errInvalidInstitutionID = errors.New("INVALID_INSTITUTION_ID")
+
+	// errInvalidInstitution is returned when the given
+	// institution is invalid.
+	errInvalidInstitution = errors.New("INVALID_INSTITUTION")
+
+	// errInstitutionExists is returned when the given
+	// institution name is taken.
+	errInstitutionExists = errors.New("INSTITUTION_EXISTS")
+
+	// errPromoNotFound is returned when the given promo code
+	// is unknown.
+	errPromoNotFound = errors.New("PROMO_NOT_FOUND")
+
+	// errPromoNotActive is returned when the given promo code
+	// is outside of its validity window.
+	errPromoNotActive = errors.New("PROMO_NOT_ACTIVE")
+
+	// errPromoNotApplicable is returned when the given promo
+	// code is not valid for the ticket type.
+	errPromoNotApplicable = errors.New("PROMO_NOT_APPLICABLE")
+
+	// errPromoUsageLimitReached is returned when the given
+	// promo code is used up.
+	errPromoUsageLimitReached = errors.New("PROMO_USAGE_LIMIT_REACHED")
+
+	// errPromoEmailLimitReached is returned when the buyer
+	// has used up the given promo code.
+	errPromoEmailLimitReached = errors.New("PROMO_EMAIL_LIMIT_REACHED")
+
+	// errInvalidMainEventAkomodasi is returned when the given
+	// main event accommodations are invalid.
+	errInvalidMainEventAkomodasi = errors.New("INVALID_MAIN_EVENT_AKOMODASI")
+
+	// errInvalidSeat is returned when the given seats are
+	// invalid.
+	errInvalidSeat = errors.New("INVALID_SEAT")
+
+	// errSeatNotAvailable is returned when a given seat is not
+	// available to the buyer.
+	errSeatNotAvailable = errors.New("SEAT_NOT_AVAILABLE")
+
+	// errInvalidSeatLayout is returned when the given seat
+	// layout is invalid.
+	errInvalidSeatLayout = errors.New("INVALID_SEAT_LAYOUT")
+
+	// errSeatInUse is returned when the given seat layout
+	// removes a seat taken by an order.
+	errSeatInUse = errors.New("SEAT_IN_USE")
+
+	// errInvalidBuyerLoginToken is returned when the given
+	// login link is unknown, expired or already used.
+	errInvalidBuyerLoginToken = errors.New("INVALID_BUYER_LOGIN_TOKEN")
+
+	// errInvalidBuyerSession is returned when the buyer
+	// session is missing, unknown or expired.
+	errInvalidBuyerSession = errors.New("INVALID_BUYER_SESSION")
+
+	// errPurchaseLimitReached is returned when the buyer has
+	// reached the purchase limit, the tickets the buyer can
+	// still buy are in the response meta.
+	errPurchaseLimitReached = errors.New("PURCHASE_LIMIT_REACHED")
+
+	// errInvalidImport is returned when the given import is
+	// invalid, the errors of its rows are in the response
+	// meta.
+	errInvalidImport = errors.New("INVALID_IMPORT")
+
+	// errComplimentaryExists is returned when the buyer
+	// already has a complimentary order.
+	errComplimentaryExists = errors.New("COMPLIMENTARY_EXISTS")
 )
 
 var (
@@ -144,5 +327,47 @@ var (
 		mainevent.ErrPaymentNotSettlement:           errPaymentNotSettlement,
 		mainevent.ErrTicketSoldOut:                  errTicketSoldOut,
 		mainevent.ErrTicketNotAlreadyAccepted:       errTicketNotAlreadyAccepted,
+		mainevent.ErrInvalidCursor:                  errInvalidCursor,
+		mainevent.ErrInvalidSort:                    errInvalidSort,
+		mainevent.ErrIllegalStatusTransition:        errIllegalStatusTransition,
+		mainevent.ErrConflict:                       errConflict,
+		mainevent.ErrTicketRefunded:                 errTicketRefunded,
+		mainevent.ErrInvalidRefundID:                errInvalidRefundID,
+		mainevent.ErrInvalidRefundNomorTiket:        errInvalidRefundNomorTiket,
+		mainevent.ErrInvalidRefundStatus:            errInvalidRefundStatus,
+		mainevent.ErrInvalidRefundMethod:            errInvalidRefundMethod,
+		mainevent.ErrRefundNotAllowed:               errRefundNotAllowed,
+		mainevent.ErrRefundAlreadyProcessed:         errRefundAlreadyProcessed,
+		mainevent.ErrInvalidHolder:                  errInvalidHolder,
+		mainevent.ErrHolderChangeNotAllowed:         errHolderChangeNotAllowed,
+		mainevent.ErrHolderTransferClosed:           errHolderTransferClosed,
+		mainevent.ErrTicketsAvailable:               errTicketsAvailable,
+		mainevent.ErrWaitlistAlreadyJoined:          errWaitlistAlreadyJoined,
+		mainevent.ErrInvalidWaitlistToken:           errInvalidWaitlistToken,
+		mainevent.ErrWaitlistOfferExpired:           errWaitlistOfferExpired,
+		mainevent.ErrInvalidGroupOrderID:            errInvalidGroupOrderID,
+		mainevent.ErrInvalidGroupAttendee:           errInvalidGroupAttendee,
+		mainevent.ErrInvalidGroupSize:               errInvalidGroupSize,
+		mainevent.ErrGroupQuotaExceeded:             errGroupQuotaExceeded,
+		mainevent.ErrInvalidGroupStatus:             errInvalidGroupStatus,
+		mainevent.ErrGroupOrderAlreadyProcessed:     errGroupOrderAlreadyProcessed,
+		mainevent.ErrInvalidInstitutionID:           errInvalidInstitutionID,
+		mainevent.ErrInvalidInstitution:             errInvalidInstitution,
+		mainevent.ErrInstitutionExists:              errInstitutionExists,
+		mainevent.ErrInvalidAccommodation:           errInvalidMainEventAkomodasi,
+		mainevent.ErrInvalidSeat:                    errInvalidSeat,
+		mainevent.ErrSeatNotAvailable:               errSeatNotAvailable,
+		mainevent.ErrInvalidSeatLayout:              errInvalidSeatLayout,
+		mainevent.ErrSeatInUse:                      errSeatInUse,
+		mainevent.ErrInvalidBuyerLoginToken:         errInvalidBuyerLoginToken,
+		mainevent.ErrInvalidBuyerSession:            errInvalidBuyerSession,
+		mainevent.ErrInvalidMainEventDisability:     errInvalidMainEventDisability,
+		mainevent.ErrInvalidImport:                  errInvalidImport,
+		mainevent.ErrComplimentaryExists:            errComplimentaryExists,
+		promo.ErrPromoNotFound:                      errPromoNotFound,
+		promo.ErrPromoNotActive:                     errPromoNotActive,
+		promo.ErrPromoNotApplicable:                 errPromoNotApplicable,
+		promo.ErrPromoUsageLimitReached:             errPromoUsageLimitReached,
+		promo.ErrPromoEmailLimitReached:             errPromoEmailLimitReached,
 	}
 )
@@ -0,0 +1,122 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/tedxub2023/global/helper"
+	"github.com/tedxub2023/global/logger"
+	"github.com/tedxub2023/internal/mainevent"
+)
+
+type groupHandler struct {
+	mainevent mainevent.Service
+}
+
+func (h *groupHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	groupID, err := strconv.ParseInt(vars["id"], 10, 64)
+	if err != nil {
+		logger.Error(r.Context(), "failed to parse group order ID", err, logger.Fields{"id": vars["id"]})
+		helper.WriteErrorResponse(w, http.StatusBadRequest, []string{errInvalidGroupOrderID.Error()})
+		return
+	}
+	logger.AddFields(r.Context(), logger.Fields{"group_id": groupID})
+
+	switch r.Method {
+	case http.MethodPatch:
+		h.handleProcessGroupOrder(w, r, groupID)
+	default:
+		helper.WriteErrorResponse(w, http.StatusMethodNotAllowed, []string{errMethodNotAllowed.Error()})
+	}
+}
+
+func (h *groupHandler) handleProcessGroupOrder(w http.ResponseWriter, r *http.Request, groupID int64) {
+	// add timeout to context
+	ctx, cancel := context.WithTimeout(r.Context(), 5000*time.Millisecond)
+	defer cancel()
+
+	var (
+		err        error
+		resBody    []byte
+		statusCode = http.StatusOK
+	)
+
+	defer func() {
+		if err != nil {
+			logger.Error(ctx, "failed to process group order", err)
+			helper.WriteErrorResponse(w, statusCode, []string{err.Error()})
+			return
+		}
+		helper.WriteResponse(w, resBody, statusCode, helper.JSONContentTypeDecorator)
+	}()
+
+	errChan := make(chan error, 1)
+	resChan := make(chan int64, 1)
+
+	go func() {
+		// read body
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			statusCode = http.StatusBadRequest
+			errChan <- errBadRequest
+			return
+		}
+
+		// unmarshall body
+		request := groupOrderHTTP{}
+		err = json.Unmarshal(body, &request)
+		if err != nil {
+			statusCode = http.StatusBadRequest
+			errChan <- errBadRequest
+			return
+		}
+
+		// format HTTP request into service object
+		reqGroup := mainevent.GroupOrder{
+			ID: groupID,
+		}
+		if request.Status != nil {
+			reqGroup.Status = mainevent.GroupStatus(*request.Status)
+		}
+		if request.Catatan != nil {
+			reqGroup.Catatan = *request.Catatan
+		}
+
+		err = h.mainevent.ProcessGroupOrder(ctx, reqGroup)
+		if err != nil {
+			parsedErr := errInternalServer
+			statusCode = http.StatusInternalServerError
+			if v, ok := mapHTTPError[err]; ok {
+				parsedErr = v
+				statusCode = http.StatusBadRequest
+			}
+			if parsedErr == errGroupOrderAlreadyProcessed {
+				statusCode = http.StatusConflict
+			}
+			if statusCode == http.StatusInternalServerError {
+				logger.Error(ctx, "internal error from ProcessGroupOrder", err)
+			}
+			errChan <- parsedErr
+			return
+		}
+		resChan <- groupID
+	}()
+
+	select {
+	case <-ctx.Done():
+		err = errRequestTimeout
+		statusCode = http.StatusGatewayTimeout
+	case err = <-errChan:
+	case groupID := <-resChan:
+		resBody, err = json.Marshal(helper.ResponseEnvelope{
+			Status: "Success",
+			Data:   groupID,
+		})
+	}
+}
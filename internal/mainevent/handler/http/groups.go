@@ -0,0 +1,225 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/tedxub2023/global/helper"
+	"github.com/tedxub2023/global/logger"
+	"github.com/tedxub2023/internal/mainevent"
+)
+
+type groupsHandler struct {
+	mainevent mainevent.Service
+}
+
+func (h *groupsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.handleGetAllGroupOrders(w, r)
+	case http.MethodPost:
+		h.handleCreateGroupOrder(w, r)
+	default:
+		helper.WriteErrorResponse(w, http.StatusMethodNotAllowed, []string{errMethodNotAllowed.Error()})
+	}
+}
+
+func (h *groupsHandler) handleGetAllGroupOrders(w http.ResponseWriter, r *http.Request) {
+	// add timeout to context
+	ctx, cancel := context.WithTimeout(r.Context(), 3000*time.Millisecond)
+	defer cancel()
+
+	var (
+		err        error           // stores error in this handler
+		resBody    []byte          // stores response body to write
+		statusCode = http.StatusOK // stores response status code
+	)
+
+	// write response
+	defer func() {
+		// error
+		if err != nil {
+			logger.Error(ctx, "failed to get all group orders", err)
+			helper.WriteErrorResponse(w, statusCode, []string{err.Error()})
+			return
+		}
+		// success
+		helper.WriteResponse(w, resBody, statusCode, helper.JSONContentTypeDecorator)
+	}()
+
+	// prepare channels for main go routine
+	resChan := make(chan []mainevent.GroupOrder, 1)
+	errChan := make(chan error, 1)
+
+	go func() {
+		// the approval queue is listed by default
+		status := mainevent.GroupStatusRequested
+		if r.URL.Query().Has("status") {
+			status = mainevent.GroupStatus(r.URL.Query().Get("status"))
+		}
+
+		res, err := h.mainevent.GetAllGroupOrders(ctx, status)
+		if err != nil {
+			// determine error and status code, by default its internal error
+			parsedErr := errInternalServer
+			statusCode = http.StatusInternalServerError
+			if v, ok := mapHTTPError[err]; ok {
+				parsedErr = v
+				statusCode = http.StatusBadRequest
+			}
+
+			// log the actual error if its internal error
+			if statusCode == http.StatusInternalServerError {
+				logger.Error(ctx, "internal error from GetAllGroupOrders", err)
+			}
+
+			errChan <- parsedErr
+			return
+		}
+
+		resChan <- res
+	}()
+
+	// wait and handle main go routine
+	select {
+	case <-ctx.Done():
+		statusCode = http.StatusGatewayTimeout
+		err = errRequestTimeout
+	case err = <-errChan:
+	case res := <-resChan:
+		// format each group orders
+		groups := make([]groupOrderHTTP, 0)
+		for _, g := range res {
+			groups = append(groups, formatGroupOrder(g))
+		}
+
+		// construct response data
+		resBody, err = json.Marshal(helper.ResponseEnvelope{
+			Data: groups,
+		})
+	}
+}
+
+func (h *groupsHandler) handleCreateGroupOrder(w http.ResponseWriter, r *http.Request) {
+	// add timeout to context
+	ctx, cancel := context.WithTimeout(r.Context(), 5000*time.Millisecond)
+	defer cancel()
+
+	var (
+		err        error           // stores error in this handler
+		resBody    []byte          // stores response body to write
+		statusCode = http.StatusOK // stores response status code
+	)
+
+	// write response
+	defer func() {
+		if err != nil {
+			logger.Error(ctx, "failed to create group order", err)
+			helper.WriteErrorResponse(w, statusCode, []string{err.Error()})
+			return
+		}
+		// success
+		helper.WriteResponse(w, resBody, statusCode, helper.JSONContentTypeDecorator)
+	}()
+
+	// prepare channels for main go routine
+	resChan := make(chan int64, 1)
+	errChan := make(chan error, 1)
+
+	go func() {
+		// read body
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			statusCode = http.StatusBadRequest
+			errChan <- errBadRequest
+			return
+		}
+
+		// unmarshall body
+		request := groupOrderHTTP{}
+		err = json.Unmarshal(body, &request)
+		if err != nil {
+			statusCode = http.StatusBadRequest
+			errChan <- errBadRequest
+			return
+		}
+
+		// format HTTP request into service object
+		reqGroup := parseGroupOrderFromCreateRequest(request)
+
+		groupID, err := h.mainevent.CreateGroupOrder(ctx, reqGroup)
+		if err != nil {
+			// determine error and status code, by default its internal error
+			parsedErr := errInternalServer
+			statusCode = http.StatusInternalServerError
+			if v, ok := mapHTTPError[err]; ok {
+				parsedErr = v
+				statusCode = http.StatusBadRequest
+			}
+
+			// log the actual error if its internal error
+			if statusCode == http.StatusInternalServerError {
+				logger.Error(ctx, "internal error from CreateGroupOrder", err)
+			}
+
+			errChan <- parsedErr
+			return
+		}
+
+		resChan <- groupID
+	}()
+
+	// wait and handle main go routine
+	select {
+	case <-ctx.Done():
+		statusCode = http.StatusGatewayTimeout
+		err = errRequestTimeout
+	case err = <-errChan:
+	case groupID := <-resChan:
+		resBody, err = json.Marshal(helper.ResponseEnvelope{
+			Status: "Success",
+			Data:   groupID,
+		})
+	}
+}
+
+// parseGroupOrderFromCreateRequest returns GroupOrder from the
+// given HTTP request object.
+func parseGroupOrderFromCreateRequest(request groupOrderHTTP) mainevent.GroupOrder {
+	result := mainevent.GroupOrder{}
+
+	if request.Nama != nil {
+		result.Nama = *request.Nama
+	}
+	if request.NomorIdentitas != nil {
+		result.NomorIdentitas = *request.NomorIdentitas
+	}
+	if request.AsalInstitusi != nil {
+		result.AsalInstitusi = *request.AsalInstitusi
+	}
+	if request.Email != nil {
+		result.Email = *request.Email
+	}
+	if request.NomorTelepon != nil {
+		result.NomorTelepon = *request.NomorTelepon
+	}
+
+	for _, p := range request.Peserta {
+		attendee := mainevent.GroupAttendee{}
+		if p.Nama != nil {
+			attendee.Nama = *p.Nama
+		}
+		if p.Email != nil {
+			attendee.Email = *p.Email
+		}
+		if p.NomorIdentitas != nil {
+			attendee.NomorIdentitas = *p.NomorIdentitas
+		}
+		result.Attendees = append(result.Attendees, attendee)
+	}
+
+	return result
+}
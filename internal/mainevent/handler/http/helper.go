@@ -1,6 +1,15 @@
 package http
 
-import "github.com/tedxub2023/internal/mainevent"
+import (
+	"time"
+
+	"github.com/tedxub2023/internal/mainevent"
+	"github.com/tedxub2023/internal/transaction"
+)
+
+// jakartaTime is the time zone of the date only query
+// parameters.
+var jakartaTime = time.FixedZone("WIB", 7*60*60)
 
 // formatMainEvent formats the given transaction into the respective
 // HTTP-format objecm.
@@ -9,7 +18,7 @@ func formatMainEvent(m mainevent.MainEvent) (mainEventHTTP, error) {
 	statusStr := m.Status.String()
 	disabilityStr := m.Disabilitas.String()
 
-	return mainEventHTTP{
+	result := mainEventHTTP{
 		ID:                &m.ID,
 		Nama:              &m.Nama,
 		Disabilitas:       &disabilityStr,
@@ -26,7 +35,312 @@ func formatMainEvent(m mainevent.MainEvent) (mainEventHTTP, error) {
 		NomorTiket:        &m.NomorTiket,
 		CheckInStatus:     &m.CheckInStatus,
 		CheckInNomorTiket: &m.CheckInNomorTiket,
-	}, nil
+	}
+
+	if m.EventID != 0 {
+		result.EventID = &m.EventID
+	}
+
+	if m.KodePromo != "" {
+		result.KodePromo = &m.KodePromo
+		result.Diskon = &m.Diskon
+	}
+
+	if len(m.RefundNomorTiket) > 0 {
+		result.RefundNomorTiket = &m.RefundNomorTiket
+	}
+
+	if len(m.Akomodasi) > 0 {
+		akomodasi := formatAccommodations(m.Akomodasi)
+		result.Akomodasi = &akomodasi
+	}
+
+	if m.CatatanAkomodasi != "" {
+		result.CatatanAkomodasi = &m.CatatanAkomodasi
+	}
+
+	for _, s := range m.Seats {
+		result.Kursi = append(result.Kursi, formatSeat(s, true))
+	}
+
+	for _, h := range m.Holders {
+		result.Holders = append(result.Holders, formatHolder(h))
+	}
+
+	if deadline := m.PaymentDeadline(); !deadline.IsZero() {
+		result.BatasPembayaran = &deadline
+	}
+
+	if !m.UpdateTime.IsZero() {
+		result.UpdateTime = &m.UpdateTime
+	}
+
+	return result, nil
+}
+
+// formatHolder formats the given holder into the respective
+// HTTP-format object.
+func formatHolder(h mainevent.Holder) holderHTTP {
+	result := holderHTTP{
+		NomorTiket: &h.NomorTiket,
+		Nama:       &h.Nama,
+		Email:      &h.Email,
+	}
+
+	if h.NomorIdentitas != "" {
+		result.NomorIdentitas = &h.NomorIdentitas
+	}
+
+	return result
+}
+
+// formatCheckIn formats the given check in into the
+// respective HTTP-format object.
+func formatCheckIn(c mainevent.CheckIn) checkInHTTP {
+	result := checkInHTTP{
+		holderHTTP: formatHolder(c.Holder),
+	}
+
+	if c.Disabilitas != mainevent.DisabilityUnknown && c.Disabilitas != mainevent.NoneDisability {
+		disabilityStr := c.Disabilitas.String()
+		result.Disabilitas = &disabilityStr
+	}
+
+	if len(c.Akomodasi) > 0 {
+		akomodasi := formatAccommodations(c.Akomodasi)
+		result.Akomodasi = &akomodasi
+	}
+
+	if c.CatatanAkomodasi != "" {
+		result.CatatanAkomodasi = &c.CatatanAkomodasi
+	}
+
+	if c.Seat.ID != 0 {
+		kursi := c.Seat.Label()
+		result.Kursi = &kursi
+	}
+
+	return result
+}
+
+// formatSeat formats the given seat into the respective
+// HTTP-format object. The seat map only tells whether the seat
+// is free, the seats of a mainevent tell their tickets.
+func formatSeat(s mainevent.Seat, withTicket bool) seatHTTP {
+	result := seatHTTP{
+		ID:        &s.ID,
+		Section:   &s.Section,
+		Baris:     &s.Baris,
+		Nomor:     &s.Nomor,
+		Aksesibel: &s.Aksesibel,
+	}
+
+	if !withTicket {
+		available := s.Available()
+		result.Tersedia = &available
+	} else if s.NomorTiket != "" {
+		result.NomorTiket = &s.NomorTiket
+	}
+
+	return result
+}
+
+// formatAccommodations formats the given accommodations into
+// their names in the HTTP requests.
+func formatAccommodations(accommodations []mainevent.Accommodation) []string {
+	result := make([]string, 0, len(accommodations))
+	for _, a := range accommodations {
+		result = append(result, a.String())
+	}
+	return result
+}
+
+// parseAccommodations returns the accommodations with the
+// given names, they are validated by the service.
+func parseAccommodations(req []string) []mainevent.Accommodation {
+	result := make([]mainevent.Accommodation, 0, len(req))
+	for _, a := range req {
+		result = append(result, mainevent.Accommodation(a))
+	}
+	return result
+}
+
+// formatAccommodationReport formats the given accommodation
+// report into the respective HTTP-format objects.
+func formatAccommodationReport(r mainevent.AccommodationReport) ([]disabilityReportHTTP, accommodationReportHTTP, error) {
+	reports := make([]disabilityReportHTTP, 0, len(r.Disabilitas))
+	for _, d := range r.Disabilitas {
+		d := d
+		disabilityStr := d.Disabilitas.String()
+
+		report := disabilityReportHTTP{
+			Disabilitas: &disabilityStr,
+			JumlahOrder: &d.JumlahOrder,
+			JumlahTiket: &d.JumlahTiket,
+			Akomodasi:   make(map[string]int, len(d.Akomodasi)),
+			Orders:      make([]mainEventHTTP, 0, len(d.Orders)),
+		}
+		for a, count := range d.Akomodasi {
+			report.Akomodasi[a.String()] = count
+		}
+		for _, m := range d.Orders {
+			order, err := formatMainEvent(m)
+			if err != nil {
+				return nil, accommodationReportHTTP{}, err
+			}
+			report.Orders = append(report.Orders, order)
+		}
+
+		reports = append(reports, report)
+	}
+
+	meta := accommodationReportHTTP{
+		KuotaKursi:    &r.KuotaKursi,
+		KursiTerpakai: &r.KursiTerpakai,
+	}
+
+	return reports, meta, nil
+}
+
+// formatRefund formats the given refund into the respective
+// HTTP-format object.
+func formatRefund(r mainevent.Refund) refundHTTP {
+	statusStr := r.Status.String()
+
+	result := refundHTTP{
+		ID:          &r.ID,
+		MainEventID: &r.MainEventID,
+		NomorTiket:  &r.NomorTiket,
+		Alasan:      &r.Alasan,
+		TotalRefund: &r.TotalRefund,
+		Status:      &statusStr,
+		CreateTime:  &r.CreateTime,
+	}
+
+	if r.Metode != mainevent.RefundMethodUnknown {
+		metodeStr := r.Metode.String()
+		result.Metode = &metodeStr
+	}
+
+	if r.Catatan != "" {
+		result.Catatan = &r.Catatan
+	}
+
+	if !r.UpdateTime.IsZero() {
+		result.UpdateTime = &r.UpdateTime
+	}
+
+	return result
+}
+
+// formatGroupOrder formats the given group order into the
+// respective HTTP-format object.
+func formatGroupOrder(g mainevent.GroupOrder) groupOrderHTTP {
+	statusStr := g.Status.String()
+
+	result := groupOrderHTTP{
+		ID:             &g.ID,
+		Nama:           &g.Nama,
+		NomorIdentitas: &g.NomorIdentitas,
+		AsalInstitusi:  &g.AsalInstitusi,
+		Email:          &g.Email,
+		NomorTelepon:   &g.NomorTelepon,
+		Peserta:        make([]groupAttendeeHTTP, 0, len(g.Attendees)),
+		HargaTiket:     &g.HargaTiket,
+		TotalHarga:     &g.TotalHarga,
+		Status:         &statusStr,
+		CreateTime:     &g.CreateTime,
+	}
+
+	for i := range g.Attendees {
+		a := g.Attendees[i]
+		result.Peserta = append(result.Peserta, groupAttendeeHTTP{
+			Nama:           &a.Nama,
+			Email:          &a.Email,
+			NomorIdentitas: &a.NomorIdentitas,
+		})
+	}
+
+	if g.MainEventID != 0 {
+		result.MainEventID = &g.MainEventID
+	}
+
+	if g.Catatan != "" {
+		result.Catatan = &g.Catatan
+	}
+
+	if !g.UpdateTime.IsZero() {
+		result.UpdateTime = &g.UpdateTime
+	}
+
+	return result
+}
+
+// formatInstitution formats the given institution into the
+// respective HTTP-format object.
+func formatInstitution(i mainevent.Institution) institutionHTTP {
+	result := institutionHTTP{
+		ID:              &i.ID,
+		Nama:            &i.Nama,
+		MaxTiket:        &i.MaxTiket,
+		KuotaTiket:      &i.KuotaTiket,
+		RequireApproval: &i.RequireApproval,
+		CreateTime:      &i.CreateTime,
+	}
+
+	if !i.UpdateTime.IsZero() {
+		result.UpdateTime = &i.UpdateTime
+	}
+
+	return result
+}
+
+// formatPagination formats the given pagination into the
+// respective HTTP-format object.
+func formatPagination(p mainevent.Pagination) paginationHTTP {
+	result := paginationHTTP{
+		Total:        &p.Total,
+		TotalTickets: &p.TotalTickets,
+	}
+	if p.NextCursor != "" {
+		result.NextCursor = &p.NextCursor
+	}
+	return result
+}
+
+// formatBuyerTransaction formats the given transaction of a
+// buyer into the respective HTTP-format object.
+func formatBuyerTransaction(t transaction.Transaction) buyerTransactionHTTP {
+	tanggal := t.Tanggal.Format("2006-01-02")
+	statusPayment := t.StatusPayment.String()
+
+	return buyerTransactionHTTP{
+		ID:                &t.ID,
+		OrderID:           &t.OrderID,
+		Nama:              &t.Nama,
+		Tanggal:           &tanggal,
+		JumlahTiket:       &t.JumlahTiket,
+		TotalHarga:        &t.TotalHarga,
+		StatusPayment:     &statusPayment,
+		NomorTiket:        &t.NomorTiket,
+		CheckInNomorTiket: &t.CheckInNomorTiket,
+		CreateTime:        &t.CreateTime,
+	}
+}
+
+// parseDate parses the given date, either a date only in
+// Western Indonesia Time, e.g. 2023-10-01, or a RFC 3339 time.
+// It also returns whether the date is date only.
+func parseDate(req string) (time.Time, bool, error) {
+	if t, err := time.ParseInLocation("2006-01-02", req, jakartaTime); err == nil {
+		return t, true, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, req)
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	return t, false, nil
 }
 
 // parseType returns mainevent.Type
@@ -39,6 +353,10 @@ func parseType(req string) (mainevent.Type, error) {
 		return mainevent.TypePresale, nil
 	case mainevent.TypeNormalSale.String():
 		return mainevent.TypeNormalSale, nil
+	case mainevent.TypeGroup.String():
+		return mainevent.TypeGroup, nil
+	case mainevent.TypeComplimentary.String():
+		return mainevent.TypeComplimentary, nil
 	}
 	return mainevent.TypeUnknown, errInvalidMainEventType
 }
@@ -53,6 +371,8 @@ func parseStatus(req string) (mainevent.Status, error) {
 		return mainevent.StatusUnpaid, nil
 	case mainevent.StatusSettlement.String():
 		return mainevent.StatusSettlement, nil
+	case mainevent.StatusRefunded.String():
+		return mainevent.StatusRefunded, nil
 	}
 	return mainevent.StatusUnknown, errInvalidMainEventStatus
 }
@@ -3,6 +3,7 @@ package http
 import (
 	"errors"
 	"net/http"
+	"time"
 
 	"github.com/gorilla/mux"
 	"github.com/tedxub2023/internal/mainevent"
@@ -59,6 +60,152 @@ var (
 		Name: "checkin",
 		URL:  "/checkin/mainevent/{id}",
 	}
+
+	// HandlerMainEventRefunds denotes HTTP handler for the
+	// buyer to request a refund of a mainevent
+	HandlerMainEventRefunds = HandlerIdentity{
+		Name: "mainevent_refunds",
+		URL:  "/mainevents/{id}/refunds",
+	}
+
+	// HandlerMainEventHolders denotes HTTP handler for the
+	// buyer to assign the tickets of a mainevent to attendees
+	HandlerMainEventHolders = HandlerIdentity{
+		Name: "mainevent_holders",
+		URL:  "/mainevents/{id}/holders",
+	}
+
+	// HandlerMainEventAccommodation denotes HTTP handler for
+	// the committee to update the accommodations of a
+	// mainevent
+	HandlerMainEventAccommodation = HandlerIdentity{
+		Name: "mainevent_accommodation",
+		URL:  "/mainevents/{id:[0-9]+}/accommodation",
+	}
+
+	// HandlerAccommodationReport denotes HTTP handler for the
+	// committee to get the accommodations of the mainevents
+	// grouped by disability
+	HandlerAccommodationReport = HandlerIdentity{
+		Name: "accommodation_report",
+		URL:  "/mainevents/accommodations/report",
+	}
+
+	// HandlerSeats denotes HTTP handler for the buyers to get
+	// the seat map and for the committee to load the seat
+	// layout
+	HandlerSeats = HandlerIdentity{
+		Name: "seats",
+		URL:  "/seats",
+	}
+
+	// HandlerWaitlist denotes HTTP handler to join the
+	// waitlist of the sold out normal sale tickets
+	HandlerWaitlist = HandlerIdentity{
+		Name: "waitlist",
+		URL:  "/waitlist",
+	}
+
+	// HandlerGroups denotes HTTP handler for the coordinators
+	// to submit group orders and for the committee to list
+	// them
+	HandlerGroups = HandlerIdentity{
+		Name: "groups",
+		URL:  "/groups",
+	}
+
+	// HandlerGroup denotes HTTP handler for the committee to
+	// approve or reject a group order
+	HandlerGroup = HandlerIdentity{
+		Name: "group",
+		URL:  "/groups/{id:[0-9]+}",
+	}
+
+	// HandlerInstitutions denotes HTTP handler for the
+	// committee to list and create the group order policies
+	// of the institutions
+	HandlerInstitutions = HandlerIdentity{
+		Name: "institutions",
+		URL:  "/institutions",
+	}
+
+	// HandlerInstitution denotes HTTP handler for the
+	// committee to update the group order policy of an
+	// institution
+	HandlerInstitution = HandlerIdentity{
+		Name: "institution",
+		URL:  "/institutions/{id:[0-9]+}",
+	}
+
+	// HandlerBuyerLogin denotes HTTP handler for the buyers
+	// to request a login link by email
+	HandlerBuyerLogin = HandlerIdentity{
+		Name: "buyer_login",
+		URL:  "/buyers/login",
+	}
+
+	// HandlerBuyerLoginVerify denotes HTTP handler for the
+	// buyers to open a session with their login link
+	HandlerBuyerLoginVerify = HandlerIdentity{
+		Name: "buyer_login_verify",
+		URL:  "/buyers/login/verify",
+	}
+
+	// HandlerBuyerMainEvents denotes HTTP handler for the
+	// buyers to list their mainevents across all events
+	HandlerBuyerMainEvents = HandlerIdentity{
+		Name: "buyer_mainevents",
+		URL:  "/buyers/me/mainevents",
+	}
+
+	// HandlerBuyerTransactions denotes HTTP handler for the
+	// buyers to list their Semayam Asa transactions
+	HandlerBuyerTransactions = HandlerIdentity{
+		Name: "buyer_transactions",
+		URL:  "/buyers/me/transactions",
+	}
+
+	// HandlerBuyerPaymentProof denotes HTTP handler for the
+	// buyers to upload the payment proof of a mainevent again
+	HandlerBuyerPaymentProof = HandlerIdentity{
+		Name: "buyer_payment_proof",
+		URL:  "/buyers/me/mainevents/{id:[0-9]+}/proof",
+	}
+
+	// HandlerBuyerTicket denotes HTTP handler for the buyers
+	// to download the ticket PDF of a mainevent again
+	HandlerBuyerTicket = HandlerIdentity{
+		Name: "buyer_ticket",
+		URL:  "/buyers/me/mainevents/{id:[0-9]+}/ticket",
+	}
+
+	// HandlerBuyerMail denotes HTTP handler for the buyers to
+	// get the email of a mainevent again
+	HandlerBuyerMail = HandlerIdentity{
+		Name: "buyer_mail",
+		URL:  "/buyers/me/mainevents/{id:[0-9]+}/mail",
+	}
+
+	// HandlerComplimentaryImport denotes HTTP handler for the
+	// committee to import the complimentary orders from a CSV
+	HandlerComplimentaryImport = HandlerIdentity{
+		Name: "complimentary_import",
+		URL:  "/mainevents/complimentary/import",
+	}
+
+	// HandlerRefunds denotes HTTP handler for the committee
+	// to list the refunds
+	HandlerRefunds = HandlerIdentity{
+		Name: "refunds",
+		URL:  "/refunds",
+	}
+
+	// HandlerRefund denotes HTTP handler for the committee
+	// to approve or reject a refund
+	HandlerRefund = HandlerIdentity{
+		Name: "refund",
+		URL:  "/refunds/{id}",
+	}
 )
 
 // New creates a new Handler.
@@ -110,6 +257,86 @@ func (h *Handler) createHTTPHandler(configName string) (http.Handler, error) {
 		httpHandler = &checkInHandler{
 			mainevent: h.mainevent,
 		}
+	case HandlerMainEventRefunds.Name:
+		httpHandler = &maineventRefundsHandler{
+			mainevent: h.mainevent,
+		}
+	case HandlerMainEventHolders.Name:
+		httpHandler = &maineventHoldersHandler{
+			mainevent: h.mainevent,
+		}
+	case HandlerMainEventAccommodation.Name:
+		httpHandler = &maineventAccommodationHandler{
+			mainevent: h.mainevent,
+		}
+	case HandlerAccommodationReport.Name:
+		httpHandler = &accommodationReportHandler{
+			mainevent: h.mainevent,
+		}
+	case HandlerSeats.Name:
+		httpHandler = &seatsHandler{
+			mainevent: h.mainevent,
+		}
+	case HandlerWaitlist.Name:
+		httpHandler = &waitlistHandler{
+			mainevent: h.mainevent,
+		}
+	case HandlerGroups.Name:
+		httpHandler = &groupsHandler{
+			mainevent: h.mainevent,
+		}
+	case HandlerGroup.Name:
+		httpHandler = &groupHandler{
+			mainevent: h.mainevent,
+		}
+	case HandlerInstitutions.Name:
+		httpHandler = &institutionsHandler{
+			mainevent: h.mainevent,
+		}
+	case HandlerInstitution.Name:
+		httpHandler = &institutionHandler{
+			mainevent: h.mainevent,
+		}
+	case HandlerBuyerLogin.Name:
+		httpHandler = &buyerLoginHandler{
+			mainevent: h.mainevent,
+		}
+	case HandlerBuyerLoginVerify.Name:
+		httpHandler = &buyerLoginVerifyHandler{
+			mainevent: h.mainevent,
+		}
+	case HandlerBuyerMainEvents.Name:
+		httpHandler = &buyerMainEventsHandler{
+			mainevent: h.mainevent,
+		}
+	case HandlerBuyerPaymentProof.Name:
+		httpHandler = &buyerPaymentProofHandler{
+			mainevent: h.mainevent,
+		}
+	case HandlerBuyerTicket.Name:
+		httpHandler = &buyerTicketHandler{
+			mainevent: h.mainevent,
+		}
+	case HandlerBuyerMail.Name:
+		httpHandler = &buyerMailHandler{
+			mainevent: h.mainevent,
+		}
+	case HandlerBuyerTransactions.Name:
+		httpHandler = &buyerTransactionsHandler{
+			mainevent: h.mainevent,
+		}
+	case HandlerRefunds.Name:
+		httpHandler = &refundsHandler{
+			mainevent: h.mainevent,
+		}
+	case HandlerRefund.Name:
+		httpHandler = &refundHandler{
+			mainevent: h.mainevent,
+		}
+	case HandlerComplimentaryImport.Name:
+		httpHandler = &complimentaryImportHandler{
+			mainevent: h.mainevent,
+		}
 	default:
 		return httpHandler, errUnknownConfig
 	}
@@ -125,20 +352,196 @@ func (h *Handler) Start(multiplexer *mux.Router) error {
 }
 
 type mainEventHTTP struct {
-	ID                *int64    `json:"id"`
-	Nama              *string   `json:"nama"`
-	Disabilitas       *string   `json:"disabilitas"`
-	NomorIdentitas    *string   `json:"nomor_identitas"`
-	AsalInstitusi     *string   `json:"asal_institusi"`
-	Email             *string   `json:"email"`
-	NomorTelepon      *string   `json:"nomor_telepon"`
-	JumlahTiket       *int      `json:"jumlah_tiket"`
-	TotalHarga        *int64    `json:"total_harga"`
-	OrderID           *string   `json:"order_id"`
-	Type              *string   `json:"type"`
-	Status            *string   `json:"status"`
-	ImageURI          *string   `json:"image_uri"`
-	NomorTiket        *[]string `json:"nomor_tiket"`
-	CheckInStatus     *bool     `json:"checkin_status"`
-	CheckInNomorTiket *[]string `json:"checkin_nomor_tiket"`
+	ID                *int64       `json:"id"`
+	EventID           *int64       `json:"event_id,omitempty"`
+	Nama              *string      `json:"nama"`
+	Disabilitas       *string      `json:"disabilitas"`
+	NomorIdentitas    *string      `json:"nomor_identitas"`
+	AsalInstitusi     *string      `json:"asal_institusi"`
+	Email             *string      `json:"email"`
+	NomorTelepon      *string      `json:"nomor_telepon"`
+	JumlahTiket       *int         `json:"jumlah_tiket"`
+	TotalHarga        *int64       `json:"total_harga"`
+	KodePromo         *string      `json:"kode_promo,omitempty"`
+	Diskon            *int64       `json:"diskon,omitempty"`
+	Akomodasi         *[]string    `json:"akomodasi,omitempty"`
+	CatatanAkomodasi  *string      `json:"catatan_akomodasi,omitempty"`
+	Kursi             []seatHTTP   `json:"kursi,omitempty"`
+	OrderID           *string      `json:"order_id"`
+	Type              *string      `json:"type"`
+	Status            *string      `json:"status"`
+	ImageURI          *string      `json:"image_uri"`
+	NomorTiket        *[]string    `json:"nomor_tiket"`
+	CheckInStatus     *bool        `json:"checkin_status"`
+	CheckInNomorTiket *[]string    `json:"checkin_nomor_tiket"`
+	BatasPembayaran   *time.Time   `json:"batas_pembayaran,omitempty"`
+	RefundNomorTiket  *[]string    `json:"refund_nomor_tiket,omitempty"`
+	Holders           []holderHTTP `json:"holders,omitempty"`
+	UpdateTime        *time.Time   `json:"update_time,omitempty"`
+
+	// WaitlistToken is only read when ordering, to claim a
+	// waitlist offer.
+	WaitlistToken *string `json:"waitlist_token,omitempty"`
+
+	// KursiID is only read when ordering, to select the seats
+	// of the tickets.
+	KursiID *[]int64 `json:"kursi_id,omitempty"`
+}
+
+type seatHTTP struct {
+	ID         *int64  `json:"id"`
+	Section    *string `json:"section"`
+	Baris      *string `json:"baris"`
+	Nomor      *int    `json:"nomor"`
+	Aksesibel  *bool   `json:"aksesibel"`
+	Tersedia   *bool   `json:"tersedia,omitempty"`
+	NomorTiket *string `json:"nomor_tiket,omitempty"`
+}
+
+type seatLayoutHTTP struct {
+	Section []sectionLayoutHTTP `json:"section"`
+}
+
+type sectionLayoutHTTP struct {
+	Nama  *string         `json:"nama"`
+	Baris []rowLayoutHTTP `json:"baris"`
+}
+
+type rowLayoutHTTP struct {
+	Nama           *string `json:"nama"`
+	JumlahKursi    *int    `json:"jumlah_kursi"`
+	KursiAksesibel []int   `json:"kursi_aksesibel,omitempty"`
+}
+
+type accommodationHTTP struct {
+	Disabilitas      *string   `json:"disabilitas"`
+	Akomodasi        *[]string `json:"akomodasi"`
+	CatatanAkomodasi *string   `json:"catatan_akomodasi,omitempty"`
+}
+
+type checkInHTTP struct {
+	holderHTTP
+	Disabilitas      *string   `json:"disabilitas,omitempty"`
+	Akomodasi        *[]string `json:"akomodasi,omitempty"`
+	CatatanAkomodasi *string   `json:"catatan_akomodasi,omitempty"`
+	Kursi            *string   `json:"kursi,omitempty"`
+}
+
+type accommodationReportHTTP struct {
+	KuotaKursi    *int `json:"kuota_kursi"`
+	KursiTerpakai *int `json:"kursi_terpakai"`
+}
+
+type disabilityReportHTTP struct {
+	Disabilitas *string         `json:"disabilitas"`
+	JumlahOrder *int            `json:"jumlah_order"`
+	JumlahTiket *int            `json:"jumlah_tiket"`
+	Akomodasi   map[string]int  `json:"akomodasi"`
+	Orders      []mainEventHTTP `json:"orders"`
+}
+
+type paginationHTTP struct {
+	NextCursor   *string `json:"next_cursor,omitempty"`
+	Total        *int64  `json:"total"`
+	TotalTickets *int64  `json:"total_tickets"`
+}
+
+type refundHTTP struct {
+	ID          *int64     `json:"id"`
+	MainEventID *int64     `json:"mainevent_id"`
+	Email       *string    `json:"email,omitempty"`
+	NomorTiket  *[]string  `json:"nomor_tiket"`
+	Alasan      *string    `json:"alasan"`
+	TotalRefund *int64     `json:"total_refund"`
+	Status      *string    `json:"status"`
+	Metode      *string    `json:"metode,omitempty"`
+	Catatan     *string    `json:"catatan,omitempty"`
+	CreateTime  *time.Time `json:"create_time"`
+	UpdateTime  *time.Time `json:"update_time,omitempty"`
+}
+
+type buyerLoginHTTP struct {
+	Email *string `json:"email,omitempty"`
+	Token *string `json:"token,omitempty"`
+}
+
+type buyerSessionHTTP struct {
+	Token      *string    `json:"token"`
+	ExpireTime *time.Time `json:"expire_time"`
+}
+
+type buyerTransactionHTTP struct {
+	ID                *int64     `json:"id"`
+	OrderID           *string    `json:"order_id"`
+	Nama              *string    `json:"nama"`
+	Tanggal           *string    `json:"tanggal"`
+	JumlahTiket       *int       `json:"jumlah_tiket"`
+	TotalHarga        *int64     `json:"total_harga"`
+	StatusPayment     *string    `json:"status_payment"`
+	NomorTiket        *[]string  `json:"nomor_tiket"`
+	CheckInNomorTiket *[]string  `json:"checkin_nomor_tiket"`
+	CreateTime        *time.Time `json:"create_time"`
+}
+
+type paymentProofHTTP struct {
+	ImageURI *string `json:"image_uri"`
+}
+
+type holderHTTP struct {
+	NomorTiket     *string `json:"nomor_tiket"`
+	Nama           *string `json:"nama"`
+	Email          *string `json:"email"`
+	NomorIdentitas *string `json:"nomor_identitas,omitempty"`
+}
+
+type assignHoldersHTTP struct {
+	Holders []holderHTTP `json:"holders"`
+}
+
+type waitlistEntryHTTP struct {
+	Nama        *string `json:"nama"`
+	Email       *string `json:"email"`
+	JumlahTiket *int    `json:"jumlah_tiket"`
+}
+
+type groupOrderHTTP struct {
+	ID             *int64              `json:"id"`
+	MainEventID    *int64              `json:"mainevent_id,omitempty"`
+	Nama           *string             `json:"nama"`
+	NomorIdentitas *string             `json:"nomor_identitas"`
+	AsalInstitusi  *string             `json:"asal_institusi"`
+	Email          *string             `json:"email"`
+	NomorTelepon   *string             `json:"nomor_telepon"`
+	Peserta        []groupAttendeeHTTP `json:"peserta"`
+	HargaTiket     *int64              `json:"harga_tiket"`
+	TotalHarga     *int64              `json:"total_harga"`
+	Status         *string             `json:"status"`
+	Catatan        *string             `json:"catatan,omitempty"`
+	CreateTime     *time.Time          `json:"create_time"`
+	UpdateTime     *time.Time          `json:"update_time,omitempty"`
+}
+
+type groupAttendeeHTTP struct {
+	Nama           *string `json:"nama"`
+	Email          *string `json:"email"`
+	NomorIdentitas *string `json:"nomor_identitas"`
+}
+
+type institutionHTTP struct {
+	ID              *int64     `json:"id"`
+	Nama            *string    `json:"nama"`
+	MaxTiket        *int       `json:"max_tiket"`
+	KuotaTiket      *int       `json:"kuota_tiket"`
+	RequireApproval *bool      `json:"require_approval"`
+	CreateTime      *time.Time `json:"create_time"`
+	UpdateTime      *time.Time `json:"update_time,omitempty"`
+}
+
+type purchaseLimitHTTP struct {
+	SisaTiket int `json:"sisa_tiket"`
+}
+
+type importRowErrorHTTP struct {
+	Baris int    `json:"baris"`
+	Error string `json:"error"`
 }
@@ -0,0 +1,122 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/tedxub2023/global/helper"
+	"github.com/tedxub2023/global/logger"
+	"github.com/tedxub2023/internal/mainevent"
+)
+
+type institutionHandler struct {
+	mainevent mainevent.Service
+}
+
+func (h *institutionHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	institutionID, err := strconv.ParseInt(vars["id"], 10, 64)
+	if err != nil {
+		logger.Error(r.Context(), "failed to parse institution ID", err, logger.Fields{"id": vars["id"]})
+		helper.WriteErrorResponse(w, http.StatusBadRequest, []string{errInvalidInstitutionID.Error()})
+		return
+	}
+	logger.AddFields(r.Context(), logger.Fields{"institution_id": institutionID})
+
+	switch r.Method {
+	case http.MethodPut:
+		h.handleUpdateInstitution(w, r, institutionID)
+	default:
+		helper.WriteErrorResponse(w, http.StatusMethodNotAllowed, []string{errMethodNotAllowed.Error()})
+	}
+}
+
+func (h *institutionHandler) handleUpdateInstitution(w http.ResponseWriter, r *http.Request, institutionID int64) {
+	// add timeout to context
+	ctx, cancel := context.WithTimeout(r.Context(), 3000*time.Millisecond)
+	defer cancel()
+
+	var (
+		err        error           // stores error in this handler
+		resBody    []byte          // stores response body to write
+		statusCode = http.StatusOK // stores response status code
+	)
+
+	// write response
+	defer func() {
+		if err != nil {
+			logger.Error(ctx, "failed to update institution", err)
+			helper.WriteErrorResponse(w, statusCode, []string{err.Error()})
+			return
+		}
+		// success
+		helper.WriteResponse(w, resBody, statusCode, helper.JSONContentTypeDecorator)
+	}()
+
+	// prepare channels for main go routine
+	resChan := make(chan int64, 1)
+	errChan := make(chan error, 1)
+
+	go func() {
+		// read body
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			statusCode = http.StatusBadRequest
+			errChan <- errBadRequest
+			return
+		}
+
+		// unmarshall body
+		request := institutionHTTP{}
+		err = json.Unmarshal(body, &request)
+		if err != nil {
+			statusCode = http.StatusBadRequest
+			errChan <- errBadRequest
+			return
+		}
+
+		// format HTTP request into service object, the whole
+		// policy is replaced
+		reqInstitution := parseInstitutionFromRequest(request)
+		reqInstitution.ID = institutionID
+
+		err = h.mainevent.UpdateInstitution(ctx, reqInstitution)
+		if err != nil {
+			// determine error and status code, by default its internal error
+			parsedErr := errInternalServer
+			statusCode = http.StatusInternalServerError
+			if v, ok := mapHTTPError[err]; ok {
+				parsedErr = v
+				statusCode = http.StatusBadRequest
+			}
+
+			// log the actual error if its internal error
+			if statusCode == http.StatusInternalServerError {
+				logger.Error(ctx, "internal error from UpdateInstitution", err)
+			}
+
+			errChan <- parsedErr
+			return
+		}
+
+		resChan <- institutionID
+	}()
+
+	// wait and handle main go routine
+	select {
+	case <-ctx.Done():
+		statusCode = http.StatusGatewayTimeout
+		err = errRequestTimeout
+	case err = <-errChan:
+	case institutionID := <-resChan:
+		resBody, err = json.Marshal(helper.ResponseEnvelope{
+			Status: "Success",
+			Data:   institutionID,
+		})
+	}
+}
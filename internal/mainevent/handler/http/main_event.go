@@ -4,7 +4,6 @@ import (
 	"context"
 	"encoding/json"
 	"io/ioutil"
-	"log"
 	"net/http"
 	"net/url"
 	"strconv"
@@ -12,6 +11,7 @@ import (
 
 	"github.com/gorilla/mux"
 	"github.com/tedxub2023/global/helper"
+	"github.com/tedxub2023/global/logger"
 	"github.com/tedxub2023/internal/mainevent"
 )
 
@@ -23,10 +23,11 @@ func (h *maineventHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	maineventID, err := strconv.ParseInt(vars["id"], 10, 64)
 	if err != nil {
-		log.Printf("[MainEvent HTTP][maineventHandler] Failed to parse mainevent ID. ID: %s. Err: %s\n", vars["id"], err.Error())
+		logger.Error(r.Context(), "failed to parse mainevent ID", err, logger.Fields{"id": vars["id"]})
 		helper.WriteErrorResponse(w, http.StatusBadRequest, []string{errInvalidMainEventID.Error()})
 		return
 	}
+	logger.AddFields(r.Context(), logger.Fields{"mainevent_id": maineventID})
 
 	switch r.Method {
 	case http.MethodGet:
@@ -45,7 +46,6 @@ func (h *maineventHandler) handleGetMainEventByID(w http.ResponseWriter, r *http
 
 	var (
 		err        error           // stores error in this handler
-		source     string          // stores request source
 		resBody    []byte          // stores response body to write
 		statusCode = http.StatusOK // stores response status code
 	)
@@ -54,7 +54,7 @@ func (h *maineventHandler) handleGetMainEventByID(w http.ResponseWriter, r *http
 	defer func() {
 		// error
 		if err != nil {
-			log.Printf("[MainEvent HTTP][handleGetMainEventByID] Failed to get mainevent. maineventID: %v. Source: %s, Err: %s\n", maineventID, source, err.Error())
+			logger.Error(ctx, "failed to get mainevent", err)
 			helper.WriteErrorResponse(w, statusCode, []string{err.Error()})
 			return
 		}
@@ -88,7 +88,7 @@ func (h *maineventHandler) handleGetMainEventByID(w http.ResponseWriter, r *http
 
 			// log the actual error if its internal error
 			if statusCode == http.StatusInternalServerError {
-				log.Printf("[MainEvent HTTP][handleGetMainEventByID] Internal error from GetMainEventByID. maineventID: %v. Err: %s\n", maineventID, err.Error())
+				logger.Error(ctx, "internal error from GetMainEventByID", err)
 			}
 
 			errChan <- parsedErr
@@ -134,14 +134,13 @@ func (h *maineventHandler) handleUpdatePaymentStatus(w http.ResponseWriter, r *h
 
 	var (
 		err        error
-		source     string
 		resBody    []byte
 		statusCode = http.StatusOK
 	)
 
 	defer func() {
 		if err != nil {
-			log.Printf("[MainEvent HTTP][handleUpdatePaymentStatus] Failed update mainevent. maineventID: %v. Source: %s, Err: %s\n", maineventID, source, err.Error())
+			logger.Error(ctx, "failed to update mainevent", err)
 			helper.WriteErrorResponse(w, statusCode, []string{err.Error()})
 			return
 		}
@@ -182,7 +181,7 @@ func (h *maineventHandler) handleUpdatePaymentStatus(w http.ResponseWriter, r *h
 
 			// log the actual error if its internal error
 			if statusCode == http.StatusInternalServerError {
-				log.Printf("[MainEvent HTTP][handleGetMainEventByID] Internal error from GetMainEventByID. maineventID: %v. Err: %s\n", maineventID, err.Error())
+				logger.Error(ctx, "internal error from GetMainEventByID", err)
 			}
 
 			errChan <- parsedErr
@@ -205,8 +204,11 @@ func (h *maineventHandler) handleUpdatePaymentStatus(w http.ResponseWriter, r *h
 				parsedErr = v
 				statusCode = http.StatusBadRequest
 			}
+			if parsedErr == errConflict {
+				statusCode = http.StatusConflict
+			}
 			if statusCode == http.StatusInternalServerError {
-				log.Printf("[MainEvent HTTP][handleUpdatePaymentStatus] Internal error from UpdatePaymentStatus. maineventID: %v. Err: %s\n", maineventID, err.Error())
+				logger.Error(ctx, "internal error from UpdatePaymentStatus", err)
 			}
 			errChan <- parsedErr
 			return
@@ -245,5 +247,11 @@ func parseMainEventFromUpdateRequest(meh mainEventHTTP, current mainevent.MainEv
 		result.Status = status
 	}
 
+	// the update is rejected if the mainevent has changed
+	// since the given update time
+	if meh.UpdateTime != nil {
+		result.UpdateTime = *meh.UpdateTime
+	}
+
 	return result, nil
 }
@@ -0,0 +1,138 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/tedxub2023/global/helper"
+	"github.com/tedxub2023/global/logger"
+	"github.com/tedxub2023/internal/mainevent"
+)
+
+type maineventAccommodationHandler struct {
+	mainevent mainevent.Service
+}
+
+func (h *maineventAccommodationHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	maineventID, err := strconv.ParseInt(vars["id"], 10, 64)
+	if err != nil {
+		logger.Error(r.Context(), "failed to parse mainevent ID", err, logger.Fields{"id": vars["id"]})
+		helper.WriteErrorResponse(w, http.StatusBadRequest, []string{errInvalidMainEventID.Error()})
+		return
+	}
+	logger.AddFields(r.Context(), logger.Fields{"mainevent_id": maineventID})
+
+	switch r.Method {
+	case http.MethodPut:
+		h.handleUpdateAccommodation(w, r, maineventID)
+	default:
+		helper.WriteErrorResponse(w, http.StatusMethodNotAllowed, []string{errMethodNotAllowed.Error()})
+	}
+}
+
+func (h *maineventAccommodationHandler) handleUpdateAccommodation(w http.ResponseWriter, r *http.Request, maineventID int64) {
+	// add timeout to context
+	ctx, cancel := context.WithTimeout(r.Context(), 5000*time.Millisecond)
+	defer cancel()
+
+	var (
+		err        error           // stores error in this handler
+		resBody    []byte          // stores response body to write
+		statusCode = http.StatusOK // stores response status code
+	)
+
+	// write response
+	defer func() {
+		if err != nil {
+			logger.Error(ctx, "failed to update accommodation", err)
+			helper.WriteErrorResponse(w, statusCode, []string{err.Error()})
+			return
+		}
+		// success
+		helper.WriteResponse(w, resBody, statusCode, helper.JSONContentTypeDecorator)
+	}()
+
+	// prepare channels for main go routine
+	resChan := make(chan int64, 1)
+	errChan := make(chan error, 1)
+
+	go func() {
+		// read body
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			statusCode = http.StatusBadRequest
+			errChan <- errBadRequest
+			return
+		}
+
+		// unmarshall body
+		request := accommodationHTTP{}
+		err = json.Unmarshal(body, &request)
+		if err != nil || request.Disabilitas == nil || request.Akomodasi == nil {
+			statusCode = http.StatusBadRequest
+			errChan <- errBadRequest
+			return
+		}
+
+		// format HTTP request into service object
+		reqMainEvent := mainevent.MainEvent{
+			ID:        maineventID,
+			Akomodasi: parseAccommodations(*request.Akomodasi),
+		}
+		reqMainEvent.Disabilitas, err = parseDisability(*request.Disabilitas)
+		if err != nil {
+			statusCode = http.StatusBadRequest
+			errChan <- err
+			return
+		}
+		if request.CatatanAkomodasi != nil {
+			reqMainEvent.CatatanAkomodasi = *request.CatatanAkomodasi
+		}
+
+		err = h.mainevent.UpdateAccommodation(ctx, reqMainEvent)
+		if err != nil {
+			// determine error and status code, by default its internal error
+			parsedErr := errInternalServer
+			statusCode = http.StatusInternalServerError
+			if v, ok := mapHTTPError[err]; ok {
+				parsedErr = v
+				statusCode = http.StatusBadRequest
+			}
+
+			// the mainevent was updated by another request at
+			// the same time, the update can be retried
+			if parsedErr == errConflict {
+				statusCode = http.StatusConflict
+			}
+
+			// log the actual error if its internal error
+			if statusCode == http.StatusInternalServerError {
+				logger.Error(ctx, "internal error from UpdateAccommodation", err)
+			}
+
+			errChan <- parsedErr
+			return
+		}
+
+		resChan <- maineventID
+	}()
+
+	// wait and handle main go routine
+	select {
+	case <-ctx.Done():
+		statusCode = http.StatusGatewayTimeout
+		err = errRequestTimeout
+	case err = <-errChan:
+	case res := <-resChan:
+		resBody, err = json.Marshal(helper.ResponseEnvelope{
+			Status: "Success",
+			Data:   res,
+		})
+	}
+}
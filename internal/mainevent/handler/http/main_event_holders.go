@@ -0,0 +1,127 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/tedxub2023/global/auth"
+	"github.com/tedxub2023/global/helper"
+	"github.com/tedxub2023/global/logger"
+	"github.com/tedxub2023/internal/mainevent"
+)
+
+type maineventHoldersHandler struct {
+	mainevent mainevent.Service
+}
+
+func (h *maineventHoldersHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	maineventID, err := strconv.ParseInt(vars["id"], 10, 64)
+	if err != nil {
+		logger.Error(r.Context(), "failed to parse mainevent ID", err, logger.Fields{"id": vars["id"]})
+		helper.WriteErrorResponse(w, http.StatusBadRequest, []string{errInvalidMainEventID.Error()})
+		return
+	}
+	logger.AddFields(r.Context(), logger.Fields{"mainevent_id": maineventID})
+
+	switch r.Method {
+	case http.MethodPut:
+		h.handleAssignHolders(w, r, maineventID)
+	default:
+		helper.WriteErrorResponse(w, http.StatusMethodNotAllowed, []string{errMethodNotAllowed.Error()})
+	}
+}
+
+func (h *maineventHoldersHandler) handleAssignHolders(w http.ResponseWriter, r *http.Request, maineventID int64) {
+	// add timeout to context
+	ctx, cancel := context.WithTimeout(r.Context(), 5000*time.Millisecond)
+	defer cancel()
+
+	var (
+		err        error           // stores error in this handler
+		resBody    []byte          // stores response body to write
+		statusCode = http.StatusOK // stores response status code
+	)
+
+	// write response
+	defer func() {
+		if err != nil {
+			logger.Error(ctx, "failed to assign holders", err)
+			helper.WriteErrorResponse(w, statusCode, []string{err.Error()})
+			return
+		}
+		// success
+		helper.WriteResponse(w, resBody, statusCode, helper.JSONContentTypeDecorator)
+	}()
+
+	// prepare channels for main go routine
+	resChan := make(chan []holderHTTP, 1)
+	errChan := make(chan error, 1)
+
+	go func() {
+		// read body
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			statusCode = http.StatusBadRequest
+			errChan <- errBadRequest
+			return
+		}
+
+		// unmarshall body
+		request := assignHoldersHTTP{}
+		err = json.Unmarshal(body, &request)
+		if err != nil || len(request.Holders) == 0 {
+			statusCode = http.StatusBadRequest
+			errChan <- errBadRequest
+			return
+		}
+
+		// format HTTP request into service objects
+		holders := make([]mainevent.Holder, 0, len(request.Holders))
+		for _, reqHolder := range request.Holders {
+			holder := mainevent.Holder{
+				MainEventID: maineventID,
+			}
+			if reqHolder.NomorTiket != nil {
+				holder.NomorTiket = *reqHolder.NomorTiket
+			}
+			if reqHolder.Nama != nil {
+				holder.Nama = *reqHolder.Nama
+			}
+			if reqHolder.Email != nil {
+				holder.Email = *reqHolder.Email
+			}
+			if reqHolder.NomorIdentitas != nil {
+				holder.NomorIdentitas = *reqHolder.NomorIdentitas
+			}
+			holders = append(holders, holder)
+		}
+
+		err = h.mainevent.AssignHolders(ctx, auth.BearerToken(r), maineventID, holders)
+		if err != nil {
+			statusCode, err = buyerError(ctx, "AssignHolders", err)
+			errChan <- err
+			return
+		}
+
+		resChan <- request.Holders
+	}()
+
+	// wait and handle main go routine
+	select {
+	case <-ctx.Done():
+		statusCode = http.StatusGatewayTimeout
+		err = errRequestTimeout
+	case err = <-errChan:
+	case res := <-resChan:
+		resBody, err = json.Marshal(helper.ResponseEnvelope{
+			Status: "Success",
+			Data:   res,
+		})
+	}
+}
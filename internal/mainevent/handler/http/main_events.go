@@ -3,14 +3,16 @@ package http
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"io/ioutil"
-	"log"
 	"net/http"
 	"net/url"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/tedxub2023/global/helper"
+	"github.com/tedxub2023/global/logger"
 	"github.com/tedxub2023/internal/mainevent"
 )
 
@@ -36,7 +38,6 @@ func (h *maineventsHandler) handleGetAllMainEvents(w http.ResponseWriter, r *htt
 
 	var (
 		err        error           // stores error in this handler
-		source     string          // stores request source
 		resBody    []byte          // stores response body to write
 		statusCode = http.StatusOK // stores response status code
 	)
@@ -45,7 +46,7 @@ func (h *maineventsHandler) handleGetAllMainEvents(w http.ResponseWriter, r *htt
 	defer func() {
 		// error
 		if err != nil {
-			log.Printf("[Transaction HTTP][handleGetAllMainEvents] Failed to get all mainevent. Source: %s, Err: %s\n", source, err.Error())
+			logger.Error(ctx, "failed to get all mainevents", err)
 			helper.WriteErrorResponse(w, statusCode, []string{err.Error()})
 			return
 		}
@@ -54,7 +55,11 @@ func (h *maineventsHandler) handleGetAllMainEvents(w http.ResponseWriter, r *htt
 	}()
 
 	// prepare channels for main go routine
-	resChan := make(chan []mainevent.MainEvent, 1)
+	type result struct {
+		mainevents []mainevent.MainEvent
+		pagination mainevent.Pagination
+	}
+	resChan := make(chan result, 1)
 	errChan := make(chan error, 1)
 
 	go func() {
@@ -64,7 +69,7 @@ func (h *maineventsHandler) handleGetAllMainEvents(w http.ResponseWriter, r *htt
 			errChan <- err
 			return
 		}
-		res, err := h.mainevent.GetAllMainEvents(ctx, filter)
+		res, pagination, err := h.mainevent.GetAllMainEvents(ctx, filter)
 		if err != nil {
 			// determine error and status code, by default its internal error
 			parsedErr := errInternalServer
@@ -76,14 +81,17 @@ func (h *maineventsHandler) handleGetAllMainEvents(w http.ResponseWriter, r *htt
 
 			// log the actual error if its internal error
 			if statusCode == http.StatusInternalServerError {
-				log.Printf("[Match HTTP][handleGetAllGames] Internal error from GetAllMatchs. Err: %s\n", err.Error())
+				logger.Error(ctx, "internal error from GetAllMainEvents", err)
 			}
 
 			errChan <- parsedErr
 			return
 		}
 
-		resChan <- res
+		resChan <- result{
+			mainevents: res,
+			pagination: pagination,
+		}
 	}()
 
 	// wait and handle main go routine
@@ -95,7 +103,7 @@ func (h *maineventsHandler) handleGetAllMainEvents(w http.ResponseWriter, r *htt
 	case res := <-resChan:
 		// format each transactions
 		mainevents := make([]mainEventHTTP, 0)
-		for _, r := range res {
+		for _, r := range res.mainevents {
 			var m mainEventHTTP
 			m, err = formatMainEvent(r)
 			if err != nil {
@@ -107,6 +115,7 @@ func (h *maineventsHandler) handleGetAllMainEvents(w http.ResponseWriter, r *htt
 		// construct response data
 		resBody, err = json.Marshal(helper.ResponseEnvelope{
 			Data: mainevents,
+			Meta: formatPagination(res.pagination),
 		})
 	}
 }
@@ -118,16 +127,16 @@ func (h *maineventsHandler) handleReplaceMainEventByEmail(w http.ResponseWriter,
 
 	var (
 		err        error           // stores error in this handler
-		source     string          // stores request source
 		resBody    []byte          // stores response body to write
+		errMeta    interface{}     // stores details of the error
 		statusCode = http.StatusOK // stores response status code
 	)
 
 	// write response
 	defer func() {
 		if err != nil {
-			log.Printf("[MainEvent HTTP][handleReplaceMainEventByEmail] Failed to replace MainEvents by email. Source: %s, Err: %s\n", source, err.Error())
-			helper.WriteErrorResponse(w, statusCode, []string{err.Error()})
+			logger.Error(ctx, "failed to replace mainevent by email", err)
+			helper.WriteErrorResponseWithMeta(w, statusCode, []string{err.Error()}, errMeta)
 			return
 		}
 		// success
@@ -164,19 +173,31 @@ func (h *maineventsHandler) handleReplaceMainEventByEmail(w http.ResponseWriter,
 			return
 		}
 
-		ticketID, err := h.mainevent.ReplaceMainEventByEmail(ctx, reqMainEvent)
+		// an order with a waitlist token claims the tickets
+		// held for the waitlist offer
+		var ticketID int64
+		if request.WaitlistToken != nil {
+			ticketID, err = h.mainevent.ClaimWaitlistOffer(ctx, *request.WaitlistToken, reqMainEvent)
+		} else {
+			ticketID, err = h.mainevent.ReplaceMainEventByEmail(ctx, reqMainEvent)
+		}
 		if err != nil {
 			// determine error and status code, by default its internal error
 			parsedErr := errInternalServer
 			statusCode = http.StatusInternalServerError
+			var limitErr mainevent.PurchaseLimitError
 			if v, ok := mapHTTPError[err]; ok {
 				parsedErr = v
 				statusCode = http.StatusBadRequest
+			} else if errors.As(err, &limitErr) {
+				parsedErr = errPurchaseLimitReached
+				statusCode = http.StatusBadRequest
+				errMeta = purchaseLimitHTTP{SisaTiket: limitErr.Remaining}
 			}
 
 			// log the actual error if its internal error
 			if statusCode == http.StatusInternalServerError {
-				log.Printf("[MainEvent HTTP][handleReplaceMainEventByEmail] Internal error from ReplaceMainEventByEmail. Err: %s\n", err.Error())
+				logger.Error(ctx, "internal error from ReplaceMainEventByEmail or ClaimWaitlistOffer", err)
 			}
 
 			errChan <- parsedErr
@@ -242,32 +263,102 @@ func parseMainEventFromCreateRequest(meh mainEventHTTP) (mainevent.MainEvent, er
 		result.JumlahTiket = *meh.JumlahTiket
 	}
 
+	if meh.KodePromo != nil {
+		result.KodePromo = *meh.KodePromo
+	}
+
+	if meh.Akomodasi != nil {
+		result.Akomodasi = parseAccommodations(*meh.Akomodasi)
+	}
+
+	if meh.CatatanAkomodasi != nil {
+		result.CatatanAkomodasi = *meh.CatatanAkomodasi
+	}
+
+	if meh.KursiID != nil {
+		result.SeatIDs = *meh.KursiID
+	}
+
 	return result, nil
 }
 
+// maxListLimit is the maximum page size of GET /mainevents.
+const maxListLimit = 500
+
+// parseGetMainEventsFilters returns the filter of
+// GetAllMainEvents from the given query parameters.
 func parseGetMainEventsFilters(request url.Values) (mainevent.GetAllMainEventsFilter, error) {
 	result := mainevent.GetAllMainEventsFilter{}
 
-	var types mainevent.Type
-	if TypeStr := request.Get("type"); TypeStr != "" {
-		parsedType, err := parseType(TypeStr)
+	if typeStr := request.Get("type"); typeStr != "" {
+		parsedType, err := parseType(typeStr)
 		if err != nil {
 			return result, errInvalidMainEventType
 		}
-		types = parsedType
+		result.Type = parsedType
+	}
+
+	if statusStr := request.Get("status"); statusStr != "" {
+		parsedStatus, err := parseStatus(statusStr)
+		if err != nil {
+			return result, errInvalidMainEventStatus
+		}
+		result.Status = parsedStatus
+	}
+
+	if disabilityStr := request.Get("disabilitas"); disabilityStr != "" {
+		parsedDisability, err := parseDisability(disabilityStr)
+		if err != nil {
+			return result, errInvalidMainEventDisability
+		}
+		result.Disabilitas = parsedDisability
 	}
 
-	var checkinStatus bool
 	if checkinStr := request.Get("checkin"); checkinStr != "" {
 		parsedCheckinStatus, err := strconv.ParseBool(checkinStr)
 		if err != nil {
-			return result, mainevent.ErrTicketAlreadyCheckedIn
+			return result, errInvalidCheckInStatus
+		}
+		result.CheckInStatus = &parsedCheckinStatus
+	}
+
+	result.Search = strings.TrimSpace(request.Get("q"))
+
+	if fromStr := request.Get("from"); fromStr != "" {
+		from, _, err := parseDate(fromStr)
+		if err != nil {
+			return result, errInvalidDateRange
 		}
-		checkinStatus = parsedCheckinStatus
+		result.CreateTimeFrom = from
 	}
 
-	return mainevent.GetAllMainEventsFilter{
-		Type:          types,
-		CheckInStatus: checkinStatus,
-	}, nil
+	if toStr := request.Get("to"); toStr != "" {
+		to, dateOnly, err := parseDate(toStr)
+		if err != nil {
+			return result, errInvalidDateRange
+		}
+		// a date only "to" includes the whole day
+		if dateOnly {
+			to = to.AddDate(0, 0, 1)
+		}
+		result.CreateTimeTo = to
+	}
+
+	if !result.CreateTimeFrom.IsZero() && !result.CreateTimeTo.IsZero() && !result.CreateTimeFrom.Before(result.CreateTimeTo) {
+		return result, errInvalidDateRange
+	}
+
+	result.SortBy = mainevent.SortBy(request.Get("sort"))
+	result.SortOrder = mainevent.SortOrder(request.Get("order"))
+	result.Cursor = request.Get("cursor")
+
+	if limitStr := request.Get("limit"); limitStr != "" {
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil || limit <= 0 || limit > maxListLimit {
+			return result, errInvalidLimit
+		}
+		result.Limit = limit
+	}
+
+	return result, nil
 }
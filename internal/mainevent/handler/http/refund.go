@@ -0,0 +1,127 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/tedxub2023/global/helper"
+	"github.com/tedxub2023/global/logger"
+	"github.com/tedxub2023/internal/mainevent"
+)
+
+type refundHandler struct {
+	mainevent mainevent.Service
+}
+
+func (h *refundHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	refundID, err := strconv.ParseInt(vars["id"], 10, 64)
+	if err != nil {
+		logger.Error(r.Context(), "failed to parse refund ID", err, logger.Fields{"id": vars["id"]})
+		helper.WriteErrorResponse(w, http.StatusBadRequest, []string{errInvalidRefundID.Error()})
+		return
+	}
+	logger.AddFields(r.Context(), logger.Fields{"refund_id": refundID})
+
+	switch r.Method {
+	case http.MethodPatch:
+		h.handleProcessRefund(w, r, refundID)
+	default:
+		helper.WriteErrorResponse(w, http.StatusMethodNotAllowed, []string{errMethodNotAllowed.Error()})
+	}
+}
+
+func (h *refundHandler) handleProcessRefund(w http.ResponseWriter, r *http.Request, refundID int64) {
+	// add timeout to context, the gateway refund may be slow
+	ctx, cancel := context.WithTimeout(r.Context(), 15000*time.Millisecond)
+	defer cancel()
+
+	var (
+		err        error
+		resBody    []byte
+		statusCode = http.StatusOK
+	)
+
+	defer func() {
+		if err != nil {
+			logger.Error(ctx, "failed to process refund", err)
+			helper.WriteErrorResponse(w, statusCode, []string{err.Error()})
+			return
+		}
+		helper.WriteResponse(w, resBody, statusCode, helper.JSONContentTypeDecorator)
+	}()
+
+	errChan := make(chan error, 1)
+	resChan := make(chan int64, 1)
+
+	go func() {
+		// read body
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			statusCode = http.StatusBadRequest
+			errChan <- errBadRequest
+			return
+		}
+
+		// unmarshall body
+		request := refundHTTP{}
+		err = json.Unmarshal(body, &request)
+		if err != nil {
+			statusCode = http.StatusBadRequest
+			errChan <- errBadRequest
+			return
+		}
+
+		// format HTTP request into service object, the money
+		// is returned manually unless stated otherwise
+		reqRefund := mainevent.Refund{
+			ID:     refundID,
+			Metode: mainevent.RefundMethodManual,
+		}
+		if request.Status != nil {
+			reqRefund.Status = mainevent.RefundStatus(*request.Status)
+		}
+		if request.Metode != nil {
+			reqRefund.Metode = mainevent.RefundMethod(*request.Metode)
+		}
+		if request.Catatan != nil {
+			reqRefund.Catatan = *request.Catatan
+		}
+
+		err = h.mainevent.ProcessRefund(ctx, reqRefund)
+		if err != nil {
+			parsedErr := errInternalServer
+			statusCode = http.StatusInternalServerError
+			if v, ok := mapHTTPError[err]; ok {
+				parsedErr = v
+				statusCode = http.StatusBadRequest
+			}
+			if parsedErr == errConflict || parsedErr == errRefundAlreadyProcessed {
+				statusCode = http.StatusConflict
+			}
+			if statusCode == http.StatusInternalServerError {
+				logger.Error(ctx, "internal error from ProcessRefund", err)
+			}
+			errChan <- parsedErr
+			return
+		}
+		resChan <- refundID
+	}()
+
+	select {
+	case <-ctx.Done():
+		err = errRequestTimeout
+		statusCode = http.StatusGatewayTimeout
+	case err = <-errChan:
+	case refundID := <-resChan:
+		resBody, err = json.Marshal(helper.ResponseEnvelope{
+			Status: "Success",
+			Data:   refundID,
+		})
+	}
+}
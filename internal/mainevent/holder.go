@@ -0,0 +1,33 @@
+package mainevent
+
+import "time"
+
+// Holder is the attendee holding a ticket of a mainevent.
+type Holder struct {
+	MainEventID    int64
+	NomorTiket     string
+	Nama           string
+	Email          string
+	NomorIdentitas string
+	CreateTime     time.Time
+	UpdateTime     time.Time
+}
+
+// HolderOf returns the holder of the given ticket number of
+// the mainevent, which is the buyer unless the ticket has
+// been assigned to another attendee.
+func (m MainEvent) HolderOf(nomorTiket string) Holder {
+	for _, h := range m.Holders {
+		if h.NomorTiket == nomorTiket {
+			return h
+		}
+	}
+
+	return Holder{
+		MainEventID:    m.ID,
+		NomorTiket:     nomorTiket,
+		Nama:           m.Nama,
+		Email:          m.Email,
+		NomorIdentitas: m.NomorIdentitas,
+	}
+}
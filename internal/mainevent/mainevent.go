@@ -3,6 +3,8 @@ package mainevent
 import (
 	"context"
 	"time"
+
+	"github.com/tedxub2023/internal/transaction"
 )
 
 type Service interface {
@@ -10,51 +12,286 @@ type Service interface {
 	// with the given email
 	ReplaceMainEventByEmail(ctx context.Context, mainevent MainEvent) (int64, error)
 
+	// ClaimWaitlistOffer replaces all mainevent with the
+	// email of the given mainevent like
+	// ReplaceMainEventByEmail, taking the tickets held for the
+	// waitlist offer of the given claim token.
+	ClaimWaitlistOffer(ctx context.Context, token string, mainevent MainEvent) (int64, error)
+
+	// JoinWaitlist puts the given entry at the end of the
+	// waitlist of the normal sale tickets and returns the
+	// created entry ID. It returns ErrTicketsAvailable if the
+	// tickets may be ordered right away.
+	JoinWaitlist(ctx context.Context, entry WaitlistEntry) (int64, error)
+
 	// GetMainEventByID returns a mainevent with the given
 	// mainevent ID.
 	GetMainEventByID(ctx context.Context, maineventID int64, nomorTiket string) (MainEvent, error)
 
-	// GetAllMainEvents returns all mainevent and filter by status and tanggal.
-	GetAllMainEvents(ctx context.Context, filter GetAllMainEventsFilter) ([]MainEvent, error)
+	// GetAllMainEvents returns the mainevents matching the
+	// given filter, one page at a time if the filter has a
+	// limit, together with the pagination info.
+	GetAllMainEvents(ctx context.Context, filter GetAllMainEventsFilter) ([]MainEvent, Pagination, error)
+
+	// UpdateCheckInStatus checks in the ticket with the given
+	// mainevent ID and ticket number, and returns the holder
+	// and the accommodations of the ticket.
+	UpdateCheckInStatus(ctx context.Context, id int64, nomorTiket string) (CheckIn, error)
+
+	// UpdateAccommodation updates the disability and the
+	// accommodations of the mainevent with the given ID.
+	UpdateAccommodation(ctx context.Context, reqMainEvent MainEvent) error
+
+	// GetAccommodationReport returns the accommodations of the
+	// settled mainevents of the event on sale grouped by
+	// disability.
+	GetAccommodationReport(ctx context.Context) (AccommodationReport, error)
+
+	// GetAllSeats returns all seats of the venue in the order
+	// of the seat layout.
+	GetAllSeats(ctx context.Context) ([]Seat, error)
+
+	// LoadSeatLayout replaces the seats of the venue with the
+	// seats of the given layout. The seats held or seated are
+	// kept, it returns ErrSeatInUse if the layout removes
+	// any of them.
+	LoadSeatLayout(ctx context.Context, layout SeatLayout) error
+
+	// RequestBuyerLogin emails a login link to the buyer with
+	// the given email if they have any mainevent, and does
+	// nothing otherwise.
+	RequestBuyerLogin(ctx context.Context, email string) error
+
+	// VerifyBuyerLogin uses the login link with the given
+	// token and returns the session it opens.
+	VerifyBuyerLogin(ctx context.Context, token string) (BuyerLogin, error)
+
+	// GetBuyerMainEvents returns the mainevents of the buyer
+	// of the given session across all events, newest first.
+	GetBuyerMainEvents(ctx context.Context, session string) ([]MainEvent, error)
+
+	// GetBuyerTransactions returns the Semayam Asa
+	// transactions of the buyer of the given session, newest
+	// first.
+	GetBuyerTransactions(ctx context.Context, session string) ([]transaction.Transaction, error)
+
+	// UploadBuyerPaymentProof uploads the payment proof of the
+	// mainevent with the given ID on behalf of the buyer of
+	// the given session, replacing the one waiting for
+	// confirmation if any.
+	UploadBuyerPaymentProof(ctx context.Context, session string, maineventID int64, imageURI string) error
+
+	// GetBuyerTicketPDF returns the ticket PDF of the settled
+	// mainevent with the given ID of the buyer of the given
+	// session, rendering it again if it is missing.
+	GetBuyerTicketPDF(ctx context.Context, session string, maineventID int64) ([]byte, error)
 
-	// UpdateCheckInStatus returns a ticket number and status with the giveb
-	// mainevent ID and ticket number
-	UpdateCheckInStatus(ctx context.Context, id int64, nomorTiket string) (string, error)
+	// ResendBuyerMail sends the latest email of the mainevent
+	// with the given ID to the buyer of the given session
+	// again, the ticket email if it is settled and the order
+	// confirmation email otherwise.
+	ResendBuyerMail(ctx context.Context, session string, maineventID int64) error
 
 	// UpdatePaymentStatus update the payment status in DB
-	// and send a email after completed payment
+	// and runs the effects of the status transition, see
+	// Transition. It returns ErrConflict if the mainevent has
+	// been updated since reqMainEvent.UpdateTime.
 	UpdatePaymentStatus(ctx context.Context, reqMainEvent MainEvent) error
+
+	// AssignHolders assigns the tickets of a settled
+	// mainevent to the given holders, on behalf of the buyer
+	// of the given session. Assigning a ticket again
+	// transfers it to the new holder. Each new holder gets
+	// the PDF of their ticket by email.
+	AssignHolders(ctx context.Context, session string, maineventID int64, holders []Holder) error
+
+	// RequestRefund creates a refund request of some tickets
+	// of a settled mainevent, on behalf of the buyer of the
+	// given session, and returns the created refund ID.
+	RequestRefund(ctx context.Context, session string, reqRefund Refund) (int64, error)
+
+	// GetAllRefunds returns the refunds with the given
+	// status, all refunds if it is unknown, oldest first.
+	GetAllRefunds(ctx context.Context, status RefundStatus) ([]Refund, error)
+
+	// ProcessRefund approves or rejects a requested refund.
+	// An approved refund invalidates its tickets, returns
+	// them to the inventory and, with RefundMethodGateway,
+	// refunds the payment through the payment gateway. The
+	// buyer is notified by email either way.
+	ProcessRefund(ctx context.Context, reqRefund Refund) error
+
+	// CreateGroupOrder creates a group order following the
+	// policy of its institution and returns the created
+	// group order ID. The invoice is issued right away unless
+	// the policy requires the approval of the committee.
+	CreateGroupOrder(ctx context.Context, reqGroup GroupOrder) (int64, error)
+
+	// GetAllGroupOrders returns the group orders with the
+	// given status, all group orders if it is unknown, oldest
+	// first.
+	GetAllGroupOrders(ctx context.Context, status GroupStatus) ([]GroupOrder, error)
+
+	// ProcessGroupOrder approves or rejects a requested group
+	// order. An approved group order gets its invoice. The
+	// coordinator is notified by email either way.
+	ProcessGroupOrder(ctx context.Context, reqGroup GroupOrder) error
+
+	// CreateInstitution creates the group order policy of an
+	// institution and returns the created institution ID.
+	CreateInstitution(ctx context.Context, reqInstitution Institution) (int64, error)
+
+	// GetAllInstitutions returns all institutions sorted by
+	// name.
+	GetAllInstitutions(ctx context.Context) ([]Institution, error)
+
+	// UpdateInstitution updates the group order policy of an
+	// institution.
+	UpdateInstitution(ctx context.Context, reqInstitution Institution) error
+
+	// ImportComplimentary creates a settled order of
+	// TypeComplimentary for each of the given orders of the
+	// event on sale, issues their tickets and emails them, and
+	// returns the created orders in the same order. None is
+	// created if any of the orders is invalid, in which case
+	// it returns ImportError.
+	ImportComplimentary(ctx context.Context, orders []MainEvent) ([]MainEvent, error)
 }
 
 // MainEvent is a mainevent.
 
 type MainEvent struct {
-	ID                int64
-	Nama              string
-	Disabilitas       Disability
-	NomorIdentitas    string
-	AsalInstitusi     string
-	Email             string
-	NomorTelepon      string
-	Type              Type
-	JumlahTiket       int
-	TotalHarga        int64
+	ID int64
+
+	// EventID is the event the mainevent is ordered for.
+	EventID int64
+
+	Nama           string
+	Disabilitas    Disability
+	NomorIdentitas string
+	AsalInstitusi  string
+	Email          string
+	NomorTelepon   string
+	Type           Type
+	JumlahTiket    int
+	TotalHarga     int64
+
+	// KodePromo is the promo code applied to the order and
+	// Diskon its discount, already taken off TotalHarga.
+	KodePromo string
+	Diskon    int64
+
 	Status            Status
 	OrderID           string
 	ImageURI          string
 	NomorTiket        []string
 	CheckInStatus     bool
 	CheckInNomorTiket []string
-	CreateTime        time.Time
-	UpdateTime        time.Time
+
+	// RefundNomorTiket are the refunded ticket numbers, they
+	// are no longer valid at the check in.
+	RefundNomorTiket []string
+
+	// Akomodasi are the accommodations the buyer needs at the
+	// venue, and CatatanAkomodasi the details for the
+	// committee.
+	Akomodasi        []Accommodation
+	CatatanAkomodasi string
+
+	// SeatIDs are the seats the buyer selects when ordering,
+	// they are held for the mainevent until it is settled or
+	// deleted.
+	SeatIDs []int64
+
+	// Seats are the seats held for the mainevent or seated
+	// with its tickets. They are only loaded by
+	// GetMainEventByID and when the tickets are issued.
+	Seats []Seat
+
+	// Holders are the attendees assigned to some of the
+	// tickets, the other tickets are held by the buyer. They
+	// are only loaded by GetMainEventByID.
+	Holders []Holder
+
+	CreateTime time.Time
+	UpdateTime time.Time
 }
 
+// GetAllMainEventsFilter is the filter of GetAllMainEvents.
+// Zero values mean no filter.
 type GetAllMainEventsFilter struct {
+	EventID       int64
 	Type          Type
 	Status        Status
-	CheckInStatus bool
+	Disabilitas   Disability
+	CheckInStatus *bool
+
+	// Email matches the orders of the buyer with the given
+	// email, case-insensitively.
+	Email string
+
+	// Search matches the name, email, order ID or ticket
+	// numbers containing the given text, case-insensitively.
+	Search string
+
+	// CreateTimeFrom and CreateTimeTo filter the orders
+	// created within [CreateTimeFrom, CreateTimeTo).
+	CreateTimeFrom time.Time
+	CreateTimeTo   time.Time
+
+	// SortBy and SortOrder default to SortByCreateTime and
+	// SortOrderDesc.
+	SortBy    SortBy
+	SortOrder SortOrder
+
+	// Cursor is the Pagination.NextCursor of the previous
+	// page. Limit is the page size, all matching mainevents
+	// are returned if it is zero.
+	Cursor string
+	Limit  int
 }
 
+// Pagination is the pagination info of GetAllMainEvents.
+type Pagination struct {
+	// NextCursor is the cursor of the next page, it is empty
+	// on the last page.
+	NextCursor string
+
+	// Total is the number of mainevents matching the filter
+	// across all pages, and TotalTickets is the sum of their
+	// JumlahTiket.
+	Total        int64
+	TotalTickets int64
+}
+
+// SortBy denotes the field mainevents are sorted by.
+type SortBy string
+
+// Followings are the known sort fields.
+const (
+	SortByCreateTime  SortBy = "create_time"
+	SortByNama        SortBy = "nama"
+	SortByJumlahTiket SortBy = "jumlah_tiket"
+	SortByTotalHarga  SortBy = "total_harga"
+)
+
+// SortByList is a list of valid sort fields.
+var SortByList = map[SortBy]struct{}{
+	SortByCreateTime:  {},
+	SortByNama:        {},
+	SortByJumlahTiket: {},
+	SortByTotalHarga:  {},
+}
+
+// SortOrder denotes the sort direction.
+type SortOrder string
+
+// Followings are the known sort directions.
+const (
+	SortOrderAsc  SortOrder = "asc"
+	SortOrderDesc SortOrder = "desc"
+)
+
 // Type denotes type of a schedule.
 type Type int
 
@@ -64,21 +301,34 @@ const (
 	TypeEarlyBird  Type = 1
 	TypePresale    Type = 2
 	TypeNormalSale Type = 3
+
+	// TypeGroup is the invoice of a group order, its tickets
+	// are taken from the normal sale tickets.
+	TypeGroup Type = 4
+
+	// TypeComplimentary is a free order imported by the
+	// committee for the speakers, sponsors and volunteers, its
+	// tickets are not taken from the tickets on sale.
+	TypeComplimentary Type = 5
 )
 
 var (
 	// TypeList is a list of valid type.
 	TypeList = map[Type]struct{}{
-		TypeEarlyBird:  {},
-		TypePresale:    {},
-		TypeNormalSale: {},
+		TypeEarlyBird:     {},
+		TypePresale:       {},
+		TypeNormalSale:    {},
+		TypeGroup:         {},
+		TypeComplimentary: {},
 	}
 
 	// typeName maps type to it's string representation.
 	typeName = map[Type]string{
-		TypeEarlyBird:  "early-bird",
-		TypePresale:    "pre-sale",
-		TypeNormalSale: "normal-sale",
+		TypeEarlyBird:     "early-bird",
+		TypePresale:       "pre-sale",
+		TypeNormalSale:    "normal-sale",
+		TypeGroup:         "group",
+		TypeComplimentary: "complimentary",
 	}
 )
 
@@ -101,6 +351,7 @@ const (
 	StatusPending    Status = 1
 	StatusUnpaid     Status = 2
 	StatusSettlement Status = 3
+	StatusRefunded   Status = 4
 )
 
 var (
@@ -109,6 +360,7 @@ var (
 		StatusPending:    {},
 		StatusUnpaid:     {},
 		StatusSettlement: {},
+		StatusRefunded:   {},
 	}
 
 	// StatusName maps status to it's string representation.
@@ -116,6 +368,7 @@ var (
 		StatusPending:    "pending",
 		StatusUnpaid:     "unpaid",
 		StatusSettlement: "settlement",
+		StatusRefunded:   "refunded",
 	}
 )
 
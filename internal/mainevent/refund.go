@@ -0,0 +1,86 @@
+package mainevent
+
+import "time"
+
+// Refund is a cancellation request of some tickets of a
+// mainevent.
+type Refund struct {
+	ID          int64
+	MainEventID int64
+	NomorTiket  []string
+	Alasan      string
+
+	// TotalRefund is the refunded amount, the price of each
+	// ticket of the mainevent times the number of tickets, up
+	// to what is left of its payment after its other refunds.
+	TotalRefund int64
+
+	Status RefundStatus
+	Metode RefundMethod
+
+	// Catatan is the note of the committee when processing
+	// the refund, e.g. the reason of a rejection.
+	Catatan string
+
+	CreateTime time.Time
+	UpdateTime time.Time
+}
+
+// RefundStatus denotes status of a refund.
+type RefundStatus string
+
+// Followings are the known refund status.
+const (
+	RefundStatusUnknown   RefundStatus = ""
+	RefundStatusRequested RefundStatus = "requested"
+	RefundStatusApproved  RefundStatus = "approved"
+	RefundStatusRejected  RefundStatus = "rejected"
+)
+
+// RefundStatusList is a list of valid refund status.
+var RefundStatusList = map[RefundStatus]struct{}{
+	RefundStatusRequested: {},
+	RefundStatusApproved:  {},
+	RefundStatusRejected:  {},
+}
+
+// String returns string representaion of a refund status.
+func (s RefundStatus) String() string {
+	return string(s)
+}
+
+// RefundMethod denotes how the money of an approved refund
+// is returned.
+type RefundMethod string
+
+// Followings are the known refund methods.
+const (
+	RefundMethodUnknown RefundMethod = ""
+
+	// RefundMethodManual means the committee transfers the
+	// money back outside of the system.
+	RefundMethodManual RefundMethod = "manual"
+
+	// RefundMethodGateway means the payment is refunded
+	// through the payment gateway.
+	RefundMethodGateway RefundMethod = "gateway"
+)
+
+// RefundMethodList is a list of valid refund methods.
+var RefundMethodList = map[RefundMethod]struct{}{
+	RefundMethodManual:  {},
+	RefundMethodGateway: {},
+}
+
+// String returns string representaion of a refund method.
+func (m RefundMethod) String() string {
+	return string(m)
+}
+
+// PaidThroughGateway returns whether the given mainevent was
+// paid through the payment gateway, rather than by a bank
+// transfer confirmed with its payment proof. Only these may be
+// refunded with RefundMethodGateway.
+func (m MainEvent) PaidThroughGateway() bool {
+	return m.Status != StatusUnpaid && m.Type != TypeComplimentary && m.ImageURI == ""
+}
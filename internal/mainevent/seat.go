@@ -0,0 +1,72 @@
+package mainevent
+
+import (
+	"fmt"
+	"time"
+)
+
+// Seat is a seat of the venue.
+type Seat struct {
+	ID      int64
+	Section string
+	Baris   string
+	Nomor   int
+
+	// Aksesibel seats are assigned to the buyers needing
+	// accessible seating first.
+	Aksesibel bool
+
+	// MainEventID is the mainevent holding the seat, it is
+	// zero if the seat is free. NomorTiket is the ticket
+	// seated on it, set once the tickets are issued.
+	MainEventID int64
+	NomorTiket  string
+
+	CreateTime time.Time
+	UpdateTime time.Time
+}
+
+// Label returns the seat as printed on the tickets.
+func (s Seat) Label() string {
+	return fmt.Sprintf("%s, Baris %s, Kursi %d", s.Section, s.Baris, s.Nomor)
+}
+
+// Available reports whether the seat is free.
+func (s Seat) Available() bool {
+	return s.MainEventID == 0
+}
+
+// SeatOf returns the seat of the given ticket number of the
+// mainevent, which is zero if the ticket has no seat.
+func (m MainEvent) SeatOf(nomorTiket string) Seat {
+	for _, s := range m.Seats {
+		if s.NomorTiket == nomorTiket {
+			return s
+		}
+	}
+	return Seat{}
+}
+
+// SeatLayout is the seat map of the venue. The seats are
+// ordered by section, row and number as laid out, which is
+// also the order the seats are assigned in.
+type SeatLayout struct {
+	Sections []SectionLayout
+}
+
+// SectionLayout is a section of the venue.
+type SectionLayout struct {
+	Nama  string
+	Baris []RowLayout
+}
+
+// RowLayout is a row of a section, its seats are numbered
+// from one to JumlahKursi.
+type RowLayout struct {
+	Nama        string
+	JumlahKursi int
+
+	// KursiAksesibel are the numbers of the accessible seats
+	// of the row.
+	KursiAksesibel []int
+}
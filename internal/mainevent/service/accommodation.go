@@ -0,0 +1,136 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"unicode/utf8"
+
+	"github.com/tedxub2023/global/logger"
+	"github.com/tedxub2023/internal/mainevent"
+)
+
+// maxCatatanAkomodasi is the longest accommodation note of a
+// mainevent, in characters.
+const maxCatatanAkomodasi = 500
+
+func (s *service) UpdateAccommodation(ctx context.Context, reqMainEvent mainevent.MainEvent) error {
+	// validate id
+	if reqMainEvent.ID <= 0 {
+		return mainevent.ErrInvalidMainEventID
+	}
+
+	if _, ok := mainevent.DisabilityList[reqMainEvent.Disabilitas]; !ok {
+		return mainevent.ErrInvalidAccommodation
+	}
+
+	err := validateAccommodation(reqMainEvent)
+	if err != nil {
+		return err
+	}
+
+	pgStoreClient, err := s.pgStore.NewClient(false)
+	if err != nil {
+		return err
+	}
+
+	current, err := pgStoreClient.GetMainEventByID(ctx, reqMainEvent.ID)
+	if err != nil {
+		return err
+	}
+	logger.AddFields(ctx, logger.Fields{"order_id": current.OrderID})
+
+	current.Disabilitas = reqMainEvent.Disabilitas
+	current.Akomodasi = reqMainEvent.Akomodasi
+	current.CatatanAkomodasi = reqMainEvent.CatatanAkomodasi
+
+	return pgStoreClient.UpdateMainEventByID(ctx, current, s.timeNow())
+}
+
+func (s *service) GetAccommodationReport(ctx context.Context) (mainevent.AccommodationReport, error) {
+	ev, err := s.currentEvent(ctx)
+	if err != nil {
+		return mainevent.AccommodationReport{}, err
+	}
+
+	pgStoreClient, err := s.pgStore.NewClient(false)
+	if err != nil {
+		return mainevent.AccommodationReport{}, err
+	}
+
+	// the accessible seats are taken by the orders of every
+	// status, as in the ticket availability
+	tickets, _, err := pgStoreClient.GetAllMainEvents(ctx, mainevent.GetAllMainEventsFilter{EventID: ev.ID})
+	if err != nil {
+		return mainevent.AccommodationReport{}, err
+	}
+
+	result := mainevent.AccommodationReport{
+		KuotaKursi:    s.config.AccessibleSeatQuota,
+		KursiTerpakai: checkAccessibleSeats(tickets),
+	}
+
+	reports := make(map[mainevent.Disability]*mainevent.DisabilityReport)
+	for _, m := range tickets {
+		if m.Status != mainevent.StatusSettlement || !m.NeedsAccessibleSeating() {
+			continue
+		}
+
+		report, ok := reports[m.Disabilitas]
+		if !ok {
+			report = &mainevent.DisabilityReport{
+				Disabilitas: m.Disabilitas,
+				Akomodasi:   make(map[mainevent.Accommodation]int),
+			}
+			reports[m.Disabilitas] = report
+		}
+
+		report.JumlahOrder++
+		report.JumlahTiket += m.JumlahTiket - len(m.RefundNomorTiket)
+		for _, a := range m.Akomodasi {
+			report.Akomodasi[a]++
+		}
+		report.Orders = append(report.Orders, m)
+	}
+
+	for _, report := range reports {
+		result.Disabilitas = append(result.Disabilitas, *report)
+	}
+	sort.Slice(result.Disabilitas, func(i, j int) bool {
+		return result.Disabilitas[i].Disabilitas < result.Disabilitas[j].Disabilitas
+	})
+
+	return result, nil
+}
+
+// checkAccessibleSeats returns the number of the normal sale
+// tickets taking the accessible seats.
+func checkAccessibleSeats(tx []mainevent.MainEvent) int {
+	counter := 0
+	for _, t := range tx {
+		if t.Type == mainevent.TypeNormalSale && t.NeedsAccessibleSeating() {
+			counter += t.JumlahTiket - len(t.RefundNomorTiket)
+		}
+	}
+	return counter
+}
+
+// validateAccommodation validates the accommodations of the
+// given mainevent.
+func validateAccommodation(reqMainEvent mainevent.MainEvent) error {
+	seen := make(map[mainevent.Accommodation]struct{}, len(reqMainEvent.Akomodasi))
+	for _, a := range reqMainEvent.Akomodasi {
+		if _, ok := mainevent.AccommodationList[a]; !ok {
+			return mainevent.ErrInvalidAccommodation
+		}
+		if _, ok := seen[a]; ok {
+			return mainevent.ErrInvalidAccommodation
+		}
+		seen[a] = struct{}{}
+	}
+
+	if utf8.RuneCountInString(reqMainEvent.CatatanAkomodasi) > maxCatatanAkomodasi {
+		return mainevent.ErrInvalidAccommodation
+	}
+
+	return nil
+}
@@ -0,0 +1,367 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/mail"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/tedxub2023/global/helper"
+	"github.com/tedxub2023/global/logger"
+	"github.com/tedxub2023/internal/mainevent"
+	"github.com/tedxub2023/internal/transaction"
+)
+
+func (s *service) RequestBuyerLogin(ctx context.Context, email string) error {
+	email = strings.TrimSpace(email)
+	if _, err := mail.ParseAddress(email); email == "" || err != nil {
+		return mainevent.ErrInvalidMainEventEmail
+	}
+
+	// get pg store client without using transaction
+	pgStoreClient, err := s.pgStore.NewClient(false)
+	if err != nil {
+		return err
+	}
+
+	// the latest order of the buyer greets them by name
+	nama, email, err := s.buyerName(ctx, pgStoreClient, email)
+	if err != nil {
+		return err
+	}
+	if nama == "" {
+		// do not tell whether the buyer exists
+		logger.Info(ctx, "buyer login requested for unknown email")
+		return nil
+	}
+
+	token, err := generateToken()
+	if err != nil {
+		return err
+	}
+
+	now := s.timeNow()
+	login := mainevent.BuyerLogin{
+		Email:      email,
+		Token:      token,
+		TokenHash:  buyerTokenHash(token),
+		ExpireTime: now.Add(s.config.BuyerLoginTTL),
+		CreateTime: now,
+	}
+
+	login.ID, err = pgStoreClient.CreateBuyerLogin(ctx, login)
+	if err != nil {
+		return err
+	}
+	logger.AddFields(ctx, logger.Fields{"buyer_login_id": login.ID})
+
+	link := s.buyerLoginLink(login)
+	s.workers.Go(ctx, "mainevent buyer login mail", func(ctx context.Context) error {
+		return s.sendBuyerLoginMail(ctx, nama, login, link)
+	})
+
+	return nil
+}
+
+func (s *service) VerifyBuyerLogin(ctx context.Context, token string) (mainevent.BuyerLogin, error) {
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return mainevent.BuyerLogin{}, mainevent.ErrInvalidBuyerLoginToken
+	}
+
+	// get pg store client without using transaction
+	pgStoreClient, err := s.pgStore.NewClient(false)
+	if err != nil {
+		return mainevent.BuyerLogin{}, err
+	}
+
+	login, err := pgStoreClient.GetBuyerLoginByToken(ctx, buyerTokenHash(token))
+	if err != nil {
+		return mainevent.BuyerLogin{}, err
+	}
+	logger.AddFields(ctx, logger.Fields{"buyer_login_id": login.ID})
+
+	// a login link opens one session only
+	now := s.timeNow()
+	if !login.UseTime.IsZero() || !now.Before(login.ExpireTime) {
+		return mainevent.BuyerLogin{}, mainevent.ErrInvalidBuyerLoginToken
+	}
+
+	login.SessionToken, err = generateToken()
+	if err != nil {
+		return mainevent.BuyerLogin{}, err
+	}
+	login.SessionTokenHash = buyerTokenHash(login.SessionToken)
+	login.SessionExpireTime = now.Add(s.config.BuyerSessionTTL)
+	login.UseTime = now
+
+	err = pgStoreClient.UseBuyerLogin(ctx, login)
+	if err != nil {
+		return mainevent.BuyerLogin{}, err
+	}
+	logger.Info(ctx, "buyer login verified")
+
+	return login, nil
+}
+
+func (s *service) GetBuyerMainEvents(ctx context.Context, session string) ([]mainevent.MainEvent, error) {
+	// get pg store client without using transaction
+	pgStoreClient, err := s.pgStore.NewClient(false)
+	if err != nil {
+		return nil, err
+	}
+
+	email, err := s.buyerEmail(ctx, pgStoreClient, session)
+	if err != nil {
+		return nil, err
+	}
+
+	// the orders of every event, not only the one on sale
+	result, _, err := pgStoreClient.GetAllMainEvents(ctx, mainevent.GetAllMainEventsFilter{
+		Email: email,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (s *service) GetBuyerTransactions(ctx context.Context, session string) ([]transaction.Transaction, error) {
+	// get pg store client without using transaction
+	pgStoreClient, err := s.pgStore.NewClient(false)
+	if err != nil {
+		return nil, err
+	}
+
+	email, err := s.buyerEmail(ctx, pgStoreClient, session)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.transaction.GetTransactionsByEmail(ctx, email)
+}
+
+func (s *service) UploadBuyerPaymentProof(ctx context.Context, session string, maineventID int64, imageURI string) error {
+	// get pg store client without using transaction
+	pgStoreClient, err := s.pgStore.NewClient(false)
+	if err != nil {
+		return err
+	}
+
+	current, err := s.buyerMainEvent(ctx, pgStoreClient, session, maineventID)
+	if err != nil {
+		return err
+	}
+
+	// the proof goes through the same status transition as
+	// the one uploaded when ordering
+	err = s.UpdatePaymentStatus(ctx, mainevent.MainEvent{
+		ID:         current.ID,
+		Status:     mainevent.StatusPending,
+		ImageURI:   strings.TrimSpace(imageURI),
+		UpdateTime: current.UpdateTime,
+	})
+	if err != nil {
+		return err
+	}
+	logger.Info(ctx, "payment proof uploaded by buyer")
+
+	// replacing a proof keeps the status, the admin is told
+	// about the new proof all the same
+	if current.Status == mainevent.StatusPending {
+		current.ImageURI = strings.TrimSpace(imageURI)
+		s.workers.Go(ctx, "mainevent inform admin mail", func(ctx context.Context) error {
+			return s.sendMailInformAdmin(ctx, current)
+		})
+	}
+
+	return nil
+}
+
+func (s *service) GetBuyerTicketPDF(ctx context.Context, session string, maineventID int64) ([]byte, error) {
+	// get pg store client without using transaction
+	pgStoreClient, err := s.pgStore.NewClient(false)
+	if err != nil {
+		return nil, err
+	}
+
+	m, err := s.buyerMainEvent(ctx, pgStoreClient, session, maineventID)
+	if err != nil {
+		return nil, err
+	}
+
+	if m.Status == mainevent.StatusRefunded {
+		return nil, mainevent.ErrTicketRefunded
+	} else if m.Status != mainevent.StatusSettlement {
+		return nil, mainevent.ErrTicketNotYetPaid
+	}
+
+	err = s.ensureTicketPDF(ctx, pgStoreClient, m)
+	if err != nil {
+		return nil, err
+	}
+
+	return os.ReadFile(helper.PDFPath(m))
+}
+
+func (s *service) ResendBuyerMail(ctx context.Context, session string, maineventID int64) error {
+	// get pg store client without using transaction
+	pgStoreClient, err := s.pgStore.NewClient(false)
+	if err != nil {
+		return err
+	}
+
+	m, err := s.buyerMainEvent(ctx, pgStoreClient, session, maineventID)
+	if err != nil {
+		return err
+	}
+
+	switch m.Status {
+	case mainevent.StatusRefunded:
+		return mainevent.ErrTicketRefunded
+	case mainevent.StatusSettlement:
+		s.workers.Go(ctx, "mainevent ticket mail", func(ctx context.Context) error {
+			if err := s.ensureTicketPDF(ctx, pgStoreClient, m); err != nil {
+				return err
+			}
+			return s.sendSuccessTransactionMail(ctx, m)
+		})
+	default:
+		s.workers.Go(ctx, "mainevent pending mail", func(ctx context.Context) error {
+			return s.sendMainEventPendingMail(ctx, m)
+		})
+	}
+	logger.Info(ctx, "buyer mail resent", logger.Fields{"status": m.Status.String()})
+
+	return nil
+}
+
+// buyerName returns the name and email of the latest
+// mainevent of the buyer with the given email, or of their
+// latest transaction if they have no mainevent. The name is
+// empty if they have neither.
+func (s *service) buyerName(ctx context.Context, pgStoreClient PGStoreClient, email string) (string, string, error) {
+	latest, _, err := pgStoreClient.GetAllMainEvents(ctx, mainevent.GetAllMainEventsFilter{
+		Email: email,
+		Limit: 1,
+	})
+	if err != nil {
+		return "", "", err
+	}
+	if len(latest) > 0 {
+		return latest[0].Nama, latest[0].Email, nil
+	}
+
+	transactions, err := s.transaction.GetTransactionsByEmail(ctx, email)
+	if err != nil {
+		return "", "", err
+	}
+	if len(transactions) > 0 {
+		return transactions[0].Nama, transactions[0].Email, nil
+	}
+
+	return "", "", nil
+}
+
+// buyerEmail returns the email of the buyer of the given
+// session.
+func (s *service) buyerEmail(ctx context.Context, pgStoreClient PGStoreClient, session string) (string, error) {
+	if session == "" {
+		return "", mainevent.ErrInvalidBuyerSession
+	}
+
+	login, err := pgStoreClient.GetBuyerLoginBySession(ctx, buyerTokenHash(session))
+	if err != nil {
+		return "", err
+	}
+	if !s.timeNow().Before(login.SessionExpireTime) {
+		return "", mainevent.ErrInvalidBuyerSession
+	}
+	logger.AddFields(ctx, logger.Fields{"buyer_login_id": login.ID})
+
+	return login.Email, nil
+}
+
+// buyerMainEvent returns the mainevent with the given ID of
+// the buyer of the given session.
+func (s *service) buyerMainEvent(ctx context.Context, pgStoreClient PGStoreClient, session string, maineventID int64) (mainevent.MainEvent, error) {
+	email, err := s.buyerEmail(ctx, pgStoreClient, session)
+	if err != nil {
+		return mainevent.MainEvent{}, err
+	}
+
+	if maineventID <= 0 {
+		return mainevent.MainEvent{}, mainevent.ErrInvalidMainEventID
+	}
+
+	m, err := pgStoreClient.GetMainEventByID(ctx, maineventID)
+	if err != nil {
+		return mainevent.MainEvent{}, err
+	}
+
+	// do not reveal the mainevent to anyone but its buyer
+	if !strings.EqualFold(email, m.Email) {
+		return mainevent.MainEvent{}, mainevent.ErrDataNotFound
+	}
+	logger.AddFields(ctx, logger.Fields{"order_id": m.OrderID})
+
+	return m, nil
+}
+
+// ensureTicketPDF renders the ticket PDF of the given settled
+// mainevent again if it is missing, e.g. after a redeploy.
+func (s *service) ensureTicketPDF(ctx context.Context, pgStoreClient PGStoreClient, m mainevent.MainEvent) error {
+	_, err := os.Stat(helper.PDFPath(m))
+	if err == nil || !os.IsNotExist(err) {
+		return err
+	}
+
+	ev, err := s.eventOf(ctx, m)
+	if err != nil {
+		return err
+	}
+
+	// the holders and the seats are printed on the tickets
+	m.Holders, err = pgStoreClient.GetHoldersByMainEventID(ctx, m.ID)
+	if err != nil {
+		return err
+	}
+
+	m.Seats, err = pgStoreClient.GetSeatsByMainEventID(ctx, m.ID)
+	if err != nil {
+		return err
+	}
+
+	err = s.generatePDF(ev, m)
+	if err != nil {
+		return err
+	}
+	logger.Info(ctx, "ticket pdf rendered again")
+
+	return nil
+}
+
+// buyerTokenHash returns the stored hash of the given buyer
+// login or session token, the SHA-256 hash in hex.
+func buyerTokenHash(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// buyerLoginLink returns the link of the given buyer login.
+func (s *service) buyerLoginLink(login mainevent.BuyerLogin) string {
+	u, err := url.Parse(s.config.BuyerLoginURL)
+	if err != nil {
+		return s.config.BuyerLoginURL
+	}
+
+	q := u.Query()
+	q.Set("token", login.Token)
+	u.RawQuery = q.Encode()
+
+	return u.String()
+}
@@ -0,0 +1,160 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	"github.com/tedxub2023/global/logger"
+	"github.com/tedxub2023/global/metrics"
+	"github.com/tedxub2023/internal/event"
+	"github.com/tedxub2023/internal/mainevent"
+)
+
+func (s *service) ImportComplimentary(ctx context.Context, orders []mainevent.MainEvent) (_ []mainevent.MainEvent, err error) {
+	if len(orders) == 0 {
+		return nil, mainevent.ErrInvalidImport
+	}
+
+	ev, err := s.currentEvent(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// the orders are created all at once, so that an import
+	// failing halfway can simply be retried
+	pgStoreClient, err := s.pgStore.NewClient(true)
+	if err != nil {
+		return nil, err
+	}
+
+	// rollback just before return if error
+	defer func() {
+		if err != nil {
+			errTx := pgStoreClient.Rollback()
+			if errTx != nil {
+				// return err from rollback
+				err = errTx
+			}
+		}
+	}()
+
+	// validate every order before creating any, the rows are
+	// numbered as in the imported file
+	result := make([]mainevent.MainEvent, 0, len(orders))
+	rowErrors := make([]mainevent.ImportRowError, 0)
+	seen := make(map[string]struct{}, len(orders))
+	for i, order := range orders {
+		order = complimentaryOrder(ev, order)
+
+		errRow := validateMainEvent(order)
+		if errRow == nil {
+			var exists bool
+			exists, err = hasComplimentary(ctx, pgStoreClient, order, seen)
+			if err != nil {
+				return nil, err
+			}
+			if exists {
+				errRow = mainevent.ErrComplimentaryExists
+			}
+		}
+		seen[strings.ToLower(order.Email)] = struct{}{}
+
+		if errRow != nil {
+			rowErrors = append(rowErrors, mainevent.ImportRowError{Baris: i + 2, Err: errRow})
+			continue
+		}
+		result = append(result, order)
+	}
+	if len(rowErrors) > 0 {
+		logger.Info(ctx, "complimentary import rejected", logger.Fields{"invalid_rows": len(rowErrors)})
+		return nil, mainevent.ImportError{Rows: rowErrors}
+	}
+
+	now := s.timeNow()
+	for i := range result {
+		m := &result[i]
+		m.OrderID = generateOrderID()
+		m.CreateTime = now
+
+		m.ID, err = pgStoreClient.CreateMainEvent(ctx, *m)
+		if err != nil {
+			return nil, err
+		}
+
+		// the tickets are issued right away, there is no
+		// payment to wait for
+		m.NomorTiket = generateNumberTicket(ticketPrefix(ev), m.ID, m.JumlahTiket)
+		err = s.assignSeats(ctx, pgStoreClient, m)
+		if err != nil {
+			return nil, err
+		}
+
+		err = pgStoreClient.UpdateMainEventByID(ctx, *m, now)
+		if err != nil {
+			return nil, err
+		}
+		m.UpdateTime = now
+	}
+
+	// commit changes
+	err = pgStoreClient.Commit()
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info(ctx, "complimentary orders imported", logger.Fields{"jumlah_order": len(result)})
+	for _, m := range result {
+		m := m
+		metrics.OrdersCreated.WithLabelValues(metrics.EventMainEvent, m.Type.String()).Inc()
+		metrics.TicketsOrdered.WithLabelValues(metrics.EventMainEvent, m.Type.String()).Add(float64(m.JumlahTiket))
+
+		s.workers.Go(ctx, "mainevent complimentary ticket mail", func(ctx context.Context) error {
+			err := s.generatePDF(ev, m)
+			if err != nil {
+				return err
+			}
+			return s.sendSuccessTransactionMail(ctx, m)
+		})
+	}
+
+	return result, nil
+}
+
+// complimentaryOrder returns the given order as a settled
+// complimentary order of the given event, free of charge.
+func complimentaryOrder(ev event.Event, order mainevent.MainEvent) mainevent.MainEvent {
+	return mainevent.MainEvent{
+		EventID:          ev.ID,
+		Nama:             strings.TrimSpace(order.Nama),
+		Disabilitas:      order.Disabilitas,
+		NomorIdentitas:   strings.TrimSpace(order.NomorIdentitas),
+		AsalInstitusi:    strings.TrimSpace(order.AsalInstitusi),
+		Email:            strings.TrimSpace(order.Email),
+		NomorTelepon:     strings.TrimSpace(order.NomorTelepon),
+		JumlahTiket:      order.JumlahTiket,
+		Akomodasi:        order.Akomodasi,
+		CatatanAkomodasi: strings.TrimSpace(order.CatatanAkomodasi),
+		Type:             mainevent.TypeComplimentary,
+		Status:           mainevent.StatusSettlement,
+	}
+}
+
+// hasComplimentary returns whether the buyer of the given
+// order already has a complimentary order of its event, or one
+// earlier in the same import whose emails are in seen.
+func hasComplimentary(ctx context.Context, pgStoreClient PGStoreClient, order mainevent.MainEvent, seen map[string]struct{}) (bool, error) {
+	if _, ok := seen[strings.ToLower(order.Email)]; ok {
+		return true, nil
+	}
+
+	existing, _, err := pgStoreClient.GetAllMainEvents(ctx, mainevent.GetAllMainEventsFilter{
+		EventID: order.EventID,
+		Email:   order.Email,
+		Type:    mainevent.TypeComplimentary,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return len(existing) > 0, nil
+}
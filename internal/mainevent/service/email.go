@@ -1,20 +1,26 @@
 package service
 
 import (
+	"context"
 	"fmt"
-	"os"
 
+	"github.com/tedxub2023/global/helper"
 	"github.com/tedxub2023/internal/mainevent"
 	m "github.com/tedxub2023/internal/ticket/service"
 )
 
-func sendTransactionDeclinedMail(tx mainevent.MainEvent) error {
-	mail := m.NewMailClient()
-	mail.SetSender("tedxuniversitasbrawijaya@gmail.com")
+func (s *service) sendTransactionDeclinedMail(ctx context.Context, tx mainevent.MainEvent) error {
+	ev, err := s.eventOf(ctx, tx)
+	if err != nil {
+		return err
+	}
+
+	mail := m.NewMailClient(s.config.Mail)
+	mail.SetSender(s.config.Mail.Sender)
 	mail.SetReciever(tx.Email)
 	mail.SetSubject("Transaksi Ditolak")
 
-	if err := mail.SetBodyHTMLDeclinedTransaction(); err != nil {
+	if err := mail.SetBodyHTMLDeclinedTransaction(ev); err != nil {
 		return err
 	}
 
@@ -24,15 +30,20 @@ func sendTransactionDeclinedMail(tx mainevent.MainEvent) error {
 	return nil
 }
 
-func sendSuccessTransactionMail(tx mainevent.MainEvent) error {
-	mail := m.NewMailClient()
-	mail.SetSender("tedxuniversitasbrawijaya@gmail.com")
+func (s *service) sendSuccessTransactionMail(ctx context.Context, tx mainevent.MainEvent) error {
+	ev, err := s.eventOf(ctx, tx)
+	if err != nil {
+		return err
+	}
+
+	mail := m.NewMailClient(s.config.Mail)
+	mail.SetSender(s.config.Mail.Sender)
 	mail.SetReciever(tx.Email)
-	mail.SetSubject("Tiket Memantik Baskara")
+	mail.SetSubject(fmt.Sprintf("Tiket %s", ev.Nama))
 
-	mail.SetAttachFile(fmt.Sprintf("global/storage/ted/%s-%s.pdf", tx.Nama, tx.Type.String()))
+	mail.SetAttachFile(helper.PDFPath(tx))
 
-	if err := mail.SetBodyHTMLSuccessTransaction(tx); err != nil {
+	if err := mail.SetBodyHTMLSuccessTransaction(ev, tx); err != nil {
 		return err
 	}
 
@@ -42,14 +53,18 @@ func sendSuccessTransactionMail(tx mainevent.MainEvent) error {
 	return nil
 }
 
-func sendMailInformAdmin(tx mainevent.MainEvent) error {
-	mail := m.NewMailClient()
-	mail.SetSender("tedxuniversitasbrawijaya@gmail.com")
-	email := os.Getenv("EMAIL_CEM")
-	mail.SetReciever(email)
+func (s *service) sendMailInformAdmin(ctx context.Context, tx mainevent.MainEvent) error {
+	ev, err := s.eventOf(ctx, tx)
+	if err != nil {
+		return err
+	}
+
+	mail := m.NewMailClient(s.config.Mail)
+	mail.SetSender(s.config.Mail.Sender)
+	mail.SetReciever(s.config.AdminEmail)
 	mail.SetSubject("Pemberitahuan Pembelian Tiket")
 
-	if err := mail.SetBodyHTMLInformAdmin(tx); err != nil {
+	if err := mail.SetBodyHTMLInformAdmin(ev, tx); err != nil {
 		return err
 	}
 
@@ -59,13 +74,148 @@ func sendMailInformAdmin(tx mainevent.MainEvent) error {
 	return nil
 }
 
-func sendMainEventPendingMail(tx mainevent.MainEvent) error {
-	mail := m.NewMailClient()
-	mail.SetSender("tedxuniversitasbrawijaya@gmail.com")
+func (s *service) sendMainEventPendingMail(ctx context.Context, tx mainevent.MainEvent) error {
+	ev, err := s.eventOf(ctx, tx)
+	if err != nil {
+		return err
+	}
+
+	mail := m.NewMailClient(s.config.Mail)
+	mail.SetSender(s.config.Mail.Sender)
 	mail.SetReciever(tx.Email)
 	mail.SetSubject("Konfirmasi Pembelian Tiket")
 
-	if err := mail.SetBodyHTMLMainEventPendingMail(); err != nil {
+	if err := mail.SetBodyHTMLMainEventPendingMail(ev); err != nil {
+		return err
+	}
+
+	if err := mail.SendMail(); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *service) sendRefundMail(ctx context.Context, tx mainevent.MainEvent, refund mainevent.Refund) error {
+	ev, err := s.eventOf(ctx, tx)
+	if err != nil {
+		return err
+	}
+
+	mail := m.NewMailClient(s.config.Mail)
+	mail.SetSender(s.config.Mail.Sender)
+	mail.SetReciever(tx.Email)
+	mail.SetSubject(fmt.Sprintf("Pembatalan Tiket %s", ev.Nama))
+
+	if err := mail.SetBodyHTMLRefund(ev, tx, refund); err != nil {
+		return err
+	}
+
+	if err := mail.SendMail(); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *service) sendHolderTicketMail(ctx context.Context, tx mainevent.MainEvent, holder mainevent.Holder, path string) error {
+	ev, err := s.eventOf(ctx, tx)
+	if err != nil {
+		return err
+	}
+
+	mail := m.NewMailClient(s.config.Mail)
+	mail.SetSender(s.config.Mail.Sender)
+	mail.SetReciever(holder.Email)
+	mail.SetSubject(fmt.Sprintf("Tiket %s", ev.Nama))
+
+	mail.SetAttachFile(path)
+
+	if err := mail.SetBodyHTMLHolderTicket(ev, tx, holder); err != nil {
+		return err
+	}
+
+	if err := mail.SendMail(); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *service) sendWaitlistOfferMail(ctx context.Context, entry mainevent.WaitlistEntry, link string) error {
+	ev, err := s.currentEvent(ctx)
+	if err != nil {
+		return err
+	}
+
+	mail := m.NewMailClient(s.config.Mail)
+	mail.SetSender(s.config.Mail.Sender)
+	mail.SetReciever(entry.Email)
+	mail.SetSubject(fmt.Sprintf("Tiket %s Tersedia", ev.Nama))
+
+	if err := mail.SetBodyHTMLWaitlistOffer(ev, entry, link); err != nil {
+		return err
+	}
+
+	if err := mail.SendMail(); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *service) sendWaitlistExpiredMail(ctx context.Context, entry mainevent.WaitlistEntry) error {
+	ev, err := s.currentEvent(ctx)
+	if err != nil {
+		return err
+	}
+
+	mail := m.NewMailClient(s.config.Mail)
+	mail.SetSender(s.config.Mail.Sender)
+	mail.SetReciever(entry.Email)
+	mail.SetSubject("Penawaran Tiket Kedaluwarsa")
+
+	if err := mail.SetBodyHTMLWaitlistExpired(ev, entry); err != nil {
+		return err
+	}
+
+	if err := mail.SendMail(); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *service) sendGroupOrderMail(ctx context.Context, group mainevent.GroupOrder, invoice mainevent.MainEvent) error {
+	// the invoice is only issued once the group order is
+	// approved
+	ev, err := s.eventOf(ctx, invoice)
+	if err != nil {
+		return err
+	}
+
+	mail := m.NewMailClient(s.config.Mail)
+	mail.SetSender(s.config.Mail.Sender)
+	mail.SetReciever(group.Email)
+	mail.SetSubject(fmt.Sprintf("Pesanan Grup %s", ev.Nama))
+
+	if err := mail.SetBodyHTMLGroupOrder(ev, group, invoice); err != nil {
+		return err
+	}
+
+	if err := mail.SendMail(); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *service) sendBuyerLoginMail(ctx context.Context, nama string, login mainevent.BuyerLogin, link string) error {
+	ev, err := s.currentEvent(ctx)
+	if err != nil {
+		return err
+	}
+
+	mail := m.NewMailClient(s.config.Mail)
+	mail.SetSender(s.config.Mail.Sender)
+	mail.SetReciever(login.Email)
+	mail.SetSubject(fmt.Sprintf("Masuk ke Pesanan %s", ev.Nama))
+
+	if err := mail.SetBodyHTMLBuyerLogin(ev, nama, login, link); err != nil {
 		return err
 	}
 
@@ -0,0 +1,57 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	"github.com/tedxub2023/global/logger"
+	"github.com/tedxub2023/internal/event"
+	"github.com/tedxub2023/internal/mainevent"
+)
+
+// currentEvent returns the event the mainevents are sold for.
+func (s *service) currentEvent(ctx context.Context) (event.Event, error) {
+	ev, err := s.event.GetEventBySlug(ctx, s.config.EventSlug)
+	if err != nil {
+		return event.Event{}, err
+	}
+	logger.AddFields(ctx, logger.Fields{"event_id": ev.ID})
+
+	return ev, nil
+}
+
+// eventOf returns the event the given mainevent is ordered
+// for, which is the current event if it is not stored yet.
+func (s *service) eventOf(ctx context.Context, m mainevent.MainEvent) (event.Event, error) {
+	if m.EventID == 0 {
+		return s.currentEvent(ctx)
+	}
+	return s.event.GetEventByID(ctx, m.EventID)
+}
+
+// ticketPrefix returns the prefix of the ticket numbers of
+// the given event, its slug without dashes in upper case, e.g.
+// "MEMANTIKBASKARA".
+func ticketPrefix(ev event.Event) string {
+	return strings.ToUpper(strings.ReplaceAll(ev.Slug, "-", ""))
+}
+
+// ticketPriceOf returns the ticket price of the given sale
+// phase of the event, which is ticketPrice if the event does
+// not set it.
+func ticketPriceOf(ev event.Event, t mainevent.Type) int64 {
+	if tt, ok := ev.TicketType(t.String()); ok {
+		return tt.Harga
+	}
+	return ticketPrice
+}
+
+// normalSaleQuotaOf returns the number of normal sale tickets
+// of the event, which is normalSaleQuota if the event does
+// not set it.
+func normalSaleQuotaOf(ev event.Event) int {
+	if tt, ok := ev.TicketType(mainevent.TypeNormalSale.String()); ok {
+		return tt.Kuota
+	}
+	return normalSaleQuota
+}
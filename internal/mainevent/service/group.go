@@ -0,0 +1,514 @@
+package service
+
+import (
+	"context"
+	"net/mail"
+	"strings"
+
+	"github.com/tedxub2023/global/helper"
+	"github.com/tedxub2023/global/logger"
+	"github.com/tedxub2023/global/metrics"
+	"github.com/tedxub2023/internal/event"
+	"github.com/tedxub2023/internal/mainevent"
+)
+
+func (s *service) CreateGroupOrder(ctx context.Context, reqGroup mainevent.GroupOrder) (_ int64, err error) {
+	reqGroup.AsalInstitusi = normalizeInstitution(reqGroup.AsalInstitusi)
+	logger.AddFields(ctx, logger.Fields{"asal_institusi": reqGroup.AsalInstitusi})
+
+	// validate field
+	reqGroup.Attendees, err = validateGroupAttendees(reqGroup.Attendees)
+	if err != nil {
+		return 0, err
+	}
+	if len(reqGroup.Attendees) < s.config.GroupMinTiket {
+		return 0, mainevent.ErrInvalidGroupSize
+	}
+	err = validateMainEvent(groupInvoice(reqGroup))
+	if err != nil {
+		return 0, err
+	}
+
+	ev, err := s.currentEvent(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	pgStoreClient, err := s.pgStore.NewClient(true)
+	if err != nil {
+		return 0, err
+	}
+
+	// rollback just before return if error
+	defer func() {
+		if err != nil {
+			errTx := pgStoreClient.Rollback()
+			if errTx != nil {
+				// return err from rollback
+				err = errTx
+			}
+		}
+	}()
+
+	policy, err := s.groupPolicy(ctx, pgStoreClient, reqGroup.AsalInstitusi)
+	if err != nil {
+		return 0, err
+	}
+
+	jumlahTiket := len(reqGroup.Attendees)
+	if jumlahTiket > policy.MaxTiket {
+		return 0, mainevent.ErrInvalidGroupSize
+	}
+
+	if policy.KuotaTiket > 0 {
+		used, err := pgStoreClient.CountGroupTickets(ctx, reqGroup.AsalInstitusi)
+		if err != nil {
+			return 0, err
+		}
+		if used+jumlahTiket > policy.KuotaTiket {
+			return 0, mainevent.ErrGroupQuotaExceeded
+		}
+	}
+
+	// the tickets are only taken once the invoice is issued,
+	// but a group order that cannot get them fails early
+	if policy.RequireApproval {
+		available, err := s.availableNormalSaleTickets(ctx, pgStoreClient, ev, 0, false)
+		if err != nil {
+			return 0, err
+		}
+		if jumlahTiket > available {
+			return 0, mainevent.ErrTicketSoldOut
+		}
+	}
+
+	reqGroup.HargaTiket = s.groupTicketPrice(ev, jumlahTiket)
+	reqGroup.TotalHarga = reqGroup.HargaTiket * int64(jumlahTiket)
+	reqGroup.Status = mainevent.GroupStatusRequested
+	reqGroup.CreateTime = s.timeNow()
+
+	reqGroup.ID, err = pgStoreClient.CreateGroupOrder(ctx, reqGroup)
+	if err != nil {
+		return 0, err
+	}
+	logger.AddFields(ctx, logger.Fields{"group_id": reqGroup.ID})
+
+	var invoice mainevent.MainEvent
+	if !policy.RequireApproval {
+		invoice, err = s.issueGroupInvoice(ctx, pgStoreClient, ev, &reqGroup)
+		if err != nil {
+			return 0, err
+		}
+
+		err = pgStoreClient.UpdateGroupOrder(ctx, reqGroup, mainevent.GroupStatusRequested, reqGroup.CreateTime)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	// commit changes
+	err = pgStoreClient.Commit()
+	if err != nil {
+		return 0, err
+	}
+
+	logger.Info(ctx, "group order created", logger.Fields{"jumlah_tiket": jumlahTiket, "status": reqGroup.Status.String()})
+	if invoice.ID != 0 {
+		metrics.OrdersCreated.WithLabelValues(metrics.EventMainEvent, invoice.Type.String()).Inc()
+		metrics.TicketsOrdered.WithLabelValues(metrics.EventMainEvent, invoice.Type.String()).Add(float64(invoice.JumlahTiket))
+	}
+
+	s.workers.Go(ctx, "mainevent group order mail", func(ctx context.Context) error {
+		return s.sendGroupOrderMail(ctx, reqGroup, invoice)
+	})
+
+	return reqGroup.ID, nil
+}
+
+func (s *service) GetAllGroupOrders(ctx context.Context, status mainevent.GroupStatus) ([]mainevent.GroupOrder, error) {
+	// validate status
+	if status != mainevent.GroupStatusUnknown {
+		if _, ok := mainevent.GroupStatusList[status]; !ok {
+			return nil, mainevent.ErrInvalidGroupStatus
+		}
+	}
+
+	// get pg store client without using transaction
+	pgStoreClient, err := s.pgStore.NewClient(false)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgStoreClient.GetAllGroupOrders(ctx, status)
+}
+
+func (s *service) ProcessGroupOrder(ctx context.Context, reqGroup mainevent.GroupOrder) (err error) {
+	// validate field
+	if reqGroup.ID <= 0 {
+		return mainevent.ErrInvalidGroupOrderID
+	}
+	if reqGroup.Status != mainevent.GroupStatusApproved && reqGroup.Status != mainevent.GroupStatusRejected {
+		return mainevent.ErrInvalidGroupStatus
+	}
+
+	pgStoreClient, err := s.pgStore.NewClient(true)
+	if err != nil {
+		return err
+	}
+
+	// rollback just before return if error
+	defer func() {
+		if err != nil {
+			errTx := pgStoreClient.Rollback()
+			if errTx != nil {
+				// return err from rollback
+				err = errTx
+			}
+		}
+	}()
+
+	group, err := pgStoreClient.GetGroupOrderByID(ctx, reqGroup.ID)
+	if err != nil {
+		return err
+	}
+	logger.AddFields(ctx, logger.Fields{"group_id": group.ID, "status": reqGroup.Status.String()})
+
+	if group.Status != mainevent.GroupStatusRequested {
+		return mainevent.ErrGroupOrderAlreadyProcessed
+	}
+	group.Catatan = strings.TrimSpace(reqGroup.Catatan)
+
+	var invoice mainevent.MainEvent
+	if reqGroup.Status == mainevent.GroupStatusApproved {
+		var ev event.Event
+		ev, err = s.currentEvent(ctx)
+		if err != nil {
+			return err
+		}
+
+		invoice, err = s.issueGroupInvoice(ctx, pgStoreClient, ev, &group)
+		if err != nil {
+			return err
+		}
+	} else {
+		group.Status = mainevent.GroupStatusRejected
+	}
+
+	err = pgStoreClient.UpdateGroupOrder(ctx, group, mainevent.GroupStatusRequested, s.timeNow())
+	if err != nil {
+		return err
+	}
+
+	// commit changes
+	err = pgStoreClient.Commit()
+	if err != nil {
+		return err
+	}
+
+	logger.Info(ctx, "group order processed")
+	if invoice.ID != 0 {
+		metrics.OrdersCreated.WithLabelValues(metrics.EventMainEvent, invoice.Type.String()).Inc()
+		metrics.TicketsOrdered.WithLabelValues(metrics.EventMainEvent, invoice.Type.String()).Add(float64(invoice.JumlahTiket))
+	}
+
+	s.workers.Go(ctx, "mainevent group order mail", func(ctx context.Context) error {
+		return s.sendGroupOrderMail(ctx, group, invoice)
+	})
+
+	return nil
+}
+
+// expireGroupInvoice deletes the given unpaid group invoice
+// and moves its group order to expired, freeing its tickets.
+func (s *service) expireGroupInvoice(ctx context.Context, invoice mainevent.MainEvent) (err error) {
+	pgStoreClient, err := s.pgStore.NewClient(true)
+	if err != nil {
+		return err
+	}
+
+	// rollback just before return if error
+	defer func() {
+		if err != nil {
+			errTx := pgStoreClient.Rollback()
+			if errTx != nil {
+				// return err from rollback
+				err = errTx
+			}
+		}
+	}()
+
+	group, err := pgStoreClient.GetGroupOrderByMainEventID(ctx, invoice.ID)
+	if err != nil {
+		return err
+	}
+	logger.AddFields(ctx, logger.Fields{"group_id": group.ID})
+
+	group.Status = mainevent.GroupStatusExpired
+	err = pgStoreClient.UpdateGroupOrder(ctx, group, mainevent.GroupStatusApproved, s.timeNow())
+	if err != nil {
+		return err
+	}
+
+	// the payment proof may have been uploaded meanwhile
+	err = pgStoreClient.DeleteMainEventByID(ctx, invoice.ID)
+	if err != nil {
+		return err
+	}
+
+	// commit changes
+	err = pgStoreClient.Commit()
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *service) CreateInstitution(ctx context.Context, reqInstitution mainevent.Institution) (int64, error) {
+	reqInstitution.Nama = normalizeInstitution(reqInstitution.Nama)
+
+	// validate field
+	err := validateInstitution(reqInstitution)
+	if err != nil {
+		return 0, err
+	}
+
+	// get pg store client without using transaction
+	pgStoreClient, err := s.pgStore.NewClient(false)
+	if err != nil {
+		return 0, err
+	}
+
+	reqInstitution.CreateTime = s.timeNow()
+
+	institutionID, err := pgStoreClient.CreateInstitution(ctx, reqInstitution)
+	if err != nil {
+		return 0, err
+	}
+	logger.Info(ctx, "institution created", logger.Fields{"institution_id": institutionID, "nama": reqInstitution.Nama})
+
+	return institutionID, nil
+}
+
+func (s *service) GetAllInstitutions(ctx context.Context) ([]mainevent.Institution, error) {
+	// get pg store client without using transaction
+	pgStoreClient, err := s.pgStore.NewClient(false)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgStoreClient.GetAllInstitutions(ctx)
+}
+
+func (s *service) UpdateInstitution(ctx context.Context, reqInstitution mainevent.Institution) error {
+	// validate id
+	if reqInstitution.ID <= 0 {
+		return mainevent.ErrInvalidInstitutionID
+	}
+
+	reqInstitution.Nama = normalizeInstitution(reqInstitution.Nama)
+
+	// validate field
+	err := validateInstitution(reqInstitution)
+	if err != nil {
+		return err
+	}
+
+	// get pg store client without using transaction
+	pgStoreClient, err := s.pgStore.NewClient(false)
+	if err != nil {
+		return err
+	}
+
+	reqInstitution.UpdateTime = s.timeNow()
+
+	return pgStoreClient.UpdateInstitution(ctx, reqInstitution)
+}
+
+// groupPolicy returns the group order policy of the given
+// institution, locking it until the end of the transaction.
+func (s *service) groupPolicy(ctx context.Context, pgStoreClient PGStoreClient, asalInstitusi string) (mainevent.Institution, error) {
+	// lock the institution even if it follows the default
+	// policy, so that two group orders never take its last
+	// tickets together
+	err := pgStoreClient.LockInstitution(ctx, asalInstitusi)
+	if err != nil {
+		return mainevent.Institution{}, err
+	}
+
+	institution, err := pgStoreClient.GetInstitutionByNama(ctx, asalInstitusi)
+	if err == mainevent.ErrDataNotFound {
+		return s.config.GroupPolicy, nil
+	}
+	if err != nil {
+		return mainevent.Institution{}, err
+	}
+	logger.AddFields(ctx, logger.Fields{"institution_id": institution.ID})
+
+	return institution, nil
+}
+
+// groupTicketPrice returns the ticket price of a group order
+// of the given event with the given number of attendees, which
+// is the price of the highest tier the group reaches or the
+// normal sale price below every tier.
+func (s *service) groupTicketPrice(ev event.Event, jumlahTiket int) int64 {
+	price, reached := ticketPriceOf(ev, mainevent.TypeNormalSale), 0
+	for minTiket, tierPrice := range s.config.GroupPriceTiers {
+		if jumlahTiket >= minTiket && minTiket > reached {
+			price, reached = tierPrice, minTiket
+		}
+	}
+	return price
+}
+
+// issueGroupInvoice creates the invoice of the given group
+// order for the given event, taking its tickets from the
+// normal sale tickets, and marks the group order approved.
+func (s *service) issueGroupInvoice(ctx context.Context, pgStoreClient PGStoreClient, ev event.Event, group *mainevent.GroupOrder) (mainevent.MainEvent, error) {
+	invoice := groupInvoice(*group)
+	invoice.EventID = ev.ID
+
+	available, err := s.availableNormalSaleTickets(ctx, pgStoreClient, ev, 0, false)
+	if err != nil {
+		return mainevent.MainEvent{}, err
+	}
+	if invoice.JumlahTiket > available {
+		return mainevent.MainEvent{}, mainevent.ErrTicketSoldOut
+	}
+
+	invoice.OrderID = generateOrderID()
+	invoice.CreateTime = s.timeNow()
+
+	invoice.ID, err = pgStoreClient.CreateMainEvent(ctx, invoice)
+	if err != nil {
+		return mainevent.MainEvent{}, err
+	}
+	logger.AddFields(ctx, logger.Fields{"mainevent_id": invoice.ID, "order_id": invoice.OrderID})
+
+	group.MainEventID = invoice.ID
+	group.Status = mainevent.GroupStatusApproved
+
+	return invoice, nil
+}
+
+// assignGroupTickets assigns the tickets of the given settled
+// group invoice to the attendees of its group order, in their
+// order, and sends each of them their ticket PDF.
+func (s *service) assignGroupTickets(ctx context.Context, invoice mainevent.MainEvent) error {
+	pgStoreClient, err := s.pgStore.NewClient(false)
+	if err != nil {
+		return err
+	}
+
+	group, err := pgStoreClient.GetGroupOrderByMainEventID(ctx, invoice.ID)
+	if err != nil {
+		return err
+	}
+
+	now := s.timeNow()
+	holders := make([]mainevent.Holder, 0, len(group.Attendees))
+	for i, attendee := range group.Attendees {
+		if i >= len(invoice.NomorTiket) {
+			break
+		}
+
+		h := mainevent.Holder{
+			MainEventID:    invoice.ID,
+			NomorTiket:     invoice.NomorTiket[i],
+			Nama:           attendee.Nama,
+			Email:          attendee.Email,
+			NomorIdentitas: attendee.NomorIdentitas,
+			CreateTime:     now,
+		}
+
+		err = pgStoreClient.UpsertHolder(ctx, h)
+		if err != nil {
+			return err
+		}
+		holders = append(holders, h)
+	}
+	logger.Info(ctx, "group tickets assigned", logger.Fields{"group_id": group.ID, "jumlah_tiket": len(holders)})
+
+	for _, h := range holders {
+		h := h
+		s.workers.Go(ctx, "mainevent holder ticket mail", func(ctx context.Context) error {
+			ev, err := s.eventOf(ctx, invoice)
+			if err != nil {
+				return err
+			}
+			path, err := helper.HolderPDF(s.config.PDF, ev, invoice, h)
+			if err != nil {
+				return err
+			}
+			return s.sendHolderTicketMail(ctx, invoice, h, path)
+		})
+	}
+
+	return nil
+}
+
+// groupInvoice returns the invoice of the given group order,
+// ordered by its coordinator.
+func groupInvoice(group mainevent.GroupOrder) mainevent.MainEvent {
+	return mainevent.MainEvent{
+		Nama:           group.Nama,
+		Disabilitas:    mainevent.NoneDisability,
+		NomorIdentitas: group.NomorIdentitas,
+		AsalInstitusi:  group.AsalInstitusi,
+		Email:          group.Email,
+		NomorTelepon:   group.NomorTelepon,
+		Type:           mainevent.TypeGroup,
+		JumlahTiket:    len(group.Attendees),
+		TotalHarga:     group.TotalHarga,
+		Status:         mainevent.StatusUnpaid,
+	}
+}
+
+// normalizeInstitution returns the given institution name
+// without the surrounding and repeated spaces.
+func normalizeInstitution(nama string) string {
+	return strings.Join(strings.Fields(nama), " ")
+}
+
+// validateGroupAttendees validates the given attendees of a
+// group order and returns them trimmed.
+func validateGroupAttendees(attendees []mainevent.GroupAttendee) ([]mainevent.GroupAttendee, error) {
+	result := make([]mainevent.GroupAttendee, 0, len(attendees))
+	seen := make(map[string]struct{}, len(attendees))
+	for _, a := range attendees {
+		a.Nama = strings.TrimSpace(a.Nama)
+		a.Email = strings.TrimSpace(a.Email)
+		a.NomorIdentitas = strings.TrimSpace(a.NomorIdentitas)
+
+		if a.Nama == "" || len(a.NomorIdentitas) < 15 {
+			return nil, mainevent.ErrInvalidGroupAttendee
+		}
+		if _, err := mail.ParseAddress(a.Email); a.Email == "" || err != nil {
+			return nil, mainevent.ErrInvalidGroupAttendee
+		}
+
+		// an attendee gets one ticket
+		if _, ok := seen[a.NomorIdentitas]; ok {
+			return nil, mainevent.ErrInvalidGroupAttendee
+		}
+		seen[a.NomorIdentitas] = struct{}{}
+
+		result = append(result, a)
+	}
+	return result, nil
+}
+
+// validateInstitution validates fields of the given
+// institution whether its comply the predetermined rules.
+func validateInstitution(i mainevent.Institution) error {
+	if i.Nama == "" {
+		return mainevent.ErrInvalidInstitution
+	}
+
+	if i.MaxTiket <= 0 || i.KuotaTiket < 0 || (i.KuotaTiket > 0 && i.KuotaTiket < i.MaxTiket) {
+		return mainevent.ErrInvalidInstitution
+	}
+
+	return nil
+}
@@ -0,0 +1,143 @@
+package service
+
+import (
+	"context"
+	"net/mail"
+	"strings"
+
+	"github.com/tedxub2023/global/helper"
+	"github.com/tedxub2023/global/logger"
+	"github.com/tedxub2023/internal/mainevent"
+)
+
+func (s *service) AssignHolders(ctx context.Context, session string, maineventID int64, holders []mainevent.Holder) (err error) {
+	// validate field
+	if maineventID <= 0 {
+		return mainevent.ErrInvalidMainEventID
+	}
+	if len(holders) == 0 {
+		return mainevent.ErrInvalidHolder
+	}
+
+	// the holders are checked against the ID cards at the
+	// gate, they are locked some time before the event
+	if cutoff := s.config.HolderTransferCutoff; !cutoff.IsZero() && !s.timeNow().Before(cutoff) {
+		return mainevent.ErrHolderTransferClosed
+	}
+
+	pgStoreClient, err := s.pgStore.NewClient(true)
+	if err != nil {
+		return err
+	}
+
+	// rollback just before return if error
+	defer func() {
+		if err != nil {
+			errTx := pgStoreClient.Rollback()
+			if errTx != nil {
+				// return err from rollback
+				err = errTx
+			}
+		}
+	}()
+
+	// only the buyer logged in with the order email may
+	// hand its tickets over
+	current, err := s.buyerMainEvent(ctx, pgStoreClient, session, maineventID)
+	if err != nil {
+		return err
+	}
+
+	if current.Status != mainevent.StatusSettlement {
+		return mainevent.ErrHolderChangeNotAllowed
+	}
+
+	current.Holders, err = pgStoreClient.GetHoldersByMainEventID(ctx, current.ID)
+	if err != nil {
+		return err
+	}
+
+	// the seats are printed on the holder tickets
+	current.Seats, err = pgStoreClient.GetSeatsByMainEventID(ctx, current.ID)
+	if err != nil {
+		return err
+	}
+
+	now := s.timeNow()
+	seen := make(map[string]struct{}, len(holders))
+	changed := make([]mainevent.Holder, 0, len(holders))
+	for _, h := range holders {
+		h.MainEventID = current.ID
+		h.Nama = strings.TrimSpace(h.Nama)
+		h.Email = strings.TrimSpace(h.Email)
+		h.NomorIdentitas = strings.TrimSpace(h.NomorIdentitas)
+		h.CreateTime = now
+
+		if err = validateHolder(current, h); err != nil {
+			return err
+		}
+		if _, ok := seen[h.NomorTiket]; ok {
+			return mainevent.ErrInvalidHolder
+		}
+		seen[h.NomorTiket] = struct{}{}
+
+		if containsAny(current.CheckInNomorTiket, []string{h.NomorTiket}) || containsAny(current.RefundNomorTiket, []string{h.NomorTiket}) {
+			return mainevent.ErrHolderChangeNotAllowed
+		}
+
+		// assigning the same holder again is a no-op
+		prev := current.HolderOf(h.NomorTiket)
+		if prev.Nama == h.Nama && strings.EqualFold(prev.Email, h.Email) && prev.NomorIdentitas == h.NomorIdentitas {
+			continue
+		}
+
+		err = pgStoreClient.UpsertHolder(ctx, h)
+		if err != nil {
+			return err
+		}
+		changed = append(changed, h)
+	}
+
+	// commit changes
+	err = pgStoreClient.Commit()
+	if err != nil {
+		return err
+	}
+
+	for _, h := range changed {
+		h := h
+		logger.Info(ctx, "ticket holder assigned", logger.Fields{"ticket_number": h.NomorTiket})
+
+		s.workers.Go(ctx, "mainevent holder ticket mail", func(ctx context.Context) error {
+			ev, err := s.eventOf(ctx, current)
+			if err != nil {
+				return err
+			}
+			path, err := helper.HolderPDF(s.config.PDF, ev, current, h)
+			if err != nil {
+				return err
+			}
+			return s.sendHolderTicketMail(ctx, current, h, path)
+		})
+	}
+
+	return nil
+}
+
+// validateHolder validates fields of the given holder of the
+// given mainevent.
+func validateHolder(m mainevent.MainEvent, h mainevent.Holder) error {
+	if h.Nama == "" {
+		return mainevent.ErrInvalidHolder
+	}
+
+	if _, err := mail.ParseAddress(h.Email); h.Email == "" || err != nil {
+		return mainevent.ErrInvalidHolder
+	}
+
+	if !containsAny(m.NomorTiket, []string{h.NomorTiket}) {
+		return mainevent.ErrInvalidHolder
+	}
+
+	return nil
+}
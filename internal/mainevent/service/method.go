@@ -3,42 +3,56 @@ package service
 import (
 	"context"
 	"fmt"
-	"log"
 	"math/rand"
 	"net/mail"
 	"time"
 
 	"github.com/robfig/cron/v3"
 	"github.com/tedxub2023/global/helper"
+	"github.com/tedxub2023/global/logger"
+	"github.com/tedxub2023/global/metrics"
+	"github.com/tedxub2023/internal/event"
 	"github.com/tedxub2023/internal/mainevent"
+	"github.com/tedxub2023/internal/promo"
 )
 
+// ticketPrice is the price of a mainevent ticket before any
+// promo discount, of the sale phases the event sets no price
+// for.
+const ticketPrice = 79000
+
 func (s *service) ReplaceMainEventByEmail(ctx context.Context, reqMainEvent mainevent.MainEvent) (int64, error) {
+	return s.replaceMainEvent(ctx, reqMainEvent, "")
+}
+
+func (s *service) ClaimWaitlistOffer(ctx context.Context, token string, reqMainEvent mainevent.MainEvent) (int64, error) {
+	if token == "" {
+		return 0, mainevent.ErrInvalidWaitlistToken
+	}
+	return s.replaceMainEvent(ctx, reqMainEvent, token)
+}
+
+// replaceMainEvent replaces the mainevents with the email of
+// the given mainevent. If the waitlist token is given, the
+// order may take the tickets held for the offer of the token.
+func (s *service) replaceMainEvent(ctx context.Context, reqMainEvent mainevent.MainEvent, waitlistToken string) (_ int64, err error) {
 	// validate field
-	err := validateMainEvent(reqMainEvent)
+	err = validateMainEvent(reqMainEvent)
 	if err != nil {
 		return 0, err
 	}
 
-	// get pg store client
-	pgStoreClient, err := s.pgStore.NewClient(true)
+	ev, err := s.currentEvent(ctx)
 	if err != nil {
 		return 0, err
 	}
 
-	tickets, err := pgStoreClient.GetAllMainEvents(ctx, mainevent.GetAllMainEventsFilter{})
+	// get pg store client
+	pgStoreClient, err := s.pgStore.NewClient(true)
 	if err != nil {
 		return 0, err
 	}
 
-	totalNormalSaleTicket := checkNormalSaleTicket(tickets)
-	if totalNormalSaleTicket+reqMainEvent.JumlahTiket > 100 {
-		return 0, mainevent.ErrTicketSoldOut
-	}
-
-	reqMainEvent.CreateTime = s.timeNow()
-	reqMainEvent.TotalHarga = 79000 * int64(reqMainEvent.JumlahTiket)
-
 	// rollback just before return if error
 	defer func() {
 		if err != nil {
@@ -50,57 +64,228 @@ func (s *service) ReplaceMainEventByEmail(ctx context.Context, reqMainEvent main
 		}
 	}()
 
+	reqMainEvent.EventID = ev.ID
+	reqMainEvent.CreateTime = s.timeNow()
+	reqMainEvent.TotalHarga = ticketPriceOf(ev, reqMainEvent.Type) * int64(reqMainEvent.JumlahTiket)
+
+	var entry mainevent.WaitlistEntry
+	if waitlistToken != "" {
+		entry, err = checkWaitlistOffer(ctx, pgStoreClient, waitlistToken, reqMainEvent)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	available, err := s.availableNormalSaleTickets(ctx, pgStoreClient, ev, entry.ID, reqMainEvent.NeedsAccessibleSeating())
+	if err != nil {
+		return 0, err
+	}
+	if reqMainEvent.JumlahTiket > available {
+		return 0, mainevent.ErrTicketSoldOut
+	}
+
+	err = s.checkPurchaseLimit(ctx, pgStoreClient, reqMainEvent)
+	if err != nil {
+		return 0, err
+	}
+
+	reqMainEvent.OrderID = generateOrderID()
+
+	// the unpaid orders replaced by this one do not count
+	// towards the promo limits, so that a buyer retrying the
+	// order is not held to the per email limit by their own
+	// order. Their redemptions are only cancelled once the
+	// order is stored.
+	replaced, err := replacedPromoOrders(ctx, pgStoreClient, reqMainEvent.Email)
+	if err != nil {
+		return 0, err
+	}
+
+	if reqMainEvent.KodePromo != "" {
+		var redemption promo.Redemption
+		redemption, err = s.promo.Redeem(ctx, promo.Redemption{
+			Kode:        reqMainEvent.KodePromo,
+			OrderID:     reqMainEvent.OrderID,
+			Email:       reqMainEvent.Email,
+			Type:        reqMainEvent.Type,
+			JumlahTiket: reqMainEvent.JumlahTiket,
+			Subtotal:    reqMainEvent.TotalHarga,
+			Replaces:    replaced,
+		})
+		if err != nil {
+			return 0, err
+		}
+
+		// release the promo code if the order is not created
+		defer func() {
+			if err != nil {
+				if errCancel := s.promo.CancelRedemption(ctx, reqMainEvent.OrderID); errCancel != nil {
+					logger.Error(ctx, "failed to cancel promo redemption", errCancel)
+				}
+			}
+		}()
+
+		reqMainEvent.KodePromo = redemption.Kode
+		reqMainEvent.Diskon = redemption.Diskon
+		reqMainEvent.TotalHarga -= redemption.Diskon
+	}
+
 	// delete MainEvent specified with email in pgstore
 	err = pgStoreClient.DeleteMainEventByEmail(ctx, reqMainEvent.Email)
 	if err != nil {
 		return 0, err
 	}
 
-	rand.Seed(time.Now().UnixNano())
-	randomNum := rand.Intn(1e10)
-	reqMainEvent.OrderID = fmt.Sprintf("%010d", randomNum)
-
 	ticketID, err := pgStoreClient.CreateMainEvent(ctx, reqMainEvent)
 	if err != nil {
 		return 0, err
 	}
+	logger.AddFields(ctx, logger.Fields{"mainevent_id": ticketID, "order_id": reqMainEvent.OrderID})
 
-	go sendMainEventPendingMail(reqMainEvent)
+	reqMainEvent.ID = ticketID
+	err = s.holdSeats(ctx, pgStoreClient, reqMainEvent)
+	if err != nil {
+		return 0, err
+	}
+
+	if entry.ID != 0 {
+		entry.Status = mainevent.WaitlistStatusClaimed
+		err = pgStoreClient.UpdateWaitlistEntry(ctx, entry, mainevent.WaitlistStatusOffered, reqMainEvent.CreateTime)
+		if err != nil {
+			return 0, err
+		}
+	}
 
 	// commit changes
 	err = pgStoreClient.Commit()
 	if err != nil {
 		return 0, err
 	}
-	s.AddCronJobs(ctx)
+
+	for _, orderID := range replaced {
+		if errCancel := s.promo.CancelRedemption(ctx, orderID); errCancel != nil {
+			logger.Error(ctx, "failed to cancel promo redemption of replaced order", errCancel, logger.Fields{"replaced_order_id": orderID})
+		}
+	}
+
+	metrics.OrdersCreated.WithLabelValues(metrics.EventMainEvent, reqMainEvent.Type.String()).Inc()
+	metrics.TicketsOrdered.WithLabelValues(metrics.EventMainEvent, reqMainEvent.Type.String()).Add(float64(reqMainEvent.JumlahTiket))
+	if entry.ID != 0 {
+		logger.Info(ctx, "waitlist offer claimed", logger.Fields{"waitlist_id": entry.ID})
+		metrics.WaitlistEntries.WithLabelValues(metrics.EventMainEvent, mainevent.WaitlistStatusClaimed.String()).Inc()
+	}
+
+	s.workers.Go(ctx, "mainevent pending mail", func(ctx context.Context) error {
+		return s.sendMainEventPendingMail(ctx, reqMainEvent)
+	})
 
 	return ticketID, nil
 }
 
+// replacedPromoOrders returns the order IDs of the unpaid
+// orders with the given email using a promo code, which are
+// replaced by a new order.
+func replacedPromoOrders(ctx context.Context, pgStoreClient PGStoreClient, email string) ([]string, error) {
+	unpaid, _, err := pgStoreClient.GetAllMainEvents(ctx, mainevent.GetAllMainEventsFilter{
+		Status: mainevent.StatusUnpaid,
+		Email:  email,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	orderIDs := make([]string, 0)
+	for _, m := range unpaid {
+		// the email of the replaced orders is compared as is
+		// and the group invoices are never replaced
+		if m.Email != email || m.Type == mainevent.TypeGroup || m.KodePromo == "" {
+			continue
+		}
+		orderIDs = append(orderIDs, m.OrderID)
+	}
+
+	return orderIDs, nil
+}
+
+// checkPurchaseLimit returns mainevent.PurchaseLimitError if
+// the given mainevent takes the tickets of its buyer over the
+// purchase limit by identity number or by email. The buyer
+// stays locked until the end of the transaction.
+func (s *service) checkPurchaseLimit(ctx context.Context, pgStoreClient PGStoreClient, reqMainEvent mainevent.MainEvent) error {
+	if s.config.PurchaseLimitPerIdentity <= 0 && s.config.PurchaseLimitPerEmail <= 0 {
+		return nil
+	}
+
+	byEmail, byIdentity, err := pgStoreClient.CountBuyerTickets(ctx, reqMainEvent.EventID, reqMainEvent.Email, reqMainEvent.NomorIdentitas)
+	if err != nil {
+		return err
+	}
+
+	// the buyer may buy up to the tighter of the limits
+	remaining := reqMainEvent.JumlahTiket
+	if limit := s.config.PurchaseLimitPerIdentity; limit > 0 && limit-byIdentity < remaining {
+		remaining = limit - byIdentity
+	}
+	if limit := s.config.PurchaseLimitPerEmail; limit > 0 && limit-byEmail < remaining {
+		remaining = limit - byEmail
+	}
+	if remaining < 0 {
+		remaining = 0
+	}
+
+	if reqMainEvent.JumlahTiket > remaining {
+		logger.Info(ctx, "purchase limit reached", logger.Fields{"remaining": remaining})
+		return mainevent.PurchaseLimitError{Remaining: remaining}
+	}
+
+	return nil
+}
+
 func checkNormalSaleTicket(tx []mainevent.MainEvent) int {
 	counter := 0
 	for _, t := range tx {
-		if t.Type == mainevent.TypeNormalSale {
-			counter += t.JumlahTiket
+		// refunded tickets are back on sale, the group
+		// orders take the normal sale tickets
+		if t.Type == mainevent.TypeNormalSale || t.Type == mainevent.TypeGroup {
+			counter += t.JumlahTiket - len(t.RefundNomorTiket)
 		}
 	}
 	return counter
 }
 
-func (s *service) GetAllMainEvents(ctx context.Context, filter mainevent.GetAllMainEventsFilter) ([]mainevent.MainEvent, error) {
+func (s *service) GetAllMainEvents(ctx context.Context, filter mainevent.GetAllMainEventsFilter) ([]mainevent.MainEvent, mainevent.Pagination, error) {
+	// validate sort
+	if filter.SortBy != "" {
+		if _, ok := mainevent.SortByList[filter.SortBy]; !ok {
+			return nil, mainevent.Pagination{}, mainevent.ErrInvalidSort
+		}
+	}
+	if filter.SortOrder != "" && filter.SortOrder != mainevent.SortOrderAsc && filter.SortOrder != mainevent.SortOrderDesc {
+		return nil, mainevent.Pagination{}, mainevent.ErrInvalidSort
+	}
+
 	// get pg store client without using MainEvent
 	pgStoreClient, err := s.pgStore.NewClient(false)
 	if err != nil {
-		return nil, err
+		return nil, mainevent.Pagination{}, err
 	}
 
-	// get all MainEvents from postgre
-	result, err := pgStoreClient.GetAllMainEvents(ctx, filter)
+	// get MainEvents from postgre
+	result, nextCursor, err := pgStoreClient.GetAllMainEvents(ctx, filter)
 	if err != nil {
-		return nil, err
+		return nil, mainevent.Pagination{}, err
 	}
 
-	return result, nil
+	total, totalTickets, err := pgStoreClient.CountMainEvents(ctx, filter)
+	if err != nil {
+		return nil, mainevent.Pagination{}, err
+	}
+
+	return result, mainevent.Pagination{
+		NextCursor:   nextCursor,
+		Total:        total,
+		TotalTickets: totalTickets,
+	}, nil
 }
 
 func (s *service) GetMainEventByID(ctx context.Context, MainEventID int64, nomorTiket string) (mainevent.MainEvent, error) {
@@ -121,6 +306,16 @@ func (s *service) GetMainEventByID(ctx context.Context, MainEventID int64, nomor
 		return mainevent.MainEvent{}, err
 	}
 
+	result.Holders, err = pgStoreClient.GetHoldersByMainEventID(ctx, MainEventID)
+	if err != nil {
+		return mainevent.MainEvent{}, err
+	}
+
+	result.Seats, err = pgStoreClient.GetSeatsByMainEventID(ctx, MainEventID)
+	if err != nil {
+		return mainevent.MainEvent{}, err
+	}
+
 	if nomorTiket != "" {
 		valid := false
 		for _, nomorTikett := range result.NomorTiket {
@@ -138,25 +333,40 @@ func (s *service) GetMainEventByID(ctx context.Context, MainEventID int64, nomor
 	return result, nil
 }
 
-func (s *service) UpdateCheckInStatus(ctx context.Context, id int64, ticketNumber string) (string, error) {
+func (s *service) UpdateCheckInStatus(ctx context.Context, id int64, ticketNumber string) (_ mainevent.CheckIn, err error) {
 	if id <= 0 {
-		return "", mainevent.ErrInvalidMainEventID
+		return mainevent.CheckIn{}, mainevent.ErrInvalidMainEventID
 	}
 
-	pgStoreClient, err := s.pgStore.NewClient(false)
+	// the check in time is stored together with the ticket
+	pgStoreClient, err := s.pgStore.NewClient(true)
 	if err != nil {
-		return "", err
+		return mainevent.CheckIn{}, err
 	}
 
+	// rollback just before return if error
+	defer func() {
+		if err != nil {
+			errTx := pgStoreClient.Rollback()
+			if errTx != nil {
+				// return err from rollback
+				err = errTx
+			}
+		}
+	}()
+
 	tx, err := pgStoreClient.GetMainEventByID(ctx, id)
 	if err != nil {
-		return "", err
+		return mainevent.CheckIn{}, err
 	}
+	logger.AddFields(ctx, logger.Fields{"order_id": tx.OrderID, "ticket_number": ticketNumber})
 
 	if tx.CheckInStatus {
-		return "", mainevent.ErrAllTicketAlreadyCheckedIn
+		return mainevent.CheckIn{}, mainevent.ErrAllTicketAlreadyCheckedIn
+	} else if tx.Status == mainevent.StatusRefunded {
+		return mainevent.CheckIn{}, mainevent.ErrTicketRefunded
 	} else if tx.Status != mainevent.StatusSettlement {
-		return "", mainevent.ErrTicketNotYetPaid
+		return mainevent.CheckIn{}, mainevent.ErrTicketNotYetPaid
 	}
 
 	var isTicketAvailable bool
@@ -171,77 +381,176 @@ func (s *service) UpdateCheckInStatus(ctx context.Context, id int64, ticketNumbe
 	if isTicketAvailable {
 		for _, ticNum := range tx.CheckInNomorTiket {
 			if ticketNumber == ticNum {
-				return "", mainevent.ErrTicketAlreadyCheckedIn
+				return mainevent.CheckIn{}, mainevent.ErrTicketAlreadyCheckedIn
+			}
+		}
+		for _, ticNum := range tx.RefundNomorTiket {
+			if ticketNumber == ticNum {
+				return mainevent.CheckIn{}, mainevent.ErrTicketRefunded
 			}
 		}
 	} else {
-		return "", mainevent.ErrDataNotFound
+		return mainevent.CheckIn{}, mainevent.ErrDataNotFound
 	}
 
 	tx.CheckInNomorTiket = append(tx.CheckInNomorTiket, ticketNumber)
 
-	if len(tx.CheckInNomorTiket) == len(tx.NomorTiket) {
+	if len(tx.CheckInNomorTiket)+len(tx.RefundNomorTiket) == len(tx.NomorTiket) {
 		tx.CheckInStatus = true
 	}
 
-	err = pgStoreClient.UpdateMainEventByID(ctx, tx)
+	now := s.timeNow()
+	err = pgStoreClient.UpdateMainEventByID(ctx, tx, now)
+	if err != nil {
+		return mainevent.CheckIn{}, err
+	}
+
+	err = pgStoreClient.CreateCheckIn(ctx, tx.ID, ticketNumber, now)
+	if err != nil {
+		return mainevent.CheckIn{}, err
+	}
+
+	// the gate checks the ID card of the holder and guides
+	// them to their seat
+	tx.Holders, err = pgStoreClient.GetHoldersByMainEventID(ctx, tx.ID)
 	if err != nil {
-		return "", err
+		return mainevent.CheckIn{}, err
 	}
 
-	return ticketNumber, nil
+	tx.Seats, err = pgStoreClient.GetSeatsByMainEventID(ctx, tx.ID)
+	if err != nil {
+		return mainevent.CheckIn{}, err
+	}
+
+	// commit changes
+	err = pgStoreClient.Commit()
+	if err != nil {
+		return mainevent.CheckIn{}, err
+	}
+
+	return mainevent.CheckIn{
+		Holder:           tx.HolderOf(ticketNumber),
+		Disabilitas:      tx.Disabilitas,
+		Akomodasi:        tx.Akomodasi,
+		CatatanAkomodasi: tx.CatatanAkomodasi,
+		Seat:             tx.SeatOf(ticketNumber),
+	}, nil
 }
 
-func (s *service) UpdatePaymentStatus(ctx context.Context, reqMainEvent mainevent.MainEvent) error {
+func (s *service) UpdatePaymentStatus(ctx context.Context, reqMainEvent mainevent.MainEvent) (err error) {
 	// validate id
 	if reqMainEvent.ID <= 0 {
 		return mainevent.ErrInvalidMainEventID
 	}
 
-	reqMainEvent.UpdateTime = s.timeNow()
+	// validate status
+	if _, ok := mainevent.StatusList[reqMainEvent.Status]; !ok {
+		return mainevent.ErrInvalidMainEventStatus
+	}
 
-	pgStoreClient, err := s.pgStore.NewClient(false)
+	// the seats are assigned within the same transaction as
+	// the status, so that a failed update releases them
+	pgStoreClient, err := s.pgStore.NewClient(true)
 	if err != nil {
 		return err
 	}
 
-	ticketNumbers := generateNumberTicket(reqMainEvent.ID, reqMainEvent.JumlahTiket)
-	reqMainEvent.NomorTiket = ticketNumbers
+	// rollback just before return if error
+	defer func() {
+		if err != nil {
+			errTx := pgStoreClient.Rollback()
+			if errTx != nil {
+				// return err from rollback
+				err = errTx
+			}
+		}
+	}()
 
-	err = pgStoreClient.UpdateMainEventByID(ctx, reqMainEvent)
+	current, err := pgStoreClient.GetMainEventByID(ctx, reqMainEvent.ID)
 	if err != nil {
 		return err
 	}
+	logger.AddFields(ctx, logger.Fields{"order_id": current.OrderID, "status": reqMainEvent.Status.String()})
 
-	if reqMainEvent.Status == mainevent.StatusPending && reqMainEvent.ImageURI != "" {
-		go sendMailInformAdmin(reqMainEvent)
+	// the request is based on an outdated mainevent
+	if !reqMainEvent.UpdateTime.Equal(current.UpdateTime) {
+		return mainevent.ErrConflict
 	}
 
-	if reqMainEvent.Status == mainevent.StatusSettlement {
-		if err := generatePDF(reqMainEvent); err != nil {
-			return err
-		}
+	effects, err := mainevent.Transition(current.Status, reqMainEvent.Status)
+	if err != nil {
+		return err
+	}
 
-		go sendSuccessTransactionMail(reqMainEvent)
+	// only the status and the payment proof are updated
+	next := current
+	next.Status = reqMainEvent.Status
+	next.ImageURI = reqMainEvent.ImageURI
+
+	if next.Status == mainevent.StatusPending && next.ImageURI == "" {
+		return mainevent.ErrInvalidMainEventImageURI
 	}
 
+	err = s.prepareEffects(ctx, pgStoreClient, &next, effects)
+	if err != nil {
+		return err
+	}
+
+	// a concurrent update of the same mainevent fails here,
+	// so the effects run once
+	err = pgStoreClient.UpdateMainEventByID(ctx, next, s.timeNow())
+	if err != nil {
+		return err
+	}
+
+	// commit changes
+	err = pgStoreClient.Commit()
+	if err != nil {
+		return err
+	}
+
+	s.runEffects(ctx, next, effects)
+
 	return nil
 }
 
-func generateNumberTicket(txID int64, totalTickets int) []string {
+// generateOrderID returns a new random order ID.
+func generateOrderID() string {
+	rand.Seed(time.Now().UnixNano())
+	randomNum := rand.Intn(1e10)
+	return fmt.Sprintf("%010d", randomNum)
+}
+
+// generateNumberTicket returns the ticket numbers of the
+// mainevent with the given ID, starting with the given prefix,
+// see ticketPrefix.
+func generateNumberTicket(prefix string, txID int64, totalTickets int) []string {
 	var ticketNumbers []string
 
-	asciiVal := 65
 	for i := 0; i < totalTickets; i++ {
-		ticketNumbers = append(ticketNumbers, fmt.Sprintf("MEMANTIKBASKARA-1/%c%d", rune(asciiVal), txID))
-		asciiVal++
+		ticketNumbers = append(ticketNumbers, fmt.Sprintf("%s-1/%s%d", prefix, ticketLetter(i), txID))
 	}
 
 	return ticketNumbers
 }
 
-func generatePDF(tx mainevent.MainEvent) error {
-	err := helper.PDF(tx)
+// ticketLetter returns the letters of the i-th ticket of an
+// order, counted from 0, as spreadsheet columns are named: A
+// to Z, then AA, AB and so on. Group orders have more than 26
+// tickets, and the letters never clash with the order ID
+// digits that follow.
+func ticketLetter(i int) string {
+	var letters []byte
+	for i++; i > 0; i = (i - 1) / 26 {
+		letters = append([]byte{byte('A' + (i-1)%26)}, letters...)
+	}
+	return string(letters)
+}
+
+// generatePDF renders the tickets of the given mainevent with
+// the name and branding of the given event.
+func (s *service) generatePDF(ev event.Event, tx mainevent.MainEvent) error {
+	err := helper.PDF(s.config.PDF, ev, tx)
 	if err != nil {
 		return err
 	}
@@ -276,43 +585,102 @@ func validateMainEvent(reqMainEvent mainevent.MainEvent) error {
 		return mainevent.ErrInvalidMainEventJumlahTiket
 	}
 
-	return nil
+	err = validateAccommodation(reqMainEvent)
+	if err != nil {
+		return err
+	}
+
+	return validateSeatSelection(reqMainEvent)
 }
 
-func (s *service) AddCronJobs(ctx context.Context) error {
+// addCronJobs registers the scheduled jobs of the service to
+// the worker group.
+func (s *service) addCronJobs() error {
 	pgStoreClient, err := s.pgStore.NewClient(false)
 	if err != nil {
 		return err
 	}
-	jakartaTime, _ := time.LoadLocation("Asia/Jakarta")
+
+	jakartaTime, err := time.LoadLocation("Asia/Jakarta")
+	if err != nil {
+		return err
+	}
+
 	scheduler := cron.New(cron.WithLocation(jakartaTime))
-	go scheduler.AddFunc("*/1 * * * *", func() {
-		results, err := s.GetAllMainEvents(ctx, mainevent.GetAllMainEventsFilter{
+	_, err = scheduler.AddFunc("*/1 * * * *", func() {
+		ctx := logger.NewContext(context.Background(), logger.Fields{"cron": "expire unpaid mainevent"})
+
+		results, _, err := pgStoreClient.GetAllMainEvents(ctx, mainevent.GetAllMainEventsFilter{
 			Status: mainevent.StatusUnpaid,
 			Type:   mainevent.TypeNormalSale,
 		})
 		if err != nil {
-			log.Println("err get all", err)
+			logger.Error(ctx, "failed to get unpaid mainevents", err)
 			return
 		}
 
 		for _, result := range results {
-			timeDifference := time.Since(result.CreateTime)
-			if timeDifference.Minutes() > 6 {
+			if s.timeNow().After(result.PaymentDeadline()) {
 				err = pgStoreClient.DeleteMainEventByEmail(ctx, result.Email)
 				if err != nil {
-					log.Println("err delete", err, result.ID)
+					logger.Error(ctx, "failed to delete expired mainevent", err, logger.Fields{"mainevent_id": result.ID, "order_id": result.OrderID})
 				}
 				if err == nil {
-					log.Println("deleted", result.ID)
-
-					go sendTransactionDeclinedMail(result)
+					logger.Info(ctx, "deleted expired mainevent", logger.Fields{"mainevent_id": result.ID, "order_id": result.OrderID})
+					metrics.OrderExpirations.WithLabelValues(metrics.EventMainEvent, result.Type.String()).Inc()
+
+					if result.KodePromo != "" {
+						if err := s.promo.CancelRedemption(ctx, result.OrderID); err != nil {
+							logger.Error(ctx, "failed to cancel promo redemption", err, logger.Fields{"order_id": result.OrderID})
+						}
+					}
+
+					result := result
+					s.workers.Go(ctx, "mainevent declined mail", func(ctx context.Context) error {
+						return s.sendTransactionDeclinedMail(ctx, result)
+					})
 				}
 			}
 		}
 
+		invoices, _, err := pgStoreClient.GetAllMainEvents(ctx, mainevent.GetAllMainEventsFilter{
+			Status: mainevent.StatusUnpaid,
+			Type:   mainevent.TypeGroup,
+		})
+		if err != nil {
+			logger.Error(ctx, "failed to get unpaid group invoices", err)
+		}
+
+		for _, invoice := range invoices {
+			if !s.timeNow().After(invoice.PaymentDeadline()) {
+				continue
+			}
+
+			err = s.expireGroupInvoice(ctx, invoice)
+			if err != nil {
+				logger.Error(ctx, "failed to expire group invoice", err, logger.Fields{"mainevent_id": invoice.ID, "order_id": invoice.OrderID})
+				continue
+			}
+			logger.Info(ctx, "expired group invoice", logger.Fields{"mainevent_id": invoice.ID, "order_id": invoice.OrderID})
+			metrics.OrderExpirations.WithLabelValues(metrics.EventMainEvent, invoice.Type.String()).Inc()
+
+			invoice := invoice
+			s.workers.Go(ctx, "mainevent declined mail", func(ctx context.Context) error {
+				return s.sendTransactionDeclinedMail(ctx, invoice)
+			})
+		}
+
+		// the expired orders free their tickets for the
+		// waitlist
+		if err := s.offerWaitlist(ctx); err != nil {
+			logger.Error(ctx, "failed to offer waitlist", err)
+		}
 	})
-	scheduler.Start()
+	if err != nil {
+		return err
+	}
+
+	s.workers.AddScheduler(scheduler)
 
 	return nil
 }
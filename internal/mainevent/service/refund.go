@@ -0,0 +1,309 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/tedxub2023/global/logger"
+	"github.com/tedxub2023/global/metrics"
+	"github.com/tedxub2023/global/payment"
+	"github.com/tedxub2023/internal/mainevent"
+)
+
+func (s *service) RequestRefund(ctx context.Context, session string, reqRefund mainevent.Refund) (int64, error) {
+	// validate field
+	if reqRefund.MainEventID <= 0 {
+		return 0, mainevent.ErrInvalidMainEventID
+	}
+	if len(reqRefund.NomorTiket) == 0 || hasDuplicate(reqRefund.NomorTiket) {
+		return 0, mainevent.ErrInvalidRefundNomorTiket
+	}
+
+	pgStoreClient, err := s.pgStore.NewClient(false)
+	if err != nil {
+		return 0, err
+	}
+
+	// only the buyer logged in with the order email may
+	// refund its tickets
+	current, err := s.buyerMainEvent(ctx, pgStoreClient, session, reqRefund.MainEventID)
+	if err != nil {
+		return 0, err
+	}
+
+	// the complimentary tickets were never paid for
+	if current.Status != mainevent.StatusSettlement || current.Type == mainevent.TypeComplimentary {
+		return 0, mainevent.ErrRefundNotAllowed
+	}
+	if err := validateRefundTickets(current, reqRefund.NomorTiket); err != nil {
+		return 0, err
+	}
+
+	// a ticket may only be in one refund waiting for approval
+	requested, err := pgStoreClient.GetAllRefunds(ctx, current.ID, mainevent.RefundStatusRequested)
+	if err != nil {
+		return 0, err
+	}
+	for _, r := range requested {
+		if containsAny(r.NomorTiket, reqRefund.NomorTiket) {
+			return 0, mainevent.ErrRefundNotAllowed
+		}
+	}
+
+	reqRefund.TotalRefund, err = s.refundAmount(ctx, pgStoreClient, current, len(reqRefund.NomorTiket))
+	if err != nil {
+		return 0, err
+	}
+	reqRefund.Status = mainevent.RefundStatusRequested
+	reqRefund.Metode = mainevent.RefundMethodUnknown
+	reqRefund.Catatan = ""
+	reqRefund.CreateTime = s.timeNow()
+
+	refundID, err := pgStoreClient.CreateRefund(ctx, reqRefund)
+	if err != nil {
+		return 0, err
+	}
+	logger.AddFields(ctx, logger.Fields{"refund_id": refundID})
+
+	return refundID, nil
+}
+
+func (s *service) GetAllRefunds(ctx context.Context, status mainevent.RefundStatus) ([]mainevent.Refund, error) {
+	// validate status
+	if status != mainevent.RefundStatusUnknown {
+		if _, ok := mainevent.RefundStatusList[status]; !ok {
+			return nil, mainevent.ErrInvalidRefundStatus
+		}
+	}
+
+	pgStoreClient, err := s.pgStore.NewClient(false)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgStoreClient.GetAllRefunds(ctx, 0, status)
+}
+
+func (s *service) ProcessRefund(ctx context.Context, reqRefund mainevent.Refund) (err error) {
+	// validate field
+	if reqRefund.ID <= 0 {
+		return mainevent.ErrInvalidRefundID
+	}
+	switch reqRefund.Status {
+	case mainevent.RefundStatusApproved:
+		if _, ok := mainevent.RefundMethodList[reqRefund.Metode]; !ok {
+			return mainevent.ErrInvalidRefundMethod
+		}
+	case mainevent.RefundStatusRejected:
+		reqRefund.Metode = mainevent.RefundMethodUnknown
+	default:
+		return mainevent.ErrInvalidRefundStatus
+	}
+
+	pgStoreClient, err := s.pgStore.NewClient(true)
+	if err != nil {
+		return err
+	}
+
+	// rollback just before return if error
+	defer func() {
+		if err != nil {
+			errTx := pgStoreClient.Rollback()
+			if errTx != nil {
+				// return err from rollback
+				err = errTx
+			}
+		}
+	}()
+
+	refund, err := pgStoreClient.GetRefundByID(ctx, reqRefund.ID)
+	if err != nil {
+		return err
+	}
+	if refund.Status != mainevent.RefundStatusRequested {
+		return mainevent.ErrRefundAlreadyProcessed
+	}
+
+	current, err := pgStoreClient.GetMainEventByID(ctx, refund.MainEventID)
+	if err != nil {
+		return err
+	}
+	logger.AddFields(ctx, logger.Fields{"refund_id": refund.ID, "mainevent_id": current.ID, "order_id": current.OrderID})
+
+	refund.Status = reqRefund.Status
+	refund.Metode = reqRefund.Metode
+	refund.Catatan = reqRefund.Catatan
+
+	// the orders paid by a bank transfer are transferred back
+	// by the committee, the gateway has nothing to refund
+	if refund.Metode == mainevent.RefundMethodGateway && !current.PaidThroughGateway() {
+		refund.Metode = mainevent.RefundMethodManual
+	}
+
+	now := s.timeNow()
+
+	// the tickets may have been used since the request
+	if refund.Status == mainevent.RefundStatusApproved {
+		if err = validateRefundTickets(current, refund.NomorTiket); err != nil {
+			return err
+		}
+
+		current.RefundNomorTiket = append(current.RefundNomorTiket, refund.NomorTiket...)
+		current.CheckInStatus = len(current.CheckInNomorTiket) > 0 &&
+			len(current.CheckInNomorTiket)+len(current.RefundNomorTiket) == len(current.NomorTiket)
+		if len(current.RefundNomorTiket) == len(current.NomorTiket) {
+			current.Status = mainevent.StatusRefunded
+		}
+
+		err = pgStoreClient.UpdateMainEventByID(ctx, current, now)
+		if err != nil {
+			return err
+		}
+
+		// the seats of the refunded tickets are back on sale
+		err = pgStoreClient.ReleaseSeats(ctx, refund.NomorTiket, now)
+		if err != nil {
+			return err
+		}
+
+		err = pgStoreClient.DeleteHolders(ctx, current.ID, refund.NomorTiket)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = pgStoreClient.UpdateRefundStatus(ctx, refund, now)
+	if err != nil {
+		return err
+	}
+
+	// the refund key makes a retried refund idempotent at
+	// the gateway, should the commit below fail
+	if refund.Status == mainevent.RefundStatusApproved && refund.Metode == mainevent.RefundMethodGateway {
+		err = s.refunder.Refund(ctx, payment.RefundRequest{
+			OrderID:   current.OrderID,
+			RefundKey: fmt.Sprintf("%s-refund-%d", current.OrderID, refund.ID),
+			Amount:    refund.TotalRefund,
+			Reason:    refund.Alasan,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	// commit changes
+	err = pgStoreClient.Commit()
+	if err != nil {
+		return err
+	}
+
+	if refund.Status == mainevent.RefundStatusApproved {
+		metrics.TicketsRefunded.WithLabelValues(metrics.EventMainEvent, current.Type.String()).Add(float64(len(refund.NomorTiket)))
+
+		// the refunded tickets are free for the waitlist
+		s.workers.Go(ctx, "mainevent waitlist offer", func(ctx context.Context) error {
+			return s.offerWaitlist(ctx)
+		})
+	}
+
+	s.workers.Go(ctx, "mainevent refund mail", func(ctx context.Context) error {
+		return s.sendRefundMail(ctx, current, refund)
+	})
+
+	return nil
+}
+
+// refundAmount returns the amount refunded for the given
+// number of tickets of the given mainevent: the price of each
+// ticket, up to what is left of its payment after its other
+// refunds, so that a discounted order is never refunded more
+// than it paid.
+func (s *service) refundAmount(ctx context.Context, pgStoreClient PGStoreClient, m mainevent.MainEvent, jumlahTiket int) (int64, error) {
+	price, err := s.ticketPriceOfOrder(ctx, pgStoreClient, m)
+	if err != nil {
+		return 0, err
+	}
+
+	refunds, err := pgStoreClient.GetAllRefunds(ctx, m.ID, mainevent.RefundStatusUnknown)
+	if err != nil {
+		return 0, err
+	}
+
+	left := m.TotalHarga
+	for _, r := range refunds {
+		if r.Status != mainevent.RefundStatusRejected {
+			left -= r.TotalRefund
+		}
+	}
+
+	amount := price * int64(jumlahTiket)
+	if amount > left {
+		amount = left
+	}
+	if amount < 0 {
+		amount = 0
+	}
+
+	return amount, nil
+}
+
+// ticketPriceOfOrder returns the price of each ticket of the
+// given mainevent before its discount, the price of its group
+// order for a group invoice.
+func (s *service) ticketPriceOfOrder(ctx context.Context, pgStoreClient PGStoreClient, m mainevent.MainEvent) (int64, error) {
+	if m.Type == mainevent.TypeGroup {
+		group, err := pgStoreClient.GetGroupOrderByMainEventID(ctx, m.ID)
+		if err != nil {
+			return 0, err
+		}
+		return group.HargaTiket, nil
+	}
+
+	ev, err := s.eventOf(ctx, m)
+	if err != nil {
+		return 0, err
+	}
+
+	return ticketPriceOf(ev, m.Type), nil
+}
+
+// validateRefundTickets validates that the given ticket
+// numbers are of the given mainevent and neither checked in
+// nor refunded.
+func validateRefundTickets(m mainevent.MainEvent, ticketNumbers []string) error {
+	for _, ticketNumber := range ticketNumbers {
+		if !containsAny(m.NomorTiket, []string{ticketNumber}) {
+			return mainevent.ErrInvalidRefundNomorTiket
+		}
+	}
+	if containsAny(m.CheckInNomorTiket, ticketNumbers) || containsAny(m.RefundNomorTiket, ticketNumbers) {
+		return mainevent.ErrRefundNotAllowed
+	}
+	return nil
+}
+
+// containsAny returns whether any of the wanted strings is in
+// the given list.
+func containsAny(list, wanted []string) bool {
+	for _, l := range list {
+		for _, w := range wanted {
+			if l == w {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// hasDuplicate returns whether the given list contains a
+// string more than once.
+func hasDuplicate(list []string) bool {
+	seen := make(map[string]struct{}, len(list))
+	for _, l := range list {
+		if _, ok := seen[l]; ok {
+			return true
+		}
+		seen[l] = struct{}{}
+	}
+	return false
+}
@@ -0,0 +1,302 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/tedxub2023/global/logger"
+	"github.com/tedxub2023/internal/mainevent"
+)
+
+func (s *service) GetAllSeats(ctx context.Context) ([]mainevent.Seat, error) {
+	pgStoreClient, err := s.pgStore.NewClient(false)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgStoreClient.GetAllSeats(ctx)
+}
+
+func (s *service) LoadSeatLayout(ctx context.Context, layout mainevent.SeatLayout) (err error) {
+	seats, err := layoutSeats(layout)
+	if err != nil {
+		return err
+	}
+
+	// get pg store client
+	pgStoreClient, err := s.pgStore.NewClient(true)
+	if err != nil {
+		return err
+	}
+
+	// rollback just before return if error
+	defer func() {
+		if err != nil {
+			errTx := pgStoreClient.Rollback()
+			if errTx != nil {
+				// return err from rollback
+				err = errTx
+			}
+		}
+	}()
+
+	current, err := pgStoreClient.GetSeatsForUpdate(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := s.timeNow()
+
+	// the seats kept by the layout stay with their mainevents,
+	// the others are removed if they are free
+	existing := make(map[string]mainevent.Seat, len(current))
+	for _, seat := range current {
+		existing[seatKey(seat)] = seat
+	}
+
+	var created, updated int
+	for _, seat := range seats {
+		key := seatKey(seat)
+		old, ok := existing[key]
+		delete(existing, key)
+
+		if !ok {
+			seat.CreateTime = now
+			err = pgStoreClient.CreateSeat(ctx, seat)
+			if err != nil {
+				return err
+			}
+			created++
+			continue
+		}
+
+		if old.Aksesibel != seat.Aksesibel {
+			old.Aksesibel = seat.Aksesibel
+			err = pgStoreClient.UpdateSeatAksesibel(ctx, old, now)
+			if err != nil {
+				return err
+			}
+			updated++
+		}
+	}
+
+	removed := make([]int64, 0, len(existing))
+	for _, seat := range existing {
+		removed = append(removed, seat.ID)
+	}
+	err = pgStoreClient.DeleteSeats(ctx, removed)
+	if err != nil {
+		return err
+	}
+
+	// commit changes
+	err = pgStoreClient.Commit()
+	if err != nil {
+		return err
+	}
+
+	logger.Info(ctx, "seat layout loaded", logger.Fields{"created": created, "updated": updated, "removed": len(removed)})
+
+	return nil
+}
+
+// holdSeats holds the seats selected for the given created
+// mainevent.
+func (s *service) holdSeats(ctx context.Context, pgStoreClient PGStoreClient, m mainevent.MainEvent) error {
+	if len(m.SeatIDs) == 0 {
+		return nil
+	}
+
+	return pgStoreClient.HoldSeats(ctx, m.ID, m.SeatIDs, m.NeedsAccessibleSeating(), s.timeNow())
+}
+
+// assignSeats seats the issued tickets of the given mainevent
+// within the transaction of the store client, first on the
+// seats held for the mainevent and then on the best free
+// seats. The tickets are left unseated if there is no seat
+// layout or not enough free seats.
+func (s *service) assignSeats(ctx context.Context, pgStoreClient PGStoreClient, m *mainevent.MainEvent) error {
+	seats, err := pgStoreClient.GetSeatsForUpdate(ctx)
+	if err != nil {
+		return err
+	}
+	if len(seats) == 0 {
+		return nil
+	}
+
+	var held, free []mainevent.Seat
+	seated := make(map[string]mainevent.Seat)
+	for _, seat := range seats {
+		switch {
+		case seat.MainEventID == m.ID && seat.NomorTiket != "":
+			seated[seat.NomorTiket] = seat
+		case seat.MainEventID == m.ID:
+			held = append(held, seat)
+		case seat.Available():
+			free = append(free, seat)
+		}
+	}
+
+	unseated := make([]string, 0, len(m.NomorTiket))
+	for _, nomorTiket := range m.NomorTiket {
+		if _, ok := seated[nomorTiket]; !ok && !containsAny(m.RefundNomorTiket, []string{nomorTiket}) {
+			unseated = append(unseated, nomorTiket)
+		}
+	}
+
+	candidates := held
+	if len(unseated) > len(held) {
+		candidates = append(candidates, pickSeats(free, len(unseated)-len(held), m.NeedsAccessibleSeating())...)
+	}
+	if len(candidates) < len(unseated) {
+		logger.Warn(ctx, "not enough free seats", logger.Fields{"jumlah_tiket": len(unseated), "jumlah_kursi": len(candidates)})
+	}
+
+	now := s.timeNow()
+	m.Seats = make([]mainevent.Seat, 0, len(m.NomorTiket))
+	for _, seat := range seated {
+		m.Seats = append(m.Seats, seat)
+	}
+	for i, seat := range candidates {
+		if i >= len(unseated) {
+			break
+		}
+
+		seat.MainEventID = m.ID
+		seat.NomorTiket = unseated[i]
+		err = pgStoreClient.AssignSeat(ctx, seat, now)
+		if err != nil {
+			return err
+		}
+		m.Seats = append(m.Seats, seat)
+	}
+	sort.Slice(m.Seats, func(i, j int) bool {
+		return m.Seats[i].ID < m.Seats[j].ID
+	})
+
+	return nil
+}
+
+// pickSeats returns up to the given number of the given free
+// seats, side by side in a row if possible. The buyers needing
+// accessible seating get the accessible seats first, the other
+// buyers only get them once the rest are taken.
+func pickSeats(free []mainevent.Seat, n int, aksesibel bool) []mainevent.Seat {
+	preferred := make([]mainevent.Seat, 0, len(free))
+	others := make([]mainevent.Seat, 0, len(free))
+	for _, seat := range free {
+		if seat.Aksesibel == aksesibel {
+			preferred = append(preferred, seat)
+		} else {
+			others = append(others, seat)
+		}
+	}
+
+	if run := adjacentSeats(preferred, n); run != nil {
+		return run
+	}
+
+	candidates := append(preferred, others...)
+	if n > len(candidates) {
+		n = len(candidates)
+	}
+	return candidates[:n]
+}
+
+// adjacentSeats returns the first run of the given number of
+// seats next to each other in a row, or nil if there is none.
+// The seats are in the order of the seat layout.
+func adjacentSeats(seats []mainevent.Seat, n int) []mainevent.Seat {
+	if n <= 0 {
+		return nil
+	}
+
+	start := 0
+	for i := range seats {
+		if i > start {
+			prev := seats[i-1]
+			if seats[i].Section != prev.Section || seats[i].Baris != prev.Baris || seats[i].Nomor != prev.Nomor+1 {
+				start = i
+			}
+		}
+		if i-start+1 == n {
+			return seats[start : i+1]
+		}
+	}
+	return nil
+}
+
+// layoutSeats returns the seats of the given seat layout in
+// its order, validating the layout.
+func layoutSeats(layout mainevent.SeatLayout) ([]mainevent.Seat, error) {
+	seats := make([]mainevent.Seat, 0)
+	sections := make(map[string]struct{}, len(layout.Sections))
+	for _, section := range layout.Sections {
+		nama := strings.TrimSpace(section.Nama)
+		if _, ok := sections[strings.ToLower(nama)]; ok || nama == "" || len(section.Baris) == 0 {
+			return nil, mainevent.ErrInvalidSeatLayout
+		}
+		sections[strings.ToLower(nama)] = struct{}{}
+
+		rows := make(map[string]struct{}, len(section.Baris))
+		for _, row := range section.Baris {
+			baris := strings.TrimSpace(row.Nama)
+			if _, ok := rows[strings.ToLower(baris)]; ok || baris == "" || row.JumlahKursi <= 0 {
+				return nil, mainevent.ErrInvalidSeatLayout
+			}
+			rows[strings.ToLower(baris)] = struct{}{}
+
+			accessible := make(map[int]bool, len(row.KursiAksesibel))
+			for _, nomor := range row.KursiAksesibel {
+				if nomor <= 0 || nomor > row.JumlahKursi {
+					return nil, mainevent.ErrInvalidSeatLayout
+				}
+				accessible[nomor] = true
+			}
+
+			for nomor := 1; nomor <= row.JumlahKursi; nomor++ {
+				seats = append(seats, mainevent.Seat{
+					Section:   nama,
+					Baris:     baris,
+					Nomor:     nomor,
+					Aksesibel: accessible[nomor],
+				})
+			}
+		}
+	}
+
+	if len(seats) == 0 {
+		return nil, mainevent.ErrInvalidSeatLayout
+	}
+
+	return seats, nil
+}
+
+// seatKey returns the position of the given seat.
+func seatKey(seat mainevent.Seat) string {
+	return fmt.Sprintf("%s\x00%s\x00%d", seat.Section, seat.Baris, seat.Nomor)
+}
+
+// validateSeatSelection validates the seats selected for the
+// given mainevent, there is either none or one per ticket.
+func validateSeatSelection(reqMainEvent mainevent.MainEvent) error {
+	if len(reqMainEvent.SeatIDs) == 0 {
+		return nil
+	}
+
+	if len(reqMainEvent.SeatIDs) != reqMainEvent.JumlahTiket {
+		return mainevent.ErrInvalidSeat
+	}
+
+	seen := make(map[int64]struct{}, len(reqMainEvent.SeatIDs))
+	for _, id := range reqMainEvent.SeatIDs {
+		if _, ok := seen[id]; ok || id <= 0 {
+			return mainevent.ErrInvalidSeat
+		}
+		seen[id] = struct{}{}
+	}
+
+	return nil
+}
@@ -2,18 +2,111 @@ package service
 
 import (
 	"time"
+
+	"github.com/tedxub2023/global/helper"
+	"github.com/tedxub2023/global/payment"
+	"github.com/tedxub2023/global/worker"
+	"github.com/tedxub2023/internal/event"
+	"github.com/tedxub2023/internal/mainevent"
+	"github.com/tedxub2023/internal/promo"
+	m "github.com/tedxub2023/internal/ticket/service"
+	"github.com/tedxub2023/internal/transaction"
 )
 
+// Config holds the configuration of the service.
+type Config struct {
+	Mail m.MailConfig
+	PDF  helper.PDFConfig
+
+	// EventSlug is the slug of the event the mainevents are
+	// sold for, its ticket types set the prices and the
+	// quota of the sale phases.
+	EventSlug string
+
+	// AdminEmail is the email address notified when a buyer
+	// uploads a payment proof.
+	AdminEmail string
+
+	// HolderTransferCutoff is the time the ticket holders
+	// can no longer be changed. Zero means no cutoff.
+	HolderTransferCutoff time.Time
+
+	// WaitlistOfferTTL is the duration a waitlist offer holds
+	// the tickets before it goes to the next entry.
+	WaitlistOfferTTL time.Duration
+
+	// WaitlistClaimURL is the page the claim links point to,
+	// the claim token is added as the "token" query parameter.
+	WaitlistClaimURL string
+
+	// GroupMinTiket is the fewest attendees of a group order.
+	GroupMinTiket int
+
+	// GroupPolicy is the group order policy of the
+	// institutions without their own.
+	GroupPolicy mainevent.Institution
+
+	// GroupPriceTiers maps the fewest attendees of each group
+	// price tier to its ticket price. The group orders below
+	// every tier pay the normal ticket price.
+	GroupPriceTiers map[int]int64
+
+	// PurchaseLimitPerIdentity and PurchaseLimitPerEmail are
+	// the most tickets a buyer may buy across all orders by
+	// identity number and by email. Zero means no limit.
+	PurchaseLimitPerIdentity int
+	PurchaseLimitPerEmail    int
+
+	// AccessibleSeatQuota is the number of the normal sale
+	// seats reserved for the buyers with a disability or an
+	// accommodation.
+	AccessibleSeatQuota int
+
+	// BuyerLoginTTL is the duration a login link emailed to a
+	// buyer is valid, and BuyerSessionTTL the duration of the
+	// session it opens.
+	BuyerLoginTTL   time.Duration
+	BuyerSessionTTL time.Duration
+
+	// BuyerLoginURL is the page the login links point to, the
+	// login token is added as the "token" query parameter.
+	BuyerLoginURL string
+}
+
 // New construts a new service.
 type service struct {
-	pgStore PGStore
-	timeNow func() time.Time
+	pgStore     PGStore
+	workers     *worker.Group
+	refunder    payment.Refunder
+	promo       promo.Service
+	event       event.Service
+	transaction transaction.Service
+	config      Config
+	timeNow     func() time.Time
 }
 
-// New returns a new service
-func New(pgStore PGStore) (*service, error) {
-	return &service{
-		pgStore: pgStore,
-		timeNow: time.Now,
-	}, nil
+// New returns a new service. The refunder refunds the
+// payments of the refunds approved with
+// mainevent.RefundMethodGateway, the promo service applies the
+// promo codes of the orders, the event service provides the
+// event of Config.EventSlug and the transaction service the
+// Semayam Asa orders of the buyers.
+func New(pgStore PGStore, workers *worker.Group, refunder payment.Refunder, promo promo.Service, event event.Service, transaction transaction.Service, config Config) (*service, error) {
+	s := &service{
+		pgStore:     pgStore,
+		workers:     workers,
+		refunder:    refunder,
+		promo:       promo,
+		event:       event,
+		transaction: transaction,
+		config:      config,
+		timeNow:     time.Now,
+	}
+
+	// schedule the expiration of unpaid orders
+	if err := s.addCronJobs(); err != nil {
+		return nil, err
+	}
+
+	return s, nil
 }
@@ -0,0 +1,59 @@
+package service
+
+import (
+	"context"
+
+	"github.com/tedxub2023/global/metrics"
+	"github.com/tedxub2023/internal/mainevent"
+)
+
+// prepareEffects runs the part of the given effects that must
+// succeed before the new status is stored, within the
+// transaction of the given store client. The tickets are
+// seated and rendered beforehand, so that a failure leaves the
+// status unchanged and the update can be retried.
+func (s *service) prepareEffects(ctx context.Context, pgStoreClient PGStoreClient, m *mainevent.MainEvent, effects []mainevent.Effect) error {
+	for _, effect := range effects {
+		switch effect {
+		case mainevent.EffectIssueTickets:
+			ev, err := s.eventOf(ctx, *m)
+			if err != nil {
+				return err
+			}
+			m.NomorTiket = generateNumberTicket(ticketPrefix(ev), m.ID, m.JumlahTiket)
+			if err := s.assignSeats(ctx, pgStoreClient, m); err != nil {
+				return err
+			}
+			if err := s.generatePDF(ev, *m); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// runEffects runs the rest of the given effects once the new
+// status is stored.
+func (s *service) runEffects(ctx context.Context, m mainevent.MainEvent, effects []mainevent.Effect) {
+	for _, effect := range effects {
+		switch effect {
+		case mainevent.EffectProofReceivedMail:
+			s.workers.Go(ctx, "mainevent inform admin mail", func(ctx context.Context) error {
+				return s.sendMailInformAdmin(ctx, m)
+			})
+		case mainevent.EffectIssueTickets:
+			metrics.OrderSettlements.WithLabelValues(metrics.EventMainEvent, m.Type.String()).Inc()
+		case mainevent.EffectTicketMail:
+			s.workers.Go(ctx, "mainevent ticket mail", func(ctx context.Context) error {
+				return s.sendSuccessTransactionMail(ctx, m)
+			})
+		case mainevent.EffectGroupTickets:
+			if m.Type != mainevent.TypeGroup {
+				continue
+			}
+			s.workers.Go(ctx, "mainevent group tickets", func(ctx context.Context) error {
+				return s.assignGroupTickets(ctx, m)
+			})
+		}
+	}
+}
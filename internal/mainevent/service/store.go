@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"time"
 
 	"github.com/tedxub2023/internal/mainevent"
 )
@@ -24,18 +25,219 @@ type PGStoreClient interface {
 
 	// GetTotalTicketByType(ctx context.Context, types int64) (int64, error)
 
-	// GetAllMainEvents returns all mainevent.
-	GetAllMainEvents(ctx context.Context, filter mainevent.GetAllMainEventsFilter) ([]mainevent.MainEvent, error)
+	// GetAllMainEvents returns the mainevents matching the
+	// given filter, and the cursor of the next page if the
+	// filter has a limit and there are more mainevents.
+	GetAllMainEvents(ctx context.Context, filter mainevent.GetAllMainEventsFilter) ([]mainevent.MainEvent, string, error)
+
+	// CountMainEvents returns the number of mainevents and
+	// the sum of their tickets matching the given filter,
+	// ignoring the cursor and limit.
+	CountMainEvents(ctx context.Context, filter mainevent.GetAllMainEventsFilter) (int64, int64, error)
 
 	// GetMainEventByID returns a mainevent with the given
 	// mainevent ID.
 	GetMainEventByID(ctx context.Context, MainEventID int64) (mainevent.MainEvent, error)
 
 	// UpdateMainEventByID updates a mainevent with the given
-	// main event ID.
-	UpdateMainEventByID(ctx context.Context, mainevent mainevent.MainEvent) error
+	// main event ID, setting its update time to the given
+	// time. It returns mainevent.ErrConflict if the update
+	// time in the DB is no longer the one of the given
+	// mainevent.
+	UpdateMainEventByID(ctx context.Context, mainevent mainevent.MainEvent, updateTime time.Time) error
+
+	// CreateCheckIn stores the time the given ticket of the
+	// given mainevent is checked in.
+	CreateCheckIn(ctx context.Context, maineventID int64, nomorTiket string, checkInTime time.Time) error
 
-	// DeleteMainEventByEmail deletes all mainevent
-	// with the given email.
+	// DeleteMainEventByEmail deletes all unpaid mainevent
+	// with the given email, except the group invoices.
 	DeleteMainEventByEmail(ctx context.Context, email string) error
+
+	// DeleteMainEventByID deletes the mainevent with the given
+	// ID if it is unpaid. It returns mainevent.ErrDataNotFound
+	// otherwise.
+	DeleteMainEventByID(ctx context.Context, maineventID int64) error
+
+	// GetHoldersByMainEventID returns the holders assigned
+	// to the tickets of the given mainevent.
+	GetHoldersByMainEventID(ctx context.Context, maineventID int64) ([]mainevent.Holder, error)
+
+	// UpsertHolder assigns the ticket of the given holder to
+	// the holder, replacing the previous holder if any.
+	UpsertHolder(ctx context.Context, holder mainevent.Holder) error
+
+	// DeleteHolders unassigns the given tickets of the given
+	// mainevent from their holders.
+	DeleteHolders(ctx context.Context, maineventID int64, nomorTiket []string) error
+
+	// CreateRefund creates a new refund and returns the
+	// created refund ID.
+	CreateRefund(ctx context.Context, refund mainevent.Refund) (int64, error)
+
+	// GetRefundByID returns a refund with the given refund
+	// ID.
+	GetRefundByID(ctx context.Context, refundID int64) (mainevent.Refund, error)
+
+	// GetAllRefunds returns the refunds of the given
+	// mainevent with the given status, oldest first. Zero
+	// values mean no filter.
+	GetAllRefunds(ctx context.Context, maineventID int64, status mainevent.RefundStatus) ([]mainevent.Refund, error)
+
+	// UpdateRefundStatus updates the status, method and note
+	// of a requested refund. It returns
+	// mainevent.ErrRefundAlreadyProcessed if the refund is no
+	// longer requested.
+	UpdateRefundStatus(ctx context.Context, refund mainevent.Refund, updateTime time.Time) error
+
+	// CreateWaitlistEntry creates a new waitlist entry and
+	// returns the created entry ID.
+	CreateWaitlistEntry(ctx context.Context, entry mainevent.WaitlistEntry) (int64, error)
+
+	// GetWaitlistEntryByToken returns the waitlist entry
+	// offered with the given claim token, locking it until the
+	// end of the transaction.
+	GetWaitlistEntryByToken(ctx context.Context, token string) (mainevent.WaitlistEntry, error)
+
+	// GetAllWaitlistEntries returns the waitlist entries with
+	// the given status, all entries if it is unknown, in the
+	// order they joined.
+	GetAllWaitlistEntries(ctx context.Context, status mainevent.WaitlistStatus) ([]mainevent.WaitlistEntry, error)
+
+	// UpdateWaitlistEntry updates the status, token and
+	// offer expire time of a waitlist entry. It returns
+	// mainevent.ErrConflict if the entry is no longer in the
+	// expected status.
+	UpdateWaitlistEntry(ctx context.Context, entry mainevent.WaitlistEntry, expectedStatus mainevent.WaitlistStatus, updateTime time.Time) error
+
+	// ExpireWaitlistOffers expires the offers not claimed by
+	// the given time and returns the expired entries.
+	ExpireWaitlistOffers(ctx context.Context, now time.Time) ([]mainevent.WaitlistEntry, error)
+
+	// CreateInstitution creates a new institution and returns
+	// the created institution ID. It returns
+	// mainevent.ErrInstitutionExists if the name is taken.
+	CreateInstitution(ctx context.Context, institution mainevent.Institution) (int64, error)
+
+	// GetAllInstitutions returns all institutions sorted by
+	// name.
+	GetAllInstitutions(ctx context.Context) ([]mainevent.Institution, error)
+
+	// GetInstitutionByNama returns the institution with the
+	// given name, case-insensitively, locking it until the
+	// end of the transaction.
+	GetInstitutionByNama(ctx context.Context, nama string) (mainevent.Institution, error)
+
+	// LockInstitution locks the group orders of the
+	// institution with the given name, case-insensitively,
+	// until the end of the transaction, whether or not the
+	// institution is stored.
+	LockInstitution(ctx context.Context, nama string) error
+
+	// UpdateInstitution updates the name and the group order
+	// policy of an institution. It returns
+	// mainevent.ErrInstitutionExists if the name is taken.
+	UpdateInstitution(ctx context.Context, institution mainevent.Institution) error
+
+	// CreateGroupOrder creates a new group order together
+	// with its attendees and returns the created group order
+	// ID.
+	CreateGroupOrder(ctx context.Context, group mainevent.GroupOrder) (int64, error)
+
+	// GetGroupOrderByID returns a group order with the given
+	// group order ID, locking it until the end of the
+	// transaction.
+	GetGroupOrderByID(ctx context.Context, groupID int64) (mainevent.GroupOrder, error)
+
+	// GetGroupOrderByMainEventID returns the group order
+	// invoiced with the given mainevent ID.
+	GetGroupOrderByMainEventID(ctx context.Context, maineventID int64) (mainevent.GroupOrder, error)
+
+	// GetAllGroupOrders returns the group orders with the
+	// given status, all group orders if it is unknown, oldest
+	// first.
+	GetAllGroupOrders(ctx context.Context, status mainevent.GroupStatus) ([]mainevent.GroupOrder, error)
+
+	// UpdateGroupOrder updates the invoice, status and note
+	// of a group order in the given status. It returns
+	// mainevent.ErrGroupOrderAlreadyProcessed if the group
+	// order is no longer in that status.
+	UpdateGroupOrder(ctx context.Context, group mainevent.GroupOrder, from mainevent.GroupStatus, updateTime time.Time) error
+
+	// CountGroupTickets returns the number of tickets of the
+	// group orders of the given institution that are not
+	// rejected or expired.
+	CountGroupTickets(ctx context.Context, asalInstitusi string) (int, error)
+
+	// CountBuyerTickets locks the buyer with the given email
+	// and identity number until the end of the transaction,
+	// and returns the number of tickets of the buyer for the
+	// event with the given ID by email and by identity number.
+	// The tickets of the approved group orders count toward
+	// their attendees, one each, rather than the coordinator.
+	// The refunded tickets and the unpaid orders with the
+	// given email are not counted.
+	CountBuyerTickets(ctx context.Context, eventID int64, email, nomorIdentitas string) (int, int, error)
+
+	// GetAllSeats returns all seats in the order of the seat
+	// layout.
+	GetAllSeats(ctx context.Context) ([]mainevent.Seat, error)
+
+	// GetSeatsForUpdate returns all seats in the order of the
+	// seat layout, and locks them until the end of the
+	// transaction.
+	GetSeatsForUpdate(ctx context.Context) ([]mainevent.Seat, error)
+
+	// GetSeatsByMainEventID returns the seats held or seated
+	// by the given mainevent.
+	GetSeatsByMainEventID(ctx context.Context, maineventID int64) ([]mainevent.Seat, error)
+
+	// CreateSeat creates a new free seat. It returns
+	// mainevent.ErrInvalidSeatLayout if the seat exists.
+	CreateSeat(ctx context.Context, seat mainevent.Seat) error
+
+	// UpdateSeatAksesibel updates whether the given seat is
+	// accessible.
+	UpdateSeatAksesibel(ctx context.Context, seat mainevent.Seat, updateTime time.Time) error
+
+	// DeleteSeats deletes the seats with the given IDs. It
+	// returns mainevent.ErrSeatInUse if any of them is held or
+	// seated.
+	DeleteSeats(ctx context.Context, ids []int64) error
+
+	// HoldSeats holds the free seats with the given IDs for
+	// the given mainevent, the accessible seats only if
+	// aksesibel is true. It returns
+	// mainevent.ErrSeatNotAvailable if any of them cannot be
+	// held.
+	HoldSeats(ctx context.Context, maineventID int64, ids []int64, aksesibel bool, updateTime time.Time) error
+
+	// AssignSeat seats the ticket of the given seat on it, if
+	// the seat is free or held by the mainevent of the ticket.
+	// It returns mainevent.ErrSeatNotAvailable otherwise, or
+	// if the ticket is seated elsewhere.
+	AssignSeat(ctx context.Context, seat mainevent.Seat, updateTime time.Time) error
+
+	// ReleaseSeats frees the seats of the given tickets.
+	ReleaseSeats(ctx context.Context, nomorTiket []string, updateTime time.Time) error
+
+	// CreateBuyerLogin creates a new buyer login link and
+	// returns the created login ID.
+	CreateBuyerLogin(ctx context.Context, login mainevent.BuyerLogin) (int64, error)
+
+	// GetBuyerLoginByToken returns the buyer login with the
+	// given link token hash. It returns
+	// mainevent.ErrInvalidBuyerLoginToken if there is none.
+	GetBuyerLoginByToken(ctx context.Context, tokenHash string) (mainevent.BuyerLogin, error)
+
+	// GetBuyerLoginBySession returns the buyer login with the
+	// given session token hash. It returns
+	// mainevent.ErrInvalidBuyerSession if there is none.
+	GetBuyerLoginBySession(ctx context.Context, sessionTokenHash string) (mainevent.BuyerLogin, error)
+
+	// UseBuyerLogin marks the given buyer login as used and
+	// stores the session it opens. It returns
+	// mainevent.ErrInvalidBuyerLoginToken if the login has
+	// been used already.
+	UseBuyerLogin(ctx context.Context, login mainevent.BuyerLogin) error
 }
@@ -0,0 +1,287 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/mail"
+	"net/url"
+	"strings"
+
+	"github.com/tedxub2023/global/logger"
+	"github.com/tedxub2023/global/metrics"
+	"github.com/tedxub2023/internal/event"
+	"github.com/tedxub2023/internal/mainevent"
+)
+
+// normalSaleQuota is the number of normal sale tickets, if
+// the event sets no quota.
+const normalSaleQuota = 100
+
+func (s *service) JoinWaitlist(ctx context.Context, reqEntry mainevent.WaitlistEntry) (_ int64, err error) {
+	// validate field
+	reqEntry.Nama = strings.TrimSpace(reqEntry.Nama)
+	reqEntry.Email = strings.TrimSpace(reqEntry.Email)
+	if reqEntry.Nama == "" {
+		return 0, mainevent.ErrInvalidMainEventNama
+	}
+	if _, err := mail.ParseAddress(reqEntry.Email); reqEntry.Email == "" || err != nil {
+		return 0, mainevent.ErrInvalidMainEventEmail
+	}
+
+	ev, err := s.currentEvent(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if reqEntry.JumlahTiket <= 0 || reqEntry.JumlahTiket > normalSaleQuotaOf(ev) {
+		return 0, mainevent.ErrInvalidMainEventJumlahTiket
+	}
+
+	pgStoreClient, err := s.pgStore.NewClient(true)
+	if err != nil {
+		return 0, err
+	}
+
+	// rollback just before return if error
+	defer func() {
+		if err != nil {
+			errTx := pgStoreClient.Rollback()
+			if errTx != nil {
+				// return err from rollback
+				err = errTx
+			}
+		}
+	}()
+
+	waiting, err := pgStoreClient.GetAllWaitlistEntries(ctx, mainevent.WaitlistStatusWaiting)
+	if err != nil {
+		return 0, err
+	}
+	offered, err := pgStoreClient.GetAllWaitlistEntries(ctx, mainevent.WaitlistStatusOffered)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, e := range append(waiting, offered...) {
+		if strings.EqualFold(e.Email, reqEntry.Email) {
+			return 0, mainevent.ErrWaitlistAlreadyJoined
+		}
+	}
+
+	// nobody is ahead in the queue, the buyer may order
+	// right away
+	if len(waiting) == 0 {
+		available, err := s.availableNormalSaleTickets(ctx, pgStoreClient, ev, 0, false)
+		if err != nil {
+			return 0, err
+		}
+		if reqEntry.JumlahTiket <= available {
+			return 0, mainevent.ErrTicketsAvailable
+		}
+	}
+
+	reqEntry.Status = mainevent.WaitlistStatusWaiting
+	reqEntry.CreateTime = s.timeNow()
+
+	entryID, err := pgStoreClient.CreateWaitlistEntry(ctx, reqEntry)
+	if err != nil {
+		return 0, err
+	}
+	logger.AddFields(ctx, logger.Fields{"waitlist_id": entryID})
+
+	// commit changes
+	err = pgStoreClient.Commit()
+	if err != nil {
+		return 0, err
+	}
+
+	metrics.WaitlistEntries.WithLabelValues(metrics.EventMainEvent, mainevent.WaitlistStatusWaiting.String()).Inc()
+
+	return entryID, nil
+}
+
+// offerWaitlist expires the offers that were not claimed in
+// time, then offers the free normal sale tickets to the
+// waiting entries, first in first out. The head of the queue
+// is never overtaken by a later entry asking for fewer
+// tickets, it waits until enough tickets are free.
+func (s *service) offerWaitlist(ctx context.Context) (err error) {
+	pgStoreClient, err := s.pgStore.NewClient(true)
+	if err != nil {
+		return err
+	}
+
+	// rollback just before return if error
+	defer func() {
+		if err != nil {
+			errTx := pgStoreClient.Rollback()
+			if errTx != nil {
+				// return err from rollback
+				err = errTx
+			}
+		}
+	}()
+
+	now := s.timeNow()
+
+	expired, err := pgStoreClient.ExpireWaitlistOffers(ctx, now)
+	if err != nil {
+		return err
+	}
+
+	waiting, err := pgStoreClient.GetAllWaitlistEntries(ctx, mainevent.WaitlistStatusWaiting)
+	if err != nil {
+		return err
+	}
+
+	offered := make([]mainevent.WaitlistEntry, 0)
+	if len(waiting) > 0 {
+		ev, err := s.currentEvent(ctx)
+		if err != nil {
+			return err
+		}
+
+		available, err := s.availableNormalSaleTickets(ctx, pgStoreClient, ev, 0, false)
+		if err != nil {
+			return err
+		}
+
+		for _, entry := range waiting {
+			if entry.JumlahTiket > available {
+				break
+			}
+
+			entry.Token, err = generateToken()
+			if err != nil {
+				return err
+			}
+			entry.Status = mainevent.WaitlistStatusOffered
+			entry.OfferExpireTime = now.Add(s.config.WaitlistOfferTTL)
+
+			err = pgStoreClient.UpdateWaitlistEntry(ctx, entry, mainevent.WaitlistStatusWaiting, now)
+			if err != nil {
+				return err
+			}
+
+			available -= entry.JumlahTiket
+			offered = append(offered, entry)
+		}
+	}
+
+	// commit changes
+	err = pgStoreClient.Commit()
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range expired {
+		entry := entry
+		logger.Info(ctx, "waitlist offer expired", logger.Fields{"waitlist_id": entry.ID})
+		metrics.WaitlistEntries.WithLabelValues(metrics.EventMainEvent, mainevent.WaitlistStatusExpired.String()).Inc()
+
+		s.workers.Go(ctx, "mainevent waitlist expired mail", func(ctx context.Context) error {
+			return s.sendWaitlistExpiredMail(ctx, entry)
+		})
+	}
+
+	for _, entry := range offered {
+		entry := entry
+		logger.Info(ctx, "waitlist offered", logger.Fields{"waitlist_id": entry.ID, "jumlah_tiket": entry.JumlahTiket})
+		metrics.WaitlistEntries.WithLabelValues(metrics.EventMainEvent, mainevent.WaitlistStatusOffered.String()).Inc()
+
+		s.workers.Go(ctx, "mainevent waitlist offer mail", func(ctx context.Context) error {
+			return s.sendWaitlistOfferMail(ctx, entry, s.waitlistClaimLink(entry))
+		})
+	}
+
+	return nil
+}
+
+// availableNormalSaleTickets returns the number of normal sale
+// tickets of the given event neither ordered nor held for a
+// waitlist offer. The
+// tickets held for the entry with the given ID are counted as
+// available, so that the entry can claim them. The accessible
+// seats not yet taken are only available to the buyers
+// needing accessible seating.
+func (s *service) availableNormalSaleTickets(ctx context.Context, pgStoreClient PGStoreClient, ev event.Event, waitlistID int64, accessible bool) (int, error) {
+	tickets, _, err := pgStoreClient.GetAllMainEvents(ctx, mainevent.GetAllMainEventsFilter{EventID: ev.ID})
+	if err != nil {
+		return 0, err
+	}
+
+	offered, err := pgStoreClient.GetAllWaitlistEntries(ctx, mainevent.WaitlistStatusOffered)
+	if err != nil {
+		return 0, err
+	}
+
+	held := 0
+	now := s.timeNow()
+	for _, entry := range offered {
+		if entry.ID != waitlistID && now.Before(entry.OfferExpireTime) {
+			held += entry.JumlahTiket
+		}
+	}
+
+	available := normalSaleQuotaOf(ev) - checkNormalSaleTicket(tickets) - held
+	if reserved := s.config.AccessibleSeatQuota - checkAccessibleSeats(tickets); !accessible && reserved > 0 {
+		available -= reserved
+	}
+
+	return available, nil
+}
+
+// checkWaitlistOffer returns the waitlist entry offered with
+// the given token if the given mainevent may claim it.
+func checkWaitlistOffer(ctx context.Context, pgStoreClient PGStoreClient, token string, reqMainEvent mainevent.MainEvent) (mainevent.WaitlistEntry, error) {
+	entry, err := pgStoreClient.GetWaitlistEntryByToken(ctx, token)
+	if err == mainevent.ErrDataNotFound {
+		return mainevent.WaitlistEntry{}, mainevent.ErrInvalidWaitlistToken
+	}
+	if err != nil {
+		return mainevent.WaitlistEntry{}, err
+	}
+	logger.AddFields(ctx, logger.Fields{"waitlist_id": entry.ID})
+
+	// the offer is personal, it is claimed with the email and
+	// up to the number of tickets the entry waited for
+	if !strings.EqualFold(entry.Email, reqMainEvent.Email) || reqMainEvent.JumlahTiket > entry.JumlahTiket {
+		return mainevent.WaitlistEntry{}, mainevent.ErrInvalidWaitlistToken
+	}
+
+	switch {
+	case entry.Status == mainevent.WaitlistStatusExpired:
+		return mainevent.WaitlistEntry{}, mainevent.ErrWaitlistOfferExpired
+	case entry.Status != mainevent.WaitlistStatusOffered:
+		return mainevent.WaitlistEntry{}, mainevent.ErrInvalidWaitlistToken
+	case !reqMainEvent.CreateTime.Before(entry.OfferExpireTime):
+		return mainevent.WaitlistEntry{}, mainevent.ErrWaitlistOfferExpired
+	}
+
+	return entry, nil
+}
+
+// waitlistClaimLink returns the claim link of the offer of
+// the given entry.
+func (s *service) waitlistClaimLink(entry mainevent.WaitlistEntry) string {
+	u, err := url.Parse(s.config.WaitlistClaimURL)
+	if err != nil {
+		return s.config.WaitlistClaimURL
+	}
+
+	q := u.Query()
+	q.Set("token", entry.Token)
+	u.RawQuery = q.Encode()
+
+	return u.String()
+}
+
+// generateToken returns a new random secret token, such as
+// the claim token of a waitlist offer.
+func generateToken() (string, error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,60 @@
+package mainevent
+
+// Effect denotes a side effect of a status transition.
+type Effect int
+
+// Followings are the known effects.
+const (
+	// EffectProofReceivedMail informs the admin that the
+	// buyer has uploaded a payment proof.
+	EffectProofReceivedMail Effect = 1
+
+	// EffectIssueTickets generates the ticket numbers, seats
+	// them and generates the ticket PDF.
+	EffectIssueTickets Effect = 2
+
+	// EffectTicketMail sends the ticket PDF to the buyer.
+	EffectTicketMail Effect = 3
+
+	// EffectGroupTickets assigns the tickets of a group order
+	// to its attendees and sends each of them their ticket
+	// PDF. It has no effect on the other orders.
+	EffectGroupTickets Effect = 4
+)
+
+// transitions maps status to the status it may change into
+// and the effects of the change. The buyer uploads a payment
+// proof (pending), which is then either confirmed by the
+// committee (settlement) or rejected (unpaid), in which case
+// the buyer may upload another one.
+//
+// An effect runs only when the status changes, so that a
+// repeated update never issues the tickets or sends the mails
+// twice.
+var transitions = map[Status]map[Status][]Effect{
+	StatusUnpaid: {
+		StatusPending: {EffectProofReceivedMail},
+	},
+	StatusPending: {
+		StatusSettlement: {EffectIssueTickets, EffectTicketMail, EffectGroupTickets},
+		StatusUnpaid:     nil,
+	},
+}
+
+// Transition returns the effects of changing the status from
+// the given status into the given next status, in the order
+// they must run. An update keeping the status has no effect.
+// It returns ErrIllegalStatusTransition if the change is not
+// allowed.
+func Transition(from, next Status) ([]Effect, error) {
+	if from == next {
+		return nil, nil
+	}
+
+	effects, ok := transitions[from][next]
+	if !ok {
+		return nil, ErrIllegalStatusTransition
+	}
+
+	return effects, nil
+}
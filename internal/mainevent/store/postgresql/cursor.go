@@ -0,0 +1,93 @@
+package postgresql
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/tedxub2023/internal/mainevent"
+)
+
+// sortColumns maps the sort fields to their columns.
+var sortColumns = map[mainevent.SortBy]string{
+	mainevent.SortByCreateTime:  "m.create_time",
+	mainevent.SortByNama:        "m.nama",
+	mainevent.SortByJumlahTiket: "m.jumlah_tiket",
+	mainevent.SortByTotalHarga:  "m.total_harga",
+}
+
+// cursor is the position of the last row of a page. It is
+// bound to the sort it was issued for.
+type cursor struct {
+	Sort  string      `json:"s"`
+	Value interface{} `json:"v"`
+	ID    int64       `json:"id"`
+}
+
+// cursorSort returns the sort the cursor is bound to.
+func cursorSort(sortBy mainevent.SortBy, sortOrder mainevent.SortOrder) string {
+	if sortBy == "" {
+		sortBy = mainevent.SortByCreateTime
+	}
+	if sortOrder == "" {
+		sortOrder = mainevent.SortOrderDesc
+	}
+	return string(sortBy) + ":" + string(sortOrder)
+}
+
+// encodeCursor returns the opaque cursor pointing after the
+// given mainevent.
+func encodeCursor(m mainevent.MainEvent, sortBy mainevent.SortBy, sortOrder mainevent.SortOrder) string {
+	c := cursor{
+		Sort: cursorSort(sortBy, sortOrder),
+		ID:   m.ID,
+	}
+
+	switch sortBy {
+	case mainevent.SortByNama:
+		c.Value = m.Nama
+	case mainevent.SortByJumlahTiket:
+		c.Value = strconv.Itoa(m.JumlahTiket)
+	case mainevent.SortByTotalHarga:
+		c.Value = strconv.FormatInt(m.TotalHarga, 10)
+	default:
+		c.Value = m.CreateTime.Format(time.RFC3339Nano)
+	}
+
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// decodeCursor decodes the given cursor and converts its value
+// to the type of the sort column.
+func decodeCursor(s string, sortBy mainevent.SortBy, sortOrder mainevent.SortOrder) (cursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return cursor{}, mainevent.ErrInvalidCursor
+	}
+
+	var c cursor
+	if err := json.Unmarshal(b, &c); err != nil {
+		return cursor{}, mainevent.ErrInvalidCursor
+	}
+
+	value, ok := c.Value.(string)
+	if !ok || c.Sort != cursorSort(sortBy, sortOrder) {
+		return cursor{}, mainevent.ErrInvalidCursor
+	}
+
+	switch sortBy {
+	case mainevent.SortByNama:
+		c.Value = value
+	case mainevent.SortByJumlahTiket, mainevent.SortByTotalHarga:
+		c.Value, err = strconv.ParseInt(value, 10, 64)
+	default:
+		c.Value, err = time.Parse(time.RFC3339Nano, value)
+	}
+	if err != nil {
+		return cursor{}, mainevent.ErrInvalidCursor
+	}
+
+	return c, nil
+}
@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/jmoiron/sqlx"
 	"github.com/tedxub2023/internal/mainevent"
@@ -12,9 +13,12 @@ import (
 
 func (sc *storeClient) DeleteMainEventByEmail(ctx context.Context, email string) error {
 	// construct arguments filled with fields for the query
+	// the group invoices are not replaced by the orders of
+	// their coordinators
 	argsKV := map[string]interface{}{
-		"email":  email,
-		"status": mainevent.StatusUnpaid,
+		"email":      email,
+		"status":     mainevent.StatusUnpaid,
+		"group_type": mainevent.TypeGroup,
 	}
 
 	// prepare query
@@ -29,26 +33,66 @@ func (sc *storeClient) DeleteMainEventByEmail(ctx context.Context, email string)
 	query = sc.q.Rebind(query)
 
 	// execute query
-	_, err = sc.q.Exec(query, args...)
+	_, err = sc.q.ExecContext(ctx, query, args...)
 	return err
 }
 
+func (sc *storeClient) DeleteMainEventByID(ctx context.Context, maineventID int64) error {
+	// construct arguments filled with fields for the query
+	argsKV := map[string]interface{}{
+		"id":     maineventID,
+		"status": mainevent.StatusUnpaid,
+	}
+
+	// prepare query
+	query, args, err := sqlx.Named(queryDeleteMainEventByID, argsKV)
+	if err != nil {
+		return err
+	}
+	query = sc.q.Rebind(query)
+
+	// execute query
+	res, err := sc.q.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return mainevent.ErrDataNotFound
+	}
+
+	return nil
+}
+
 func (sc *storeClient) CreateMainEvent(ctx context.Context, reqMainEvent mainevent.MainEvent) (int64, error) {
 	// construct arguments filled with fields for the query
 	argsKV := map[string]interface{}{
-		"nama":            reqMainEvent.Nama,
-		"disabilitas":     reqMainEvent.Disabilitas,
-		"nomor_identitas": reqMainEvent.NomorIdentitas,
-		"asal_institusi":  reqMainEvent.AsalInstitusi,
-		"email":           reqMainEvent.Email,
-		"nomor_telepon":   reqMainEvent.NomorTelepon,
-		"jumlah_tiket":    reqMainEvent.JumlahTiket,
-		"total_harga":     reqMainEvent.TotalHarga,
-		"order_id":        reqMainEvent.OrderID,
-		"status":          reqMainEvent.Status,
-		"type":            reqMainEvent.Type,
-		"image_uri":       reqMainEvent.ImageURI,
-		"create_time":     reqMainEvent.CreateTime,
+		"event_id":          reqMainEvent.EventID,
+		"nama":              reqMainEvent.Nama,
+		"disabilitas":       reqMainEvent.Disabilitas,
+		"nomor_identitas":   reqMainEvent.NomorIdentitas,
+		"asal_institusi":    reqMainEvent.AsalInstitusi,
+		"email":             reqMainEvent.Email,
+		"nomor_telepon":     reqMainEvent.NomorTelepon,
+		"jumlah_tiket":      reqMainEvent.JumlahTiket,
+		"total_harga":       reqMainEvent.TotalHarga,
+		"kode_promo":        nil,
+		"diskon":            reqMainEvent.Diskon,
+		"akomodasi":         accommodationsArray(reqMainEvent.Akomodasi),
+		"catatan_akomodasi": nullString(reqMainEvent.CatatanAkomodasi),
+		"order_id":          reqMainEvent.OrderID,
+		"status":            reqMainEvent.Status,
+		"type":              reqMainEvent.Type,
+		"image_uri":         reqMainEvent.ImageURI,
+		"create_time":       reqMainEvent.CreateTime,
+	}
+
+	if reqMainEvent.KodePromo != "" {
+		argsKV["kode_promo"] = reqMainEvent.KodePromo
 	}
 
 	// prepare query
@@ -64,7 +108,7 @@ func (sc *storeClient) CreateMainEvent(ctx context.Context, reqMainEvent maineve
 
 	// execute query
 	var ticketID int64
-	err = sc.q.QueryRowx(query, args...).Scan(&ticketID)
+	err = sc.q.QueryRowxContext(ctx, query, args...).Scan(&ticketID)
 	if err != nil {
 		return 0, err
 	}
@@ -72,23 +116,31 @@ func (sc *storeClient) CreateMainEvent(ctx context.Context, reqMainEvent maineve
 	return ticketID, nil
 }
 
-func (sc *storeClient) GetAllMainEvents(ctx context.Context, filter mainevent.GetAllMainEventsFilter) ([]mainevent.MainEvent, error) {
+func (sc *storeClient) GetAllMainEvents(ctx context.Context, filter mainevent.GetAllMainEventsFilter) ([]mainevent.MainEvent, string, error) {
 	// define variables to custom query
-	argsKV := make(map[string]interface{})
-	addConditions := make([]string, 0)
+	argsKV, addConditions := filterConditions(filter)
 
-	if filter.Status != 0 {
-		addConditions = append(addConditions, "m.status = :status")
-		argsKV["status"] = filter.Status
+	sortColumn, ok := sortColumns[filter.SortBy]
+	if !ok {
+		sortColumn = sortColumns[mainevent.SortByCreateTime]
 	}
-	if filter.Type != 0 {
-		addConditions = append(addConditions, "m.type = :type")
-		argsKV["type"] = filter.Type
+	sortOrder, comparator := "DESC", "<"
+	if filter.SortOrder == mainevent.SortOrderAsc {
+		sortOrder, comparator = "ASC", ">"
 	}
-	if filter.CheckInStatus {
-		addConditions = append(addConditions, "m.checkin_status = :checkin_status")
-		argsKV["checkin_status"] = filter.CheckInStatus
+
+	// continue after the last row of the previous page, the id
+	// breaks the ties of the sort column
+	if filter.Cursor != "" {
+		c, err := decodeCursor(filter.Cursor, filter.SortBy, filter.SortOrder)
+		if err != nil {
+			return nil, "", err
+		}
+		addConditions = append(addConditions, fmt.Sprintf("(%s, m.id) %s (:cursor_value, :cursor_id)", sortColumn, comparator))
+		argsKV["cursor_value"] = c.Value
+		argsKV["cursor_id"] = c.ID
 	}
+
 	// construct strings to custom query
 	addCondition := strings.Join(addConditions, " AND ")
 
@@ -97,43 +149,145 @@ func (sc *storeClient) GetAllMainEvents(ctx context.Context, filter mainevent.Ge
 	if len(addConditions) > 0 {
 		addCondition = fmt.Sprintf("WHERE %s", addCondition)
 	}
+	addCondition += fmt.Sprintf(" ORDER BY %s %s, m.id %s", sortColumn, sortOrder, sortOrder)
+
+	// fetch one more row to know whether there is a next page
+	if filter.Limit > 0 {
+		addCondition += " LIMIT :limit"
+		argsKV["limit"] = filter.Limit + 1
+	}
 	query := fmt.Sprintf(queryGetMainEvent, addCondition)
 
 	// prepare query
 	query, args, err := sqlx.Named(query, argsKV)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 	query, args, err = sqlx.In(query, args...)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 	query = sc.q.Rebind(query)
 
 	// query to database
-	rows, err := sc.q.Queryx(query, args...)
+	rows, err := sc.q.QueryxContext(ctx, query, args...)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 	defer rows.Close()
 
-	// read match
-	match := make([]mainevent.MainEvent, 0)
+	// read mainevents
+	result := make([]mainevent.MainEvent, 0)
 	for rows.Next() {
 		var row maineventDB
 		err = rows.StructScan(&row)
 		if err != nil {
-			return nil, err
+			return nil, "", err
 		}
 
-		match = append(match, row.format())
+		result = append(result, row.format())
 	}
 
 	if err := rows.Err(); err != nil {
-		return nil, err
+		return nil, "", err
+	}
+
+	var nextCursor string
+	if filter.Limit > 0 && len(result) > filter.Limit {
+		result = result[:filter.Limit]
+		nextCursor = encodeCursor(result[len(result)-1], filter.SortBy, filter.SortOrder)
+	}
+
+	return result, nextCursor, nil
+}
+
+func (sc *storeClient) CountMainEvents(ctx context.Context, filter mainevent.GetAllMainEventsFilter) (int64, int64, error) {
+	// define variables to custom query
+	argsKV, addConditions := filterConditions(filter)
+
+	// construct strings to custom query
+	addCondition := strings.Join(addConditions, " AND ")
+	if len(addConditions) > 0 {
+		addCondition = fmt.Sprintf("WHERE %s", addCondition)
+	}
+	query := fmt.Sprintf(queryCountMainEvent, addCondition)
+
+	// prepare query
+	query, args, err := sqlx.Named(query, argsKV)
+	if err != nil {
+		return 0, 0, err
+	}
+	query, args, err = sqlx.In(query, args...)
+	if err != nil {
+		return 0, 0, err
+	}
+	query = sc.q.Rebind(query)
+
+	// query single row
+	var total, totalTickets int64
+	err = sc.q.QueryRowxContext(ctx, query, args...).Scan(&total, &totalTickets)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return total, totalTickets, nil
+}
+
+// filterConditions returns the query conditions and their
+// arguments of the given filter, the cursor excluded.
+func filterConditions(filter mainevent.GetAllMainEventsFilter) (map[string]interface{}, []string) {
+	argsKV := make(map[string]interface{})
+	addConditions := make([]string, 0)
+
+	if filter.EventID != 0 {
+		addConditions = append(addConditions, "m.event_id = :event_id")
+		argsKV["event_id"] = filter.EventID
+	}
+	if filter.Status != 0 {
+		addConditions = append(addConditions, "m.status = :status")
+		argsKV["status"] = filter.Status
+	}
+	if filter.Type != 0 {
+		addConditions = append(addConditions, "m.type = :type")
+		argsKV["type"] = filter.Type
+	}
+	if filter.Disabilitas != 0 {
+		addConditions = append(addConditions, "m.disabilitas = :disabilitas")
+		argsKV["disabilitas"] = filter.Disabilitas
+	}
+	if filter.CheckInStatus != nil {
+		addConditions = append(addConditions, "COALESCE(m.checkin_status, FALSE) = :checkin_status")
+		argsKV["checkin_status"] = *filter.CheckInStatus
+	}
+	if filter.Email != "" {
+		addConditions = append(addConditions, "lower(m.email) = lower(:email)")
+		argsKV["email"] = strings.TrimSpace(filter.Email)
+	}
+	if filter.Search != "" {
+		addConditions = append(addConditions, `(
+			m.nama ILIKE :search OR
+			m.email ILIKE :search OR
+			m.order_id ILIKE :search OR
+			array_to_string(m.nomor_tiket, ' ') ILIKE :search
+		)`)
+		argsKV["search"] = "%" + escapeLike(filter.Search) + "%"
+	}
+	if !filter.CreateTimeFrom.IsZero() {
+		addConditions = append(addConditions, "m.create_time >= :create_time_from")
+		argsKV["create_time_from"] = filter.CreateTimeFrom
+	}
+	if !filter.CreateTimeTo.IsZero() {
+		addConditions = append(addConditions, "m.create_time < :create_time_to")
+		argsKV["create_time_to"] = filter.CreateTimeTo
 	}
 
-	return match, nil
+	return argsKV, addConditions
+}
+
+// escapeLike escapes the LIKE pattern characters, so that
+// the search text is matched literally.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
 }
 
 func (sc *storeClient) GetMainEventByID(ctx context.Context, maineventID int64) (mainevent.MainEvent, error) {
@@ -141,7 +295,7 @@ func (sc *storeClient) GetMainEventByID(ctx context.Context, maineventID int64)
 
 	// query single row
 	var mdb maineventDB
-	err := sc.q.QueryRowx(query, maineventID).StructScan(&mdb)
+	err := sc.q.QueryRowxContext(ctx, query, maineventID).StructScan(&mdb)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return mainevent.MainEvent{}, mainevent.ErrDataNotFound
@@ -152,30 +306,50 @@ func (sc *storeClient) GetMainEventByID(ctx context.Context, maineventID int64)
 	return mdb.format(), nil
 }
 
-func (sc *storeClient) UpdateMainEventByID(ctx context.Context, tx mainevent.MainEvent) error {
+func (sc *storeClient) UpdateMainEventByID(ctx context.Context, tx mainevent.MainEvent, updateTime time.Time) error {
+	// the row is only updated if it has not changed since it
+	// was read, the update time of a never updated row is null
+	var expectedUpdateTime *time.Time
+	if !tx.UpdateTime.IsZero() {
+		expectedUpdateTime = &tx.UpdateTime
+	}
+
 	argsKV := map[string]interface{}{
-		"nama":            tx.Nama,
-		"disabilitas":     tx.Disabilitas,
-		"nomor_identitas": tx.NomorIdentitas,
-		"asal_institusi":  tx.AsalInstitusi,
-		"email":           tx.Email,
-		"nomor_telepon":   tx.NomorTelepon,
-		"jumlah_tiket":    tx.JumlahTiket,
-		"total_harga":     tx.TotalHarga,
-		"order_id":        tx.OrderID,
-		"status":          tx.Status,
-		"image_uri":       tx.ImageURI,
-		"nomor_tiket":     tx.NomorTiket,
-		"checkin_status":  tx.CheckInStatus,
-		"update_time":     tx.UpdateTime,
-		"id":              tx.ID,
+		"nama":                 tx.Nama,
+		"disabilitas":          tx.Disabilitas,
+		"nomor_identitas":      tx.NomorIdentitas,
+		"asal_institusi":       tx.AsalInstitusi,
+		"email":                tx.Email,
+		"nomor_telepon":        tx.NomorTelepon,
+		"jumlah_tiket":         tx.JumlahTiket,
+		"total_harga":          tx.TotalHarga,
+		"akomodasi":            accommodationsArray(tx.Akomodasi),
+		"catatan_akomodasi":    nullString(tx.CatatanAkomodasi),
+		"order_id":             tx.OrderID,
+		"status":               tx.Status,
+		"image_uri":            tx.ImageURI,
+		"checkin_status":       tx.CheckInStatus,
+		"update_time":          updateTime,
+		"id":                   tx.ID,
+		"expected_update_time": expectedUpdateTime,
 	}
-	query := fmt.Sprintf(queryUpdateMainEvent, "")
 
+	// the arrays are only set when not empty, since sqlx.In
+	// rejects empty slices
+	addSets := make([]string, 0)
+	if len(tx.NomorTiket) != 0 {
+		argsKV["nomor_tiket"] = tx.NomorTiket
+		addSets = append(addSets, ", nomor_tiket = ARRAY[:nomor_tiket]")
+	}
 	if len(tx.CheckInNomorTiket) != 0 {
 		argsKV["checkin_nomor_tiket"] = tx.CheckInNomorTiket
-		query = fmt.Sprintf(queryUpdateMainEvent, ", checkin_nomor_tiket = ARRAY[:checkin_nomor_tiket]")
+		addSets = append(addSets, ", checkin_nomor_tiket = ARRAY[:checkin_nomor_tiket]")
+	}
+	if len(tx.RefundNomorTiket) != 0 {
+		argsKV["refund_nomor_tiket"] = tx.RefundNomorTiket
+		addSets = append(addSets, ", refund_nomor_tiket = ARRAY[:refund_nomor_tiket]")
 	}
+	query := fmt.Sprintf(queryUpdateMainEvent, strings.Join(addSets, ""))
 
 	query, args, err := sqlx.Named(query, argsKV)
 	if err != nil {
@@ -189,10 +363,1209 @@ func (sc *storeClient) UpdateMainEventByID(ctx context.Context, tx mainevent.Mai
 
 	query = sc.q.Rebind(query)
 
-	_, err = sc.q.Exec(query, args...)
+	res, err := sc.q.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return mainevent.ErrConflict
+	}
+
+	return nil
+}
+
+func (sc *storeClient) CreateRefund(ctx context.Context, reqRefund mainevent.Refund) (int64, error) {
+	// construct arguments filled with fields for the query
+	argsKV := map[string]interface{}{
+		"mainevent_id": reqRefund.MainEventID,
+		"nomor_tiket":  reqRefund.NomorTiket,
+		"alasan":       reqRefund.Alasan,
+		"total_refund": reqRefund.TotalRefund,
+		"status":       reqRefund.Status,
+		"create_time":  reqRefund.CreateTime,
+	}
+
+	// prepare query
+	query, args, err := sqlx.Named(queryCreateRefund, argsKV)
+	if err != nil {
+		return 0, err
+	}
+	query, args, err = sqlx.In(query, args...)
+	if err != nil {
+		return 0, err
+	}
+	query = sc.q.Rebind(query)
+
+	// execute query
+	var refundID int64
+	err = sc.q.QueryRowxContext(ctx, query, args...).Scan(&refundID)
+	if err != nil {
+		return 0, err
+	}
+
+	return refundID, nil
+}
+
+func (sc *storeClient) GetRefundByID(ctx context.Context, refundID int64) (mainevent.Refund, error) {
+	query := fmt.Sprintf(queryGetRefund, "WHERE r.id = $1")
+
+	// query single row
+	var rdb refundDB
+	err := sc.q.QueryRowxContext(ctx, query, refundID).StructScan(&rdb)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return mainevent.Refund{}, mainevent.ErrDataNotFound
+		}
+		return mainevent.Refund{}, err
+	}
+
+	return rdb.format(), nil
+}
+
+func (sc *storeClient) GetAllRefunds(ctx context.Context, maineventID int64, status mainevent.RefundStatus) ([]mainevent.Refund, error) {
+	// define variables to custom query
+	argsKV := make(map[string]interface{})
+	addConditions := make([]string, 0)
+
+	if maineventID != 0 {
+		addConditions = append(addConditions, "r.mainevent_id = :mainevent_id")
+		argsKV["mainevent_id"] = maineventID
+	}
+	if status != mainevent.RefundStatusUnknown {
+		addConditions = append(addConditions, "r.status = :status")
+		argsKV["status"] = status
+	}
+
+	// construct strings to custom query
+	addCondition := strings.Join(addConditions, " AND ")
+	if len(addConditions) > 0 {
+		addCondition = fmt.Sprintf("WHERE %s", addCondition)
+	}
+	addCondition += " ORDER BY r.create_time ASC, r.id ASC"
+	query := fmt.Sprintf(queryGetRefund, addCondition)
+
+	// prepare query
+	query, args, err := sqlx.Named(query, argsKV)
+	if err != nil {
+		return nil, err
+	}
+	query, args, err = sqlx.In(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	query = sc.q.Rebind(query)
+
+	// query to database
+	rows, err := sc.q.QueryxContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	// read refunds
+	result := make([]mainevent.Refund, 0)
+	for rows.Next() {
+		var row refundDB
+		err = rows.StructScan(&row)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, row.format())
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (sc *storeClient) UpdateRefundStatus(ctx context.Context, reqRefund mainevent.Refund, updateTime time.Time) error {
+	// only a requested refund may be processed, so that a
+	// refund is never processed twice
+	argsKV := map[string]interface{}{
+		"status":          reqRefund.Status,
+		"metode":          reqRefund.Metode,
+		"catatan":         reqRefund.Catatan,
+		"update_time":     updateTime,
+		"id":              reqRefund.ID,
+		"expected_status": mainevent.RefundStatusRequested,
+	}
+
+	// prepare query
+	query, args, err := sqlx.Named(queryUpdateRefund, argsKV)
+	if err != nil {
+		return err
+	}
+	query, args, err = sqlx.In(query, args...)
+	if err != nil {
+		return err
+	}
+	query = sc.q.Rebind(query)
+
+	// execute query
+	res, err := sc.q.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	rows, err := res.RowsAffected()
 	if err != nil {
 		return err
 	}
+	if rows == 0 {
+		return mainevent.ErrRefundAlreadyProcessed
+	}
 
 	return nil
 }
+
+func (sc *storeClient) GetHoldersByMainEventID(ctx context.Context, maineventID int64) ([]mainevent.Holder, error) {
+	query := fmt.Sprintf(queryGetHolder, "WHERE h.mainevent_id = $1 ORDER BY h.nomor_tiket")
+
+	// query to database
+	rows, err := sc.q.QueryxContext(ctx, query, maineventID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	// read holders
+	result := make([]mainevent.Holder, 0)
+	for rows.Next() {
+		var row holderDB
+		err = rows.StructScan(&row)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, row.format())
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (sc *storeClient) UpsertHolder(ctx context.Context, holder mainevent.Holder) error {
+	// construct arguments filled with fields for the query
+	argsKV := map[string]interface{}{
+		"mainevent_id":    holder.MainEventID,
+		"nomor_tiket":     holder.NomorTiket,
+		"nama":            holder.Nama,
+		"email":           holder.Email,
+		"nomor_identitas": holder.NomorIdentitas,
+		"create_time":     holder.CreateTime,
+	}
+
+	// prepare query
+	query, args, err := sqlx.Named(queryUpsertHolder, argsKV)
+	if err != nil {
+		return err
+	}
+	query = sc.q.Rebind(query)
+
+	// execute query
+	_, err = sc.q.ExecContext(ctx, query, args...)
+	return err
+}
+
+func (sc *storeClient) CreateWaitlistEntry(ctx context.Context, entry mainevent.WaitlistEntry) (int64, error) {
+	// construct arguments filled with fields for the query
+	argsKV := map[string]interface{}{
+		"nama":         entry.Nama,
+		"email":        entry.Email,
+		"jumlah_tiket": entry.JumlahTiket,
+		"status":       entry.Status,
+		"create_time":  entry.CreateTime,
+	}
+
+	// prepare query
+	query, args, err := sqlx.Named(queryCreateWaitlistEntry, argsKV)
+	if err != nil {
+		return 0, err
+	}
+	query = sc.q.Rebind(query)
+
+	// execute query
+	var entryID int64
+	err = sc.q.QueryRowxContext(ctx, query, args...).Scan(&entryID)
+	if err != nil {
+		return 0, err
+	}
+
+	return entryID, nil
+}
+
+func (sc *storeClient) GetWaitlistEntryByToken(ctx context.Context, token string) (mainevent.WaitlistEntry, error) {
+	// lock the entry within the transaction, so that an offer
+	// is claimed once
+	query := fmt.Sprintf(queryGetWaitlistEntry, "WHERE w.token = $1 FOR UPDATE")
+
+	// query single row
+	var wdb waitlistEntryDB
+	err := sc.q.QueryRowxContext(ctx, query, token).StructScan(&wdb)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return mainevent.WaitlistEntry{}, mainevent.ErrDataNotFound
+		}
+		return mainevent.WaitlistEntry{}, err
+	}
+
+	return wdb.format(), nil
+}
+
+func (sc *storeClient) GetAllWaitlistEntries(ctx context.Context, status mainevent.WaitlistStatus) ([]mainevent.WaitlistEntry, error) {
+	// define variables to custom query
+	argsKV := make(map[string]interface{})
+	addCondition := ""
+
+	if status != mainevent.WaitlistStatusUnknown {
+		addCondition = "WHERE w.status = :status"
+		argsKV["status"] = status
+	}
+
+	// first in first out
+	addCondition += " ORDER BY w.create_time ASC, w.id ASC"
+	query := fmt.Sprintf(queryGetWaitlistEntry, addCondition)
+
+	// prepare query
+	query, args, err := sqlx.Named(query, argsKV)
+	if err != nil {
+		return nil, err
+	}
+	query = sc.q.Rebind(query)
+
+	// query to database
+	rows, err := sc.q.QueryxContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	// read waitlist entries
+	result := make([]mainevent.WaitlistEntry, 0)
+	for rows.Next() {
+		var row waitlistEntryDB
+		err = rows.StructScan(&row)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, row.format())
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (sc *storeClient) UpdateWaitlistEntry(ctx context.Context, entry mainevent.WaitlistEntry, expectedStatus mainevent.WaitlistStatus, updateTime time.Time) error {
+	argsKV := map[string]interface{}{
+		"status":            entry.Status,
+		"token":             nil,
+		"offer_expire_time": nil,
+		"update_time":       updateTime,
+		"id":                entry.ID,
+		"expected_status":   expectedStatus,
+	}
+	if entry.Token != "" {
+		argsKV["token"] = entry.Token
+	}
+	if !entry.OfferExpireTime.IsZero() {
+		argsKV["offer_expire_time"] = entry.OfferExpireTime
+	}
+
+	// prepare query
+	query, args, err := sqlx.Named(queryUpdateWaitlistEntry, argsKV)
+	if err != nil {
+		return err
+	}
+	query = sc.q.Rebind(query)
+
+	// execute query
+	res, err := sc.q.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return mainevent.ErrConflict
+	}
+
+	return nil
+}
+
+func (sc *storeClient) ExpireWaitlistOffers(ctx context.Context, now time.Time) ([]mainevent.WaitlistEntry, error) {
+	argsKV := map[string]interface{}{
+		"expired_status": mainevent.WaitlistStatusExpired,
+		"offered_status": mainevent.WaitlistStatusOffered,
+		"update_time":    now,
+	}
+
+	// prepare query
+	query, args, err := sqlx.Named(queryExpireWaitlistOffers, argsKV)
+	if err != nil {
+		return nil, err
+	}
+	query = sc.q.Rebind(query)
+
+	// query to database
+	rows, err := sc.q.QueryxContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	// read expired entries
+	result := make([]mainevent.WaitlistEntry, 0)
+	for rows.Next() {
+		var row waitlistEntryDB
+		err = rows.StructScan(&row)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, row.format())
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (sc *storeClient) CreateInstitution(ctx context.Context, institution mainevent.Institution) (int64, error) {
+	// construct arguments filled with fields for the query
+	argsKV := map[string]interface{}{
+		"nama":             institution.Nama,
+		"max_tiket":        institution.MaxTiket,
+		"kuota_tiket":      institution.KuotaTiket,
+		"require_approval": institution.RequireApproval,
+		"create_time":      institution.CreateTime,
+	}
+
+	// prepare query
+	query, args, err := sqlx.Named(queryCreateInstitution, argsKV)
+	if err != nil {
+		return 0, err
+	}
+	query = sc.q.Rebind(query)
+
+	// execute query
+	var institutionID int64
+	err = sc.q.QueryRowxContext(ctx, query, args...).Scan(&institutionID)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return 0, mainevent.ErrInstitutionExists
+		}
+		return 0, err
+	}
+
+	return institutionID, nil
+}
+
+func (sc *storeClient) GetAllInstitutions(ctx context.Context) ([]mainevent.Institution, error) {
+	query := fmt.Sprintf(queryGetInstitution, "ORDER BY lower(i.nama) ASC")
+
+	// query to database
+	rows, err := sc.q.QueryxContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	// read institutions
+	result := make([]mainevent.Institution, 0)
+	for rows.Next() {
+		var row institutionDB
+		err = rows.StructScan(&row)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, row.format())
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (sc *storeClient) GetInstitutionByNama(ctx context.Context, nama string) (mainevent.Institution, error) {
+	// lock the institution within the transaction, so that
+	// two group orders never take its last tickets
+	query := fmt.Sprintf(queryGetInstitution, "WHERE lower(i.nama) = lower($1) FOR UPDATE")
+
+	// query single row
+	var idb institutionDB
+	err := sc.q.QueryRowxContext(ctx, query, nama).StructScan(&idb)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return mainevent.Institution{}, mainevent.ErrDataNotFound
+		}
+		return mainevent.Institution{}, err
+	}
+
+	return idb.format(), nil
+}
+
+func (sc *storeClient) LockInstitution(ctx context.Context, nama string) error {
+	// the name is locked rather than the row, so that the
+	// institutions following the default policy are locked
+	// as well
+	key := "mainevent_institution:" + strings.ToLower(strings.TrimSpace(nama))
+
+	query, args, err := sqlx.Named(queryAdvisoryLock, map[string]interface{}{"key": key})
+	if err != nil {
+		return err
+	}
+	query = sc.q.Rebind(query)
+
+	_, err = sc.q.ExecContext(ctx, query, args...)
+	return err
+}
+
+func (sc *storeClient) UpdateInstitution(ctx context.Context, institution mainevent.Institution) error {
+	// construct arguments filled with fields for the query
+	argsKV := map[string]interface{}{
+		"nama":             institution.Nama,
+		"max_tiket":        institution.MaxTiket,
+		"kuota_tiket":      institution.KuotaTiket,
+		"require_approval": institution.RequireApproval,
+		"update_time":      institution.UpdateTime,
+		"id":               institution.ID,
+	}
+
+	// prepare query
+	query, args, err := sqlx.Named(queryUpdateInstitution, argsKV)
+	if err != nil {
+		return err
+	}
+	query = sc.q.Rebind(query)
+
+	// execute query
+	res, err := sc.q.ExecContext(ctx, query, args...)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return mainevent.ErrInstitutionExists
+		}
+		return err
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return mainevent.ErrDataNotFound
+	}
+
+	return nil
+}
+
+func (sc *storeClient) CreateGroupOrder(ctx context.Context, group mainevent.GroupOrder) (int64, error) {
+	// construct arguments filled with fields for the query
+	argsKV := map[string]interface{}{
+		"nama":            group.Nama,
+		"nomor_identitas": group.NomorIdentitas,
+		"asal_institusi":  group.AsalInstitusi,
+		"email":           group.Email,
+		"nomor_telepon":   group.NomorTelepon,
+		"harga_tiket":     group.HargaTiket,
+		"total_harga":     group.TotalHarga,
+		"status":          group.Status,
+		"create_time":     group.CreateTime,
+	}
+
+	// prepare query
+	query, args, err := sqlx.Named(queryCreateGroupOrder, argsKV)
+	if err != nil {
+		return 0, err
+	}
+	query = sc.q.Rebind(query)
+
+	// execute query
+	var groupID int64
+	err = sc.q.QueryRowxContext(ctx, query, args...).Scan(&groupID)
+	if err != nil {
+		return 0, err
+	}
+
+	// insert the attendees in their order
+	for i, attendee := range group.Attendees {
+		query, args, err := sqlx.Named(queryCreateGroupAttendee, map[string]interface{}{
+			"group_id":        groupID,
+			"urutan":          i,
+			"nama":            attendee.Nama,
+			"email":           attendee.Email,
+			"nomor_identitas": attendee.NomorIdentitas,
+		})
+		if err != nil {
+			return 0, err
+		}
+		query = sc.q.Rebind(query)
+
+		_, err = sc.q.ExecContext(ctx, query, args...)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	return groupID, nil
+}
+
+func (sc *storeClient) GetGroupOrderByID(ctx context.Context, groupID int64) (mainevent.GroupOrder, error) {
+	// lock the group order within the transaction, so that
+	// it is processed once
+	return sc.getGroupOrder(ctx, "WHERE g.id = $1 FOR UPDATE", groupID)
+}
+
+func (sc *storeClient) GetGroupOrderByMainEventID(ctx context.Context, maineventID int64) (mainevent.GroupOrder, error) {
+	return sc.getGroupOrder(ctx, "WHERE g.mainevent_id = $1", maineventID)
+}
+
+// getGroupOrder returns the group order matching the given
+// condition, together with its attendees.
+func (sc *storeClient) getGroupOrder(ctx context.Context, condition string, arg interface{}) (mainevent.GroupOrder, error) {
+	query := fmt.Sprintf(queryGetGroupOrder, condition)
+
+	// query single row
+	var gdb groupOrderDB
+	err := sc.q.QueryRowxContext(ctx, query, arg).StructScan(&gdb)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return mainevent.GroupOrder{}, mainevent.ErrDataNotFound
+		}
+		return mainevent.GroupOrder{}, err
+	}
+
+	groups := []mainevent.GroupOrder{gdb.format()}
+	err = sc.fillGroupAttendees(ctx, groups)
+	if err != nil {
+		return mainevent.GroupOrder{}, err
+	}
+
+	return groups[0], nil
+}
+
+func (sc *storeClient) GetAllGroupOrders(ctx context.Context, status mainevent.GroupStatus) ([]mainevent.GroupOrder, error) {
+	// define variables to custom query
+	argsKV := make(map[string]interface{})
+	addCondition := ""
+
+	if status != mainevent.GroupStatusUnknown {
+		addCondition = "WHERE g.status = :status"
+		argsKV["status"] = status
+	}
+
+	addCondition += " ORDER BY g.create_time ASC, g.id ASC"
+	query := fmt.Sprintf(queryGetGroupOrder, addCondition)
+
+	// prepare query
+	query, args, err := sqlx.Named(query, argsKV)
+	if err != nil {
+		return nil, err
+	}
+	query = sc.q.Rebind(query)
+
+	// query to database
+	rows, err := sc.q.QueryxContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	// read group orders
+	result := make([]mainevent.GroupOrder, 0)
+	for rows.Next() {
+		var row groupOrderDB
+		err = rows.StructScan(&row)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, row.format())
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	err = sc.fillGroupAttendees(ctx, result)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// fillGroupAttendees reads the attendees of the given group
+// orders into them.
+func (sc *storeClient) fillGroupAttendees(ctx context.Context, groups []mainevent.GroupOrder) error {
+	if len(groups) == 0 {
+		return nil
+	}
+
+	groupIDs := make([]int64, 0, len(groups))
+	index := make(map[int64]int, len(groups))
+	for i, g := range groups {
+		groupIDs = append(groupIDs, g.ID)
+		index[g.ID] = i
+	}
+
+	// prepare query
+	query, args, err := sqlx.Named(queryGetGroupAttendees, map[string]interface{}{
+		"group_ids": groupIDs,
+	})
+	if err != nil {
+		return err
+	}
+	query, args, err = sqlx.In(query, args...)
+	if err != nil {
+		return err
+	}
+	query = sc.q.Rebind(query)
+
+	// query to database
+	rows, err := sc.q.QueryxContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	// read attendees
+	for rows.Next() {
+		var row groupAttendeeDB
+		err = rows.StructScan(&row)
+		if err != nil {
+			return err
+		}
+
+		i := index[row.GroupID]
+		groups[i].Attendees = append(groups[i].Attendees, row.format())
+	}
+
+	return rows.Err()
+}
+
+func (sc *storeClient) UpdateGroupOrder(ctx context.Context, group mainevent.GroupOrder, from mainevent.GroupStatus, updateTime time.Time) error {
+	// a group order is only processed from the given status,
+	// so that its invoice is never issued or expired twice
+	argsKV := map[string]interface{}{
+		"mainevent_id":    nil,
+		"status":          group.Status,
+		"catatan":         group.Catatan,
+		"update_time":     updateTime,
+		"id":              group.ID,
+		"expected_status": from,
+	}
+	if group.MainEventID != 0 {
+		argsKV["mainevent_id"] = group.MainEventID
+	}
+
+	// prepare query
+	query, args, err := sqlx.Named(queryUpdateGroupOrder, argsKV)
+	if err != nil {
+		return err
+	}
+	query = sc.q.Rebind(query)
+
+	// execute query
+	res, err := sc.q.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return mainevent.ErrGroupOrderAlreadyProcessed
+	}
+
+	return nil
+}
+
+func (sc *storeClient) CountGroupTickets(ctx context.Context, asalInstitusi string) (int, error) {
+	argsKV := map[string]interface{}{
+		"asal_institusi": asalInstitusi,
+		"closed_status":  []mainevent.GroupStatus{mainevent.GroupStatusRejected, mainevent.GroupStatusExpired},
+	}
+
+	// prepare query
+	query, args, err := sqlx.Named(queryCountGroupTickets, argsKV)
+	if err != nil {
+		return 0, err
+	}
+	query, args, err = sqlx.In(query, args...)
+	if err != nil {
+		return 0, err
+	}
+	query = sc.q.Rebind(query)
+
+	// query single row
+	var count int
+	err = sc.q.QueryRowxContext(ctx, query, args...).Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (sc *storeClient) CountBuyerTickets(ctx context.Context, eventID int64, email, nomorIdentitas string) (int, int, error) {
+	email = strings.TrimSpace(email)
+	nomorIdentitas = strings.TrimSpace(nomorIdentitas)
+
+	// lock the buyer within the transaction, so that two
+	// orders of the same buyer never pass the limits together.
+	// the identity is always locked before the email to avoid
+	// deadlocks between the orders.
+	for _, key := range []string{
+		"mainevent_buyer_identity:" + nomorIdentitas,
+		"mainevent_buyer_email:" + strings.ToLower(email),
+	} {
+		query, args, err := sqlx.Named(queryAdvisoryLock, map[string]interface{}{"key": key})
+		if err != nil {
+			return 0, 0, err
+		}
+		query = sc.q.Rebind(query)
+
+		_, err = sc.q.ExecContext(ctx, query, args...)
+		if err != nil {
+			return 0, 0, err
+		}
+	}
+
+	// the unpaid orders with the email are replaced by the new
+	// order, so they are not counted, and neither are the
+	// complimentary ones. the tickets of the group invoices
+	// count toward their attendees rather than the coordinator
+	argsKV := map[string]interface{}{
+		"event_id":              eventID,
+		"email":                 email,
+		"nomor_identitas":       nomorIdentitas,
+		"group_type":            mainevent.TypeGroup,
+		"complimentary_type":    mainevent.TypeComplimentary,
+		"unpaid_status":         mainevent.StatusUnpaid,
+		"approved_group_status": mainevent.GroupStatusApproved,
+	}
+
+	// prepare query
+	query, args, err := sqlx.Named(queryCountBuyerTickets, argsKV)
+	if err != nil {
+		return 0, 0, err
+	}
+	query = sc.q.Rebind(query)
+
+	// query single row
+	var byEmail, byIdentity int
+	err = sc.q.QueryRowxContext(ctx, query, args...).Scan(&byEmail, &byIdentity)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return byEmail, byIdentity, nil
+}
+
+func (sc *storeClient) GetAllSeats(ctx context.Context) ([]mainevent.Seat, error) {
+	query := fmt.Sprintf(queryGetSeat, "", "")
+	return sc.getSeats(ctx, query)
+}
+
+func (sc *storeClient) GetSeatsForUpdate(ctx context.Context) ([]mainevent.Seat, error) {
+	// lock the seats within the transaction, so that two
+	// settlements or a settlement and a layout never take
+	// the same seats
+	query := fmt.Sprintf(queryGetSeat, "", "FOR UPDATE")
+	return sc.getSeats(ctx, query)
+}
+
+func (sc *storeClient) GetSeatsByMainEventID(ctx context.Context, maineventID int64) ([]mainevent.Seat, error) {
+	query := fmt.Sprintf(queryGetSeat, "WHERE s.mainevent_id = $1", "")
+	return sc.getSeats(ctx, query, maineventID)
+}
+
+// getSeats returns the seats of the given query.
+func (sc *storeClient) getSeats(ctx context.Context, query string, args ...interface{}) ([]mainevent.Seat, error) {
+	// query to database
+	rows, err := sc.q.QueryxContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	// read seats
+	result := make([]mainevent.Seat, 0)
+	for rows.Next() {
+		var row seatDB
+		err = rows.StructScan(&row)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, row.format())
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (sc *storeClient) CreateSeat(ctx context.Context, seat mainevent.Seat) error {
+	// construct arguments filled with fields for the query
+	argsKV := map[string]interface{}{
+		"section":     seat.Section,
+		"baris":       seat.Baris,
+		"nomor":       seat.Nomor,
+		"aksesibel":   seat.Aksesibel,
+		"create_time": seat.CreateTime,
+	}
+
+	// prepare query
+	query, args, err := sqlx.Named(queryCreateSeat, argsKV)
+	if err != nil {
+		return err
+	}
+	query = sc.q.Rebind(query)
+
+	// execute query
+	_, err = sc.q.ExecContext(ctx, query, args...)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return mainevent.ErrInvalidSeatLayout
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (sc *storeClient) UpdateSeatAksesibel(ctx context.Context, seat mainevent.Seat, updateTime time.Time) error {
+	// construct arguments filled with fields for the query
+	argsKV := map[string]interface{}{
+		"aksesibel":   seat.Aksesibel,
+		"update_time": updateTime,
+		"id":          seat.ID,
+	}
+
+	// prepare query
+	query, args, err := sqlx.Named(queryUpdateSeatAksesibel, argsKV)
+	if err != nil {
+		return err
+	}
+	query = sc.q.Rebind(query)
+
+	// execute query
+	res, err := sc.q.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return mainevent.ErrDataNotFound
+	}
+
+	return nil
+}
+
+func (sc *storeClient) DeleteSeats(ctx context.Context, ids []int64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	argsKV := map[string]interface{}{
+		"ids": ids,
+	}
+
+	// prepare query
+	query, args, err := sqlx.Named(queryDeleteSeats, argsKV)
+	if err != nil {
+		return err
+	}
+	query, args, err = sqlx.In(query, args...)
+	if err != nil {
+		return err
+	}
+	query = sc.q.Rebind(query)
+
+	// execute query
+	res, err := sc.q.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	// only the free seats are deleted
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows != int64(len(ids)) {
+		return mainevent.ErrSeatInUse
+	}
+
+	return nil
+}
+
+func (sc *storeClient) HoldSeats(ctx context.Context, maineventID int64, ids []int64, aksesibel bool, updateTime time.Time) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	argsKV := map[string]interface{}{
+		"mainevent_id": maineventID,
+		"ids":          ids,
+		"aksesibel":    aksesibel,
+		"update_time":  updateTime,
+	}
+
+	// prepare query
+	query, args, err := sqlx.Named(queryHoldSeats, argsKV)
+	if err != nil {
+		return err
+	}
+	query, args, err = sqlx.In(query, args...)
+	if err != nil {
+		return err
+	}
+	query = sc.q.Rebind(query)
+
+	// execute query
+	res, err := sc.q.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	// every seat must be free and allowed to the buyer
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows != int64(len(ids)) {
+		return mainevent.ErrSeatNotAvailable
+	}
+
+	return nil
+}
+
+func (sc *storeClient) AssignSeat(ctx context.Context, seat mainevent.Seat, updateTime time.Time) error {
+	argsKV := map[string]interface{}{
+		"mainevent_id": seat.MainEventID,
+		"nomor_tiket":  seat.NomorTiket,
+		"update_time":  updateTime,
+		"id":           seat.ID,
+	}
+
+	// prepare query
+	query, args, err := sqlx.Named(queryAssignSeat, argsKV)
+	if err != nil {
+		return err
+	}
+	query = sc.q.Rebind(query)
+
+	// execute query
+	res, err := sc.q.ExecContext(ctx, query, args...)
+	if err != nil {
+		// the ticket is already seated elsewhere
+		if isUniqueViolation(err) {
+			return mainevent.ErrSeatNotAvailable
+		}
+		return err
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return mainevent.ErrSeatNotAvailable
+	}
+
+	return nil
+}
+
+func (sc *storeClient) DeleteHolders(ctx context.Context, maineventID int64, nomorTiket []string) error {
+	if len(nomorTiket) == 0 {
+		return nil
+	}
+
+	argsKV := map[string]interface{}{
+		"mainevent_id": maineventID,
+		"nomor_tiket":  nomorTiket,
+	}
+
+	// prepare query
+	query, args, err := sqlx.Named(queryDeleteHolders, argsKV)
+	if err != nil {
+		return err
+	}
+	query, args, err = sqlx.In(query, args...)
+	if err != nil {
+		return err
+	}
+	query = sc.q.Rebind(query)
+
+	// execute query
+	_, err = sc.q.ExecContext(ctx, query, args...)
+	return err
+}
+
+func (sc *storeClient) ReleaseSeats(ctx context.Context, nomorTiket []string, updateTime time.Time) error {
+	if len(nomorTiket) == 0 {
+		return nil
+	}
+
+	argsKV := map[string]interface{}{
+		"nomor_tiket": nomorTiket,
+		"update_time": updateTime,
+	}
+
+	// prepare query
+	query, args, err := sqlx.Named(queryReleaseSeats, argsKV)
+	if err != nil {
+		return err
+	}
+	query, args, err = sqlx.In(query, args...)
+	if err != nil {
+		return err
+	}
+	query = sc.q.Rebind(query)
+
+	// execute query
+	_, err = sc.q.ExecContext(ctx, query, args...)
+	return err
+}
+
+func (sc *storeClient) CreateBuyerLogin(ctx context.Context, login mainevent.BuyerLogin) (int64, error) {
+	// construct arguments filled with fields for the query
+	argsKV := map[string]interface{}{
+		"email":       login.Email,
+		"token_hash":  login.TokenHash,
+		"expire_time": login.ExpireTime,
+		"create_time": login.CreateTime,
+	}
+
+	// prepare query
+	query, args, err := sqlx.Named(queryCreateBuyerLogin, argsKV)
+	if err != nil {
+		return 0, err
+	}
+	query = sc.q.Rebind(query)
+
+	// execute query
+	var loginID int64
+	err = sc.q.QueryRowxContext(ctx, query, args...).Scan(&loginID)
+	if err != nil {
+		return 0, err
+	}
+
+	return loginID, nil
+}
+
+func (sc *storeClient) GetBuyerLoginByToken(ctx context.Context, tokenHash string) (mainevent.BuyerLogin, error) {
+	login, err := sc.getBuyerLogin(ctx, "WHERE b.token_hash = $1", tokenHash)
+	if err == mainevent.ErrDataNotFound {
+		return mainevent.BuyerLogin{}, mainevent.ErrInvalidBuyerLoginToken
+	}
+	return login, err
+}
+
+func (sc *storeClient) GetBuyerLoginBySession(ctx context.Context, sessionTokenHash string) (mainevent.BuyerLogin, error) {
+	login, err := sc.getBuyerLogin(ctx, "WHERE b.session_token_hash = $1", sessionTokenHash)
+	if err == mainevent.ErrDataNotFound {
+		return mainevent.BuyerLogin{}, mainevent.ErrInvalidBuyerSession
+	}
+	return login, err
+}
+
+func (sc *storeClient) getBuyerLogin(ctx context.Context, condition string, arg interface{}) (mainevent.BuyerLogin, error) {
+	query := fmt.Sprintf(queryGetBuyerLogin, condition)
+
+	// query single row
+	var bdb buyerLoginDB
+	err := sc.q.QueryRowxContext(ctx, query, arg).StructScan(&bdb)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return mainevent.BuyerLogin{}, mainevent.ErrDataNotFound
+		}
+		return mainevent.BuyerLogin{}, err
+	}
+
+	return bdb.format(), nil
+}
+
+func (sc *storeClient) UseBuyerLogin(ctx context.Context, login mainevent.BuyerLogin) error {
+	argsKV := map[string]interface{}{
+		"session_token_hash":  login.SessionTokenHash,
+		"session_expire_time": login.SessionExpireTime,
+		"use_time":            login.UseTime,
+		"id":                  login.ID,
+	}
+
+	// prepare query
+	query, args, err := sqlx.Named(queryUseBuyerLogin, argsKV)
+	if err != nil {
+		return err
+	}
+	query = sc.q.Rebind(query)
+
+	// execute query
+	res, err := sc.q.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return mainevent.ErrInvalidBuyerLoginToken
+	}
+
+	return nil
+}
+
+func (sc *storeClient) CreateCheckIn(ctx context.Context, maineventID int64, nomorTiket string, checkInTime time.Time) error {
+	argsKV := map[string]interface{}{
+		"mainevent_id": maineventID,
+		"nomor_tiket":  nomorTiket,
+		"checkin_time": checkInTime,
+	}
+
+	// prepare query
+	query, args, err := sqlx.Named(queryCreateCheckIn, argsKV)
+	if err != nil {
+		return err
+	}
+	query = sc.q.Rebind(query)
+
+	// execute query
+	_, err = sc.q.ExecContext(ctx, query, args...)
+	return err
+}
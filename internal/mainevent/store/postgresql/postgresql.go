@@ -22,7 +22,7 @@ type store struct {
 
 // storeClient implements transaction/service.PGStoreClient
 type storeClient struct {
-	q sqlx.Ext
+	q sqlx.ExtContext
 }
 
 // New creates a new store.
@@ -35,7 +35,7 @@ func New(db *sqlx.DB) (*store, error) {
 }
 
 func (s *store) NewClient(useTx bool) (service.PGStoreClient, error) {
-	var q sqlx.Ext
+	var q sqlx.ExtContext
 
 	// determine what object should be use as querier
 	q = s.db
@@ -68,6 +68,7 @@ func (sc *storeClient) Rollback() error {
 
 type maineventDB struct {
 	ID                int64                `db:"id"`
+	EventID           *int64               `db:"event_id"`
 	Nama              string               `db:"nama"`
 	Disabilitas       mainevent.Disability `db:"disabilitas"`
 	NomorIdentitas    string               `db:"nomor_identitas"`
@@ -76,6 +77,10 @@ type maineventDB struct {
 	NomorTelepon      string               `db:"nomor_telepon"`
 	JumlahTiket       int                  `db:"jumlah_tiket"`
 	TotalHarga        int64                `db:"total_harga"`
+	KodePromo         *string              `db:"kode_promo"`
+	Diskon            int64                `db:"diskon"`
+	Akomodasi         pq.StringArray       `db:"akomodasi"`
+	CatatanAkomodasi  *string              `db:"catatan_akomodasi"`
 	OrderID           string               `db:"order_id"`
 	Type              mainevent.Type       `db:"type"`
 	Status            mainevent.Status     `db:"status"`
@@ -83,6 +88,7 @@ type maineventDB struct {
 	NomorTiket        pq.StringArray       `db:"nomor_tiket"`
 	CheckInStatus     *bool                `db:"checkin_status"`
 	CheckInNomorTiket pq.StringArray       `db:"checkin_nomor_tiket"`
+	RefundNomorTiket  pq.StringArray       `db:"refund_nomor_tiket"`
 	CreateTime        time.Time            `db:"create_time"`
 	UpdateTime        *time.Time           `db:"update_time"`
 }
@@ -99,6 +105,7 @@ func (mdb *maineventDB) format() mainevent.MainEvent {
 		NomorTelepon:   mdb.NomorTelepon,
 		JumlahTiket:    mdb.JumlahTiket,
 		TotalHarga:     mdb.TotalHarga,
+		Diskon:         mdb.Diskon,
 		OrderID:        mdb.OrderID,
 		Type:           mdb.Type,
 		Status:         mdb.Status,
@@ -113,6 +120,14 @@ func (mdb *maineventDB) format() mainevent.MainEvent {
 		t.NomorTiket = ticketNumbers
 	}
 
+	if mdb.EventID != nil {
+		t.EventID = *mdb.EventID
+	}
+
+	if mdb.KodePromo != nil {
+		t.KodePromo = *mdb.KodePromo
+	}
+
 	if mdb.ImageURI != nil {
 		t.ImageURI = *mdb.ImageURI
 	}
@@ -129,9 +144,320 @@ func (mdb *maineventDB) format() mainevent.MainEvent {
 		t.CheckInNomorTiket = checkinTicketNumbers
 	}
 
+	if len(mdb.RefundNomorTiket) > 0 {
+		t.RefundNomorTiket = append([]string(nil), mdb.RefundNomorTiket...)
+	}
+
+	for _, a := range mdb.Akomodasi {
+		t.Akomodasi = append(t.Akomodasi, mainevent.Accommodation(a))
+	}
+
+	if mdb.CatatanAkomodasi != nil {
+		t.CatatanAkomodasi = *mdb.CatatanAkomodasi
+	}
+
 	if mdb.UpdateTime != nil {
 		t.UpdateTime = *mdb.UpdateTime
 	}
 
 	return t
 }
+
+type refundDB struct {
+	ID          int64                  `db:"id"`
+	MainEventID int64                  `db:"mainevent_id"`
+	NomorTiket  pq.StringArray         `db:"nomor_tiket"`
+	Alasan      string                 `db:"alasan"`
+	TotalRefund int64                  `db:"total_refund"`
+	Status      mainevent.RefundStatus `db:"status"`
+	Metode      *string                `db:"metode"`
+	Catatan     *string                `db:"catatan"`
+	CreateTime  time.Time              `db:"create_time"`
+	UpdateTime  *time.Time             `db:"update_time"`
+}
+
+// format formats database struct into domain struct.
+func (rdb *refundDB) format() mainevent.Refund {
+	r := mainevent.Refund{
+		ID:          rdb.ID,
+		MainEventID: rdb.MainEventID,
+		NomorTiket:  append([]string(nil), rdb.NomorTiket...),
+		Alasan:      rdb.Alasan,
+		TotalRefund: rdb.TotalRefund,
+		Status:      rdb.Status,
+		CreateTime:  rdb.CreateTime,
+	}
+
+	if rdb.Metode != nil {
+		r.Metode = mainevent.RefundMethod(*rdb.Metode)
+	}
+
+	if rdb.Catatan != nil {
+		r.Catatan = *rdb.Catatan
+	}
+
+	if rdb.UpdateTime != nil {
+		r.UpdateTime = *rdb.UpdateTime
+	}
+
+	return r
+}
+
+type holderDB struct {
+	MainEventID    int64      `db:"mainevent_id"`
+	NomorTiket     string     `db:"nomor_tiket"`
+	Nama           string     `db:"nama"`
+	Email          string     `db:"email"`
+	NomorIdentitas *string    `db:"nomor_identitas"`
+	CreateTime     time.Time  `db:"create_time"`
+	UpdateTime     *time.Time `db:"update_time"`
+}
+
+// format formats database struct into domain struct.
+func (hdb *holderDB) format() mainevent.Holder {
+	h := mainevent.Holder{
+		MainEventID: hdb.MainEventID,
+		NomorTiket:  hdb.NomorTiket,
+		Nama:        hdb.Nama,
+		Email:       hdb.Email,
+		CreateTime:  hdb.CreateTime,
+	}
+
+	if hdb.NomorIdentitas != nil {
+		h.NomorIdentitas = *hdb.NomorIdentitas
+	}
+
+	if hdb.UpdateTime != nil {
+		h.UpdateTime = *hdb.UpdateTime
+	}
+
+	return h
+}
+
+type waitlistEntryDB struct {
+	ID              int64                    `db:"id"`
+	Nama            string                   `db:"nama"`
+	Email           string                   `db:"email"`
+	JumlahTiket     int                      `db:"jumlah_tiket"`
+	Status          mainevent.WaitlistStatus `db:"status"`
+	Token           *string                  `db:"token"`
+	OfferExpireTime *time.Time               `db:"offer_expire_time"`
+	CreateTime      time.Time                `db:"create_time"`
+	UpdateTime      *time.Time               `db:"update_time"`
+}
+
+// format formats database struct into domain struct.
+func (wdb *waitlistEntryDB) format() mainevent.WaitlistEntry {
+	w := mainevent.WaitlistEntry{
+		ID:          wdb.ID,
+		Nama:        wdb.Nama,
+		Email:       wdb.Email,
+		JumlahTiket: wdb.JumlahTiket,
+		Status:      wdb.Status,
+		CreateTime:  wdb.CreateTime,
+	}
+
+	if wdb.Token != nil {
+		w.Token = *wdb.Token
+	}
+
+	if wdb.OfferExpireTime != nil {
+		w.OfferExpireTime = *wdb.OfferExpireTime
+	}
+
+	if wdb.UpdateTime != nil {
+		w.UpdateTime = *wdb.UpdateTime
+	}
+
+	return w
+}
+
+type institutionDB struct {
+	ID              int64      `db:"id"`
+	Nama            string     `db:"nama"`
+	MaxTiket        int        `db:"max_tiket"`
+	KuotaTiket      int        `db:"kuota_tiket"`
+	RequireApproval bool       `db:"require_approval"`
+	CreateTime      time.Time  `db:"create_time"`
+	UpdateTime      *time.Time `db:"update_time"`
+}
+
+// format formats database struct into domain struct.
+func (idb *institutionDB) format() mainevent.Institution {
+	i := mainevent.Institution{
+		ID:              idb.ID,
+		Nama:            idb.Nama,
+		MaxTiket:        idb.MaxTiket,
+		KuotaTiket:      idb.KuotaTiket,
+		RequireApproval: idb.RequireApproval,
+		CreateTime:      idb.CreateTime,
+	}
+
+	if idb.UpdateTime != nil {
+		i.UpdateTime = *idb.UpdateTime
+	}
+
+	return i
+}
+
+type groupOrderDB struct {
+	ID             int64                 `db:"id"`
+	MainEventID    *int64                `db:"mainevent_id"`
+	Nama           string                `db:"nama"`
+	NomorIdentitas string                `db:"nomor_identitas"`
+	AsalInstitusi  string                `db:"asal_institusi"`
+	Email          string                `db:"email"`
+	NomorTelepon   string                `db:"nomor_telepon"`
+	HargaTiket     int64                 `db:"harga_tiket"`
+	TotalHarga     int64                 `db:"total_harga"`
+	Status         mainevent.GroupStatus `db:"status"`
+	Catatan        *string               `db:"catatan"`
+	CreateTime     time.Time             `db:"create_time"`
+	UpdateTime     *time.Time            `db:"update_time"`
+}
+
+// format formats database struct into domain struct.
+func (gdb *groupOrderDB) format() mainevent.GroupOrder {
+	g := mainevent.GroupOrder{
+		ID:             gdb.ID,
+		Nama:           gdb.Nama,
+		NomorIdentitas: gdb.NomorIdentitas,
+		AsalInstitusi:  gdb.AsalInstitusi,
+		Email:          gdb.Email,
+		NomorTelepon:   gdb.NomorTelepon,
+		HargaTiket:     gdb.HargaTiket,
+		TotalHarga:     gdb.TotalHarga,
+		Status:         gdb.Status,
+		CreateTime:     gdb.CreateTime,
+	}
+
+	if gdb.MainEventID != nil {
+		g.MainEventID = *gdb.MainEventID
+	}
+
+	if gdb.Catatan != nil {
+		g.Catatan = *gdb.Catatan
+	}
+
+	if gdb.UpdateTime != nil {
+		g.UpdateTime = *gdb.UpdateTime
+	}
+
+	return g
+}
+
+type groupAttendeeDB struct {
+	GroupID        int64  `db:"group_id"`
+	Nama           string `db:"nama"`
+	Email          string `db:"email"`
+	NomorIdentitas string `db:"nomor_identitas"`
+}
+
+// format formats database struct into domain struct.
+func (adb *groupAttendeeDB) format() mainevent.GroupAttendee {
+	return mainevent.GroupAttendee{
+		Nama:           adb.Nama,
+		Email:          adb.Email,
+		NomorIdentitas: adb.NomorIdentitas,
+	}
+}
+
+// accommodationsArray returns the given accommodations as a
+// database array.
+func accommodationsArray(accommodations []mainevent.Accommodation) pq.StringArray {
+	arr := make(pq.StringArray, 0, len(accommodations))
+	for _, a := range accommodations {
+		arr = append(arr, a.String())
+	}
+	return arr
+}
+
+// nullString returns nil for the empty string, so that it is
+// stored as null.
+func nullString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+// isUniqueViolation returns whether the given error is a
+// unique constraint violation.
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23505"
+}
+
+type seatDB struct {
+	ID          int64      `db:"id"`
+	Section     string     `db:"section"`
+	Baris       string     `db:"baris"`
+	Nomor       int        `db:"nomor"`
+	Aksesibel   bool       `db:"aksesibel"`
+	MainEventID *int64     `db:"mainevent_id"`
+	NomorTiket  *string    `db:"nomor_tiket"`
+	CreateTime  time.Time  `db:"create_time"`
+	UpdateTime  *time.Time `db:"update_time"`
+}
+
+// format formats database struct into domain struct.
+func (sdb *seatDB) format() mainevent.Seat {
+	s := mainevent.Seat{
+		ID:         sdb.ID,
+		Section:    sdb.Section,
+		Baris:      sdb.Baris,
+		Nomor:      sdb.Nomor,
+		Aksesibel:  sdb.Aksesibel,
+		CreateTime: sdb.CreateTime,
+	}
+
+	if sdb.MainEventID != nil {
+		s.MainEventID = *sdb.MainEventID
+	}
+
+	if sdb.NomorTiket != nil {
+		s.NomorTiket = *sdb.NomorTiket
+	}
+
+	if sdb.UpdateTime != nil {
+		s.UpdateTime = *sdb.UpdateTime
+	}
+
+	return s
+}
+
+type buyerLoginDB struct {
+	ID                int64      `db:"id"`
+	Email             string     `db:"email"`
+	TokenHash         string     `db:"token_hash"`
+	ExpireTime        time.Time  `db:"expire_time"`
+	SessionTokenHash  *string    `db:"session_token_hash"`
+	SessionExpireTime *time.Time `db:"session_expire_time"`
+	CreateTime        time.Time  `db:"create_time"`
+	UseTime           *time.Time `db:"use_time"`
+}
+
+// format formats database struct into domain struct.
+func (bdb *buyerLoginDB) format() mainevent.BuyerLogin {
+	b := mainevent.BuyerLogin{
+		ID:         bdb.ID,
+		Email:      bdb.Email,
+		TokenHash:  bdb.TokenHash,
+		ExpireTime: bdb.ExpireTime,
+		CreateTime: bdb.CreateTime,
+	}
+
+	if bdb.SessionTokenHash != nil {
+		b.SessionTokenHash = *bdb.SessionTokenHash
+	}
+
+	if bdb.SessionExpireTime != nil {
+		b.SessionExpireTime = *bdb.SessionExpireTime
+	}
+
+	if bdb.UseTime != nil {
+		b.UseTime = *bdb.UseTime
+	}
+
+	return b
+}
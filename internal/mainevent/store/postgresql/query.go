@@ -4,6 +4,7 @@ const queryCreateMainEvent = `
 	INSERT INTO
 		mainevent
 	(
+		event_id,
 		nama,
 		disabilitas,
 		nomor_identitas,
@@ -12,11 +13,16 @@ const queryCreateMainEvent = `
 		nomor_telepon,
 		jumlah_tiket,
 		total_harga,
+		kode_promo,
+		diskon,
+		akomodasi,
+		catatan_akomodasi,
 		order_id,
 		type,
 		status,
 		create_time
 	) VALUES (
+		:event_id,
 		:nama,
 		:disabilitas,
 		:nomor_identitas,
@@ -25,6 +31,10 @@ const queryCreateMainEvent = `
 		:nomor_telepon,
 		:jumlah_tiket,
 		:total_harga,
+		:kode_promo,
+		:diskon,
+		:akomodasi,
+		:catatan_akomodasi,
 		:order_id,
 		:type,
 		:status,
@@ -36,6 +46,7 @@ const queryCreateMainEvent = `
 const queryGetMainEvent = `
 	SELECT
 		m.id,
+		m.event_id,
 		m.nama,
 		m.disabilitas,
 		m.nomor_identitas,
@@ -44,6 +55,10 @@ const queryGetMainEvent = `
 		m.nomor_telepon,
 		m.jumlah_tiket,
 		m.total_harga,
+		m.kode_promo,
+		m.diskon,
+		m.akomodasi,
+		m.catatan_akomodasi,
 		m.order_id,
 		m.type,
 		m.status,
@@ -51,13 +66,21 @@ const queryGetMainEvent = `
 		m.nomor_tiket,
 		m.checkin_status,
 		m.checkin_nomor_tiket,
+		m.refund_nomor_tiket,
 		m.create_time,
 		m.update_time
 	FROM
 		mainevent m
 	%s
-	ORDER BY 
-		m.create_time DESC
+`
+
+const queryCountMainEvent = `
+	SELECT
+		COUNT(*) AS total,
+		COALESCE(SUM(m.jumlah_tiket - COALESCE(cardinality(m.refund_nomor_tiket), 0)), 0) AS total_tickets
+	FROM
+		mainevent m
+	%s
 `
 
 const queryuDeleteMainEventByEmail = `
@@ -67,6 +90,16 @@ const queryuDeleteMainEventByEmail = `
 		email = :email
 	AND
 		status = :status
+	AND
+		type <> :group_type
+`
+
+const queryDeleteMainEventByID = `
+	DELETE FROM
+		mainevent
+	WHERE
+		id = :id AND
+		status = :status
 `
 const queryUpdateMainEvent = `
 	UPDATE
@@ -80,13 +113,525 @@ const queryUpdateMainEvent = `
 		nomor_telepon = :nomor_telepon,
 		jumlah_tiket = :jumlah_tiket,
 		total_harga = :total_harga,
+		akomodasi = :akomodasi,
+		catatan_akomodasi = :catatan_akomodasi,
 		order_id = :order_id,
 		status = :status,
 		image_uri = :image_uri,
-		nomor_tiket = ARRAY[:nomor_tiket],
 		checkin_status = :checkin_status,
 		update_time = :update_time
 		%s
+	WHERE
+		id = :id AND
+		update_time IS NOT DISTINCT FROM :expected_update_time
+`
+
+const queryCreateRefund = `
+	INSERT INTO
+		mainevent_refund
+	(
+		mainevent_id,
+		nomor_tiket,
+		alasan,
+		total_refund,
+		status,
+		create_time
+	) VALUES (
+		:mainevent_id,
+		ARRAY[:nomor_tiket],
+		:alasan,
+		:total_refund,
+		:status,
+		:create_time
+	) RETURNING
+		id
+`
+
+const queryGetRefund = `
+	SELECT
+		r.id,
+		r.mainevent_id,
+		r.nomor_tiket,
+		r.alasan,
+		r.total_refund,
+		r.status,
+		r.metode,
+		r.catatan,
+		r.create_time,
+		r.update_time
+	FROM
+		mainevent_refund r
+	%s
+`
+
+const queryUpdateRefund = `
+	UPDATE
+		mainevent_refund
+	SET
+		status = :status,
+		metode = :metode,
+		catatan = :catatan,
+		update_time = :update_time
+	WHERE
+		id = :id AND
+		status = :expected_status
+`
+
+const queryGetHolder = `
+	SELECT
+		h.mainevent_id,
+		h.nomor_tiket,
+		h.nama,
+		h.email,
+		h.nomor_identitas,
+		h.create_time,
+		h.update_time
+	FROM
+		mainevent_holder h
+	%s
+`
+
+const queryUpsertHolder = `
+	INSERT INTO
+		mainevent_holder
+	(
+		mainevent_id,
+		nomor_tiket,
+		nama,
+		email,
+		nomor_identitas,
+		create_time
+	) VALUES (
+		:mainevent_id,
+		:nomor_tiket,
+		:nama,
+		:email,
+		:nomor_identitas,
+		:create_time
+	)
+	ON CONFLICT (mainevent_id, nomor_tiket) DO UPDATE SET
+		nama = EXCLUDED.nama,
+		email = EXCLUDED.email,
+		nomor_identitas = EXCLUDED.nomor_identitas,
+		update_time = EXCLUDED.create_time
+`
+
+const queryCreateWaitlistEntry = `
+	INSERT INTO
+		mainevent_waitlist
+	(
+		nama,
+		email,
+		jumlah_tiket,
+		status,
+		create_time
+	) VALUES (
+		:nama,
+		:email,
+		:jumlah_tiket,
+		:status,
+		:create_time
+	) RETURNING
+		id
+`
+
+const queryGetWaitlistEntry = `
+	SELECT
+		w.id,
+		w.nama,
+		w.email,
+		w.jumlah_tiket,
+		w.status,
+		w.token,
+		w.offer_expire_time,
+		w.create_time,
+		w.update_time
+	FROM
+		mainevent_waitlist w
+	%s
+`
+
+const queryUpdateWaitlistEntry = `
+	UPDATE
+		mainevent_waitlist
+	SET
+		status = :status,
+		token = :token,
+		offer_expire_time = :offer_expire_time,
+		update_time = :update_time
+	WHERE
+		id = :id AND
+		status = :expected_status
+`
+
+const queryExpireWaitlistOffers = `
+	UPDATE
+		mainevent_waitlist
+	SET
+		status = :expired_status,
+		update_time = :update_time
+	WHERE
+		status = :offered_status AND
+		offer_expire_time <= :update_time
+	RETURNING
+		id,
+		nama,
+		email,
+		jumlah_tiket,
+		status,
+		token,
+		offer_expire_time,
+		create_time,
+		update_time
+`
+
+const queryCreateInstitution = `
+	INSERT INTO
+		mainevent_institution
+	(
+		nama,
+		max_tiket,
+		kuota_tiket,
+		require_approval,
+		create_time
+	) VALUES (
+		:nama,
+		:max_tiket,
+		:kuota_tiket,
+		:require_approval,
+		:create_time
+	) RETURNING
+		id
+`
+
+const queryGetInstitution = `
+	SELECT
+		i.id,
+		i.nama,
+		i.max_tiket,
+		i.kuota_tiket,
+		i.require_approval,
+		i.create_time,
+		i.update_time
+	FROM
+		mainevent_institution i
+	%s
+`
+
+const queryUpdateInstitution = `
+	UPDATE
+		mainevent_institution
+	SET
+		nama = :nama,
+		max_tiket = :max_tiket,
+		kuota_tiket = :kuota_tiket,
+		require_approval = :require_approval,
+		update_time = :update_time
+	WHERE
+		id = :id
+`
+
+const queryCreateGroupOrder = `
+	INSERT INTO
+		mainevent_group
+	(
+		nama,
+		nomor_identitas,
+		asal_institusi,
+		email,
+		nomor_telepon,
+		harga_tiket,
+		total_harga,
+		status,
+		create_time
+	) VALUES (
+		:nama,
+		:nomor_identitas,
+		:asal_institusi,
+		:email,
+		:nomor_telepon,
+		:harga_tiket,
+		:total_harga,
+		:status,
+		:create_time
+	) RETURNING
+		id
+`
+
+const queryCreateGroupAttendee = `
+	INSERT INTO
+		mainevent_group_attendee
+	(
+		group_id,
+		urutan,
+		nama,
+		email,
+		nomor_identitas
+	) VALUES (
+		:group_id,
+		:urutan,
+		:nama,
+		:email,
+		:nomor_identitas
+	)
+`
+
+const queryGetGroupOrder = `
+	SELECT
+		g.id,
+		g.mainevent_id,
+		g.nama,
+		g.nomor_identitas,
+		g.asal_institusi,
+		g.email,
+		g.nomor_telepon,
+		g.harga_tiket,
+		g.total_harga,
+		g.status,
+		g.catatan,
+		g.create_time,
+		g.update_time
+	FROM
+		mainevent_group g
+	%s
+`
+
+const queryGetGroupAttendees = `
+	SELECT
+		a.group_id,
+		a.nama,
+		a.email,
+		a.nomor_identitas
+	FROM
+		mainevent_group_attendee a
+	WHERE
+		a.group_id IN (:group_ids)
+	ORDER BY
+		a.group_id, a.urutan
+`
+
+const queryUpdateGroupOrder = `
+	UPDATE
+		mainevent_group
+	SET
+		mainevent_id = :mainevent_id,
+		status = :status,
+		catatan = :catatan,
+		update_time = :update_time
+	WHERE
+		id = :id AND
+		status = :expected_status
+`
+
+const queryCountGroupTickets = `
+	SELECT
+		COUNT(a.urutan)
+	FROM
+		mainevent_group g
+	JOIN
+		mainevent_group_attendee a ON a.group_id = g.id
+	WHERE
+		lower(g.asal_institusi) = lower(:asal_institusi) AND
+		g.status NOT IN (:closed_status)
+`
+
+const queryAdvisoryLock = `
+	SELECT pg_advisory_xact_lock(hashtext(:key))
+`
+
+const queryCountBuyerTickets = `
+	SELECT
+		COALESCE(SUM(b.jumlah_tiket) FILTER (WHERE lower(b.email) = lower(:email)), 0) AS email_tickets,
+		COALESCE(SUM(b.jumlah_tiket) FILTER (WHERE btrim(b.nomor_identitas) = :nomor_identitas), 0) AS identity_tickets
+	FROM (
+		SELECT
+			m.email,
+			m.nomor_identitas,
+			m.jumlah_tiket - COALESCE(cardinality(m.refund_nomor_tiket), 0) AS jumlah_tiket
+		FROM
+			mainevent m
+		WHERE
+			(lower(m.email) = lower(:email) OR btrim(m.nomor_identitas) = :nomor_identitas)
+		AND
+			m.event_id = :event_id
+		AND
+			m.type NOT IN (:group_type, :complimentary_type)
+		AND
+			NOT (m.email = :email AND m.status = :unpaid_status)
+		UNION ALL
+		SELECT
+			a.email,
+			a.nomor_identitas,
+			1 AS jumlah_tiket
+		FROM
+			mainevent_group_attendee a
+		JOIN
+			mainevent_group g ON g.id = a.group_id
+		JOIN
+			mainevent m ON m.id = g.mainevent_id
+		WHERE
+			(lower(a.email) = lower(:email) OR btrim(a.nomor_identitas) = :nomor_identitas)
+		AND
+			m.event_id = :event_id
+		AND
+			g.status = :approved_group_status
+	) b
+`
+
+const queryGetSeat = `
+	SELECT
+		s.id,
+		s.section,
+		s.baris,
+		s.nomor,
+		s.aksesibel,
+		s.mainevent_id,
+		s.nomor_tiket,
+		s.create_time,
+		s.update_time
+	FROM
+		mainevent_seat s
+	%s
+	ORDER BY
+		s.id
+	%s
+`
+
+const queryCreateSeat = `
+	INSERT INTO
+		mainevent_seat
+	(
+		section,
+		baris,
+		nomor,
+		aksesibel,
+		create_time
+	) VALUES (
+		:section,
+		:baris,
+		:nomor,
+		:aksesibel,
+		:create_time
+	)
+`
+
+const queryUpdateSeatAksesibel = `
+	UPDATE
+		mainevent_seat
+	SET
+		aksesibel = :aksesibel,
+		update_time = :update_time
 	WHERE
 		id = :id
 `
+
+const queryDeleteSeats = `
+	DELETE FROM
+		mainevent_seat
+	WHERE
+		id IN (:ids) AND
+		mainevent_id IS NULL
+`
+
+const queryHoldSeats = `
+	UPDATE
+		mainevent_seat
+	SET
+		mainevent_id = :mainevent_id,
+		update_time = :update_time
+	WHERE
+		id IN (:ids) AND
+		mainevent_id IS NULL AND
+		(NOT aksesibel OR :aksesibel)
+`
+
+const queryAssignSeat = `
+	UPDATE
+		mainevent_seat
+	SET
+		mainevent_id = :mainevent_id,
+		nomor_tiket = :nomor_tiket,
+		update_time = :update_time
+	WHERE
+		id = :id AND
+		nomor_tiket IS NULL AND
+		(mainevent_id IS NULL OR mainevent_id = :mainevent_id)
+`
+
+const queryDeleteHolders = `
+	DELETE FROM
+		mainevent_holder
+	WHERE
+		mainevent_id = :mainevent_id AND
+		nomor_tiket IN (:nomor_tiket)
+`
+
+const queryReleaseSeats = `
+	UPDATE
+		mainevent_seat
+	SET
+		mainevent_id = NULL,
+		nomor_tiket = NULL,
+		update_time = :update_time
+	WHERE
+		nomor_tiket IN (:nomor_tiket)
+`
+
+const queryCreateBuyerLogin = `
+	INSERT INTO
+		mainevent_buyer_login
+	(
+		email,
+		token_hash,
+		expire_time,
+		create_time
+	) VALUES (
+		:email,
+		:token_hash,
+		:expire_time,
+		:create_time
+	) RETURNING
+		id
+`
+
+const queryGetBuyerLogin = `
+	SELECT
+		b.id,
+		b.email,
+		b.token_hash,
+		b.expire_time,
+		b.session_token_hash,
+		b.session_expire_time,
+		b.create_time,
+		b.use_time
+	FROM
+		mainevent_buyer_login b
+	%s
+`
+
+const queryUseBuyerLogin = `
+	UPDATE
+		mainevent_buyer_login
+	SET
+		session_token_hash = :session_token_hash,
+		session_expire_time = :session_expire_time,
+		use_time = :use_time
+	WHERE
+		id = :id AND
+		use_time IS NULL
+`
+
+const queryCreateCheckIn = `
+	INSERT INTO
+		mainevent_checkin
+	(
+		mainevent_id,
+		nomor_tiket,
+		checkin_time
+	) VALUES (
+		:mainevent_id,
+		:nomor_tiket,
+		:checkin_time
+	)
+	ON CONFLICT (mainevent_id, nomor_tiket) DO NOTHING
+`
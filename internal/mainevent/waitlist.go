@@ -0,0 +1,58 @@
+package mainevent
+
+import "time"
+
+// WaitlistEntry is a buyer waiting for normal sale tickets
+// after they sold out.
+type WaitlistEntry struct {
+	ID          int64
+	Nama        string
+	Email       string
+	JumlahTiket int
+	Status      WaitlistStatus
+
+	// Token is the secret of the claim link sent with an
+	// offer, the offer is claimed by ordering with it before
+	// OfferExpireTime.
+	Token           string
+	OfferExpireTime time.Time
+
+	CreateTime time.Time
+	UpdateTime time.Time
+}
+
+// WaitlistStatus denotes status of a waitlist entry.
+type WaitlistStatus string
+
+// Followings are the known waitlist status.
+const (
+	WaitlistStatusUnknown WaitlistStatus = ""
+
+	// WaitlistStatusWaiting means the entry is in the queue.
+	WaitlistStatusWaiting WaitlistStatus = "waiting"
+
+	// WaitlistStatusOffered means the tickets are held for
+	// the entry until the offer expires.
+	WaitlistStatusOffered WaitlistStatus = "offered"
+
+	// WaitlistStatusClaimed means the entry has ordered the
+	// offered tickets.
+	WaitlistStatusClaimed WaitlistStatus = "claimed"
+
+	// WaitlistStatusExpired means the offer was not claimed
+	// in time and went to the next entry.
+	WaitlistStatusExpired WaitlistStatus = "expired"
+)
+
+// WaitlistStatusList is a list of valid waitlist status.
+var WaitlistStatusList = map[WaitlistStatus]struct{}{
+	WaitlistStatusWaiting: {},
+	WaitlistStatusOffered: {},
+	WaitlistStatusClaimed: {},
+	WaitlistStatusExpired: {},
+}
+
+// String returns string representaion of a waitlist status.
+func (s WaitlistStatus) String() string {
+	return string(s)
+}
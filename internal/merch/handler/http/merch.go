@@ -3,11 +3,11 @@ package http
 import (
 	"context"
 	"encoding/json"
-	"log"
 	"net/http"
 	"time"
 
 	"github.com/tedxub2023/global/helper"
+	"github.com/tedxub2023/global/logger"
 )
 
 type merchHandler struct {
@@ -28,7 +28,6 @@ func (h *merchHandler) handleGetAllMerch(w http.ResponseWriter, r *http.Request)
 
 	var (
 		err        error           // stores error in this handler
-		source     string          // stores request source
 		resBody    []byte          // stores response body to write
 		statusCode = http.StatusOK // stores response status code
 	)
@@ -37,7 +36,7 @@ func (h *merchHandler) handleGetAllMerch(w http.ResponseWriter, r *http.Request)
 	defer func() {
 		// error
 		if err != nil {
-			log.Printf("[Merch HTTP][handleGetAllMerch] Failed to get merch. Source: %s, Err: %s\n", source, err.Error())
+			logger.Error(ctx, "failed to get merch", err)
 			helper.WriteErrorResponse(w, statusCode, []string{err.Error()})
 			return
 		}
@@ -3,11 +3,11 @@ package http
 import (
 	"context"
 	"encoding/json"
-	"log"
 	"net/http"
 	"time"
 
 	"github.com/tedxub2023/global/helper"
+	"github.com/tedxub2023/global/logger"
 )
 
 type ourteamsHandler struct {
@@ -28,7 +28,6 @@ func (h *ourteamsHandler) handleGetAllOurTeams(w http.ResponseWriter, r *http.Re
 
 	var (
 		err        error           // stores error in this handler
-		source     string          // stores request source
 		resBody    []byte          // stores response body to write
 		statusCode = http.StatusOK // stores response status code
 	)
@@ -37,7 +36,7 @@ func (h *ourteamsHandler) handleGetAllOurTeams(w http.ResponseWriter, r *http.Re
 	defer func() {
 		// error
 		if err != nil {
-			log.Printf("[OurTeam HTTP][handleGetAllOurTeams] Failed to get our team. Source: %s, Err: %s\n", source, err.Error())
+			logger.Error(ctx, "failed to get our team", err)
 			helper.WriteErrorResponse(w, statusCode, []string{err.Error()})
 			return
 		}
@@ -0,0 +1,60 @@
+package promo
+
+import "errors"
+
+// Followings are the known errors returned from promo.
+var (
+	// ErrDataNotFound is returned when the wanted data is
+	// not found.
+	ErrDataNotFound = errors.New("data not found")
+
+	// ErrInvalidPromoID is returned when the given promo id
+	// is invalid.
+	ErrInvalidPromoID = errors.New("invalid promo id")
+
+	// ErrInvalidPromoKode is returned when the given promo
+	// code is empty or contains other than letters, digits,
+	// dashes and underscores.
+	ErrInvalidPromoKode = errors.New("invalid promo kode")
+
+	// ErrInvalidPromoDiscount is returned when the given
+	// discount type is unknown, or its value is not positive
+	// or above 100 percent.
+	ErrInvalidPromoDiscount = errors.New("invalid promo discount")
+
+	// ErrInvalidPromoLimit is returned when the given usage
+	// limits are negative.
+	ErrInvalidPromoLimit = errors.New("invalid promo limit")
+
+	// ErrInvalidPromoType is returned when the given sale
+	// phases are invalid.
+	ErrInvalidPromoType = errors.New("invalid promo type")
+
+	// ErrInvalidPromoWindow is returned when the given
+	// validity window ends before it starts.
+	ErrInvalidPromoWindow = errors.New("invalid promo window")
+
+	// ErrPromoKodeExists is returned when the given promo
+	// code is already used by another promo.
+	ErrPromoKodeExists = errors.New("promo kode exists")
+
+	// ErrPromoNotFound is returned when the promo code of an
+	// order is unknown.
+	ErrPromoNotFound = errors.New("promo not found")
+
+	// ErrPromoNotActive is returned when the promo code of an
+	// order is used outside of its validity window.
+	ErrPromoNotActive = errors.New("promo not active")
+
+	// ErrPromoNotApplicable is returned when the promo code
+	// of an order is not valid for its sale phase.
+	ErrPromoNotApplicable = errors.New("promo not applicable")
+
+	// ErrPromoUsageLimitReached is returned when the promo
+	// code has been used for as many orders as allowed.
+	ErrPromoUsageLimitReached = errors.New("promo usage limit reached")
+
+	// ErrPromoEmailLimitReached is returned when the buyer
+	// has used the promo code as many times as allowed.
+	ErrPromoEmailLimitReached = errors.New("promo email limit reached")
+)
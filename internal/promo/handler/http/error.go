@@ -0,0 +1,77 @@
+package http
+
+import (
+	"errors"
+
+	"github.com/tedxub2023/internal/promo"
+)
+
+// Followings are the known errors from Promo HTTP handlers.
+var (
+	// errBadRequest is returned when the given request is
+	// bad/invalid.
+	errBadRequest = errors.New("BAD_REQUEST")
+
+	// errInternalServer is returned when there is an
+	// unexpected error encountered when processing a request.
+	errInternalServer = errors.New("INTERNAL_SERVER_ERROR")
+
+	// errDataNotFound is returned when the desired data is
+	// not found.
+	errDataNotFound = errors.New("DATA_NOT_FOUND")
+
+	// errMethodNotAllowed is returned when accessing not
+	// allowed HTTP method.
+	errMethodNotAllowed = errors.New("METHOD_NOT_ALLOWED")
+
+	// errRequestTimeout is returned when processing time
+	// has reached the timeout limit.
+	errRequestTimeout = errors.New("REQUEST_TIMEOUT")
+
+	// errInvalidPromoID is returned when the given promo id
+	// is invalid.
+	errInvalidPromoID = errors.New("INVALID_PROMO_ID")
+
+	// errInvalidPromoKode is returned when the given promo
+	// code is invalid.
+	errInvalidPromoKode = errors.New("INVALID_PROMO_KODE")
+
+	// errInvalidPromoDiscount is returned when the given
+	// discount is invalid.
+	errInvalidPromoDiscount = errors.New("INVALID_PROMO_DISCOUNT")
+
+	// errInvalidPromoLimit is returned when the given usage
+	// limits are invalid.
+	errInvalidPromoLimit = errors.New("INVALID_PROMO_LIMIT")
+
+	// errInvalidPromoType is returned when the given sale
+	// phases are invalid.
+	errInvalidPromoType = errors.New("INVALID_PROMO_TYPE")
+
+	// errInvalidPromoWindow is returned when the given
+	// validity window is invalid.
+	errInvalidPromoWindow = errors.New("INVALID_PROMO_WINDOW")
+
+	// errPromoKodeExists is returned when the given promo
+	// code is taken.
+	errPromoKodeExists = errors.New("PROMO_KODE_EXISTS")
+)
+
+var (
+	// mapHTTPError maps service error into HTTP error that
+	// categorize as bad request error.
+	//
+	// Internal server error-related should not be mapped
+	// here, and the handler should just return `errInternal`
+	// as the error instead
+	mapHTTPError = map[error]error{
+		promo.ErrDataNotFound:         errDataNotFound,
+		promo.ErrInvalidPromoID:       errInvalidPromoID,
+		promo.ErrInvalidPromoKode:     errInvalidPromoKode,
+		promo.ErrInvalidPromoDiscount: errInvalidPromoDiscount,
+		promo.ErrInvalidPromoLimit:    errInvalidPromoLimit,
+		promo.ErrInvalidPromoType:     errInvalidPromoType,
+		promo.ErrInvalidPromoWindow:   errInvalidPromoWindow,
+		promo.ErrPromoKodeExists:      errPromoKodeExists,
+	}
+)
@@ -0,0 +1,116 @@
+package http
+
+import (
+	"github.com/tedxub2023/internal/mainevent"
+	"github.com/tedxub2023/internal/promo"
+)
+
+// formatPromo formats the given promo into the respective
+// HTTP-format object.
+func formatPromo(p promo.Promo) promoHTTP {
+	jenis := p.Jenis.String()
+	types := make([]string, 0, len(p.Types))
+	for _, t := range p.Types {
+		types = append(types, t.String())
+	}
+
+	result := promoHTTP{
+		ID:            &p.ID,
+		Kode:          &p.Kode,
+		Jenis:         &jenis,
+		Nilai:         &p.Nilai,
+		Kuota:         &p.Kuota,
+		KuotaPerEmail: &p.KuotaPerEmail,
+		Types:         &types,
+		Partner:       &p.Partner,
+		CreateTime:    &p.CreateTime,
+	}
+
+	if !p.MulaiTime.IsZero() {
+		result.MulaiTime = &p.MulaiTime
+	}
+
+	if !p.SelesaiTime.IsZero() {
+		result.SelesaiTime = &p.SelesaiTime
+	}
+
+	if !p.UpdateTime.IsZero() {
+		result.UpdateTime = &p.UpdateTime
+	}
+
+	return result
+}
+
+// parsePromoFromRequest returns promo from the given HTTP
+// request object.
+func parsePromoFromRequest(ph promoHTTP) (promo.Promo, error) {
+	result := promo.Promo{}
+
+	if ph.Kode != nil {
+		result.Kode = *ph.Kode
+	}
+
+	if ph.Jenis != nil {
+		result.Jenis = promo.DiscountType(*ph.Jenis)
+	}
+
+	if ph.Nilai != nil {
+		result.Nilai = *ph.Nilai
+	}
+
+	if ph.Kuota != nil {
+		result.Kuota = *ph.Kuota
+	}
+
+	if ph.KuotaPerEmail != nil {
+		result.KuotaPerEmail = *ph.KuotaPerEmail
+	}
+
+	if ph.Types != nil {
+		for _, name := range *ph.Types {
+			t, err := parseType(name)
+			if err != nil {
+				return promo.Promo{}, err
+			}
+			result.Types = append(result.Types, t)
+		}
+	}
+
+	if ph.Partner != nil {
+		result.Partner = *ph.Partner
+	}
+
+	if ph.MulaiTime != nil {
+		result.MulaiTime = *ph.MulaiTime
+	}
+
+	if ph.SelesaiTime != nil {
+		result.SelesaiTime = *ph.SelesaiTime
+	}
+
+	return result, nil
+}
+
+// parseType parses the name of a mainevent sale phase.
+func parseType(name string) (mainevent.Type, error) {
+	for t := range mainevent.TypeList {
+		if t.String() == name {
+			return t, nil
+		}
+	}
+	return mainevent.TypeUnknown, errInvalidPromoType
+}
+
+// formatReport formats the given report into the respective
+// HTTP-format object.
+func formatReport(r promo.Report) reportHTTP {
+	return reportHTTP{
+		PromoID:         r.PromoID,
+		Kode:            r.Kode,
+		Partner:         r.Partner,
+		TotalRedemption: r.TotalRedemption,
+		TotalTiket:      r.TotalTiket,
+		TotalDiskon:     r.TotalDiskon,
+		TotalHarga:      r.TotalHarga,
+	}
+}
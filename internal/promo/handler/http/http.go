@@ -0,0 +1,140 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/tedxub2023/internal/promo"
+)
+
+var (
+	errUnknownConfig = errors.New("unknown config name")
+)
+
+// Handler contains promo HTTP-handlers.
+type Handler struct {
+	handlers map[string]*handler
+	promo    promo.Service
+}
+
+// handler is the HTTP handler wrapper.
+type handler struct {
+	h        http.Handler
+	identity HandlerIdentity
+}
+
+// HandlerIdentity denotes the identity of an HTTP hanlder.
+type HandlerIdentity struct {
+	Name string
+	URL  string
+}
+
+// Followings are the known HTTP handler identities
+var (
+	// HandlerPromos denotes HTTP handler for the committee
+	// to create and list the promo codes
+	HandlerPromos = HandlerIdentity{
+		Name: "promos",
+		URL:  "/promos",
+	}
+
+	// HandlerPromo denotes HTTP handler for the committee to
+	// update a promo code
+	HandlerPromo = HandlerIdentity{
+		Name: "promo",
+		URL:  "/promos/{id:[0-9]+}",
+	}
+
+	// HandlerPromoReport denotes HTTP handler of the
+	// redemptions per promo code for the sponsorship report
+	HandlerPromoReport = HandlerIdentity{
+		Name: "promo_report",
+		URL:  "/promos/report",
+	}
+)
+
+// New creates a new Handler.
+func New(promo promo.Service, identities []HandlerIdentity) (*Handler, error) {
+	h := &Handler{
+		handlers: make(map[string]*handler),
+		promo:    promo,
+	}
+
+	// apply options
+	for _, identity := range identities {
+		if h.handlers == nil {
+			h.handlers = map[string]*handler{}
+		}
+
+		h.handlers[identity.Name] = &handler{
+			identity: identity,
+		}
+
+		handler, err := h.createHTTPHandler(identity.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		h.handlers[identity.Name].h = handler
+	}
+
+	return h, nil
+}
+
+// createHTTPHandler creates a new HTTP handler that
+// implements http.Handler.
+func (h *Handler) createHTTPHandler(configName string) (http.Handler, error) {
+	var httpHandler http.Handler
+	switch configName {
+	case HandlerPromos.Name:
+		httpHandler = &promosHandler{
+			promo: h.promo,
+		}
+	case HandlerPromo.Name:
+		httpHandler = &promoHandler{
+			promo: h.promo,
+		}
+	case HandlerPromoReport.Name:
+		httpHandler = &promoReportHandler{
+			promo: h.promo,
+		}
+	default:
+		return httpHandler, errUnknownConfig
+	}
+	return httpHandler, nil
+}
+
+// Start starts all HTTP handlers.
+func (h *Handler) Start(multiplexer *mux.Router) error {
+	for _, handler := range h.handlers {
+		multiplexer.Handle(handler.identity.URL, handler.h)
+	}
+	return nil
+}
+
+type promoHTTP struct {
+	ID            *int64     `json:"id"`
+	Kode          *string    `json:"kode"`
+	Jenis         *string    `json:"jenis"`
+	Nilai         *int64     `json:"nilai"`
+	Kuota         *int       `json:"kuota"`
+	KuotaPerEmail *int       `json:"kuota_per_email"`
+	Types         *[]string  `json:"types"`
+	Partner       *string    `json:"partner"`
+	MulaiTime     *time.Time `json:"mulai_time"`
+	SelesaiTime   *time.Time `json:"selesai_time"`
+	CreateTime    *time.Time `json:"create_time,omitempty"`
+	UpdateTime    *time.Time `json:"update_time,omitempty"`
+}
+
+type reportHTTP struct {
+	PromoID         int64  `json:"promo_id"`
+	Kode            string `json:"kode"`
+	Partner         string `json:"partner"`
+	TotalRedemption int64  `json:"total_redemption"`
+	TotalTiket      int64  `json:"total_tiket"`
+	TotalDiskon     int64  `json:"total_diskon"`
+	TotalHarga      int64  `json:"total_harga"`
+}
@@ -0,0 +1,201 @@
+package promo
+
+import (
+	"context"
+	"time"
+
+	"github.com/tedxub2023/internal/mainevent"
+)
+
+type Service interface {
+	// CreatePromo creates a new promo code and returns the
+	// created promo ID.
+	CreatePromo(ctx context.Context, promo Promo) (int64, error)
+
+	// GetAllPromos returns all promo codes.
+	GetAllPromos(ctx context.Context) ([]Promo, error)
+
+	// UpdatePromo updates the rules of a promo code, its code
+	// can not be changed.
+	UpdatePromo(ctx context.Context, promo Promo) error
+
+	// Redeem applies the promo code of the given redemption
+	// to an order and records the redemption. It returns the
+	// redemption with its discount, or an error if the code
+	// can not be used for the order.
+	Redeem(ctx context.Context, redemption Redemption) (Redemption, error)
+
+	// CancelRedemption releases the redemption of the order
+	// with the given order ID, e.g. when the order expires,
+	// so that it no longer counts towards the limits.
+	CancelRedemption(ctx context.Context, orderID string) error
+
+	// GetReport returns the redemptions of every promo code
+	// summed up, for the sponsorship report.
+	GetReport(ctx context.Context) ([]Report, error)
+}
+
+// Promo is a promo code giving a discount on the orders.
+type Promo struct {
+	ID   int64
+	Kode string
+
+	// Jenis and Nilai are the discount, either a percentage
+	// of the order price or a fixed amount off the order.
+	Jenis DiscountType
+	Nilai int64
+
+	// Kuota is the number of orders the code may be used
+	// for, and KuotaPerEmail the number of orders per buyer.
+	// Zero means no limit.
+	Kuota         int
+	KuotaPerEmail int
+
+	// Types are the sale phases the code may be used for,
+	// all phases if it is empty.
+	Types []mainevent.Type
+
+	// Partner is the partner or community the code is given
+	// to, the redemptions are attributed to them.
+	Partner string
+
+	// MulaiTime and SelesaiTime bound the validity window
+	// [MulaiTime, SelesaiTime). Zero means no bound.
+	MulaiTime   time.Time
+	SelesaiTime time.Time
+
+	CreateTime time.Time
+	UpdateTime time.Time
+}
+
+// Discount returns the discount of the promo for an order
+// with the given price. A fixed discount never exceeds the
+// price.
+func (p Promo) Discount(subtotal int64) int64 {
+	var discount int64
+	switch p.Jenis {
+	case DiscountTypePercentage:
+		discount = subtotal * p.Nilai / 100
+	case DiscountTypeFixed:
+		discount = p.Nilai
+	}
+
+	if discount > subtotal {
+		return subtotal
+	}
+	return discount
+}
+
+// AppliesTo returns whether the promo may be used for the
+// given sale phase.
+func (p Promo) AppliesTo(t mainevent.Type) bool {
+	if len(p.Types) == 0 {
+		return true
+	}
+	for _, pt := range p.Types {
+		if pt == t {
+			return true
+		}
+	}
+	return false
+}
+
+// ActiveAt returns whether the given time is within the
+// validity window of the promo.
+func (p Promo) ActiveAt(t time.Time) bool {
+	if !p.MulaiTime.IsZero() && t.Before(p.MulaiTime) {
+		return false
+	}
+	if !p.SelesaiTime.IsZero() && !t.Before(p.SelesaiTime) {
+		return false
+	}
+	return true
+}
+
+// DiscountType denotes type of a discount.
+type DiscountType string
+
+// Followings are the known discount types.
+const (
+	DiscountTypeUnknown DiscountType = ""
+
+	// DiscountTypePercentage takes Nilai percent off the
+	// order price.
+	DiscountTypePercentage DiscountType = "percentage"
+
+	// DiscountTypeFixed takes Nilai rupiah off the order
+	// price.
+	DiscountTypeFixed DiscountType = "fixed"
+)
+
+// DiscountTypeList is a list of valid discount types.
+var DiscountTypeList = map[DiscountType]struct{}{
+	DiscountTypePercentage: {},
+	DiscountTypeFixed:      {},
+}
+
+// String returns string representaion of a discount type.
+func (t DiscountType) String() string {
+	return string(t)
+}
+
+// Redemption is a use of a promo code for an order.
+type Redemption struct {
+	ID          int64
+	PromoID     int64
+	Kode        string
+	OrderID     string
+	Email       string
+	Type        mainevent.Type
+	JumlahTiket int
+
+	// Subtotal is the order price before the discount.
+	Subtotal int64
+	Diskon   int64
+
+	// Replaces are the order IDs of the unpaid orders the
+	// order replaces. Their redemptions do not count towards
+	// the limits, the caller cancels them once the order is
+	// stored.
+	Replaces []string
+
+	Status     RedemptionStatus
+	CreateTime time.Time
+	UpdateTime time.Time
+}
+
+// RedemptionStatus denotes status of a redemption.
+type RedemptionStatus string
+
+// Followings are the known redemption status.
+const (
+	RedemptionStatusUnknown RedemptionStatus = ""
+
+	// RedemptionStatusRedeemed means the code is used by an
+	// existing order.
+	RedemptionStatusRedeemed RedemptionStatus = "redeemed"
+
+	// RedemptionStatusCancelled means the order is gone, e.g.
+	// expired, and the code use is released.
+	RedemptionStatusCancelled RedemptionStatus = "cancelled"
+)
+
+// String returns string representaion of a redemption
+// status.
+func (s RedemptionStatus) String() string {
+	return string(s)
+}
+
+// Report is the summary of the redemptions of a promo code.
+type Report struct {
+	PromoID         int64
+	Kode            string
+	Partner         string
+	TotalRedemption int64
+	TotalTiket      int64
+	TotalDiskon     int64
+
+	// TotalHarga is the price paid by the orders using the
+	// code, after the discount.
+	TotalHarga int64
+}
@@ -0,0 +1,226 @@
+package service
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	"github.com/tedxub2023/global/logger"
+	"github.com/tedxub2023/internal/mainevent"
+	"github.com/tedxub2023/internal/promo"
+)
+
+// kodePattern is the pattern of a promo code.
+var kodePattern = regexp.MustCompile(`^[A-Z0-9_-]{3,32}$`)
+
+func (s *service) CreatePromo(ctx context.Context, reqPromo promo.Promo) (int64, error) {
+	reqPromo.Kode = normalizeKode(reqPromo.Kode)
+	reqPromo.Partner = strings.TrimSpace(reqPromo.Partner)
+
+	// validate field
+	err := validatePromo(reqPromo)
+	if err != nil {
+		return 0, err
+	}
+
+	// get pg store client without using transaction
+	pgStoreClient, err := s.pgStore.NewClient(false)
+	if err != nil {
+		return 0, err
+	}
+
+	reqPromo.CreateTime = s.timeNow()
+
+	promoID, err := pgStoreClient.CreatePromo(ctx, reqPromo)
+	if err != nil {
+		return 0, err
+	}
+	logger.Info(ctx, "promo created", logger.Fields{"promo_id": promoID, "kode": reqPromo.Kode})
+
+	return promoID, nil
+}
+
+func (s *service) GetAllPromos(ctx context.Context) ([]promo.Promo, error) {
+	// get pg store client without using transaction
+	pgStoreClient, err := s.pgStore.NewClient(false)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgStoreClient.GetAllPromos(ctx)
+}
+
+func (s *service) UpdatePromo(ctx context.Context, reqPromo promo.Promo) error {
+	// validate id
+	if reqPromo.ID <= 0 {
+		return promo.ErrInvalidPromoID
+	}
+
+	// get pg store client without using transaction
+	pgStoreClient, err := s.pgStore.NewClient(false)
+	if err != nil {
+		return err
+	}
+
+	current, err := pgStoreClient.GetPromoByID(ctx, reqPromo.ID)
+	if err != nil {
+		return err
+	}
+
+	// the code is printed on the partner materials, it is
+	// never changed
+	reqPromo.Kode = current.Kode
+	reqPromo.Partner = strings.TrimSpace(reqPromo.Partner)
+
+	err = validatePromo(reqPromo)
+	if err != nil {
+		return err
+	}
+
+	reqPromo.UpdateTime = s.timeNow()
+
+	return pgStoreClient.UpdatePromo(ctx, reqPromo)
+}
+
+func (s *service) Redeem(ctx context.Context, reqRedemption promo.Redemption) (_ promo.Redemption, err error) {
+	reqRedemption.Kode = normalizeKode(reqRedemption.Kode)
+	if reqRedemption.Kode == "" {
+		return promo.Redemption{}, promo.ErrPromoNotFound
+	}
+
+	pgStoreClient, err := s.pgStore.NewClient(true)
+	if err != nil {
+		return promo.Redemption{}, err
+	}
+
+	// rollback just before return if error
+	defer func() {
+		if err != nil {
+			errTx := pgStoreClient.Rollback()
+			if errTx != nil {
+				// return err from rollback
+				err = errTx
+			}
+		}
+	}()
+
+	p, err := pgStoreClient.GetPromoByKode(ctx, reqRedemption.Kode)
+	if err == promo.ErrDataNotFound {
+		return promo.Redemption{}, promo.ErrPromoNotFound
+	}
+	if err != nil {
+		return promo.Redemption{}, err
+	}
+	logger.AddFields(ctx, logger.Fields{"promo_id": p.ID})
+
+	now := s.timeNow()
+	if !p.ActiveAt(now) {
+		return promo.Redemption{}, promo.ErrPromoNotActive
+	}
+	if !p.AppliesTo(reqRedemption.Type) {
+		return promo.Redemption{}, promo.ErrPromoNotApplicable
+	}
+
+	if p.Kuota > 0 {
+		used, err := pgStoreClient.CountRedemptions(ctx, p.ID, "", reqRedemption.Replaces)
+		if err != nil {
+			return promo.Redemption{}, err
+		}
+		if used >= p.Kuota {
+			return promo.Redemption{}, promo.ErrPromoUsageLimitReached
+		}
+	}
+
+	if p.KuotaPerEmail > 0 {
+		used, err := pgStoreClient.CountRedemptions(ctx, p.ID, reqRedemption.Email, reqRedemption.Replaces)
+		if err != nil {
+			return promo.Redemption{}, err
+		}
+		if used >= p.KuotaPerEmail {
+			return promo.Redemption{}, promo.ErrPromoEmailLimitReached
+		}
+	}
+
+	reqRedemption.PromoID = p.ID
+	reqRedemption.Diskon = p.Discount(reqRedemption.Subtotal)
+	reqRedemption.Status = promo.RedemptionStatusRedeemed
+	reqRedemption.CreateTime = now
+
+	reqRedemption.ID, err = pgStoreClient.CreateRedemption(ctx, reqRedemption)
+	if err != nil {
+		return promo.Redemption{}, err
+	}
+
+	// commit changes
+	err = pgStoreClient.Commit()
+	if err != nil {
+		return promo.Redemption{}, err
+	}
+
+	logger.Info(ctx, "promo redeemed", logger.Fields{"kode": p.Kode, "diskon": reqRedemption.Diskon})
+
+	return reqRedemption, nil
+}
+
+func (s *service) CancelRedemption(ctx context.Context, orderID string) error {
+	// get pg store client without using transaction
+	pgStoreClient, err := s.pgStore.NewClient(false)
+	if err != nil {
+		return err
+	}
+
+	return pgStoreClient.CancelRedemptionsByOrderID(ctx, orderID, s.timeNow())
+}
+
+func (s *service) GetReport(ctx context.Context) ([]promo.Report, error) {
+	// get pg store client without using transaction
+	pgStoreClient, err := s.pgStore.NewClient(false)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgStoreClient.GetReport(ctx)
+}
+
+// normalizeKode returns the given promo code in its stored
+// form, the codes are case-insensitive.
+func normalizeKode(kode string) string {
+	return strings.ToUpper(strings.TrimSpace(kode))
+}
+
+// validatePromo validates fields of the given promo whether
+// its comply the predetermined rules.
+func validatePromo(p promo.Promo) error {
+	if !kodePattern.MatchString(p.Kode) {
+		return promo.ErrInvalidPromoKode
+	}
+
+	switch p.Jenis {
+	case promo.DiscountTypePercentage:
+		if p.Nilai <= 0 || p.Nilai > 100 {
+			return promo.ErrInvalidPromoDiscount
+		}
+	case promo.DiscountTypeFixed:
+		if p.Nilai <= 0 {
+			return promo.ErrInvalidPromoDiscount
+		}
+	default:
+		return promo.ErrInvalidPromoDiscount
+	}
+
+	if p.Kuota < 0 || p.KuotaPerEmail < 0 {
+		return promo.ErrInvalidPromoLimit
+	}
+
+	for _, t := range p.Types {
+		if _, ok := mainevent.TypeList[t]; !ok {
+			return promo.ErrInvalidPromoType
+		}
+	}
+
+	if !p.MulaiTime.IsZero() && !p.SelesaiTime.IsZero() && !p.MulaiTime.Before(p.SelesaiTime) {
+		return promo.ErrInvalidPromoWindow
+	}
+
+	return nil
+}
@@ -0,0 +1,57 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/tedxub2023/internal/promo"
+)
+
+// PGStore is the PostgreSQL store for promo service.
+type PGStore interface {
+	NewClient(useTx bool) (PGStoreClient, error)
+}
+
+type PGStoreClient interface {
+	// Commit commits the transaction.
+	Commit() error
+
+	// Rollback aborts the transaction.
+	Rollback() error
+
+	// CreatePromo creates a new promo and returns the created
+	// promo ID. It returns promo.ErrPromoKodeExists if the
+	// code is taken.
+	CreatePromo(ctx context.Context, promo promo.Promo) (int64, error)
+
+	// GetAllPromos returns all promos, newest first.
+	GetAllPromos(ctx context.Context) ([]promo.Promo, error)
+
+	// GetPromoByID returns a promo with the given promo ID.
+	GetPromoByID(ctx context.Context, promoID int64) (promo.Promo, error)
+
+	// GetPromoByKode returns a promo with the given code,
+	// locking it until the end of the transaction so that
+	// its limits are checked one order at a time.
+	GetPromoByKode(ctx context.Context, kode string) (promo.Promo, error)
+
+	// UpdatePromo updates the rules of a promo.
+	UpdatePromo(ctx context.Context, promo promo.Promo) error
+
+	// CountRedemptions returns the number of redeemed
+	// redemptions of the given promo, only of the given email
+	// if it is not empty, besides those of the given orders.
+	CountRedemptions(ctx context.Context, promoID int64, email string, excludeOrderIDs []string) (int, error)
+
+	// CreateRedemption creates a new redemption and returns
+	// the created redemption ID.
+	CreateRedemption(ctx context.Context, redemption promo.Redemption) (int64, error)
+
+	// CancelRedemptionsByOrderID cancels the redeemed
+	// redemptions of the given order.
+	CancelRedemptionsByOrderID(ctx context.Context, orderID string, updateTime time.Time) error
+
+	// GetReport returns the redeemed redemptions summed up
+	// per promo.
+	GetReport(ctx context.Context) ([]promo.Report, error)
+}
@@ -0,0 +1,279 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/tedxub2023/internal/promo"
+)
+
+func (sc *storeClient) CreatePromo(ctx context.Context, reqPromo promo.Promo) (int64, error) {
+	// construct arguments filled with fields for the query
+	argsKV := map[string]interface{}{
+		"kode":            reqPromo.Kode,
+		"jenis":           reqPromo.Jenis,
+		"nilai":           reqPromo.Nilai,
+		"kuota":           reqPromo.Kuota,
+		"kuota_per_email": reqPromo.KuotaPerEmail,
+		"types":           typesArray(reqPromo.Types),
+		"partner":         reqPromo.Partner,
+		"mulai_time":      nullTime(reqPromo.MulaiTime),
+		"selesai_time":    nullTime(reqPromo.SelesaiTime),
+		"create_time":     reqPromo.CreateTime,
+	}
+
+	// prepare query
+	query, args, err := sqlx.Named(queryCreatePromo, argsKV)
+	if err != nil {
+		return 0, err
+	}
+	query = sc.q.Rebind(query)
+
+	// execute query
+	var promoID int64
+	err = sc.q.QueryRowxContext(ctx, query, args...).Scan(&promoID)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return 0, promo.ErrPromoKodeExists
+		}
+		return 0, err
+	}
+
+	return promoID, nil
+}
+
+func (sc *storeClient) GetAllPromos(ctx context.Context) ([]promo.Promo, error) {
+	query := fmt.Sprintf(queryGetPromo, "ORDER BY p.create_time DESC, p.id DESC")
+
+	// query to database
+	rows, err := sc.q.QueryxContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	// read promos
+	result := make([]promo.Promo, 0)
+	for rows.Next() {
+		var row promoDB
+		err = rows.StructScan(&row)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, row.format())
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (sc *storeClient) GetPromoByID(ctx context.Context, promoID int64) (promo.Promo, error) {
+	query := fmt.Sprintf(queryGetPromo, "WHERE p.id = $1")
+
+	// query single row
+	var pdb promoDB
+	err := sc.q.QueryRowxContext(ctx, query, promoID).StructScan(&pdb)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return promo.Promo{}, promo.ErrDataNotFound
+		}
+		return promo.Promo{}, err
+	}
+
+	return pdb.format(), nil
+}
+
+func (sc *storeClient) GetPromoByKode(ctx context.Context, kode string) (promo.Promo, error) {
+	// lock the promo within the transaction, so that two
+	// orders never take its last use
+	query := fmt.Sprintf(queryGetPromo, "WHERE p.kode = $1 FOR UPDATE")
+
+	// query single row
+	var pdb promoDB
+	err := sc.q.QueryRowxContext(ctx, query, kode).StructScan(&pdb)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return promo.Promo{}, promo.ErrDataNotFound
+		}
+		return promo.Promo{}, err
+	}
+
+	return pdb.format(), nil
+}
+
+func (sc *storeClient) UpdatePromo(ctx context.Context, reqPromo promo.Promo) error {
+	// construct arguments filled with fields for the query
+	argsKV := map[string]interface{}{
+		"jenis":           reqPromo.Jenis,
+		"nilai":           reqPromo.Nilai,
+		"kuota":           reqPromo.Kuota,
+		"kuota_per_email": reqPromo.KuotaPerEmail,
+		"types":           typesArray(reqPromo.Types),
+		"partner":         reqPromo.Partner,
+		"mulai_time":      nullTime(reqPromo.MulaiTime),
+		"selesai_time":    nullTime(reqPromo.SelesaiTime),
+		"update_time":     reqPromo.UpdateTime,
+		"id":              reqPromo.ID,
+	}
+
+	// prepare query
+	query, args, err := sqlx.Named(queryUpdatePromo, argsKV)
+	if err != nil {
+		return err
+	}
+	query = sc.q.Rebind(query)
+
+	// execute query
+	res, err := sc.q.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return promo.ErrDataNotFound
+	}
+
+	return nil
+}
+
+func (sc *storeClient) CountRedemptions(ctx context.Context, promoID int64, email string, excludeOrderIDs []string) (int, error) {
+	// define variables to custom query
+	argsKV := map[string]interface{}{
+		"promo_id": promoID,
+		"status":   promo.RedemptionStatusRedeemed,
+	}
+	addConditions := make([]string, 0)
+
+	if email != "" {
+		addConditions = append(addConditions, "AND lower(r.email) = lower(:email)")
+		argsKV["email"] = strings.TrimSpace(email)
+	}
+	if len(excludeOrderIDs) > 0 {
+		addConditions = append(addConditions, "AND r.order_id NOT IN (:order_ids)")
+		argsKV["order_ids"] = excludeOrderIDs
+	}
+
+	query := fmt.Sprintf(queryCountRedemptions, strings.Join(addConditions, " "))
+
+	// prepare query
+	query, args, err := sqlx.Named(query, argsKV)
+	if err != nil {
+		return 0, err
+	}
+	query, args, err = sqlx.In(query, args...)
+	if err != nil {
+		return 0, err
+	}
+	query = sc.q.Rebind(query)
+
+	// query single row
+	var count int
+	err = sc.q.QueryRowxContext(ctx, query, args...).Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (sc *storeClient) CreateRedemption(ctx context.Context, reqRedemption promo.Redemption) (int64, error) {
+	// construct arguments filled with fields for the query
+	argsKV := map[string]interface{}{
+		"promo_id":     reqRedemption.PromoID,
+		"order_id":     reqRedemption.OrderID,
+		"email":        reqRedemption.Email,
+		"type":         reqRedemption.Type,
+		"jumlah_tiket": reqRedemption.JumlahTiket,
+		"subtotal":     reqRedemption.Subtotal,
+		"diskon":       reqRedemption.Diskon,
+		"status":       reqRedemption.Status,
+		"create_time":  reqRedemption.CreateTime,
+	}
+
+	// prepare query
+	query, args, err := sqlx.Named(queryCreateRedemption, argsKV)
+	if err != nil {
+		return 0, err
+	}
+	query = sc.q.Rebind(query)
+
+	// execute query
+	var redemptionID int64
+	err = sc.q.QueryRowxContext(ctx, query, args...).Scan(&redemptionID)
+	if err != nil {
+		return 0, err
+	}
+
+	return redemptionID, nil
+}
+
+func (sc *storeClient) CancelRedemptionsByOrderID(ctx context.Context, orderID string, updateTime time.Time) error {
+	// construct arguments filled with fields for the query
+	argsKV := map[string]interface{}{
+		"cancelled_status": promo.RedemptionStatusCancelled,
+		"redeemed_status":  promo.RedemptionStatusRedeemed,
+		"update_time":      updateTime,
+		"order_id":         orderID,
+	}
+
+	// prepare query
+	query, args, err := sqlx.Named(queryCancelRedemptions, argsKV)
+	if err != nil {
+		return err
+	}
+	query = sc.q.Rebind(query)
+
+	// execute query
+	_, err = sc.q.ExecContext(ctx, query, args...)
+	return err
+}
+
+func (sc *storeClient) GetReport(ctx context.Context) ([]promo.Report, error) {
+	argsKV := map[string]interface{}{
+		"status": promo.RedemptionStatusRedeemed,
+	}
+
+	// prepare query
+	query, args, err := sqlx.Named(queryGetReport, argsKV)
+	if err != nil {
+		return nil, err
+	}
+	query = sc.q.Rebind(query)
+
+	// query to database
+	rows, err := sc.q.QueryxContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	// read reports
+	result := make([]promo.Report, 0)
+	for rows.Next() {
+		var row reportDB
+		err = rows.StructScan(&row)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, row.format())
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
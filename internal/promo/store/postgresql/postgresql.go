@@ -0,0 +1,163 @@
+package postgresql
+
+import (
+	"errors"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/tedxub2023/internal/mainevent"
+	"github.com/tedxub2023/internal/promo"
+	"github.com/tedxub2023/internal/promo/service"
+)
+
+var (
+	errInvalidCommit   = errors.New("cannot do commit on non-transactional querier")
+	errInvalidRollback = errors.New("cannot do rollback on non-transactional querier")
+)
+
+// store implements promo/service.PGStore
+type store struct {
+	db *sqlx.DB
+}
+
+// storeClient implements promo/service.PGStoreClient
+type storeClient struct {
+	q sqlx.ExtContext
+}
+
+// New creates a new store.
+func New(db *sqlx.DB) (*store, error) {
+	s := &store{
+		db: db,
+	}
+
+	return s, nil
+}
+
+func (s *store) NewClient(useTx bool) (service.PGStoreClient, error) {
+	var q sqlx.ExtContext
+
+	// determine what object should be use as querier
+	q = s.db
+	if useTx {
+		var err error
+		q, err = s.db.Beginx()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &storeClient{
+		q: q,
+	}, nil
+}
+
+func (sc *storeClient) Commit() error {
+	if tx, ok := sc.q.(*sqlx.Tx); ok {
+		return tx.Commit()
+	}
+	return errInvalidCommit
+}
+
+func (sc *storeClient) Rollback() error {
+	if tx, ok := sc.q.(*sqlx.Tx); ok {
+		return tx.Rollback()
+	}
+	return errInvalidRollback
+}
+
+type promoDB struct {
+	ID            int64              `db:"id"`
+	Kode          string             `db:"kode"`
+	Jenis         promo.DiscountType `db:"jenis"`
+	Nilai         int64              `db:"nilai"`
+	Kuota         int                `db:"kuota"`
+	KuotaPerEmail int                `db:"kuota_per_email"`
+	Types         pq.Int64Array      `db:"types"`
+	Partner       string             `db:"partner"`
+	MulaiTime     *time.Time         `db:"mulai_time"`
+	SelesaiTime   *time.Time         `db:"selesai_time"`
+	CreateTime    time.Time          `db:"create_time"`
+	UpdateTime    *time.Time         `db:"update_time"`
+}
+
+// format formats database struct into domain struct.
+func (pdb *promoDB) format() promo.Promo {
+	p := promo.Promo{
+		ID:            pdb.ID,
+		Kode:          pdb.Kode,
+		Jenis:         pdb.Jenis,
+		Nilai:         pdb.Nilai,
+		Kuota:         pdb.Kuota,
+		KuotaPerEmail: pdb.KuotaPerEmail,
+		Partner:       pdb.Partner,
+		CreateTime:    pdb.CreateTime,
+	}
+
+	for _, t := range pdb.Types {
+		p.Types = append(p.Types, mainevent.Type(t))
+	}
+
+	if pdb.MulaiTime != nil {
+		p.MulaiTime = *pdb.MulaiTime
+	}
+
+	if pdb.SelesaiTime != nil {
+		p.SelesaiTime = *pdb.SelesaiTime
+	}
+
+	if pdb.UpdateTime != nil {
+		p.UpdateTime = *pdb.UpdateTime
+	}
+
+	return p
+}
+
+type reportDB struct {
+	PromoID         int64  `db:"promo_id"`
+	Kode            string `db:"kode"`
+	Partner         string `db:"partner"`
+	TotalRedemption int64  `db:"total_redemption"`
+	TotalTiket      int64  `db:"total_tiket"`
+	TotalDiskon     int64  `db:"total_diskon"`
+	TotalHarga      int64  `db:"total_harga"`
+}
+
+// format formats database struct into domain struct.
+func (rdb *reportDB) format() promo.Report {
+	return promo.Report{
+		PromoID:         rdb.PromoID,
+		Kode:            rdb.Kode,
+		Partner:         rdb.Partner,
+		TotalRedemption: rdb.TotalRedemption,
+		TotalTiket:      rdb.TotalTiket,
+		TotalDiskon:     rdb.TotalDiskon,
+		TotalHarga:      rdb.TotalHarga,
+	}
+}
+
+// nullTime returns nil for the zero time, so that it is
+// stored as NULL.
+func nullTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
+
+// typesArray returns the given types as a PostgreSQL array.
+func typesArray(types []mainevent.Type) pq.Int64Array {
+	arr := make(pq.Int64Array, 0, len(types))
+	for _, t := range types {
+		arr = append(arr, int64(t))
+	}
+	return arr
+}
+
+// isUniqueViolation returns whether the given error is a
+// unique constraint violation.
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23505"
+}
@@ -0,0 +1,138 @@
+package postgresql
+
+const queryCreatePromo = `
+	INSERT INTO
+		promo
+	(
+		kode,
+		jenis,
+		nilai,
+		kuota,
+		kuota_per_email,
+		types,
+		partner,
+		mulai_time,
+		selesai_time,
+		create_time
+	) VALUES (
+		:kode,
+		:jenis,
+		:nilai,
+		:kuota,
+		:kuota_per_email,
+		:types,
+		:partner,
+		:mulai_time,
+		:selesai_time,
+		:create_time
+	) RETURNING
+		id
+`
+
+const queryGetPromo = `
+	SELECT
+		p.id,
+		p.kode,
+		p.jenis,
+		p.nilai,
+		p.kuota,
+		p.kuota_per_email,
+		p.types,
+		p.partner,
+		p.mulai_time,
+		p.selesai_time,
+		p.create_time,
+		p.update_time
+	FROM
+		promo p
+	%s
+`
+
+const queryUpdatePromo = `
+	UPDATE
+		promo
+	SET
+		jenis = :jenis,
+		nilai = :nilai,
+		kuota = :kuota,
+		kuota_per_email = :kuota_per_email,
+		types = :types,
+		partner = :partner,
+		mulai_time = :mulai_time,
+		selesai_time = :selesai_time,
+		update_time = :update_time
+	WHERE
+		id = :id
+`
+
+const queryCountRedemptions = `
+	SELECT
+		COUNT(*)
+	FROM
+		promo_redemption r
+	WHERE
+		r.promo_id = :promo_id AND
+		r.status = :status
+		%s
+`
+
+const queryCreateRedemption = `
+	INSERT INTO
+		promo_redemption
+	(
+		promo_id,
+		order_id,
+		email,
+		type,
+		jumlah_tiket,
+		subtotal,
+		diskon,
+		status,
+		create_time
+	) VALUES (
+		:promo_id,
+		:order_id,
+		:email,
+		:type,
+		:jumlah_tiket,
+		:subtotal,
+		:diskon,
+		:status,
+		:create_time
+	) RETURNING
+		id
+`
+
+const queryCancelRedemptions = `
+	UPDATE
+		promo_redemption
+	SET
+		status = :cancelled_status,
+		update_time = :update_time
+	WHERE
+		order_id = :order_id AND
+		status = :redeemed_status
+`
+
+const queryGetReport = `
+	SELECT
+		p.id AS promo_id,
+		p.kode,
+		p.partner,
+		COUNT(r.id) AS total_redemption,
+		COALESCE(SUM(r.jumlah_tiket), 0) AS total_tiket,
+		COALESCE(SUM(r.diskon), 0) AS total_diskon,
+		COALESCE(SUM(r.subtotal - r.diskon), 0) AS total_harga
+	FROM
+		promo p
+	LEFT JOIN
+		promo_redemption r
+	ON
+		r.promo_id = p.id AND
+		r.status = :status
+	GROUP BY
+		p.id
+	ORDER BY
+		p.partner ASC,
+		p.kode ASC
+`
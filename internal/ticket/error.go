@@ -63,4 +63,47 @@ var (
 	// errNumberIdentityAlreadyRegistered is returned when the request
 	// number identity already in DB
 	ErrNumberIdentityAlreadyRegistered = errors.New("number identity already registered")
+
+	// ErrInvalidDraw is returned when the given draw has no
+	// seats, or its quotas have an unknown or more than one
+	// attribute, an empty or repeated value, or more seats
+	// than the draw.
+	ErrInvalidDraw = errors.New("invalid draw")
+
+	// ErrDrawNotFound is returned when the draw has not been
+	// committed.
+	ErrDrawNotFound = errors.New("draw not found")
+
+	// ErrDrawExists is returned when the draw has already
+	// been committed.
+	ErrDrawExists = errors.New("draw exists")
+
+	// ErrDrawAlreadyRun is returned when the draw has already
+	// been run.
+	ErrDrawAlreadyRun = errors.New("draw already run")
+
+	// ErrRegistrationClosed is returned when a ticket is
+	// registered after the draw is committed.
+	ErrRegistrationClosed = errors.New("registration closed")
+
+	// ErrInvalidConfirmationToken is returned when the given
+	// confirmation token is unknown or its winner has
+	// already confirmed or declined.
+	ErrInvalidConfirmationToken = errors.New("invalid confirmation token")
+
+	// ErrConfirmationExpired is returned when the winner
+	// confirms after the deadline.
+	ErrConfirmationExpired = errors.New("confirmation expired")
+
+	// ErrInvalidLookupCode is returned when the given lookup
+	// code is wrong or was not requested.
+	ErrInvalidLookupCode = errors.New("invalid lookup code")
+
+	// ErrLookupCodeExpired is returned when the lookup code
+	// has expired or has been tried too many times.
+	ErrLookupCodeExpired = errors.New("lookup code expired")
+
+	// ErrInvalidLookupToken is returned when the given lookup
+	// session token is unknown or has expired.
+	ErrInvalidLookupToken = errors.New("invalid lookup token")
 )
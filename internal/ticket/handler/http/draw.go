@@ -0,0 +1,442 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/tedxub2023/global/helper"
+	"github.com/tedxub2023/global/logger"
+	"github.com/tedxub2023/internal/ticket"
+)
+
+type drawHandler struct {
+	ticket ticket.Service
+}
+
+func (h *drawHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.handleGetDraw(w, r)
+	case http.MethodPost:
+		h.handleCommitDraw(w, r)
+	default:
+		helper.WriteErrorResponse(w, http.StatusMethodNotAllowed, []string{errMethodNotAllowed.Error()})
+	}
+}
+
+func (h *drawHandler) handleGetDraw(w http.ResponseWriter, r *http.Request) {
+	// add timeout to context
+	ctx, cancel := context.WithTimeout(r.Context(), 3000*time.Millisecond)
+	defer cancel()
+
+	var (
+		err        error           // stores error in this handler
+		resBody    []byte          // stores response body to write
+		statusCode = http.StatusOK // stores response status code
+	)
+
+	// write response
+	defer func() {
+		// error
+		if err != nil {
+			logger.Error(ctx, "failed to get draw", err)
+			helper.WriteErrorResponse(w, statusCode, []string{err.Error()})
+			return
+		}
+		// success
+		helper.WriteResponse(w, resBody, statusCode, helper.JSONContentTypeDecorator)
+	}()
+
+	// prepare channels for main go routine
+	resChan := make(chan ticket.Draw, 1)
+	errChan := make(chan error, 1)
+
+	go func() {
+		res, err := h.ticket.GetDraw(ctx)
+		if err != nil {
+			// determine error and status code, by default its internal error
+			parsedErr := errInternalServer
+			statusCode = http.StatusInternalServerError
+			if v, ok := mapHTTPError[err]; ok {
+				parsedErr = v
+				statusCode = http.StatusBadRequest
+			}
+
+			// log the actual error if its internal error
+			if statusCode == http.StatusInternalServerError {
+				logger.Error(ctx, "internal error from GetDraw", err)
+			}
+
+			errChan <- parsedErr
+			return
+		}
+
+		resChan <- res
+	}()
+
+	// wait and handle main go routine
+	select {
+	case <-ctx.Done():
+		statusCode = http.StatusGatewayTimeout
+		err = errRequestTimeout
+	case err = <-errChan:
+	case res := <-resChan:
+		resBody, err = json.Marshal(helper.ResponseEnvelope{
+			Data: formatDraw(res),
+		})
+	}
+}
+
+func (h *drawHandler) handleCommitDraw(w http.ResponseWriter, r *http.Request) {
+	// add timeout to context
+	ctx, cancel := context.WithTimeout(r.Context(), 3000*time.Millisecond)
+	defer cancel()
+
+	var (
+		err        error           // stores error in this handler
+		resBody    []byte          // stores response body to write
+		statusCode = http.StatusOK // stores response status code
+	)
+
+	// write response
+	defer func() {
+		if err != nil {
+			logger.Error(ctx, "failed to commit draw", err)
+			helper.WriteErrorResponse(w, statusCode, []string{err.Error()})
+			return
+		}
+		// success
+		helper.WriteResponse(w, resBody, statusCode, helper.JSONContentTypeDecorator)
+	}()
+
+	// prepare channels for main go routine
+	resChan := make(chan ticket.Draw, 1)
+	errChan := make(chan error, 1)
+
+	go func() {
+		// read body
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			statusCode = http.StatusBadRequest
+			errChan <- errBadRequest
+			return
+		}
+
+		// unmarshall body
+		request := drawHTTP{}
+		err = json.Unmarshal(body, &request)
+		if err != nil {
+			statusCode = http.StatusBadRequest
+			errChan <- errBadRequest
+			return
+		}
+
+		res, err := h.ticket.CommitDraw(ctx, parseDrawFromRequest(request))
+		if err != nil {
+			// determine error and status code, by default its internal error
+			parsedErr := errInternalServer
+			statusCode = http.StatusInternalServerError
+			if v, ok := mapHTTPError[err]; ok {
+				parsedErr = v
+				statusCode = http.StatusBadRequest
+			}
+
+			// there is only one draw
+			if parsedErr == errDrawExists {
+				statusCode = http.StatusConflict
+			}
+
+			// log the actual error if its internal error
+			if statusCode == http.StatusInternalServerError {
+				logger.Error(ctx, "internal error from CommitDraw", err)
+			}
+
+			errChan <- parsedErr
+			return
+		}
+
+		resChan <- res
+	}()
+
+	// wait and handle main go routine
+	select {
+	case <-ctx.Done():
+		statusCode = http.StatusGatewayTimeout
+		err = errRequestTimeout
+	case err = <-errChan:
+	case res := <-resChan:
+		resBody, err = json.Marshal(helper.ResponseEnvelope{
+			Status: "Success",
+			Data:   formatDraw(res),
+		})
+	}
+}
+
+type drawPreviewHandler struct {
+	ticket ticket.Service
+}
+
+func (h *drawPreviewHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+		h.handlePreviewDraw(w, r)
+	default:
+		helper.WriteErrorResponse(w, http.StatusMethodNotAllowed, []string{errMethodNotAllowed.Error()})
+	}
+}
+
+func (h *drawPreviewHandler) handlePreviewDraw(w http.ResponseWriter, r *http.Request) {
+	// add timeout to context
+	ctx, cancel := context.WithTimeout(r.Context(), 3000*time.Millisecond)
+	defer cancel()
+
+	var (
+		err        error           // stores error in this handler
+		resBody    []byte          // stores response body to write
+		statusCode = http.StatusOK // stores response status code
+	)
+
+	// write response
+	defer func() {
+		if err != nil {
+			logger.Error(ctx, "failed to preview draw", err)
+			helper.WriteErrorResponse(w, statusCode, []string{err.Error()})
+			return
+		}
+		// success
+		helper.WriteResponse(w, resBody, statusCode, helper.JSONContentTypeDecorator)
+	}()
+
+	// prepare channels for main go routine
+	resChan := make(chan ticket.Draw, 1)
+	errChan := make(chan error, 1)
+
+	go func() {
+		// read body
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			statusCode = http.StatusBadRequest
+			errChan <- errBadRequest
+			return
+		}
+
+		// unmarshall body
+		request := drawHTTP{}
+		err = json.Unmarshal(body, &request)
+		if err != nil {
+			statusCode = http.StatusBadRequest
+			errChan <- errBadRequest
+			return
+		}
+
+		var seed string
+		if request.Seed != nil {
+			seed = *request.Seed
+		}
+
+		res, err := h.ticket.PreviewDraw(ctx, parseDrawFromRequest(request), seed)
+		if err != nil {
+			// determine error and status code, by default its internal error
+			parsedErr := errInternalServer
+			statusCode = http.StatusInternalServerError
+			if v, ok := mapHTTPError[err]; ok {
+				parsedErr = v
+				statusCode = http.StatusBadRequest
+			}
+
+			// log the actual error if its internal error
+			if statusCode == http.StatusInternalServerError {
+				logger.Error(ctx, "internal error from PreviewDraw", err)
+			}
+
+			errChan <- parsedErr
+			return
+		}
+
+		resChan <- res
+	}()
+
+	// wait and handle main go routine
+	select {
+	case <-ctx.Done():
+		statusCode = http.StatusGatewayTimeout
+		err = errRequestTimeout
+	case err = <-errChan:
+	case res := <-resChan:
+		resBody, err = json.Marshal(helper.ResponseEnvelope{
+			Data: formatDraw(res),
+		})
+	}
+}
+
+type drawRunHandler struct {
+	ticket ticket.Service
+}
+
+func (h *drawRunHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+		h.handleRunDraw(w, r)
+	default:
+		helper.WriteErrorResponse(w, http.StatusMethodNotAllowed, []string{errMethodNotAllowed.Error()})
+	}
+}
+
+func (h *drawRunHandler) handleRunDraw(w http.ResponseWriter, r *http.Request) {
+	// add timeout to context
+	ctx, cancel := context.WithTimeout(r.Context(), 10000*time.Millisecond)
+	defer cancel()
+
+	var (
+		err        error           // stores error in this handler
+		resBody    []byte          // stores response body to write
+		statusCode = http.StatusOK // stores response status code
+	)
+
+	// write response
+	defer func() {
+		if err != nil {
+			logger.Error(ctx, "failed to run draw", err)
+			helper.WriteErrorResponse(w, statusCode, []string{err.Error()})
+			return
+		}
+		// success
+		helper.WriteResponse(w, resBody, statusCode, helper.JSONContentTypeDecorator)
+	}()
+
+	// prepare channels for main go routine
+	resChan := make(chan ticket.Draw, 1)
+	errChan := make(chan error, 1)
+
+	go func() {
+		res, err := h.ticket.RunDraw(ctx)
+		if err != nil {
+			// determine error and status code, by default its internal error
+			parsedErr := errInternalServer
+			statusCode = http.StatusInternalServerError
+			if v, ok := mapHTTPError[err]; ok {
+				parsedErr = v
+				statusCode = http.StatusBadRequest
+			}
+
+			// the draw is never run twice
+			if parsedErr == errDrawAlreadyRun {
+				statusCode = http.StatusConflict
+			}
+
+			// log the actual error if its internal error
+			if statusCode == http.StatusInternalServerError {
+				logger.Error(ctx, "internal error from RunDraw", err)
+			}
+
+			errChan <- parsedErr
+			return
+		}
+
+		resChan <- res
+	}()
+
+	// wait and handle main go routine
+	select {
+	case <-ctx.Done():
+		statusCode = http.StatusGatewayTimeout
+		err = errRequestTimeout
+	case err = <-errChan:
+	case res := <-resChan:
+		resBody, err = json.Marshal(helper.ResponseEnvelope{
+			Status: "Success",
+			Data:   formatDraw(res),
+		})
+	}
+}
+
+// formatDraw formats the given draw into the respective
+// HTTP-format object.
+func formatDraw(d ticket.Draw) drawHTTP {
+	quotas := make([]attributeQuotaHTTP, 0, len(d.KuotaAtribut))
+	for _, q := range d.KuotaAtribut {
+		quotas = append(quotas, attributeQuotaHTTP{
+			Atribut: q.Atribut.String(),
+			Nilai:   q.Nilai,
+			Kuota:   q.Kuota,
+		})
+	}
+
+	status := d.Status.String()
+	result := drawHTTP{
+		Kuota:        &d.Kuota,
+		KuotaAtribut: &quotas,
+		SeedHash:     &d.SeedHash,
+		Status:       &status,
+	}
+
+	if d.ID != 0 {
+		result.ID = &d.ID
+	}
+
+	if d.Seed != "" {
+		result.Seed = &d.Seed
+	}
+
+	if !d.CommitTime.IsZero() {
+		result.CommitTime = &d.CommitTime
+	}
+
+	if !d.DrawTime.IsZero() {
+		result.DrawTime = &d.DrawTime
+	}
+
+	if d.Results != nil {
+		results := make([]drawResultHTTP, 0, len(d.Results))
+		for _, r := range d.Results {
+			results = append(results, formatDrawResult(r))
+		}
+		result.Hasil = &results
+	}
+
+	return result
+}
+
+// formatDrawResult formats the given draw result into the
+// respective HTTP-format object.
+func formatDrawResult(r ticket.DrawResult) drawResultHTTP {
+	result := drawResultHTTP{
+		TicketID:   r.TicketID,
+		Nama:       r.Nama,
+		Peringkat:  r.Peringkat,
+		Status:     r.Status.String(),
+		Urutan:     r.Urutan,
+		NomorTiket: r.NomorTiket,
+	}
+
+	if r.Pending() && !r.ConfirmDeadline.IsZero() {
+		result.BatasKonfirmasi = &r.ConfirmDeadline
+	}
+
+	return result
+}
+
+// parseDrawFromRequest returns draw from the given HTTP
+// request object.
+func parseDrawFromRequest(dh drawHTTP) ticket.Draw {
+	result := ticket.Draw{}
+
+	if dh.Kuota != nil {
+		result.Kuota = *dh.Kuota
+	}
+
+	if dh.KuotaAtribut != nil {
+		for _, q := range *dh.KuotaAtribut {
+			result.KuotaAtribut = append(result.KuotaAtribut, ticket.AttributeQuota{
+				Atribut: ticket.Attribute(q.Atribut),
+				Nilai:   q.Nilai,
+				Kuota:   q.Kuota,
+			})
+		}
+	}
+
+	return result
+}
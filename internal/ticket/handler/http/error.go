@@ -83,6 +83,46 @@ var (
 	// errNumberIdentityAlreadyRegistered is returned when the request
 	// number identity already in DB
 	errNumberIdentityAlreadyRegistered = errors.New("NUMBER_IDENTITY_ALREADY_REGISTERED")
+
+	// errInvalidDraw is returned when the given draw is
+	// invalid.
+	errInvalidDraw = errors.New("INVALID_DRAW")
+
+	// errDrawNotFound is returned when the draw has not been
+	// committed.
+	errDrawNotFound = errors.New("DRAW_NOT_FOUND")
+
+	// errDrawExists is returned when the draw has already
+	// been committed.
+	errDrawExists = errors.New("DRAW_EXISTS")
+
+	// errDrawAlreadyRun is returned when the draw has already
+	// been run.
+	errDrawAlreadyRun = errors.New("DRAW_ALREADY_RUN")
+
+	// errRegistrationClosed is returned when a ticket is
+	// registered after the draw is committed.
+	errRegistrationClosed = errors.New("REGISTRATION_CLOSED")
+
+	// errInvalidConfirmationToken is returned when the given
+	// confirmation token is invalid.
+	errInvalidConfirmationToken = errors.New("INVALID_CONFIRMATION_TOKEN")
+
+	// errConfirmationExpired is returned when the winner
+	// confirms after the deadline.
+	errConfirmationExpired = errors.New("CONFIRMATION_EXPIRED")
+
+	// errInvalidLookupCode is returned when the given lookup
+	// code is invalid.
+	errInvalidLookupCode = errors.New("INVALID_LOOKUP_CODE")
+
+	// errLookupCodeExpired is returned when the lookup code
+	// has expired or has been tried too many times.
+	errLookupCodeExpired = errors.New("LOOKUP_CODE_EXPIRED")
+
+	// errInvalidLookupToken is returned when the given lookup
+	// session token is invalid or has expired.
+	errInvalidLookupToken = errors.New("INVALID_LOOKUP_TOKEN")
 )
 
 var (
@@ -108,5 +148,15 @@ var (
 		ticket.ErrInvalidTicketJenisKelamin:       errInvalidTicketJenisKelamin,
 		ticket.ErrEmailAlreadyRegistered:          errEmailAlreadyRegistered,
 		ticket.ErrNumberIdentityAlreadyRegistered: errNumberIdentityAlreadyRegistered,
+		ticket.ErrInvalidDraw:                     errInvalidDraw,
+		ticket.ErrDrawNotFound:                    errDrawNotFound,
+		ticket.ErrDrawExists:                      errDrawExists,
+		ticket.ErrDrawAlreadyRun:                  errDrawAlreadyRun,
+		ticket.ErrRegistrationClosed:              errRegistrationClosed,
+		ticket.ErrInvalidConfirmationToken:        errInvalidConfirmationToken,
+		ticket.ErrConfirmationExpired:             errConfirmationExpired,
+		ticket.ErrInvalidLookupCode:               errInvalidLookupCode,
+		ticket.ErrLookupCodeExpired:               errLookupCodeExpired,
+		ticket.ErrInvalidLookupToken:              errInvalidLookupToken,
 	}
 )
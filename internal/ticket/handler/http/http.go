@@ -3,6 +3,7 @@ package http
 import (
 	"errors"
 	"net/http"
+	"time"
 
 	"github.com/gorilla/mux"
 	"github.com/tedxub2023/internal/ticket"
@@ -38,6 +39,56 @@ var (
 		Name: "tickets",
 		URL:  "/tickets",
 	}
+
+	// HandlerDraw denotes HTTP handler to get and commit
+	// the draw of the free seats.
+	HandlerDraw = HandlerIdentity{
+		Name: "draw",
+		URL:  "/tickets/draw",
+	}
+
+	// HandlerDrawPreview denotes HTTP handler to preview the
+	// results of a draw.
+	HandlerDrawPreview = HandlerIdentity{
+		Name: "draw-preview",
+		URL:  "/tickets/draw/preview",
+	}
+
+	// HandlerDrawRun denotes HTTP handler to run the
+	// committed draw.
+	HandlerDrawRun = HandlerIdentity{
+		Name: "draw-run",
+		URL:  "/tickets/draw/run",
+	}
+
+	// HandlerDrawConfirmation denotes HTTP handler for the
+	// winners to confirm their attendance.
+	HandlerDrawConfirmation = HandlerIdentity{
+		Name: "draw-confirmation",
+		URL:  "/tickets/draw/confirmation",
+	}
+
+	// HandlerLookup denotes HTTP handler for the registrants
+	// to request a lookup code.
+	HandlerLookup = HandlerIdentity{
+		Name: "lookup",
+		URL:  "/tickets/lookup",
+	}
+
+	// HandlerLookupVerify denotes HTTP handler to verify a
+	// lookup code.
+	HandlerLookupVerify = HandlerIdentity{
+		Name: "lookup-verify",
+		URL:  "/tickets/lookup/verify",
+	}
+
+	// HandlerRegistration denotes HTTP handler for the
+	// registrants to see, correct and withdraw their
+	// registration.
+	HandlerRegistration = HandlerIdentity{
+		Name: "registration",
+		URL:  "/tickets/me",
+	}
 )
 
 // New creates a new Handler.
@@ -77,6 +128,34 @@ func (h *Handler) createHTTPHandler(configName string) (http.Handler, error) {
 		httpHandler = &ticketsHandler{
 			ticket: h.ticket,
 		}
+	case HandlerDraw.Name:
+		httpHandler = &drawHandler{
+			ticket: h.ticket,
+		}
+	case HandlerDrawPreview.Name:
+		httpHandler = &drawPreviewHandler{
+			ticket: h.ticket,
+		}
+	case HandlerDrawRun.Name:
+		httpHandler = &drawRunHandler{
+			ticket: h.ticket,
+		}
+	case HandlerDrawConfirmation.Name:
+		httpHandler = &drawConfirmationHandler{
+			ticket: h.ticket,
+		}
+	case HandlerLookup.Name:
+		httpHandler = &lookupHandler{
+			ticket: h.ticket,
+		}
+	case HandlerLookupVerify.Name:
+		httpHandler = &lookupVerifyHandler{
+			ticket: h.ticket,
+		}
+	case HandlerRegistration.Name:
+		httpHandler = &registrationHandler{
+			ticket: h.ticket,
+		}
 	default:
 		return httpHandler, errUnknownConfig
 	}
@@ -103,3 +182,55 @@ type ticketHTTP struct {
 	LineID         *string `json:"line_id"`
 	Instagram      *string `json:"instagram"`
 }
+
+type lookupHTTP struct {
+	Email          *string `json:"email"`
+	NomorIdentitas *string `json:"nomor_identitas"`
+	Kode           *string `json:"kode"`
+}
+
+type lookupSessionHTTP struct {
+	Token      string    `json:"token"`
+	ExpireTime time.Time `json:"expire_time"`
+}
+
+type registrationHTTP struct {
+	Tiket        ticketHTTP      `json:"tiket"`
+	StatusUndian string          `json:"status_undian"`
+	Hasil        *drawResultHTTP `json:"hasil,omitempty"`
+	BisaDiubah   bool            `json:"bisa_diubah"`
+}
+
+type drawHTTP struct {
+	ID           *int64                `json:"id"`
+	Kuota        *int                  `json:"kuota"`
+	KuotaAtribut *[]attributeQuotaHTTP `json:"kuota_atribut"`
+	SeedHash     *string               `json:"seed_hash"`
+	Seed         *string               `json:"seed"`
+	Status       *string               `json:"status"`
+	CommitTime   *time.Time            `json:"commit_time,omitempty"`
+	DrawTime     *time.Time            `json:"draw_time,omitempty"`
+	Hasil        *[]drawResultHTTP     `json:"hasil,omitempty"`
+}
+
+type attributeQuotaHTTP struct {
+	Atribut string `json:"atribut"`
+	Nilai   string `json:"nilai"`
+	Kuota   int    `json:"kuota"`
+}
+
+type confirmationHTTP struct {
+	Token *string `json:"token"`
+	Hadir *bool   `json:"hadir"`
+}
+
+type drawResultHTTP struct {
+	TicketID   int64  `json:"ticket_id"`
+	Nama       string `json:"nama"`
+	Peringkat  int    `json:"peringkat"`
+	Status     string `json:"status"`
+	Urutan     int    `json:"urutan"`
+	NomorTiket string `json:"nomor_tiket,omitempty"`
+
+	BatasKonfirmasi *time.Time `json:"batas_konfirmasi,omitempty"`
+}
@@ -0,0 +1,258 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/tedxub2023/global/auth"
+	"github.com/tedxub2023/global/helper"
+	"github.com/tedxub2023/global/logger"
+	"github.com/tedxub2023/internal/ticket"
+)
+
+type registrationHandler struct {
+	ticket ticket.Service
+}
+
+func (h *registrationHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.handleGetRegistration(w, r)
+	case http.MethodPut:
+		h.handleUpdateRegistration(w, r)
+	case http.MethodDelete:
+		h.handleWithdrawRegistration(w, r)
+	default:
+		helper.WriteErrorResponse(w, http.StatusMethodNotAllowed, []string{errMethodNotAllowed.Error()})
+	}
+}
+
+func (h *registrationHandler) handleGetRegistration(w http.ResponseWriter, r *http.Request) {
+	// add timeout to context
+	ctx, cancel := context.WithTimeout(r.Context(), 3000*time.Millisecond)
+	defer cancel()
+
+	var (
+		err        error           // stores error in this handler
+		resBody    []byte          // stores response body to write
+		statusCode = http.StatusOK // stores response status code
+	)
+
+	// write response
+	defer func() {
+		// error
+		if err != nil {
+			logger.Error(ctx, "failed to get registration", err)
+			helper.WriteErrorResponse(w, statusCode, []string{err.Error()})
+			return
+		}
+		// success
+		helper.WriteResponse(w, resBody, statusCode, helper.JSONContentTypeDecorator)
+	}()
+
+	// prepare channels for main go routine
+	resChan := make(chan ticket.Registration, 1)
+	errChan := make(chan error, 1)
+
+	go func() {
+		res, err := h.ticket.GetRegistration(ctx, auth.BearerToken(r))
+		if err != nil {
+			statusCode, err = registrationError(ctx, "GetRegistration", err)
+			errChan <- err
+			return
+		}
+
+		resChan <- res
+	}()
+
+	// wait and handle main go routine
+	select {
+	case <-ctx.Done():
+		statusCode = http.StatusGatewayTimeout
+		err = errRequestTimeout
+	case err = <-errChan:
+	case res := <-resChan:
+		resBody, err = json.Marshal(helper.ResponseEnvelope{
+			Data: formatRegistration(res),
+		})
+	}
+}
+
+func (h *registrationHandler) handleUpdateRegistration(w http.ResponseWriter, r *http.Request) {
+	// add timeout to context
+	ctx, cancel := context.WithTimeout(r.Context(), 3000*time.Millisecond)
+	defer cancel()
+
+	var (
+		err        error           // stores error in this handler
+		resBody    []byte          // stores response body to write
+		statusCode = http.StatusOK // stores response status code
+	)
+
+	// write response
+	defer func() {
+		if err != nil {
+			logger.Error(ctx, "failed to update registration", err)
+			helper.WriteErrorResponse(w, statusCode, []string{err.Error()})
+			return
+		}
+		// success
+		helper.WriteResponse(w, resBody, statusCode, helper.JSONContentTypeDecorator)
+	}()
+
+	// prepare channels for main go routine
+	resChan := make(chan ticket.Registration, 1)
+	errChan := make(chan error, 1)
+
+	go func() {
+		// read body
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			statusCode = http.StatusBadRequest
+			errChan <- errBadRequest
+			return
+		}
+
+		// unmarshall body
+		request := ticketHTTP{}
+		err = json.Unmarshal(body, &request)
+		if err != nil {
+			statusCode = http.StatusBadRequest
+			errChan <- errBadRequest
+			return
+		}
+
+		// format HTTP request into service object
+		reqTicket, err := parseTicketFromCreateRequest(request)
+		if err != nil {
+			statusCode = http.StatusBadRequest
+			errChan <- err
+			return
+		}
+
+		res, err := h.ticket.UpdateRegistration(ctx, auth.BearerToken(r), reqTicket)
+		if err != nil {
+			statusCode, err = registrationError(ctx, "UpdateRegistration", err)
+			errChan <- err
+			return
+		}
+
+		resChan <- res
+	}()
+
+	// wait and handle main go routine
+	select {
+	case <-ctx.Done():
+		statusCode = http.StatusGatewayTimeout
+		err = errRequestTimeout
+	case err = <-errChan:
+	case res := <-resChan:
+		resBody, err = json.Marshal(helper.ResponseEnvelope{
+			Status: "Success",
+			Data:   formatRegistration(res),
+		})
+	}
+}
+
+func (h *registrationHandler) handleWithdrawRegistration(w http.ResponseWriter, r *http.Request) {
+	// add timeout to context
+	ctx, cancel := context.WithTimeout(r.Context(), 3000*time.Millisecond)
+	defer cancel()
+
+	var (
+		err        error           // stores error in this handler
+		resBody    []byte          // stores response body to write
+		statusCode = http.StatusOK // stores response status code
+	)
+
+	// write response
+	defer func() {
+		if err != nil {
+			logger.Error(ctx, "failed to withdraw registration", err)
+			helper.WriteErrorResponse(w, statusCode, []string{err.Error()})
+			return
+		}
+		// success
+		helper.WriteResponse(w, resBody, statusCode, helper.JSONContentTypeDecorator)
+	}()
+
+	// prepare channels for main go routine
+	resChan := make(chan struct{}, 1)
+	errChan := make(chan error, 1)
+
+	go func() {
+		err := h.ticket.WithdrawRegistration(ctx, auth.BearerToken(r))
+		if err != nil {
+			statusCode, err = registrationError(ctx, "WithdrawRegistration", err)
+			errChan <- err
+			return
+		}
+
+		resChan <- struct{}{}
+	}()
+
+	// wait and handle main go routine
+	select {
+	case <-ctx.Done():
+		statusCode = http.StatusGatewayTimeout
+		err = errRequestTimeout
+	case err = <-errChan:
+	case <-resChan:
+		resBody, err = json.Marshal(helper.ResponseEnvelope{
+			Status: "Success",
+		})
+	}
+}
+
+// registrationError returns the status code and the HTTP
+// error of the given error from the given registration
+// method, logging the internal errors.
+func registrationError(ctx context.Context, method string, err error) (int, error) {
+	// determine error and status code, by default its internal error
+	parsedErr, ok := mapHTTPError[err]
+	if !ok {
+		logger.Error(ctx, "internal error from "+method, err)
+		return http.StatusInternalServerError, errInternalServer
+	}
+
+	switch parsedErr {
+	case errInvalidLookupToken:
+		return http.StatusUnauthorized, parsedErr
+	case errRegistrationClosed:
+		return http.StatusConflict, parsedErr
+	}
+
+	return http.StatusBadRequest, parsedErr
+}
+
+// formatRegistration formats the given registration into the
+// respective HTTP-format object.
+func formatRegistration(reg ticket.Registration) registrationHTTP {
+	t := reg.Ticket
+	result := registrationHTTP{
+		Tiket: ticketHTTP{
+			ID:             &t.ID,
+			Nama:           &t.Nama,
+			JenisKelamin:   &t.JenisKelamin,
+			NomorIdentitas: &t.NomorIdentitas,
+			AsalInstitusi:  &t.AsalInstitusi,
+			Domisili:       &t.Domisili,
+			Email:          &t.Email,
+			NomorTelepon:   &t.NomorTelepon,
+			LineID:         &t.LineID,
+			Instagram:      &t.Instagram,
+		},
+		StatusUndian: reg.DrawStatus.String(),
+		BisaDiubah:   reg.Open(),
+	}
+
+	if reg.Result.TicketID != 0 {
+		hasil := formatDrawResult(reg.Result)
+		result.Hasil = &hasil
+	}
+
+	return result
+}
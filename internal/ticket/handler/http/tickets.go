@@ -4,11 +4,11 @@ import (
 	"context"
 	"encoding/json"
 	"io/ioutil"
-	"log"
 	"net/http"
 	"time"
 
 	"github.com/tedxub2023/global/helper"
+	"github.com/tedxub2023/global/logger"
 	"github.com/tedxub2023/internal/ticket"
 )
 
@@ -20,8 +20,6 @@ func (h *ticketsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	switch r.Method {
 	case http.MethodPost:
 		h.handleCreateTicket(w, r)
-	case http.MethodPatch:
-		h.handleUpdateTicket(w, r)
 	default:
 		helper.WriteErrorResponse(w, http.StatusMethodNotAllowed, []string{errMethodNotAllowed.Error()})
 	}
@@ -34,7 +32,6 @@ func (h *ticketsHandler) handleCreateTicket(w http.ResponseWriter, r *http.Reque
 
 	var (
 		err        error           // stores error in this handler
-		source     string          // stores request source
 		resBody    []byte          // stores response body to write
 		statusCode = http.StatusOK // stores response status code
 	)
@@ -42,7 +39,7 @@ func (h *ticketsHandler) handleCreateTicket(w http.ResponseWriter, r *http.Reque
 	// write response
 	defer func() {
 		if err != nil {
-			log.Printf("[Ticket HTTP][handleCreateTicket] Failed to create tickets. Source: %s, Err: %s\n", source, err.Error())
+			logger.Error(ctx, "failed to create tickets", err)
 			helper.WriteErrorResponse(w, statusCode, []string{err.Error()})
 			return
 		}
@@ -92,7 +89,7 @@ func (h *ticketsHandler) handleCreateTicket(w http.ResponseWriter, r *http.Reque
 
 			// log the actual error if its internal error
 			if statusCode == http.StatusInternalServerError {
-				log.Printf("[Ticket HTTP][handleCreateTicket] Internal error from CreateTicket. Err: %s\n", err.Error())
+				logger.Error(ctx, "internal error from CreateTicket", err)
 			}
 
 			errChan <- parsedErr
@@ -161,63 +158,3 @@ func parseTicketFromCreateRequest(th ticketHTTP) (ticket.Ticket, error) {
 
 	return result, nil
 }
-
-func (h *ticketsHandler) handleUpdateTicket(w http.ResponseWriter, r *http.Request) {
-	// add timeout to context
-	ctx, cancel := context.WithTimeout(r.Context(), 3000*time.Millisecond)
-	defer cancel()
-
-	// create variables to store error and response body
-	var (
-		err        error
-		resBody    []byte
-		statusCode = http.StatusOK
-	)
-
-	// write response
-	defer func() {
-		if err != nil {
-			log.Printf("[Ticket HTTP][handleUpdateTicket] Failed to update tickets. Err: %s\n", err.Error())
-			helper.WriteErrorResponse(w, statusCode, []string{err.Error()})
-			return
-		}
-		helper.WriteResponse(w, resBody, statusCode, helper.JSONContentTypeDecorator)
-	}()
-
-	// prepare channels for main go routine
-	errChan := make(chan error, 1)
-
-	// create goroutine to handle main logic
-	go func() {
-		err := h.ticket.UpdateTicket(ctx)
-
-		if err != nil {
-			parsedErr := errInternalServer
-			statusCode = http.StatusInternalServerError
-			if v, ok := mapHTTPError[err]; ok {
-				parsedErr = v
-				statusCode = http.StatusBadRequest
-			}
-
-			// log the actual error if its internal error
-			if statusCode == http.StatusInternalServerError {
-				log.Printf("[Ticket HTTP][handleUpdateTicket] Internal error from UpdateTicket. Err: %s\n", err.Error())
-			}
-
-			errChan <- parsedErr
-			return
-		}
-	}()
-
-	// wait and handle main go routine
-	select {
-	case <-ctx.Done():
-		statusCode = http.StatusGatewayTimeout
-		err = errRequestTimeout
-	case err = <-errChan:
-	default:
-		resBody, err = json.Marshal(helper.ResponseEnvelope{
-			Status: "Success",
-		})
-	}
-}
@@ -0,0 +1,46 @@
+package ticket
+
+import "time"
+
+// Lookup is a one-time code emailed to a registrant to look
+// up their registration, and the session it opens once it is
+// verified.
+type Lookup struct {
+	ID       int64
+	TicketID int64
+
+	// KodeHash is the SHA-256 hash of the code in hex, the
+	// code is valid until ExpireTime for a few attempts.
+	KodeHash   string
+	Percobaan  int
+	ExpireTime time.Time
+
+	// Token is the secret of the session opened by the code,
+	// valid until TokenExpireTime. Only its SHA-256 hash in
+	// hex, TokenHash, is stored.
+	Token           string
+	TokenHash       string
+	TokenExpireTime time.Time
+
+	CreateTime time.Time
+	UseTime    time.Time
+}
+
+// Registration is a registered ticket as shown to the
+// registrant.
+type Registration struct {
+	Ticket Ticket
+
+	// DrawStatus is the status of the draw, it is unknown
+	// while the registration is open. Result is the result of
+	// the ticket once the draw is run, it is zero if the
+	// ticket was registered too late to take part.
+	DrawStatus DrawStatus
+	Result     DrawResult
+}
+
+// Open reports whether the registration can still be
+// corrected or withdrawn.
+func (r Registration) Open() bool {
+	return r.DrawStatus == DrawStatusUnknown
+}
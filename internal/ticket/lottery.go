@@ -0,0 +1,184 @@
+package ticket
+
+import "time"
+
+// Draw is the lottery of the free seats among the registered
+// tickets.
+//
+// The draw is verifiable with a commit-reveal seed: the hash
+// of the seed is published when the draw is committed and the
+// seed is revealed when it is run. Each ticket is ranked by
+// the SHA-256 hash of "<seed>:<ticket ID>" in hex, lowest
+// first, so anyone can check the seed against SeedHash and
+// reproduce the ranks of the results.
+type Draw struct {
+	ID int64
+
+	// EventID is the event whose tickets take part in the
+	// draw.
+	EventID int64
+
+	// Kuota is the number of free seats.
+	Kuota int
+
+	// KuotaAtribut are the seats reserved for the tickets
+	// with each value of one attribute, the other seats are
+	// open to every ticket. The reserved seats a value can
+	// not fill go to the next ranked tickets of any value.
+	KuotaAtribut []AttributeQuota
+
+	// SeedHash is the SHA-256 hash of Seed in hex, published
+	// when the draw is committed. Seed is empty until the
+	// draw is run.
+	SeedHash string
+	Seed     string
+
+	Status     DrawStatus
+	CommitTime time.Time
+	DrawTime   time.Time
+
+	// Results are the results of every ticket taking part in
+	// the draw, in the order of their rank.
+	Results []DrawResult
+}
+
+// AttributeQuota is the number of seats of a draw set aside
+// for the tickets with the given attribute value.
+type AttributeQuota struct {
+	Atribut Attribute
+	Nilai   string
+	Kuota   int
+}
+
+// Attribute denotes an attribute of a ticket the draw quotas
+// may be set on.
+type Attribute string
+
+// Followings are the known attributes.
+const (
+	AttributeUnknown       Attribute = ""
+	AttributeJenisKelamin  Attribute = "jenis_kelamin"
+	AttributeAsalInstitusi Attribute = "asal_institusi"
+	AttributeDomisili      Attribute = "domisili"
+)
+
+// AttributeList is a list of valid attributes.
+var AttributeList = map[Attribute]struct{}{
+	AttributeJenisKelamin:  {},
+	AttributeAsalInstitusi: {},
+	AttributeDomisili:      {},
+}
+
+// String returns string representaion of an attribute.
+func (a Attribute) String() string {
+	return string(a)
+}
+
+// ValueOf returns the value of the attribute of the given
+// ticket.
+func (a Attribute) ValueOf(t Ticket) string {
+	switch a {
+	case AttributeJenisKelamin:
+		return t.JenisKelamin
+	case AttributeAsalInstitusi:
+		return t.AsalInstitusi
+	case AttributeDomisili:
+		return t.Domisili
+	}
+	return ""
+}
+
+// DrawStatus denotes status of a draw.
+type DrawStatus string
+
+// Followings are the known draw status.
+const (
+	DrawStatusUnknown DrawStatus = ""
+
+	// DrawStatusCommitted means the seed hash is published
+	// and the draw is waiting to be run.
+	DrawStatusCommitted DrawStatus = "committed"
+
+	// DrawStatusDrawn means the draw has been run, its
+	// results are final.
+	DrawStatusDrawn DrawStatus = "drawn"
+)
+
+// String returns string representaion of a draw status.
+func (s DrawStatus) String() string {
+	return string(s)
+}
+
+// DrawResult is the result of a ticket in a draw.
+type DrawResult struct {
+	TicketID int64
+	Nama     string
+	Email    string
+
+	// Peringkat is the rank of the ticket by its hash, from
+	// one.
+	Peringkat int
+
+	// Status tells whether the ticket won a seat, and Urutan
+	// is its order among the winners or its place on the
+	// waitlist, from one. A ticket promoted from the waitlist
+	// takes the order of the seat it fills.
+	Status ResultStatus
+	Urutan int
+
+	// NomorTiket is the ticket number of the winners.
+	NomorTiket string
+
+	// Token is the secret of the confirmation link sent to
+	// the winners, who confirm or decline their attendance
+	// with it before ConfirmDeadline. Only its SHA-256 hash
+	// in hex, TokenHash, is stored, so a new token is issued
+	// whenever the link is emailed.
+	Token           string
+	TokenHash       string
+	ConfirmDeadline time.Time
+
+	// NotifyTime is the time the registrant was emailed
+	// about the current status, it is zero while the email
+	// is queued.
+	NotifyTime time.Time
+}
+
+// Pending reports whether the winner has yet to confirm
+// their attendance.
+func (r DrawResult) Pending() bool {
+	return r.Status == ResultStatusWinner
+}
+
+// ResultStatus denotes status of a draw result.
+type ResultStatus string
+
+// Followings are the known result status.
+const (
+	ResultStatusUnknown ResultStatus = ""
+
+	// ResultStatusWinner means the ticket won a seat and its
+	// attendance is awaiting confirmation.
+	ResultStatusWinner ResultStatus = "winner"
+
+	// ResultStatusWaitlist means the ticket is waiting for a
+	// seat given up by a winner.
+	ResultStatusWaitlist ResultStatus = "waitlist"
+
+	// ResultStatusConfirmed means the winner confirmed their
+	// attendance, the seat is theirs.
+	ResultStatusConfirmed ResultStatus = "confirmed"
+
+	// ResultStatusDeclined means the winner declined and the
+	// seat went to the waitlist.
+	ResultStatusDeclined ResultStatus = "declined"
+
+	// ResultStatusExpired means the winner did not confirm
+	// in time and the seat went to the waitlist.
+	ResultStatusExpired ResultStatus = "expired"
+)
+
+// String returns string representaion of a result status.
+func (s ResultStatus) String() string {
+	return string(s)
+}
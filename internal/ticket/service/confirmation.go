@@ -0,0 +1,433 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	"github.com/tedxub2023/global/logger"
+	"github.com/tedxub2023/internal/event"
+	"github.com/tedxub2023/internal/ticket"
+)
+
+func (s *service) ConfirmAttendance(ctx context.Context, token string, hadir bool) (_ ticket.DrawResult, err error) {
+	if token == "" {
+		return ticket.DrawResult{}, ticket.ErrInvalidConfirmationToken
+	}
+
+	ev, err := s.currentEvent(ctx)
+	if err != nil {
+		return ticket.DrawResult{}, err
+	}
+
+	pgStoreClient, err := s.pgStore.NewClient(true)
+	if err != nil {
+		return ticket.DrawResult{}, err
+	}
+
+	// rollback just before return if error
+	defer func() {
+		if err != nil {
+			errTx := pgStoreClient.Rollback()
+			if errTx != nil {
+				// return err from rollback
+				err = errTx
+			}
+		}
+	}()
+
+	// lock the draw, so that a seat is never given twice
+	draw, err := pgStoreClient.GetDrawForUpdate(ctx, ev.ID)
+	if err == ticket.ErrDrawNotFound {
+		return ticket.DrawResult{}, ticket.ErrInvalidConfirmationToken
+	}
+	if err != nil {
+		return ticket.DrawResult{}, err
+	}
+
+	results, err := pgStoreClient.GetDrawResults(ctx, draw.ID)
+	if err != nil {
+		return ticket.DrawResult{}, err
+	}
+
+	i := indexOfToken(results, tokenHash(token))
+	if i < 0 || !results[i].Pending() {
+		return ticket.DrawResult{}, ticket.ErrInvalidConfirmationToken
+	}
+	logger.AddFields(ctx, logger.Fields{"ticket_id": results[i].TicketID})
+
+	now := s.timeNow()
+	if !now.Before(results[i].ConfirmDeadline) {
+		return ticket.DrawResult{}, ticket.ErrConfirmationExpired
+	}
+
+	// the registrant acted on the link, there is nothing to
+	// email about
+	result := results[i]
+	result.NotifyTime = now
+
+	var promoted bool
+	if hadir {
+		result.Status = ticket.ResultStatusConfirmed
+		err = pgStoreClient.UpdateDrawResult(ctx, draw.ID, result, ticket.ResultStatusWinner, now)
+		if err != nil {
+			return ticket.DrawResult{}, err
+		}
+	} else {
+		result.Status = ticket.ResultStatusDeclined
+		promoted, err = s.releaseSeat(ctx, pgStoreClient, draw, results, i, result)
+		if err != nil {
+			return ticket.DrawResult{}, err
+		}
+		result = results[i]
+	}
+
+	// commit changes
+	err = pgStoreClient.Commit()
+	if err != nil {
+		return ticket.DrawResult{}, err
+	}
+	logger.Info(ctx, "draw attendance confirmed", logger.Fields{"status": result.Status.String()})
+
+	if promoted {
+		s.workers.Go(ctx, "ticket draw result mails", s.notifyDrawResults)
+	}
+
+	return result, nil
+}
+
+// expireConfirmations gives the seats of the winners who did
+// not confirm their attendance in time to the waitlist.
+func (s *service) expireConfirmations(ctx context.Context) (err error) {
+	ev, err := s.currentEvent(ctx)
+	if err != nil {
+		return err
+	}
+
+	pgStoreClient, err := s.pgStore.NewClient(true)
+	if err != nil {
+		return err
+	}
+
+	// rollback just before return if error
+	defer func() {
+		if err != nil {
+			errTx := pgStoreClient.Rollback()
+			if errTx != nil {
+				// return err from rollback
+				err = errTx
+			}
+		}
+	}()
+
+	draw, err := pgStoreClient.GetDrawForUpdate(ctx, ev.ID)
+	if err == ticket.ErrDrawNotFound {
+		return pgStoreClient.Rollback()
+	}
+	if err != nil {
+		return err
+	}
+	if draw.Status != ticket.DrawStatusDrawn {
+		return pgStoreClient.Rollback()
+	}
+
+	results, err := pgStoreClient.GetDrawResults(ctx, draw.ID)
+	if err != nil {
+		return err
+	}
+
+	now := s.timeNow()
+	expired := 0
+	for i := range results {
+		if !results[i].Pending() || now.Before(results[i].ConfirmDeadline) {
+			continue
+		}
+
+		result := results[i]
+		result.Status = ticket.ResultStatusExpired
+		result.NotifyTime = time.Time{}
+		_, err = s.releaseSeat(ctx, pgStoreClient, draw, results, i, result)
+		if err != nil {
+			return err
+		}
+		expired++
+	}
+
+	// commit changes
+	err = pgStoreClient.Commit()
+	if err != nil {
+		return err
+	}
+
+	if expired > 0 {
+		logger.Info(ctx, "draw confirmations expired", logger.Fields{"jumlah_tiket": expired})
+	}
+
+	return nil
+}
+
+// releaseSeat stores the given result of the winner at the
+// given index of the results giving up their seat, and gives
+// the seat to the first ticket on the waitlist if there is
+// one. The results are updated in place, so that the next
+// seat released goes to the next ticket. It reports whether a
+// ticket was promoted.
+func (s *service) releaseSeat(ctx context.Context, pgStoreClient PGStoreClient, draw ticket.Draw, results []ticket.DrawResult, i int, result ticket.DrawResult) (bool, error) {
+	now := s.timeNow()
+
+	// the promoted ticket takes the order and number of the
+	// seat
+	urutan, nomor := result.Urutan, result.NomorTiket
+	result.NomorTiket = ""
+	result.TokenHash = ""
+	err := pgStoreClient.UpdateDrawResult(ctx, draw.ID, result, ticket.ResultStatusWinner, now)
+	if err != nil {
+		return false, err
+	}
+	results[i] = result
+
+	err = pgStoreClient.UpdateTicket(ctx, ticket.Ticket{
+		ID:         result.TicketID,
+		Status:     false,
+		UpdateTime: now,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	next := -1
+	for j, r := range results {
+		if r.Status == ticket.ResultStatusWaitlist && (next < 0 || r.Urutan < results[next].Urutan) {
+			next = j
+		}
+	}
+	if next < 0 {
+		logger.Warn(ctx, "draw waitlist is empty", logger.Fields{"nomor_tiket": nomor})
+		return false, nil
+	}
+
+	promoted := results[next]
+	promoted.Status = ticket.ResultStatusWinner
+	promoted.Urutan = urutan
+	promoted.NomorTiket = nomor
+	promoted.ConfirmDeadline = now.Add(s.config.ConfirmationTTL)
+	promoted.NotifyTime = time.Time{}
+	promoted.TokenHash = ""
+
+	err = pgStoreClient.UpdateDrawResult(ctx, draw.ID, promoted, ticket.ResultStatusWaitlist, now)
+	if err != nil {
+		return false, err
+	}
+	results[next] = promoted
+
+	err = pgStoreClient.UpdateTicket(ctx, ticket.Ticket{
+		ID:         promoted.TicketID,
+		Status:     true,
+		NomorTiket: promoted.NomorTiket,
+		UpdateTime: now,
+	})
+	if err != nil {
+		return false, err
+	}
+	logger.Info(ctx, "draw waitlist promoted", logger.Fields{"ticket_id": promoted.TicketID, "nomor_tiket": nomor})
+
+	return true, nil
+}
+
+// notifyDrawResults sends the queued emails of the draw
+// results: the confirmation link to the winners, the place on
+// the waitlist to the others, and the notice to the winners
+// who missed the deadline. A result is marked as notified once
+// its email is sent, so that the failed emails are retried by
+// the next run.
+func (s *service) notifyDrawResults(ctx context.Context) error {
+	s.notifyMu.Lock()
+	defer s.notifyMu.Unlock()
+
+	ev, err := s.currentEvent(ctx)
+	if err != nil {
+		return err
+	}
+
+	// get pg store client without using transaction
+	pgStoreClient, err := s.pgStore.NewClient(false)
+	if err != nil {
+		return err
+	}
+
+	draw, err := pgStoreClient.GetDraw(ctx, ev.ID)
+	if err == ticket.ErrDrawNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if draw.Status != ticket.DrawStatusDrawn {
+		return nil
+	}
+
+	results, err := pgStoreClient.GetDrawResults(ctx, draw.ID)
+	if err != nil {
+		return err
+	}
+
+	var sent, failed int
+	for _, result := range results {
+		if !result.NotifyTime.IsZero() {
+			continue
+		}
+
+		switch result.Status {
+		case ticket.ResultStatusWinner:
+			err = s.notifyDrawWinner(ctx, pgStoreClient, ev, draw.ID, result)
+		case ticket.ResultStatusWaitlist:
+			err = s.sendDrawWaitlistMail(ev, result)
+		case ticket.ResultStatusExpired:
+			err = s.sendDrawExpiredMail(ev, result)
+		default:
+			continue
+		}
+		if err != nil {
+			logger.Error(ctx, "failed to send draw result mail", err, logger.Fields{"ticket_id": result.TicketID, "status": result.Status.String()})
+			failed++
+			continue
+		}
+
+		err = pgStoreClient.UpdateDrawResultNotifyTime(ctx, draw.ID, result, s.timeNow())
+		if err != nil {
+			return err
+		}
+		sent++
+	}
+
+	if sent > 0 || failed > 0 {
+		logger.Info(ctx, "draw result mails sent", logger.Fields{"sent": sent, "failed": failed})
+	}
+
+	return nil
+}
+
+// addCronJobs schedules the expiration of the unconfirmed
+// winners and the retry of the draw result emails.
+func (s *service) addCronJobs() error {
+	jakartaTime, err := time.LoadLocation("Asia/Jakarta")
+	if err != nil {
+		return err
+	}
+
+	scheduler := cron.New(cron.WithLocation(jakartaTime))
+	_, err = scheduler.AddFunc("*/1 * * * *", func() {
+		ctx := logger.NewContext(context.Background(), logger.Fields{"cron": "expire draw confirmations"})
+
+		if err := s.expireConfirmations(ctx); err != nil {
+			logger.Error(ctx, "failed to expire draw confirmations", err)
+		}
+
+		if err := s.notifyDrawResults(ctx); err != nil {
+			logger.Error(ctx, "failed to send draw result mails", err)
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	s.workers.AddScheduler(scheduler)
+
+	return nil
+}
+
+// notifyDrawWinner issues a new confirmation token to the
+// given winner of the given draw and emails them its link. A
+// token issued before is replaced, its email was not sent as
+// the email of a winner is only retried until it is sent.
+func (s *service) notifyDrawWinner(ctx context.Context, pgStoreClient PGStoreClient, ev event.Event, drawID int64, result ticket.DrawResult) error {
+	token, err := generateConfirmationToken()
+	if err != nil {
+		return err
+	}
+	result.Token = token
+	result.TokenHash = tokenHash(token)
+
+	err = pgStoreClient.UpdateDrawResultToken(ctx, drawID, result)
+	if err != nil {
+		return err
+	}
+
+	return s.sendDrawWinnerMail(ev, result, s.confirmationLink(result))
+}
+
+func (s *service) sendDrawWinnerMail(ev event.Event, result ticket.DrawResult, link string) error {
+	mail := NewMailClient(s.config.Mail)
+	mail.SetSender(s.config.Mail.Sender)
+	mail.SetReciever(result.Email)
+	mail.SetSubject(fmt.Sprintf("Selamat! Kamu Terpilih untuk %s", ev.Nama))
+	if err := mail.SetBodyHTMLDrawWinner(ev, result, link); err != nil {
+		return err
+	}
+
+	return mail.SendMail()
+}
+
+func (s *service) sendDrawWaitlistMail(ev event.Event, result ticket.DrawResult) error {
+	mail := NewMailClient(s.config.Mail)
+	mail.SetSender(s.config.Mail.Sender)
+	mail.SetReciever(result.Email)
+	mail.SetSubject(fmt.Sprintf("Hasil Undian %s", ev.Nama))
+	if err := mail.SetBodyHTMLDrawWaitlist(ev, result); err != nil {
+		return err
+	}
+
+	return mail.SendMail()
+}
+
+func (s *service) sendDrawExpiredMail(ev event.Event, result ticket.DrawResult) error {
+	mail := NewMailClient(s.config.Mail)
+	mail.SetSender(s.config.Mail.Sender)
+	mail.SetReciever(result.Email)
+	mail.SetSubject(fmt.Sprintf("Konfirmasi Kehadiran %s Berakhir", ev.Nama))
+	if err := mail.SetBodyHTMLDrawExpired(ev, result); err != nil {
+		return err
+	}
+
+	return mail.SendMail()
+}
+
+// confirmationLink returns the confirmation link of the given
+// winner.
+func (s *service) confirmationLink(result ticket.DrawResult) string {
+	u, err := url.Parse(s.config.ConfirmationURL)
+	if err != nil {
+		return s.config.ConfirmationURL
+	}
+
+	q := u.Query()
+	q.Set("token", result.Token)
+	u.RawQuery = q.Encode()
+
+	return u.String()
+}
+
+// indexOfToken returns the index of the result with the given
+// confirmation token hash, or -1 if there is none.
+func indexOfToken(results []ticket.DrawResult, tokenHash string) int {
+	for i, r := range results {
+		if r.TokenHash != "" && r.TokenHash == tokenHash {
+			return i
+		}
+	}
+	return -1
+}
+
+// generateConfirmationToken returns a new random confirmation
+// token.
+func generateConfirmationToken() (string, error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
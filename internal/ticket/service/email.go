@@ -2,37 +2,44 @@ package service
 
 import (
 	"bytes"
-	"io/ioutil"
-	"log"
-	"os"
-	"strconv"
+	"strings"
 	"text/template"
 	"time"
 
 	"github.com/leekchan/accounting"
+	"github.com/tedxub2023/global/helper"
+	"github.com/tedxub2023/global/metrics"
+	"github.com/tedxub2023/internal/event"
 	"github.com/tedxub2023/internal/mainevent"
 	"github.com/tedxub2023/internal/ticket"
 	"gopkg.in/gomail.v2"
 )
 
+// MailConfig holds the SMTP configuration used to send
+// emails.
+type MailConfig struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+
+	// Sender is the address used in the From header.
+	Sender string
+}
+
 type Gomail struct {
 	message *gomail.Message
 	dialer  *gomail.Dialer
 }
 
-func NewMailClient() *Gomail {
-	port, err := strconv.Atoi(os.Getenv("CONFIG_SMTP_PORT"))
-	if err != nil {
-		log.Fatalf("[tedxub2023-api-http] failed to convert smtp port: %s\n", err.Error())
-	}
-
+func NewMailClient(cfg MailConfig) *Gomail {
 	return &Gomail{
 		gomail.NewMessage(),
 		gomail.NewDialer(
-			os.Getenv("CONFIG_SMTP_HOST"),
-			port,
-			os.Getenv("CONFIG_AUTH_EMAIL"),
-			os.Getenv("CONFIG_AUTH_PASSWORD"),
+			cfg.Host,
+			cfg.Port,
+			cfg.Username,
+			cfg.Password,
 		)}
 }
 
@@ -48,18 +55,22 @@ func (g *Gomail) SetSubject(subject string) {
 	g.message.SetHeader("Subject", subject)
 }
 
-func (g *Gomail) SetBodyHTML(nameReciever string) error {
+// SetBodyHTML sets the registration mail of the given event,
+// from its email template if it has one.
+func (g *Gomail) SetBodyHTML(ev event.Event, nameReciever string) error {
 	var body bytes.Buffer
-	path := "global/template/template.html"
+	path := helper.TemplatePath(ev.Templates.Email, "template.html")
 	t, err := template.ParseFiles(path)
 	if err != nil {
 		return ticket.ErrParseBodyHTML
 	}
 
 	t.Execute(&body, struct {
-		Nama string
+		Event helper.TemplateEvent
+		Nama  string
 	}{
-		Nama: nameReciever,
+		Event: helper.NewTemplateEvent(ev),
+		Nama:  nameReciever,
 	})
 	g.message.SetBody("text/html", body.String())
 	return nil
@@ -67,14 +78,17 @@ func (g *Gomail) SetBodyHTML(nameReciever string) error {
 
 func (g *Gomail) SendMail() error {
 	if err := g.dialer.DialAndSend(g.message); err != nil {
+		metrics.EmailSendFailures.WithLabelValues(strings.Join(g.message.GetHeader("Subject"), "")).Inc()
 		return ticket.ErrSendEmail
 	}
 	return nil
 }
 
-func (g *Gomail) SetBodyHTMLMainEvent(totalTickets int, totalPrice string) error {
+// SetBodyHTMLMainEvent sets the ticket mail of a transaction of
+// the given event, from its email template if it has one.
+func (g *Gomail) SetBodyHTMLMainEvent(ev event.Event, dateTime string, totalTickets int, totalPrice string) error {
 	var body bytes.Buffer
-	path := "global/template/mainEvent.html"
+	path := helper.TemplatePath(ev.Templates.Email, "mainEvent.html")
 
 	t, err := template.ParseFiles(path)
 	if err != nil {
@@ -82,9 +96,13 @@ func (g *Gomail) SetBodyHTMLMainEvent(totalTickets int, totalPrice string) error
 	}
 
 	t.Execute(&body, struct {
+		Event        helper.TemplateEvent
+		DateTime     string
 		TotalTickets int
 		TotalPrice   string
 	}{
+		Event:        helper.NewTemplateEvent(ev),
+		DateTime:     dateTime,
 		TotalTickets: totalTickets,
 		TotalPrice:   totalPrice,
 	})
@@ -97,39 +115,51 @@ func (g *Gomail) SetAttachFile(path string) {
 	g.message.Attach(path)
 }
 
-func (g *Gomail) SetBodyHTMLPendingMail() error {
-	var body bytes.Buffer
+func (g *Gomail) SetBodyHTMLPendingMail(ev event.Event) error {
 	path := "global/template/pendingMail.html"
 
-	htmlContent, err := ioutil.ReadFile(path)
+	t, err := template.ParseFiles(path)
 	if err != nil {
-		return err
+		return ticket.ErrParseBodyHTML
 	}
 
-	body.Write(htmlContent)
+	var body bytes.Buffer
+
+	t.Execute(&body, struct {
+		Event helper.TemplateEvent
+	}{
+		Event: helper.NewTemplateEvent(ev),
+	})
 
 	g.message.SetBody("text/html", body.String())
 	return nil
 }
 
-func (g *Gomail) SetBodyHTMLDeclinedTransaction() error {
-	var body bytes.Buffer
-
+func (g *Gomail) SetBodyHTMLDeclinedTransaction(ev event.Event) error {
 	path := "global/template/declinedTransaction.html"
 
-	file, err := ioutil.ReadFile(path)
+	t, err := template.ParseFiles(path)
 	if err != nil {
-		return err
+		return ticket.ErrParseBodyHTML
 	}
 
-	body.Write(file)
+	var body bytes.Buffer
+
+	t.Execute(&body, struct {
+		Event helper.TemplateEvent
+	}{
+		Event: helper.NewTemplateEvent(ev),
+	})
 
 	g.message.SetBody("text/html", body.String())
 	return nil
 }
 
-func (g *Gomail) SetBodyHTMLSuccessTransaction(tx mainevent.MainEvent) error {
-	path := "global/template/successTransaction.html"
+// SetBodyHTMLSuccessTransaction sets the ticket mail of a
+// mainevent of the given event, from its email template if it
+// has one.
+func (g *Gomail) SetBodyHTMLSuccessTransaction(ev event.Event, tx mainevent.MainEvent) error {
+	path := helper.TemplatePath(ev.Templates.Email, "successTransaction.html")
 
 	t, err := template.ParseFiles(path)
 	if err != nil {
@@ -141,14 +171,21 @@ func (g *Gomail) SetBodyHTMLSuccessTransaction(tx mainevent.MainEvent) error {
 	ac := accounting.Accounting{Symbol: "Rp", Precision: 0, Thousand: ".", Decimal: ","}
 	totalPrice := ac.FormatMoney(tx.TotalHarga)
 
+	typeTickets := "Normal Sale"
+	if tt, ok := ev.TicketType(tx.Type.String()); ok {
+		typeTickets = tt.Nama
+	}
+
 	t.Execute(&body, struct {
+		Event        helper.TemplateEvent
 		TypeTickets  string
 		Date         string
 		TotalTickets int
 		TotalPrice   string
 	}{
-		TypeTickets:  "Normal Sale",
-		Date:         "3 Desember 2023",
+		Event:        helper.NewTemplateEvent(ev),
+		TypeTickets:  typeTickets,
+		Date:         helper.EventTanggal(ev),
 		TotalTickets: tx.JumlahTiket,
 		TotalPrice:   totalPrice,
 	})
@@ -157,23 +194,27 @@ func (g *Gomail) SetBodyHTMLSuccessTransaction(tx mainevent.MainEvent) error {
 	return nil
 }
 
-func (g *Gomail) SetBodyHTMLMainEventPendingMail() error {
-	var body bytes.Buffer
-
+func (g *Gomail) SetBodyHTMLMainEventPendingMail(ev event.Event) error {
 	path := "global/template/mainEventPendingMail.html"
 
-	file, err := ioutil.ReadFile(path)
+	t, err := template.ParseFiles(path)
 	if err != nil {
-		return err
+		return ticket.ErrParseBodyHTML
 	}
 
-	body.Write(file)
+	var body bytes.Buffer
+
+	t.Execute(&body, struct {
+		Event helper.TemplateEvent
+	}{
+		Event: helper.NewTemplateEvent(ev),
+	})
 
 	g.message.SetBody("text/html", body.String())
 	return nil
 }
 
-func (g *Gomail) SetBodyHTMLInformAdmin(tx mainevent.MainEvent) error {
+func (g *Gomail) SetBodyHTMLInformAdmin(ev event.Event, tx mainevent.MainEvent) error {
 	path := "global/template/informEmail.html"
 
 	t, err := template.ParseFiles(path)
@@ -184,10 +225,12 @@ func (g *Gomail) SetBodyHTMLInformAdmin(tx mainevent.MainEvent) error {
 	var body bytes.Buffer
 
 	t.Execute(&body, struct {
+		Event helper.TemplateEvent
 		Name  string
 		Email string
 		Date  string
 	}{
+		Event: helper.NewTemplateEvent(ev),
 		Name:  tx.Nama,
 		Email: tx.Email,
 		Date:  time.Now().Format("2006-01-02 15:04:05"),
@@ -196,3 +239,290 @@ func (g *Gomail) SetBodyHTMLInformAdmin(tx mainevent.MainEvent) error {
 	g.message.SetBody("text/html", body.String())
 	return nil
 }
+
+func (g *Gomail) SetBodyHTMLRefund(ev event.Event, tx mainevent.MainEvent, refund mainevent.Refund) error {
+	path := "global/template/refundMail.html"
+
+	t, err := template.ParseFiles(path)
+	if err != nil {
+		return ticket.ErrParseBodyHTML
+	}
+
+	var body bytes.Buffer
+
+	ac := accounting.Accounting{Symbol: "Rp", Precision: 0, Thousand: ".", Decimal: ","}
+	totalRefund := ac.FormatMoney(refund.TotalRefund)
+
+	t.Execute(&body, struct {
+		Event       helper.TemplateEvent
+		Name        string
+		Approved    bool
+		Manual      bool
+		TicketList  string
+		TotalRefund string
+		Note        string
+	}{
+		Event:       helper.NewTemplateEvent(ev),
+		Name:        tx.Nama,
+		Approved:    refund.Status == mainevent.RefundStatusApproved,
+		Manual:      refund.Metode == mainevent.RefundMethodManual,
+		TicketList:  strings.Join(refund.NomorTiket, ", "),
+		TotalRefund: totalRefund,
+		Note:        refund.Catatan,
+	})
+
+	g.message.SetBody("text/html", body.String())
+	return nil
+}
+
+func (g *Gomail) SetBodyHTMLHolderTicket(ev event.Event, tx mainevent.MainEvent, holder mainevent.Holder) error {
+	path := "global/template/holderTicketMail.html"
+
+	t, err := template.ParseFiles(path)
+	if err != nil {
+		return ticket.ErrParseBodyHTML
+	}
+
+	var body bytes.Buffer
+
+	t.Execute(&body, struct {
+		Event        helper.TemplateEvent
+		Name         string
+		BuyerName    string
+		NumberTicket string
+		Date         string
+	}{
+		Event:        helper.NewTemplateEvent(ev),
+		Name:         holder.Nama,
+		BuyerName:    tx.Nama,
+		NumberTicket: holder.NomorTiket,
+		Date:         helper.EventTanggal(ev),
+	})
+
+	g.message.SetBody("text/html", body.String())
+	return nil
+}
+
+func (g *Gomail) SetBodyHTMLWaitlistOffer(ev event.Event, entry mainevent.WaitlistEntry, link string) error {
+	path := "global/template/waitlistOfferMail.html"
+
+	t, err := template.ParseFiles(path)
+	if err != nil {
+		return ticket.ErrParseBodyHTML
+	}
+
+	var body bytes.Buffer
+
+	wib := time.FixedZone("WIB", 7*60*60)
+
+	t.Execute(&body, struct {
+		Event       helper.TemplateEvent
+		Name        string
+		TotalTicket int
+		Link        string
+		ExpireTime  string
+	}{
+		Event:       helper.NewTemplateEvent(ev),
+		Name:        entry.Nama,
+		TotalTicket: entry.JumlahTiket,
+		Link:        link,
+		ExpireTime:  entry.OfferExpireTime.In(wib).Format("15:04 WIB, 02-01-2006"),
+	})
+
+	g.message.SetBody("text/html", body.String())
+	return nil
+}
+
+func (g *Gomail) SetBodyHTMLWaitlistExpired(ev event.Event, entry mainevent.WaitlistEntry) error {
+	path := "global/template/waitlistExpiredMail.html"
+
+	t, err := template.ParseFiles(path)
+	if err != nil {
+		return ticket.ErrParseBodyHTML
+	}
+
+	var body bytes.Buffer
+
+	t.Execute(&body, struct {
+		Event helper.TemplateEvent
+		Name  string
+	}{
+		Event: helper.NewTemplateEvent(ev),
+		Name:  entry.Nama,
+	})
+
+	g.message.SetBody("text/html", body.String())
+	return nil
+}
+
+func (g *Gomail) SetBodyHTMLBuyerLogin(ev event.Event, nama string, login mainevent.BuyerLogin, link string) error {
+	path := "global/template/buyerLoginMail.html"
+
+	t, err := template.ParseFiles(path)
+	if err != nil {
+		return ticket.ErrParseBodyHTML
+	}
+
+	var body bytes.Buffer
+
+	wib := time.FixedZone("WIB", 7*60*60)
+
+	t.Execute(&body, struct {
+		Event      helper.TemplateEvent
+		Name       string
+		Link       string
+		ExpireTime string
+	}{
+		Event:      helper.NewTemplateEvent(ev),
+		Name:       nama,
+		Link:       link,
+		ExpireTime: login.ExpireTime.In(wib).Format("15:04 WIB, 02-01-2006"),
+	})
+
+	g.message.SetBody("text/html", body.String())
+	return nil
+}
+
+func (g *Gomail) SetBodyHTMLGroupOrder(ev event.Event, group mainevent.GroupOrder, invoice mainevent.MainEvent) error {
+	path := "global/template/groupOrderMail.html"
+
+	t, err := template.ParseFiles(path)
+	if err != nil {
+		return ticket.ErrParseBodyHTML
+	}
+
+	var body bytes.Buffer
+
+	ac := accounting.Accounting{Symbol: "Rp", Precision: 0, Thousand: ".", Decimal: ","}
+
+	t.Execute(&body, struct {
+		Event       helper.TemplateEvent
+		Name        string
+		Institution string
+		Approved    bool
+		Rejected    bool
+		OrderID     string
+		TotalTicket int
+		TicketPrice string
+		TotalPrice  string
+		Note        string
+	}{
+		Event:       helper.NewTemplateEvent(ev),
+		Name:        group.Nama,
+		Institution: group.AsalInstitusi,
+		Approved:    group.Status == mainevent.GroupStatusApproved,
+		Rejected:    group.Status == mainevent.GroupStatusRejected,
+		OrderID:     invoice.OrderID,
+		TotalTicket: len(group.Attendees),
+		TicketPrice: ac.FormatMoney(group.HargaTiket),
+		TotalPrice:  ac.FormatMoney(group.TotalHarga),
+		Note:        group.Catatan,
+	})
+
+	g.message.SetBody("text/html", body.String())
+	return nil
+}
+
+func (g *Gomail) SetBodyHTMLDrawWinner(ev event.Event, result ticket.DrawResult, link string) error {
+	path := "global/template/drawWinnerMail.html"
+
+	t, err := template.ParseFiles(path)
+	if err != nil {
+		return ticket.ErrParseBodyHTML
+	}
+
+	var body bytes.Buffer
+
+	wib := time.FixedZone("WIB", 7*60*60)
+
+	t.Execute(&body, struct {
+		Event        helper.TemplateEvent
+		Name         string
+		TicketNumber string
+		Link         string
+		Deadline     string
+	}{
+		Event:        helper.NewTemplateEvent(ev),
+		Name:         result.Nama,
+		TicketNumber: result.NomorTiket,
+		Link:         link,
+		Deadline:     result.ConfirmDeadline.In(wib).Format("15:04 WIB, 02-01-2006"),
+	})
+
+	g.message.SetBody("text/html", body.String())
+	return nil
+}
+
+func (g *Gomail) SetBodyHTMLDrawWaitlist(ev event.Event, result ticket.DrawResult) error {
+	path := "global/template/drawWaitlistMail.html"
+
+	t, err := template.ParseFiles(path)
+	if err != nil {
+		return ticket.ErrParseBodyHTML
+	}
+
+	var body bytes.Buffer
+
+	t.Execute(&body, struct {
+		Event    helper.TemplateEvent
+		Name     string
+		Position int
+	}{
+		Event:    helper.NewTemplateEvent(ev),
+		Name:     result.Nama,
+		Position: result.Urutan,
+	})
+
+	g.message.SetBody("text/html", body.String())
+	return nil
+}
+
+func (g *Gomail) SetBodyHTMLDrawExpired(ev event.Event, result ticket.DrawResult) error {
+	path := "global/template/drawExpiredMail.html"
+
+	t, err := template.ParseFiles(path)
+	if err != nil {
+		return ticket.ErrParseBodyHTML
+	}
+
+	var body bytes.Buffer
+
+	t.Execute(&body, struct {
+		Event helper.TemplateEvent
+		Name  string
+	}{
+		Event: helper.NewTemplateEvent(ev),
+		Name:  result.Nama,
+	})
+
+	g.message.SetBody("text/html", body.String())
+	return nil
+}
+
+func (g *Gomail) SetBodyHTMLLookupCode(ev event.Event, t ticket.Ticket, kode string, lookup ticket.Lookup) error {
+	path := "global/template/lookupCodeMail.html"
+
+	tmpl, err := template.ParseFiles(path)
+	if err != nil {
+		return ticket.ErrParseBodyHTML
+	}
+
+	var body bytes.Buffer
+
+	wib := time.FixedZone("WIB", 7*60*60)
+
+	tmpl.Execute(&body, struct {
+		Event      helper.TemplateEvent
+		Name       string
+		Code       string
+		ExpireTime string
+	}{
+		Event:      helper.NewTemplateEvent(ev),
+		Name:       t.Nama,
+		Code:       kode,
+		ExpireTime: lookup.ExpireTime.In(wib).Format("15:04 WIB, 02-01-2006"),
+	})
+
+	g.message.SetBody("text/html", body.String())
+	return nil
+}
@@ -0,0 +1,20 @@
+package service
+
+import (
+	"context"
+
+	"github.com/tedxub2023/global/logger"
+	"github.com/tedxub2023/internal/event"
+)
+
+// currentEvent returns the event the tickets are registered
+// for.
+func (s *service) currentEvent(ctx context.Context) (event.Event, error) {
+	ev, err := s.event.GetEventBySlug(ctx, s.config.EventSlug)
+	if err != nil {
+		return event.Event{}, err
+	}
+	logger.AddFields(ctx, logger.Fields{"event_id": ev.ID})
+
+	return ev, nil
+}
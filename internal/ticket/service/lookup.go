@@ -0,0 +1,361 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/tedxub2023/global/logger"
+	"github.com/tedxub2023/internal/event"
+	"github.com/tedxub2023/internal/ticket"
+)
+
+// maxLookupAttempts is the most times a lookup code may be
+// tried.
+const maxLookupAttempts = 5
+
+func (s *service) RequestLookupCode(ctx context.Context, email, nomorIdentitas string) error {
+	email, nomorIdentitas = strings.TrimSpace(email), strings.TrimSpace(nomorIdentitas)
+	if email == "" {
+		return ticket.ErrInvalidTicketEmail
+	}
+	if nomorIdentitas == "" {
+		return ticket.ErrInvalidTicketNomorIdentitas
+	}
+
+	// get pg store client without using transaction
+	pgStoreClient, err := s.pgStore.NewClient(false)
+	if err != nil {
+		return err
+	}
+
+	t, err := pgStoreClient.GetTicketByIdentity(ctx, email, nomorIdentitas)
+	if err == ticket.ErrTicketNotFound {
+		// do not tell whether the registrant exists
+		logger.Info(ctx, "lookup code requested for unknown ticket")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	logger.AddFields(ctx, logger.Fields{"ticket_id": t.ID})
+
+	kode, err := generateLookupCode()
+	if err != nil {
+		return err
+	}
+
+	now := s.timeNow()
+	lookup := ticket.Lookup{
+		TicketID:   t.ID,
+		KodeHash:   lookupCodeHash(kode),
+		ExpireTime: now.Add(s.config.LookupCodeTTL),
+		CreateTime: now,
+	}
+
+	lookup.ID, err = pgStoreClient.CreateLookup(ctx, lookup)
+	if err != nil {
+		return err
+	}
+
+	s.workers.Go(ctx, "ticket lookup code mail", func(ctx context.Context) error {
+		ev, err := s.currentEvent(ctx)
+		if err != nil {
+			return err
+		}
+		return s.sendLookupCodeMail(ev, t, kode, lookup)
+	})
+
+	return nil
+}
+
+func (s *service) VerifyLookupCode(ctx context.Context, email, nomorIdentitas, kode string) (ticket.Lookup, error) {
+	email, nomorIdentitas, kode = strings.TrimSpace(email), strings.TrimSpace(nomorIdentitas), strings.TrimSpace(kode)
+	if email == "" || nomorIdentitas == "" || kode == "" {
+		return ticket.Lookup{}, ticket.ErrInvalidLookupCode
+	}
+
+	// get pg store client without using transaction
+	pgStoreClient, err := s.pgStore.NewClient(false)
+	if err != nil {
+		return ticket.Lookup{}, err
+	}
+
+	t, err := pgStoreClient.GetTicketByIdentity(ctx, email, nomorIdentitas)
+	if err == ticket.ErrTicketNotFound {
+		return ticket.Lookup{}, ticket.ErrInvalidLookupCode
+	}
+	if err != nil {
+		return ticket.Lookup{}, err
+	}
+	logger.AddFields(ctx, logger.Fields{"ticket_id": t.ID})
+
+	// only the latest code is valid, and only once
+	lookup, err := pgStoreClient.GetLatestLookup(ctx, t.ID)
+	if err != nil {
+		return ticket.Lookup{}, err
+	}
+	if !lookup.UseTime.IsZero() {
+		return ticket.Lookup{}, ticket.ErrInvalidLookupCode
+	}
+
+	now := s.timeNow()
+	if !now.Before(lookup.ExpireTime) {
+		return ticket.Lookup{}, ticket.ErrLookupCodeExpired
+	}
+
+	// every attempt is counted before the code is compared,
+	// so that concurrent attempts can not go over the limit
+	err = pgStoreClient.AddLookupAttempt(ctx, lookup.ID, maxLookupAttempts)
+	if err != nil {
+		return ticket.Lookup{}, err
+	}
+	lookup.Percobaan++
+
+	if subtle.ConstantTimeCompare([]byte(lookupCodeHash(kode)), []byte(lookup.KodeHash)) != 1 {
+		logger.Warn(ctx, "wrong lookup code", logger.Fields{"percobaan": lookup.Percobaan})
+		return ticket.Lookup{}, ticket.ErrInvalidLookupCode
+	}
+
+	lookup.Token, err = generateConfirmationToken()
+	if err != nil {
+		return ticket.Lookup{}, err
+	}
+	lookup.TokenHash = tokenHash(lookup.Token)
+	lookup.TokenExpireTime = now.Add(s.config.LookupSessionTTL)
+	lookup.UseTime = now
+
+	err = pgStoreClient.UseLookup(ctx, lookup)
+	if err != nil {
+		return ticket.Lookup{}, err
+	}
+	logger.Info(ctx, "lookup code verified")
+
+	return lookup, nil
+}
+
+func (s *service) GetRegistration(ctx context.Context, token string) (ticket.Registration, error) {
+	// get pg store client without using transaction
+	pgStoreClient, err := s.pgStore.NewClient(false)
+	if err != nil {
+		return ticket.Registration{}, err
+	}
+
+	t, err := s.lookupTicket(ctx, pgStoreClient, token)
+	if err != nil {
+		return ticket.Registration{}, err
+	}
+
+	return s.registrationOf(ctx, pgStoreClient, t)
+}
+
+func (s *service) UpdateRegistration(ctx context.Context, token string, reqTicket ticket.Ticket) (_ ticket.Registration, err error) {
+	pgStoreClient, err := s.pgStore.NewClient(true)
+	if err != nil {
+		return ticket.Registration{}, err
+	}
+
+	// rollback just before return if error
+	defer func() {
+		if err != nil {
+			errTx := pgStoreClient.Rollback()
+			if errTx != nil {
+				// return err from rollback
+				err = errTx
+			}
+		}
+	}()
+
+	current, err := s.lookupTicket(ctx, pgStoreClient, token)
+	if err != nil {
+		return ticket.Registration{}, err
+	}
+
+	err = checkRegistrationOpen(ctx, pgStoreClient, current.EventID)
+	if err != nil {
+		return ticket.Registration{}, err
+	}
+
+	// the email and the identity number identify the
+	// registrant, they stay as registered
+	reqTicket.ID = current.ID
+	reqTicket.EventID = current.EventID
+	reqTicket.Email = current.Email
+	reqTicket.NomorIdentitas = current.NomorIdentitas
+	reqTicket.Status = current.Status
+	reqTicket.NomorTiket = current.NomorTiket
+	reqTicket.CreateTime = current.CreateTime
+	reqTicket.UpdateTime = s.timeNow()
+
+	// validate field
+	err = validateTicket(reqTicket)
+	if err != nil {
+		return ticket.Registration{}, err
+	}
+
+	err = pgStoreClient.UpdateTicketData(ctx, reqTicket)
+	if err != nil {
+		return ticket.Registration{}, err
+	}
+
+	// commit changes
+	err = pgStoreClient.Commit()
+	if err != nil {
+		return ticket.Registration{}, err
+	}
+	logger.Info(ctx, "registration corrected")
+
+	return ticket.Registration{Ticket: reqTicket}, nil
+}
+
+func (s *service) WithdrawRegistration(ctx context.Context, token string) (err error) {
+	pgStoreClient, err := s.pgStore.NewClient(true)
+	if err != nil {
+		return err
+	}
+
+	// rollback just before return if error
+	defer func() {
+		if err != nil {
+			errTx := pgStoreClient.Rollback()
+			if errTx != nil {
+				// return err from rollback
+				err = errTx
+			}
+		}
+	}()
+
+	current, err := s.lookupTicket(ctx, pgStoreClient, token)
+	if err != nil {
+		return err
+	}
+
+	err = checkRegistrationOpen(ctx, pgStoreClient, current.EventID)
+	if err != nil {
+		return err
+	}
+
+	err = pgStoreClient.DeleteTicket(ctx, current.ID)
+	if err != nil {
+		return err
+	}
+
+	// commit changes
+	err = pgStoreClient.Commit()
+	if err != nil {
+		return err
+	}
+	logger.Info(ctx, "registration withdrawn")
+
+	return nil
+}
+
+// lookupTicket returns the ticket of the given lookup session
+// token.
+func (s *service) lookupTicket(ctx context.Context, pgStoreClient PGStoreClient, token string) (ticket.Ticket, error) {
+	if token == "" {
+		return ticket.Ticket{}, ticket.ErrInvalidLookupToken
+	}
+
+	lookup, err := pgStoreClient.GetLookupByToken(ctx, tokenHash(token))
+	if err != nil {
+		return ticket.Ticket{}, err
+	}
+	if !s.timeNow().Before(lookup.TokenExpireTime) {
+		return ticket.Ticket{}, ticket.ErrInvalidLookupToken
+	}
+	logger.AddFields(ctx, logger.Fields{"ticket_id": lookup.TicketID})
+
+	t, err := pgStoreClient.GetTicketByID(ctx, lookup.TicketID)
+	if err == ticket.ErrTicketNotFound {
+		return ticket.Ticket{}, ticket.ErrInvalidLookupToken
+	}
+
+	return t, err
+}
+
+// registrationOf returns the registration of the given
+// ticket with its draw result.
+func (s *service) registrationOf(ctx context.Context, pgStoreClient PGStoreClient, t ticket.Ticket) (ticket.Registration, error) {
+	result := ticket.Registration{Ticket: t}
+
+	draw, err := pgStoreClient.GetDraw(ctx, t.EventID)
+	if err == ticket.ErrDrawNotFound {
+		return result, nil
+	}
+	if err != nil {
+		return ticket.Registration{}, err
+	}
+
+	result.DrawStatus = draw.Status
+	if draw.Status != ticket.DrawStatusDrawn {
+		return result, nil
+	}
+
+	results, err := pgStoreClient.GetDrawResults(ctx, draw.ID)
+	if err != nil {
+		return ticket.Registration{}, err
+	}
+	for _, r := range results {
+		if r.TicketID == t.ID {
+			result.Result = r
+			break
+		}
+	}
+
+	return result, nil
+}
+
+// checkRegistrationOpen returns ticket.ErrRegistrationClosed
+// if the draw of the given event has been committed.
+func checkRegistrationOpen(ctx context.Context, pgStoreClient PGStoreClient, eventID int64) error {
+	_, err := pgStoreClient.GetDraw(ctx, eventID)
+	if err == nil {
+		return ticket.ErrRegistrationClosed
+	}
+	if err != ticket.ErrDrawNotFound {
+		return err
+	}
+	return nil
+}
+
+func (s *service) sendLookupCodeMail(ev event.Event, t ticket.Ticket, kode string, lookup ticket.Lookup) error {
+	mail := NewMailClient(s.config.Mail)
+	mail.SetSender(s.config.Mail.Sender)
+	mail.SetReciever(t.Email)
+	mail.SetSubject(fmt.Sprintf("Kode Verifikasi %s", ev.Nama))
+	if err := mail.SetBodyHTMLLookupCode(ev, t, kode, lookup); err != nil {
+		return err
+	}
+
+	return mail.SendMail()
+}
+
+// lookupCodeHash returns the stored hash of the given lookup
+// code.
+func lookupCodeHash(kode string) string {
+	sum := sha256.Sum256([]byte(kode))
+	return hex.EncodeToString(sum[:])
+}
+
+// tokenHash returns the stored hash of the given lookup
+// session or confirmation token, the SHA-256 hash in hex.
+func tokenHash(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// generateLookupCode returns a new random six digit lookup
+// code.
+func generateLookupCode() (string, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(1000000))
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%06d", n.Int64()), nil
+}
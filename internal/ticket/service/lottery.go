@@ -0,0 +1,406 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/tedxub2023/global/logger"
+	"github.com/tedxub2023/internal/ticket"
+)
+
+func (s *service) CommitDraw(ctx context.Context, reqDraw ticket.Draw) (_ ticket.Draw, err error) {
+	reqDraw = normalizeDraw(reqDraw)
+
+	// validate field
+	err = validateDraw(reqDraw)
+	if err != nil {
+		return ticket.Draw{}, err
+	}
+
+	reqDraw.Seed, err = generateSeed()
+	if err != nil {
+		return ticket.Draw{}, err
+	}
+	reqDraw.SeedHash = seedHash(reqDraw.Seed)
+	reqDraw.Status = ticket.DrawStatusCommitted
+	reqDraw.CommitTime = s.timeNow()
+
+	ev, err := s.currentEvent(ctx)
+	if err != nil {
+		return ticket.Draw{}, err
+	}
+	reqDraw.EventID = ev.ID
+
+	pgStoreClient, err := s.pgStore.NewClient(true)
+	if err != nil {
+		return ticket.Draw{}, err
+	}
+
+	// rollback just before return if error
+	defer func() {
+		if err != nil {
+			errTx := pgStoreClient.Rollback()
+			if errTx != nil {
+				// return err from rollback
+				err = errTx
+			}
+		}
+	}()
+
+	reqDraw.ID, err = pgStoreClient.CreateDraw(ctx, reqDraw)
+	if err != nil {
+		return ticket.Draw{}, err
+	}
+
+	// commit changes
+	err = pgStoreClient.Commit()
+	if err != nil {
+		return ticket.Draw{}, err
+	}
+	logger.Info(ctx, "draw committed", logger.Fields{"draw_id": reqDraw.ID, "seed_hash": reqDraw.SeedHash})
+
+	// the seed stays secret until the draw is run
+	reqDraw.Seed = ""
+
+	return reqDraw, nil
+}
+
+func (s *service) GetDraw(ctx context.Context) (ticket.Draw, error) {
+	ev, err := s.currentEvent(ctx)
+	if err != nil {
+		return ticket.Draw{}, err
+	}
+
+	// get pg store client without using transaction
+	pgStoreClient, err := s.pgStore.NewClient(false)
+	if err != nil {
+		return ticket.Draw{}, err
+	}
+
+	draw, err := pgStoreClient.GetDraw(ctx, ev.ID)
+	if err != nil {
+		return ticket.Draw{}, err
+	}
+
+	if draw.Status != ticket.DrawStatusDrawn {
+		draw.Seed = ""
+		return draw, nil
+	}
+
+	draw.Results, err = pgStoreClient.GetDrawResults(ctx, draw.ID)
+	if err != nil {
+		return ticket.Draw{}, err
+	}
+
+	return draw, nil
+}
+
+func (s *service) PreviewDraw(ctx context.Context, reqDraw ticket.Draw, seed string) (ticket.Draw, error) {
+	reqDraw = normalizeDraw(reqDraw)
+
+	// validate field
+	err := validateDraw(reqDraw)
+	if err != nil {
+		return ticket.Draw{}, err
+	}
+
+	if seed == "" {
+		seed, err = generateSeed()
+		if err != nil {
+			return ticket.Draw{}, err
+		}
+	}
+	reqDraw.Seed = seed
+	reqDraw.SeedHash = seedHash(seed)
+
+	ev, err := s.currentEvent(ctx)
+	if err != nil {
+		return ticket.Draw{}, err
+	}
+	reqDraw.EventID = ev.ID
+
+	// get pg store client without using transaction
+	pgStoreClient, err := s.pgStore.NewClient(false)
+	if err != nil {
+		return ticket.Draw{}, err
+	}
+
+	tickets, err := pgStoreClient.GetAllTicket(ctx, ev.ID)
+	if err != nil {
+		return ticket.Draw{}, err
+	}
+
+	reqDraw.Results = drawTickets(reqDraw, tickets)
+
+	return reqDraw, nil
+}
+
+func (s *service) RunDraw(ctx context.Context) (_ ticket.Draw, err error) {
+	ev, err := s.currentEvent(ctx)
+	if err != nil {
+		return ticket.Draw{}, err
+	}
+
+	pgStoreClient, err := s.pgStore.NewClient(true)
+	if err != nil {
+		return ticket.Draw{}, err
+	}
+
+	// rollback just before return if error
+	defer func() {
+		if err != nil {
+			errTx := pgStoreClient.Rollback()
+			if errTx != nil {
+				// return err from rollback
+				err = errTx
+			}
+		}
+	}()
+
+	// lock the draw, so that it is never run twice
+	draw, err := pgStoreClient.GetDrawForUpdate(ctx, ev.ID)
+	if err != nil {
+		return ticket.Draw{}, err
+	}
+	logger.AddFields(ctx, logger.Fields{"draw_id": draw.ID})
+
+	if draw.Status != ticket.DrawStatusCommitted {
+		return ticket.Draw{}, ticket.ErrDrawAlreadyRun
+	}
+
+	registered, err := pgStoreClient.GetAllTicket(ctx, draw.EventID)
+	if err != nil {
+		return ticket.Draw{}, err
+	}
+
+	// only the tickets registered before the seed hash is
+	// published take part in the draw
+	tickets := make([]ticket.Ticket, 0, len(registered))
+	for _, t := range registered {
+		if t.CreateTime.Before(draw.CommitTime) {
+			tickets = append(tickets, t)
+		}
+	}
+	if len(tickets) == 0 {
+		return ticket.Draw{}, ticket.ErrTicketNotFound
+	}
+
+	now := s.timeNow()
+	draw.Results = drawTickets(draw, tickets)
+	for i, result := range draw.Results {
+		// the winners confirm their attendance with the link
+		// emailed to them before the deadline
+		if result.Status == ticket.ResultStatusWinner {
+			result.ConfirmDeadline = now.Add(s.config.ConfirmationTTL)
+			draw.Results[i] = result
+		}
+
+		err = pgStoreClient.CreateDrawResult(ctx, draw.ID, result)
+		if err != nil {
+			return ticket.Draw{}, err
+		}
+
+		err = pgStoreClient.UpdateTicket(ctx, ticket.Ticket{
+			ID:         result.TicketID,
+			Status:     result.Status == ticket.ResultStatusWinner,
+			NomorTiket: result.NomorTiket,
+			UpdateTime: now,
+		})
+		if err != nil {
+			return ticket.Draw{}, err
+		}
+	}
+
+	draw.Status = ticket.DrawStatusDrawn
+	draw.DrawTime = now
+	err = pgStoreClient.UpdateDrawStatus(ctx, draw, ticket.DrawStatusCommitted)
+	if err != nil {
+		return ticket.Draw{}, err
+	}
+
+	// commit changes
+	err = pgStoreClient.Commit()
+	if err != nil {
+		return ticket.Draw{}, err
+	}
+	logger.Info(ctx, "draw run", logger.Fields{"jumlah_tiket": len(tickets), "kuota": draw.Kuota})
+
+	s.workers.Go(ctx, "ticket draw result mails", s.notifyDrawResults)
+
+	return draw, nil
+}
+
+// drawTickets ranks the given tickets with the seed of the
+// given draw and returns their results in the order of their
+// rank. The tickets first take the reserved seats of their
+// attribute value or the open seats, then the seats left go
+// to the next ranked tickets of any value.
+func drawTickets(draw ticket.Draw, tickets []ticket.Ticket) []ticket.DrawResult {
+	ranked := rankTickets(tickets, draw.Seed)
+
+	reserved := make(map[string]int, len(draw.KuotaAtribut))
+	open := draw.Kuota
+	for _, quota := range draw.KuotaAtribut {
+		reserved[quota.Nilai] = quota.Kuota
+		open -= quota.Kuota
+	}
+
+	won := make([]bool, len(ranked))
+	winners := 0
+	for i, t := range ranked {
+		if winners == draw.Kuota {
+			break
+		}
+
+		value := attributeValue(draw, t)
+		switch {
+		case reserved[value] > 0:
+			reserved[value]--
+		case open > 0:
+			open--
+		default:
+			continue
+		}
+		won[i] = true
+		winners++
+	}
+
+	for i := range ranked {
+		if winners == draw.Kuota {
+			break
+		}
+		if !won[i] {
+			won[i] = true
+			winners++
+		}
+	}
+
+	results := make([]ticket.DrawResult, 0, len(ranked))
+	var winnerUrutan, waitlistUrutan int
+	for i, t := range ranked {
+		result := ticket.DrawResult{
+			TicketID:  t.ID,
+			Nama:      t.Nama,
+			Email:     t.Email,
+			Peringkat: i + 1,
+		}
+
+		if won[i] {
+			winnerUrutan++
+			result.Status = ticket.ResultStatusWinner
+			result.Urutan = winnerUrutan
+			result.NomorTiket = nomorTiket(winnerUrutan)
+		} else {
+			waitlistUrutan++
+			result.Status = ticket.ResultStatusWaitlist
+			result.Urutan = waitlistUrutan
+		}
+
+		results = append(results, result)
+	}
+
+	return results
+}
+
+// rankTickets returns the given tickets sorted by the hash of
+// the given seed and their ID, see ticket.Draw.
+func rankTickets(tickets []ticket.Ticket, seed string) []ticket.Ticket {
+	keys := make(map[int64]string, len(tickets))
+	for _, t := range tickets {
+		keys[t.ID] = rankKey(seed, t.ID)
+	}
+
+	ranked := append([]ticket.Ticket(nil), tickets...)
+	sort.SliceStable(ranked, func(i, j int) bool {
+		ki, kj := keys[ranked[i].ID], keys[ranked[j].ID]
+		if ki != kj {
+			return ki < kj
+		}
+		return ranked[i].ID < ranked[j].ID
+	})
+
+	return ranked
+}
+
+// rankKey returns the rank key of the ticket with the given
+// ID.
+func rankKey(seed string, ticketID int64) string {
+	sum := sha256.Sum256([]byte(seed + ":" + strconv.FormatInt(ticketID, 10)))
+	return hex.EncodeToString(sum[:])
+}
+
+// nomorTiket returns the ticket number of the seat with the
+// given order.
+func nomorTiket(urutan int) string {
+	return fmt.Sprintf("TICKET/TEDXUB/%02d", urutan)
+}
+
+// seedHash returns the published hash of the given seed.
+func seedHash(seed string) string {
+	sum := sha256.Sum256([]byte(seed))
+	return hex.EncodeToString(sum[:])
+}
+
+// generateSeed returns a new random seed.
+func generateSeed() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// attributeValue returns the value of the quota attribute of
+// the given draw of the given ticket, compared
+// case-insensitively.
+func attributeValue(draw ticket.Draw, t ticket.Ticket) string {
+	if len(draw.KuotaAtribut) == 0 {
+		return ""
+	}
+	return strings.ToLower(strings.TrimSpace(draw.KuotaAtribut[0].Atribut.ValueOf(t)))
+}
+
+// normalizeDraw trims the quota values of the given draw.
+func normalizeDraw(draw ticket.Draw) ticket.Draw {
+	quotas := make([]ticket.AttributeQuota, 0, len(draw.KuotaAtribut))
+	for _, quota := range draw.KuotaAtribut {
+		quota.Nilai = strings.ToLower(strings.TrimSpace(quota.Nilai))
+		quotas = append(quotas, quota)
+	}
+	draw.KuotaAtribut = quotas
+
+	return draw
+}
+
+// validateDraw validates fields of the given draw whether its
+// comply the predetermined rules.
+func validateDraw(draw ticket.Draw) error {
+	if draw.Kuota <= 0 {
+		return ticket.ErrInvalidDraw
+	}
+
+	total := 0
+	values := make(map[string]struct{}, len(draw.KuotaAtribut))
+	for _, quota := range draw.KuotaAtribut {
+		if _, ok := ticket.AttributeList[quota.Atribut]; !ok || quota.Atribut != draw.KuotaAtribut[0].Atribut {
+			return ticket.ErrInvalidDraw
+		}
+		if _, ok := values[quota.Nilai]; ok || quota.Nilai == "" || quota.Kuota <= 0 {
+			return ticket.ErrInvalidDraw
+		}
+		values[quota.Nilai] = struct{}{}
+		total += quota.Kuota
+	}
+
+	if total > draw.Kuota {
+		return ticket.ErrInvalidDraw
+	}
+
+	return nil
+}
@@ -3,11 +3,11 @@ package service
 import (
 	"context"
 	"fmt"
-	"log"
-	"math/rand"
 	"net/mail"
 	"strings"
 
+	"github.com/tedxub2023/global/logger"
+	"github.com/tedxub2023/internal/event"
 	"github.com/tedxub2023/internal/ticket"
 )
 
@@ -25,6 +25,12 @@ func (s *service) CreateTicket(ctx context.Context, reqTicket ticket.Ticket) (st
 
 	reqTicket.CreateTime = createTime
 
+	ev, err := s.currentEvent(ctx)
+	if err != nil {
+		return "", err
+	}
+	reqTicket.EventID = ev.ID
+
 	// get pg store client without using transaction
 	pgStoreClient, err := s.pgStore.NewClient(true)
 	if err != nil {
@@ -34,13 +40,19 @@ func (s *service) CreateTicket(ctx context.Context, reqTicket ticket.Ticket) (st
 	defer func() error {
 		if err != nil {
 			pgStoreClient.Rollback()
-			log.Printf("[tedxub2023-api-service] Service got rollback, error occured: %s\n", err.Error())
+			logger.Error(ctx, "service got rollback", err)
 			return err
 		}
 		return nil
 	}()
 
-	totalEmail, totalNomorIdentitas, err := pgStoreClient.CountUniqueValue(ctx, reqTicket.Email, reqTicket.NomorIdentitas)
+	// the registration closes once the draw is committed
+	err = checkRegistrationOpen(ctx, pgStoreClient, ev.ID)
+	if err != nil {
+		return "", err
+	}
+
+	totalEmail, totalNomorIdentitas, err := pgStoreClient.CountUniqueValue(ctx, ev.ID, reqTicket.Email, reqTicket.NomorIdentitas)
 	if err != nil {
 		return "", err
 	}
@@ -59,21 +71,23 @@ func (s *service) CreateTicket(ctx context.Context, reqTicket ticket.Ticket) (st
 	}
 
 	if err = pgStoreClient.Commit(); err != nil {
-		log.Printf("[tedxub2023-api-service] Failed to commit the transaction.: %s\n", err.Error())
+		logger.Error(ctx, "failed to commit the transaction", err)
 		return "", err
 	}
 
-	go sendEmail(reqTicket)
+	s.workers.Go(ctx, "ticket registration mail", func(ctx context.Context) error {
+		return s.sendEmail(ev, reqTicket)
+	})
 
 	return ticketNama, nil
 }
 
-func sendEmail(reqTicket ticket.Ticket) error {
-	mail := NewMailClient()
-	mail.SetSender("tedxuniversitasbrawijaya@gmail.com")
+func (s *service) sendEmail(ev event.Event, reqTicket ticket.Ticket) error {
+	mail := NewMailClient(s.config.Mail)
+	mail.SetSender(s.config.Mail.Sender)
 	mail.SetReciever(reqTicket.Email)
-	mail.SetSubject("Registrasi Panggung Swara Insan")
-	if err := mail.SetBodyHTML(reqTicket.Nama); err != nil {
+	mail.SetSubject(fmt.Sprintf("Registrasi %s", ev.Nama))
+	if err := mail.SetBodyHTML(ev, reqTicket.Nama); err != nil {
 		return err
 	}
 	if err := mail.SendMail(); err != nil {
@@ -125,105 +139,3 @@ func validateTicket(reqTicket ticket.Ticket) error {
 
 	return nil
 }
-
-func (s *service) UpdateTicket(ctx context.Context) error {
-	pgStoreClient, err := s.pgStore.NewClient(true)
-	if err != nil {
-		return ticket.ErrFailedTransaction
-	}
-
-	defer func() error {
-		if err != nil {
-			pgStoreClient.Rollback()
-			log.Printf("[tedxub2023-api-service] Service got rollback, error occured: %s\n", err.Error())
-			return err
-		}
-		return nil
-	}()
-
-	tickets, err := pgStoreClient.GetAllTicket(ctx)
-	if err != nil {
-		return err
-	}
-
-	if len(tickets) == 0 {
-		return ticket.ErrTicketNotFound
-	}
-
-	rand.Shuffle(len(tickets), func(i, j int) {
-		tickets[i], tickets[j] = tickets[j], tickets[i]
-	})
-
-	var womanTickets []ticket.Ticket
-	var manTickets []ticket.Ticket
-
-	for _, ticket := range tickets {
-		if ticket.JenisKelamin == "Wanita" {
-			womanTickets = append(womanTickets, ticket)
-		} else {
-			manTickets = append(manTickets, ticket)
-		}
-	}
-
-	var womanCount int
-	var manCount int
-	if len(womanTickets) < 18 && len(womanTickets) < len(manTickets) {
-		womanCount = len(womanTickets)
-		manCount = 35 - len(womanTickets)
-	} else if len(manTickets) < 17 && len(manTickets) < len(womanTickets) {
-		manCount = len(manTickets)
-		womanCount = 35 - len(manTickets)
-	} else if len(womanTickets) < 18 && len(manTickets) < 17 {
-		manCount = len(manTickets)
-		womanCount = len(womanTickets)
-	} else {
-		manCount = 17
-		womanCount = 18
-	}
-
-	for i := 0; i < len(womanTickets); i++ {
-		status := i < womanCount
-		ticketKey := fmt.Sprintf("TICKET/TEDXUB/%02d", i+1)
-
-		req := ticket.Ticket{
-			ID:         womanTickets[i].ID,
-			Status:     status,
-			NomorTiket: ticketKey,
-			UpdateTime: s.timeNow(),
-		}
-
-		if status {
-			if err := pgStoreClient.UpdateTicket(ctx, req); err != nil {
-				return err
-			}
-		} else {
-			break
-		}
-	}
-
-	for i := 0; i < len(manTickets); i++ {
-		status := i < manCount
-		ticketKey := fmt.Sprintf("TICKET/TEDXUB/%02d", (i + 1 + womanCount))
-
-		req := ticket.Ticket{
-			ID:         manTickets[i].ID,
-			Status:     status,
-			NomorTiket: ticketKey,
-			UpdateTime: s.timeNow(),
-		}
-
-		if status {
-			if err := pgStoreClient.UpdateTicket(ctx, req); err != nil {
-				return err
-			}
-		} else {
-			break
-		}
-	}
-
-	if err = pgStoreClient.Commit(); err != nil {
-		log.Printf("[tedxub2023-api-service] Failed to commit the transaction.: %s\n", err.Error())
-		return err
-	}
-	return nil
-}
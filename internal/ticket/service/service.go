@@ -1,17 +1,66 @@
 package service
 
-import "time"
+import (
+	"sync"
+	"time"
+
+	"github.com/tedxub2023/global/worker"
+	"github.com/tedxub2023/internal/event"
+)
+
+// Config holds the configuration of the service.
+type Config struct {
+	Mail MailConfig
+
+	// EventSlug is the slug of the event the tickets are
+	// registered for, its name and branding are used in the
+	// emails.
+	EventSlug string
+
+	// ConfirmationTTL is the duration the winners of the draw
+	// have to confirm their attendance before the seat goes
+	// to the waitlist.
+	ConfirmationTTL time.Duration
+
+	// ConfirmationURL is the page the confirmation links
+	// point to, the confirmation token is added as the
+	// "token" query parameter.
+	ConfirmationURL string
+
+	// LookupCodeTTL is the duration a lookup code emailed to
+	// a registrant is valid, and LookupSessionTTL the
+	// duration of the session it opens.
+	LookupCodeTTL    time.Duration
+	LookupSessionTTL time.Duration
+}
 
 // New construts a new service.
 type service struct {
 	pgStore PGStore
+	workers *worker.Group
+	event   event.Service
+	config  Config
 	timeNow func() time.Time
+
+	// notifyMu keeps the draw result emails from being sent
+	// twice by concurrent jobs.
+	notifyMu sync.Mutex
 }
 
 // New returns a new service
-func New(pgStore PGStore) (*service, error) {
-	return &service{
+func New(pgStore PGStore, workers *worker.Group, eventSvc event.Service, config Config) (*service, error) {
+	s := &service{
 		pgStore: pgStore,
+		workers: workers,
+		event:   eventSvc,
+		config:  config,
 		timeNow: time.Now,
-	}, nil
+	}
+
+	// schedule the expiration of unconfirmed winners
+	if err := s.addCronJobs(); err != nil {
+		return nil, err
+	}
+
+	return s, nil
 }
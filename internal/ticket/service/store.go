@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"time"
 
 	"github.com/tedxub2023/internal/ticket"
 )
@@ -22,9 +23,100 @@ type PGStoreClient interface {
 	// created ticket ID.
 	CreateTicket(ctx context.Context, ticket ticket.Ticket) (string, error)
 
-	GetAllTicket(ctx context.Context) ([]ticket.Ticket, error)
+	// GetAllTicket returns the tickets registered for the
+	// given event.
+	GetAllTicket(ctx context.Context, eventID int64) ([]ticket.Ticket, error)
 
 	UpdateTicket(ctx context.Context, t ticket.Ticket) error
 
-	CountUniqueValue(ctx context.Context, email, numberIdentity string) (int, int, error)
+	// CountUniqueValue returns the number of tickets of the
+	// given event with the given email and with the given
+	// identity number.
+	CountUniqueValue(ctx context.Context, eventID int64, email, numberIdentity string) (int, int, error)
+
+	// GetTicketByID returns the ticket with the given ID. It
+	// returns ticket.ErrTicketNotFound if there is none.
+	GetTicketByID(ctx context.Context, ticketID int64) (ticket.Ticket, error)
+
+	// GetTicketByIdentity returns the ticket with the given
+	// email, compared case-insensitively, and identity
+	// number. It returns ticket.ErrTicketNotFound if there is
+	// none.
+	GetTicketByIdentity(ctx context.Context, email, nomorIdentitas string) (ticket.Ticket, error)
+
+	// UpdateTicketData updates the registration data of the
+	// given ticket besides its email and identity number.
+	UpdateTicketData(ctx context.Context, t ticket.Ticket) error
+
+	// DeleteTicket deletes the ticket with the given ID and
+	// its lookups.
+	DeleteTicket(ctx context.Context, ticketID int64) error
+
+	// CreateLookup creates a new lookup and returns the
+	// created lookup ID.
+	CreateLookup(ctx context.Context, lookup ticket.Lookup) (int64, error)
+
+	// GetLatestLookup returns the latest lookup of the given
+	// ticket. It returns ticket.ErrInvalidLookupCode if there
+	// is none.
+	GetLatestLookup(ctx context.Context, ticketID int64) (ticket.Lookup, error)
+
+	// GetLookupByToken returns the lookup with the given
+	// session token hash. It returns
+	// ticket.ErrInvalidLookupToken if there is none.
+	GetLookupByToken(ctx context.Context, tokenHash string) (ticket.Lookup, error)
+
+	// AddLookupAttempt counts an attempt of the lookup with
+	// the given ID if it has been tried fewer than the given
+	// times. It returns ticket.ErrLookupCodeExpired otherwise.
+	AddLookupAttempt(ctx context.Context, lookupID int64, maxAttempts int) error
+
+	// UseLookup stores the session of the given lookup if its
+	// code has not been used. It returns
+	// ticket.ErrInvalidLookupCode otherwise.
+	UseLookup(ctx context.Context, lookup ticket.Lookup) error
+
+	// CreateDraw creates a new draw with its quotas and
+	// returns the created draw ID. It returns
+	// ticket.ErrDrawExists if the event of the draw already
+	// has one.
+	CreateDraw(ctx context.Context, draw ticket.Draw) (int64, error)
+
+	// GetDraw returns the draw of the given event with its
+	// quotas. It returns ticket.ErrDrawNotFound if there is
+	// no draw.
+	GetDraw(ctx context.Context, eventID int64) (ticket.Draw, error)
+
+	// GetDrawForUpdate is GetDraw locking the draw until the
+	// transaction ends.
+	GetDrawForUpdate(ctx context.Context, eventID int64) (ticket.Draw, error)
+
+	// GetDrawResults returns the results of the given draw
+	// in the order of their rank.
+	GetDrawResults(ctx context.Context, drawID int64) ([]ticket.DrawResult, error)
+
+	// CreateDrawResult stores a result of the given draw.
+	CreateDrawResult(ctx context.Context, drawID int64, result ticket.DrawResult) error
+
+	// UpdateDrawResult updates the status, order, ticket
+	// number, confirmation and notify time of the given
+	// result of the given draw if it is still in the given
+	// status. It returns ticket.ErrInvalidConfirmationToken
+	// otherwise.
+	UpdateDrawResult(ctx context.Context, drawID int64, result ticket.DrawResult, from ticket.ResultStatus, updateTime time.Time) error
+
+	// UpdateDrawResultToken stores the confirmation token
+	// hash of the given winner of the given draw if it has
+	// not been notified yet. It returns
+	// ticket.ErrInvalidConfirmationToken otherwise.
+	UpdateDrawResultToken(ctx context.Context, drawID int64, result ticket.DrawResult) error
+
+	// UpdateDrawResultNotifyTime marks the given result of
+	// the given draw as notified at the given time.
+	UpdateDrawResultNotifyTime(ctx context.Context, drawID int64, result ticket.DrawResult, notifyTime time.Time) error
+
+	// UpdateDrawStatus updates the status and draw time of
+	// the given draw if it is still in the given status. It
+	// returns ticket.ErrDrawAlreadyRun otherwise.
+	UpdateDrawStatus(ctx context.Context, draw ticket.Draw, from ticket.DrawStatus) error
 }
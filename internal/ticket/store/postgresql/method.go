@@ -2,6 +2,9 @@ package postgresql
 
 import (
 	"context"
+	"database/sql"
+	"fmt"
+	"time"
 
 	"github.com/jmoiron/sqlx"
 	"github.com/tedxub2023/internal/ticket"
@@ -10,6 +13,7 @@ import (
 func (sc *storeClient) CreateTicket(ctx context.Context, reqTicket ticket.Ticket) (string, error) {
 	// construct arguments filled with fields for the query
 	argsKV := map[string]interface{}{
+		"event_id":        reqTicket.EventID,
 		"nama":            reqTicket.Nama,
 		"jenis_kelamin":   reqTicket.JenisKelamin,
 		"nomor_identitas": reqTicket.NomorIdentitas,
@@ -35,7 +39,7 @@ func (sc *storeClient) CreateTicket(ctx context.Context, reqTicket ticket.Ticket
 
 	// execute query
 	var ticketNama string
-	err = sc.q.QueryRowx(query, args...).Scan(&ticketNama)
+	err = sc.q.QueryRowxContext(ctx, query, args...).Scan(&ticketNama)
 	if err != nil {
 		return "", err
 	}
@@ -43,10 +47,12 @@ func (sc *storeClient) CreateTicket(ctx context.Context, reqTicket ticket.Ticket
 	return ticketNama, nil
 }
 
-func (sc *storeClient) GetAllTicket(ctx context.Context) ([]ticket.Ticket, error) {
+func (sc *storeClient) GetAllTicket(ctx context.Context, eventID int64) ([]ticket.Ticket, error) {
 	var tickets []ticket.Ticket
 
-	query, args, err := sqlx.Named(queryGetAllTicket, map[string]interface{}{})
+	query, args, err := sqlx.Named(queryGetAllTicket, map[string]interface{}{
+		"event_id": eventID,
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -56,7 +62,7 @@ func (sc *storeClient) GetAllTicket(ctx context.Context) ([]ticket.Ticket, error
 	}
 	query = sc.q.Rebind(query)
 
-	rows, err := sc.q.Queryx(query, args...)
+	rows, err := sc.q.QueryxContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -99,7 +105,7 @@ func (sc *storeClient) UpdateTicket(ctx context.Context, t ticket.Ticket) error
 
 	queryUpdate = sc.q.Rebind(queryUpdate)
 
-	_, err = sc.q.Exec(queryUpdate, args...)
+	_, err = sc.q.ExecContext(ctx, queryUpdate, args...)
 
 	if err != nil {
 		return err
@@ -108,9 +114,10 @@ func (sc *storeClient) UpdateTicket(ctx context.Context, t ticket.Ticket) error
 	return nil
 }
 
-func (sc *storeClient) CountUniqueValue(ctx context.Context, email, numberIdentity string) (int, int, error) {
+func (sc *storeClient) CountUniqueValue(ctx context.Context, eventID int64, email, numberIdentity string) (int, int, error) {
 	argKV1 := map[string]interface{}{
-		"email": email,
+		"event_id": eventID,
+		"email":    email,
 	}
 
 	query, args, err := sqlx.Named(queryCountEmail, argKV1)
@@ -124,11 +131,12 @@ func (sc *storeClient) CountUniqueValue(ctx context.Context, email, numberIdenti
 	query = sc.q.Rebind(query)
 
 	var countEmail int
-	if err := sc.q.QueryRowx(query, args...).Scan(&countEmail); err != nil {
+	if err := sc.q.QueryRowxContext(ctx, query, args...).Scan(&countEmail); err != nil {
 		return -1, -1, err
 	}
 
 	argKV2 := map[string]interface{}{
+		"event_id":        eventID,
 		"nomor_identitas": numberIdentity,
 	}
 
@@ -143,9 +151,489 @@ func (sc *storeClient) CountUniqueValue(ctx context.Context, email, numberIdenti
 	query = sc.q.Rebind(query)
 
 	var countNumberIdentity int
-	if err := sc.q.QueryRowx(query, args...).Scan(&countNumberIdentity); err != nil {
+	if err := sc.q.QueryRowxContext(ctx, query, args...).Scan(&countNumberIdentity); err != nil {
 		return -1, -1, err
 	}
 
 	return countEmail, countNumberIdentity, nil
 }
+
+func (sc *storeClient) CreateDraw(ctx context.Context, draw ticket.Draw) (int64, error) {
+	// construct arguments filled with fields for the query
+	argsKV := map[string]interface{}{
+		"event_id":    draw.EventID,
+		"kuota":       draw.Kuota,
+		"seed":        draw.Seed,
+		"seed_hash":   draw.SeedHash,
+		"status":      draw.Status.String(),
+		"commit_time": draw.CommitTime,
+	}
+
+	// prepare query
+	query, args, err := sqlx.Named(queryCreateDraw, argsKV)
+	if err != nil {
+		return 0, err
+	}
+	query = sc.q.Rebind(query)
+
+	// execute query
+	var drawID int64
+	err = sc.q.QueryRowxContext(ctx, query, args...).Scan(&drawID)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return 0, ticket.ErrDrawExists
+		}
+		return 0, err
+	}
+
+	for _, quota := range draw.KuotaAtribut {
+		argsKV := map[string]interface{}{
+			"draw_id": drawID,
+			"atribut": quota.Atribut.String(),
+			"nilai":   quota.Nilai,
+			"kuota":   quota.Kuota,
+		}
+
+		query, args, err := sqlx.Named(queryCreateDrawQuota, argsKV)
+		if err != nil {
+			return 0, err
+		}
+		query = sc.q.Rebind(query)
+
+		_, err = sc.q.ExecContext(ctx, query, args...)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	return drawID, nil
+}
+
+func (sc *storeClient) GetDraw(ctx context.Context, eventID int64) (ticket.Draw, error) {
+	return sc.getDraw(ctx, fmt.Sprintf(queryGetDraw, ""), eventID)
+}
+
+func (sc *storeClient) GetDrawForUpdate(ctx context.Context, eventID int64) (ticket.Draw, error) {
+	return sc.getDraw(ctx, fmt.Sprintf(queryGetDraw, "FOR UPDATE"), eventID)
+}
+
+// getDraw returns the draw of the given event read by the
+// given query with its quotas.
+func (sc *storeClient) getDraw(ctx context.Context, query string, eventID int64) (ticket.Draw, error) {
+	// query single row
+	var ddb drawDB
+	err := sc.q.QueryRowxContext(ctx, query, eventID).StructScan(&ddb)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return ticket.Draw{}, ticket.ErrDrawNotFound
+		}
+		return ticket.Draw{}, err
+	}
+	draw := ddb.format()
+
+	rows, err := sc.q.QueryxContext(ctx, queryGetDrawQuotas, draw.ID)
+	if err != nil {
+		return ticket.Draw{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var row drawQuotaDB
+		err = rows.StructScan(&row)
+		if err != nil {
+			return ticket.Draw{}, err
+		}
+		draw.KuotaAtribut = append(draw.KuotaAtribut, row.format())
+	}
+
+	if err := rows.Err(); err != nil {
+		return ticket.Draw{}, err
+	}
+
+	return draw, nil
+}
+
+func (sc *storeClient) GetDrawResults(ctx context.Context, drawID int64) ([]ticket.DrawResult, error) {
+	// query to database
+	rows, err := sc.q.QueryxContext(ctx, queryGetDrawResults, drawID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	// read results
+	result := make([]ticket.DrawResult, 0)
+	for rows.Next() {
+		var row drawResultDB
+		err = rows.StructScan(&row)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, row.format())
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (sc *storeClient) CreateDrawResult(ctx context.Context, drawID int64, result ticket.DrawResult) error {
+	// construct arguments filled with fields for the query
+	argsKV := map[string]interface{}{
+		"draw_id":          drawID,
+		"ticket_id":        result.TicketID,
+		"peringkat":        result.Peringkat,
+		"status":           result.Status.String(),
+		"urutan":           result.Urutan,
+		"nomor_tiket":      nullString(result.NomorTiket),
+		"token_hash":       nullString(result.TokenHash),
+		"confirm_deadline": nullTime(result.ConfirmDeadline),
+	}
+
+	// prepare query
+	query, args, err := sqlx.Named(queryCreateDrawResult, argsKV)
+	if err != nil {
+		return err
+	}
+	query = sc.q.Rebind(query)
+
+	// execute query
+	_, err = sc.q.ExecContext(ctx, query, args...)
+	return err
+}
+
+func (sc *storeClient) UpdateDrawResult(ctx context.Context, drawID int64, result ticket.DrawResult, from ticket.ResultStatus, updateTime time.Time) error {
+	// construct arguments filled with fields for the query
+	argsKV := map[string]interface{}{
+		"status":           result.Status.String(),
+		"urutan":           result.Urutan,
+		"nomor_tiket":      nullString(result.NomorTiket),
+		"token_hash":       nullString(result.TokenHash),
+		"confirm_deadline": nullTime(result.ConfirmDeadline),
+		"notify_time":      nullTime(result.NotifyTime),
+		"update_time":      updateTime,
+		"draw_id":          drawID,
+		"ticket_id":        result.TicketID,
+		"from_status":      from.String(),
+	}
+
+	// prepare query
+	query, args, err := sqlx.Named(queryUpdateDrawResult, argsKV)
+	if err != nil {
+		return err
+	}
+	query = sc.q.Rebind(query)
+
+	// execute query
+	res, err := sc.q.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ticket.ErrInvalidConfirmationToken
+	}
+
+	return nil
+}
+
+func (sc *storeClient) UpdateDrawResultToken(ctx context.Context, drawID int64, result ticket.DrawResult) error {
+	// construct arguments filled with fields for the query
+	argsKV := map[string]interface{}{
+		"token_hash": result.TokenHash,
+		"draw_id":    drawID,
+		"ticket_id":  result.TicketID,
+		"status":     ticket.ResultStatusWinner.String(),
+	}
+
+	// prepare query
+	query, args, err := sqlx.Named(queryUpdateDrawResultToken, argsKV)
+	if err != nil {
+		return err
+	}
+	query = sc.q.Rebind(query)
+
+	// execute query
+	res, err := sc.q.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ticket.ErrInvalidConfirmationToken
+	}
+
+	return nil
+}
+
+func (sc *storeClient) UpdateDrawResultNotifyTime(ctx context.Context, drawID int64, result ticket.DrawResult, notifyTime time.Time) error {
+	// construct arguments filled with fields for the query
+	argsKV := map[string]interface{}{
+		"notify_time": notifyTime,
+		"draw_id":     drawID,
+		"ticket_id":   result.TicketID,
+	}
+
+	// prepare query
+	query, args, err := sqlx.Named(queryUpdateDrawResultNotifyTime, argsKV)
+	if err != nil {
+		return err
+	}
+	query = sc.q.Rebind(query)
+
+	// execute query
+	_, err = sc.q.ExecContext(ctx, query, args...)
+	return err
+}
+
+func (sc *storeClient) UpdateDrawStatus(ctx context.Context, draw ticket.Draw, from ticket.DrawStatus) error {
+	// construct arguments filled with fields for the query
+	argsKV := map[string]interface{}{
+		"status":      draw.Status.String(),
+		"draw_time":   draw.DrawTime,
+		"id":          draw.ID,
+		"from_status": from.String(),
+	}
+
+	// prepare query
+	query, args, err := sqlx.Named(queryUpdateDrawStatus, argsKV)
+	if err != nil {
+		return err
+	}
+	query = sc.q.Rebind(query)
+
+	// execute query
+	res, err := sc.q.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ticket.ErrDrawAlreadyRun
+	}
+
+	return nil
+}
+
+func (sc *storeClient) GetTicketByID(ctx context.Context, ticketID int64) (ticket.Ticket, error) {
+	query := fmt.Sprintf(queryGetTicket, "WHERE id = $1")
+
+	// query single row
+	var tdb TicketDB
+	err := sc.q.QueryRowxContext(ctx, query, ticketID).StructScan(&tdb)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return ticket.Ticket{}, ticket.ErrTicketNotFound
+		}
+		return ticket.Ticket{}, err
+	}
+
+	return tdb.formatting(), nil
+}
+
+func (sc *storeClient) GetTicketByIdentity(ctx context.Context, email, nomorIdentitas string) (ticket.Ticket, error) {
+	query := fmt.Sprintf(queryGetTicket, "WHERE lower(email) = lower($1) AND nomor_identitas = $2 ORDER BY id LIMIT 1")
+
+	// query single row
+	var tdb TicketDB
+	err := sc.q.QueryRowxContext(ctx, query, email, nomorIdentitas).StructScan(&tdb)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return ticket.Ticket{}, ticket.ErrTicketNotFound
+		}
+		return ticket.Ticket{}, err
+	}
+
+	return tdb.formatting(), nil
+}
+
+func (sc *storeClient) UpdateTicketData(ctx context.Context, t ticket.Ticket) error {
+	// construct arguments filled with fields for the query
+	argsKV := map[string]interface{}{
+		"nama":           t.Nama,
+		"jenis_kelamin":  t.JenisKelamin,
+		"asal_institusi": t.AsalInstitusi,
+		"domisili":       t.Domisili,
+		"nomor_telepon":  t.NomorTelepon,
+		"line_id":        t.LineID,
+		"instagram":      t.Instagram,
+		"update_time":    t.UpdateTime,
+		"id":             t.ID,
+	}
+
+	// prepare query
+	query, args, err := sqlx.Named(queryUpdateTicketData, argsKV)
+	if err != nil {
+		return err
+	}
+	query = sc.q.Rebind(query)
+
+	// execute query
+	res, err := sc.q.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ticket.ErrTicketNotFound
+	}
+
+	return nil
+}
+
+func (sc *storeClient) DeleteTicket(ctx context.Context, ticketID int64) error {
+	res, err := sc.q.ExecContext(ctx, queryDeleteTicket, ticketID)
+	if err != nil {
+		return err
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ticket.ErrTicketNotFound
+	}
+
+	return nil
+}
+
+func (sc *storeClient) CreateLookup(ctx context.Context, lookup ticket.Lookup) (int64, error) {
+	// construct arguments filled with fields for the query
+	argsKV := map[string]interface{}{
+		"ticket_id":   lookup.TicketID,
+		"kode_hash":   lookup.KodeHash,
+		"expire_time": lookup.ExpireTime,
+		"create_time": lookup.CreateTime,
+	}
+
+	// prepare query
+	query, args, err := sqlx.Named(queryCreateLookup, argsKV)
+	if err != nil {
+		return 0, err
+	}
+	query = sc.q.Rebind(query)
+
+	// execute query
+	var lookupID int64
+	err = sc.q.QueryRowxContext(ctx, query, args...).Scan(&lookupID)
+	if err != nil {
+		return 0, err
+	}
+
+	return lookupID, nil
+}
+
+func (sc *storeClient) GetLatestLookup(ctx context.Context, ticketID int64) (ticket.Lookup, error) {
+	query := fmt.Sprintf(queryGetLookup, "WHERE ticket_id = $1 ORDER BY id DESC LIMIT 1")
+
+	// query single row
+	var ldb lookupDB
+	err := sc.q.QueryRowxContext(ctx, query, ticketID).StructScan(&ldb)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return ticket.Lookup{}, ticket.ErrInvalidLookupCode
+		}
+		return ticket.Lookup{}, err
+	}
+
+	return ldb.format(), nil
+}
+
+func (sc *storeClient) GetLookupByToken(ctx context.Context, tokenHash string) (ticket.Lookup, error) {
+	query := fmt.Sprintf(queryGetLookup, "WHERE token_hash = $1")
+
+	// query single row
+	var ldb lookupDB
+	err := sc.q.QueryRowxContext(ctx, query, tokenHash).StructScan(&ldb)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return ticket.Lookup{}, ticket.ErrInvalidLookupToken
+		}
+		return ticket.Lookup{}, err
+	}
+
+	return ldb.format(), nil
+}
+
+func (sc *storeClient) AddLookupAttempt(ctx context.Context, lookupID int64, maxAttempts int) error {
+	// construct arguments filled with fields for the query
+	argsKV := map[string]interface{}{
+		"id":            lookupID,
+		"max_percobaan": maxAttempts,
+	}
+
+	// prepare query
+	query, args, err := sqlx.Named(queryAddLookupAttempt, argsKV)
+	if err != nil {
+		return err
+	}
+	query = sc.q.Rebind(query)
+
+	// execute query
+	res, err := sc.q.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ticket.ErrLookupCodeExpired
+	}
+
+	return nil
+}
+
+func (sc *storeClient) UseLookup(ctx context.Context, lookup ticket.Lookup) error {
+	// construct arguments filled with fields for the query
+	argsKV := map[string]interface{}{
+		"token_hash":        lookup.TokenHash,
+		"token_expire_time": lookup.TokenExpireTime,
+		"use_time":          lookup.UseTime,
+		"id":                lookup.ID,
+	}
+
+	// prepare query
+	query, args, err := sqlx.Named(queryUseLookup, argsKV)
+	if err != nil {
+		return err
+	}
+	query = sc.q.Rebind(query)
+
+	// execute query
+	res, err := sc.q.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ticket.ErrInvalidLookupCode
+	}
+
+	return nil
+}
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 	"github.com/tedxub2023/internal/ticket"
 	"github.com/tedxub2023/internal/ticket/service"
 )
@@ -21,7 +22,7 @@ type store struct {
 
 // storeClient implements configuration/service.PGStoreClient
 type storeClient struct {
-	q sqlx.Ext
+	q sqlx.ExtContext
 }
 
 // New creates a new store.
@@ -34,7 +35,7 @@ func New(db *sqlx.DB) (*store, error) {
 }
 
 func (s *store) NewClient(useTx bool) (service.PGStoreClient, error) {
-	var q sqlx.Ext
+	var q sqlx.ExtContext
 
 	// determine what object should be use as querier
 	q = s.db
@@ -67,6 +68,7 @@ func (sc *storeClient) Rollback() error {
 
 type TicketDB struct {
 	ID             int64      `db:"id"`
+	EventID        *int64     `db:"event_id"`
 	Nama           string     `db:"nama"`
 	JenisKelamin   string     `db:"jenis_kelamin"`
 	NomorIdentitas string     `db:"nomor_identitas"`
@@ -97,6 +99,10 @@ func (tdb *TicketDB) formatting() ticket.Ticket {
 		CreateTime:     tdb.CreateTime,
 	}
 
+	if tdb.EventID != nil {
+		t.EventID = *tdb.EventID
+	}
+
 	if tdb.Status != nil {
 		t.Status = *tdb.Status
 	}
@@ -111,3 +117,150 @@ func (tdb *TicketDB) formatting() ticket.Ticket {
 
 	return t
 }
+
+type drawDB struct {
+	ID         int64      `db:"id"`
+	EventID    int64      `db:"event_id"`
+	Kuota      int        `db:"kuota"`
+	Seed       string     `db:"seed"`
+	SeedHash   string     `db:"seed_hash"`
+	Status     string     `db:"status"`
+	CommitTime time.Time  `db:"commit_time"`
+	DrawTime   *time.Time `db:"draw_time"`
+}
+
+func (ddb *drawDB) format() ticket.Draw {
+	d := ticket.Draw{
+		ID:         ddb.ID,
+		EventID:    ddb.EventID,
+		Kuota:      ddb.Kuota,
+		Seed:       ddb.Seed,
+		SeedHash:   ddb.SeedHash,
+		Status:     ticket.DrawStatus(ddb.Status),
+		CommitTime: ddb.CommitTime,
+	}
+
+	if ddb.DrawTime != nil {
+		d.DrawTime = *ddb.DrawTime
+	}
+
+	return d
+}
+
+type drawQuotaDB struct {
+	Atribut string `db:"atribut"`
+	Nilai   string `db:"nilai"`
+	Kuota   int    `db:"kuota"`
+}
+
+func (qdb *drawQuotaDB) format() ticket.AttributeQuota {
+	return ticket.AttributeQuota{
+		Atribut: ticket.Attribute(qdb.Atribut),
+		Nilai:   qdb.Nilai,
+		Kuota:   qdb.Kuota,
+	}
+}
+
+type drawResultDB struct {
+	TicketID        int64      `db:"ticket_id"`
+	Nama            string     `db:"nama"`
+	Email           string     `db:"email"`
+	Peringkat       int        `db:"peringkat"`
+	Status          string     `db:"status"`
+	Urutan          int        `db:"urutan"`
+	NomorTiket      *string    `db:"nomor_tiket"`
+	TokenHash       *string    `db:"token_hash"`
+	ConfirmDeadline *time.Time `db:"confirm_deadline"`
+	NotifyTime      *time.Time `db:"notify_time"`
+}
+
+func (rdb *drawResultDB) format() ticket.DrawResult {
+	r := ticket.DrawResult{
+		TicketID:  rdb.TicketID,
+		Nama:      rdb.Nama,
+		Email:     rdb.Email,
+		Peringkat: rdb.Peringkat,
+		Status:    ticket.ResultStatus(rdb.Status),
+		Urutan:    rdb.Urutan,
+	}
+
+	if rdb.NomorTiket != nil {
+		r.NomorTiket = *rdb.NomorTiket
+	}
+
+	if rdb.TokenHash != nil {
+		r.TokenHash = *rdb.TokenHash
+	}
+
+	if rdb.ConfirmDeadline != nil {
+		r.ConfirmDeadline = *rdb.ConfirmDeadline
+	}
+
+	if rdb.NotifyTime != nil {
+		r.NotifyTime = *rdb.NotifyTime
+	}
+
+	return r
+}
+
+type lookupDB struct {
+	ID              int64      `db:"id"`
+	TicketID        int64      `db:"ticket_id"`
+	KodeHash        string     `db:"kode_hash"`
+	Percobaan       int        `db:"percobaan"`
+	ExpireTime      time.Time  `db:"expire_time"`
+	TokenHash       *string    `db:"token_hash"`
+	TokenExpireTime *time.Time `db:"token_expire_time"`
+	CreateTime      time.Time  `db:"create_time"`
+	UseTime         *time.Time `db:"use_time"`
+}
+
+func (ldb *lookupDB) format() ticket.Lookup {
+	l := ticket.Lookup{
+		ID:         ldb.ID,
+		TicketID:   ldb.TicketID,
+		KodeHash:   ldb.KodeHash,
+		Percobaan:  ldb.Percobaan,
+		ExpireTime: ldb.ExpireTime,
+		CreateTime: ldb.CreateTime,
+	}
+
+	if ldb.TokenHash != nil {
+		l.TokenHash = *ldb.TokenHash
+	}
+
+	if ldb.TokenExpireTime != nil {
+		l.TokenExpireTime = *ldb.TokenExpireTime
+	}
+
+	if ldb.UseTime != nil {
+		l.UseTime = *ldb.UseTime
+	}
+
+	return l
+}
+
+// nullString returns nil for the empty string, so that it
+// is stored as NULL.
+func nullString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+// nullTime returns nil for the zero time, so that it is
+// stored as NULL.
+func nullTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
+
+// isUniqueViolation returns whether the given error is a
+// unique constraint violation.
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23505"
+}
@@ -4,6 +4,7 @@ const queryCreateTicket = `
 	INSERT INTO
 		ticket
 	(
+		event_id,
 		nama,
 		jenis_kelamin,
 		nomor_identitas,
@@ -15,6 +16,7 @@ const queryCreateTicket = `
 		instagram,
 		create_time
 	) VALUES (
+		:event_id,
 		:nama,
 		:jenis_kelamin,
 		:nomor_identitas,
@@ -34,7 +36,7 @@ const queryUpdateTicket = `
 		ticket
 	SET 
 		status = :status, 
-		nomor_tiket = :nomor_tiket,
+		nomor_tiket = NULLIF(:nomor_tiket, ''),
 		update_time = :update_time
 	WHERE 
 		id = :id
@@ -43,6 +45,7 @@ const queryUpdateTicket = `
 const queryGetAllTicket = `
 	SELECT
 		id,
+		event_id,
 		nama,
 		jenis_kelamin,
 		nomor_identitas,
@@ -57,6 +60,8 @@ const queryGetAllTicket = `
 		create_time
 	FROM
 		ticket
+	WHERE
+		event_id = :event_id
 `
 
 const queryCountEmail = `
@@ -65,6 +70,7 @@ const queryCountEmail = `
 		FROM
 			ticket
 		WHERE
+			event_id = :event_id AND
 			email = :email
 `
 
@@ -74,5 +80,270 @@ const queryCountNumberIdentity = `
 		FROM
 			ticket
 		WHERE
+			event_id = :event_id AND
 			nomor_identitas = :nomor_identitas
 `
+
+const queryCreateDraw = `
+	INSERT INTO
+		ticket_draw
+	(
+		event_id,
+		kuota,
+		seed,
+		seed_hash,
+		status,
+		commit_time
+	) VALUES (
+		:event_id,
+		:kuota,
+		:seed,
+		:seed_hash,
+		:status,
+		:commit_time
+	) RETURNING
+		id
+`
+
+const queryCreateDrawQuota = `
+	INSERT INTO
+		ticket_draw_quota
+	(
+		draw_id,
+		atribut,
+		nilai,
+		kuota
+	) VALUES (
+		:draw_id,
+		:atribut,
+		:nilai,
+		:kuota
+	)
+`
+
+const queryGetDraw = `
+	SELECT
+		id,
+		event_id,
+		kuota,
+		seed,
+		seed_hash,
+		status,
+		commit_time,
+		draw_time
+	FROM
+		ticket_draw
+	WHERE
+		event_id = $1
+	%s
+`
+
+const queryGetDrawQuotas = `
+	SELECT
+		atribut,
+		nilai,
+		kuota
+	FROM
+		ticket_draw_quota
+	WHERE
+		draw_id = $1
+	ORDER BY
+		atribut,
+		nilai
+`
+
+const queryGetDrawResults = `
+	SELECT
+		r.ticket_id,
+		t.nama,
+		t.email,
+		r.peringkat,
+		r.status,
+		r.urutan,
+		r.nomor_tiket,
+		r.token_hash,
+		r.confirm_deadline,
+		r.notify_time
+	FROM
+		ticket_draw_result r
+	JOIN
+		ticket t ON t.id = r.ticket_id
+	WHERE
+		r.draw_id = $1
+	ORDER BY
+		r.peringkat
+`
+
+const queryCreateDrawResult = `
+	INSERT INTO
+		ticket_draw_result
+	(
+		draw_id,
+		ticket_id,
+		peringkat,
+		status,
+		urutan,
+		nomor_tiket,
+		token_hash,
+		confirm_deadline
+	) VALUES (
+		:draw_id,
+		:ticket_id,
+		:peringkat,
+		:status,
+		:urutan,
+		:nomor_tiket,
+		:token_hash,
+		:confirm_deadline
+	)
+`
+
+const queryUpdateDrawResult = `
+	UPDATE
+		ticket_draw_result
+	SET
+		status = :status,
+		urutan = :urutan,
+		nomor_tiket = :nomor_tiket,
+		token_hash = :token_hash,
+		confirm_deadline = :confirm_deadline,
+		notify_time = :notify_time,
+		update_time = :update_time
+	WHERE
+		draw_id = :draw_id AND
+		ticket_id = :ticket_id AND
+		status = :from_status
+`
+
+const queryUpdateDrawResultToken = `
+	UPDATE
+		ticket_draw_result
+	SET
+		token_hash = :token_hash
+	WHERE
+		draw_id = :draw_id AND
+		ticket_id = :ticket_id AND
+		status = :status AND
+		notify_time IS NULL
+`
+
+const queryUpdateDrawResultNotifyTime = `
+	UPDATE
+		ticket_draw_result
+	SET
+		notify_time = :notify_time
+	WHERE
+		draw_id = :draw_id AND
+		ticket_id = :ticket_id
+`
+
+const queryUpdateDrawStatus = `
+	UPDATE
+		ticket_draw
+	SET
+		status = :status,
+		draw_time = :draw_time
+	WHERE
+		id = :id AND
+		status = :from_status
+`
+
+const queryGetTicket = `
+	SELECT
+		id,
+		event_id,
+		nama,
+		jenis_kelamin,
+		nomor_identitas,
+		asal_institusi,
+		domisili,
+		email,
+		nomor_telepon,
+		line_id,
+		instagram,
+		status,
+		nomor_tiket,
+		create_time,
+		update_time
+	FROM
+		ticket
+	%s
+`
+
+const queryUpdateTicketData = `
+	UPDATE
+		ticket
+	SET
+		nama = :nama,
+		jenis_kelamin = :jenis_kelamin,
+		asal_institusi = :asal_institusi,
+		domisili = :domisili,
+		nomor_telepon = :nomor_telepon,
+		line_id = :line_id,
+		instagram = :instagram,
+		update_time = :update_time
+	WHERE
+		id = :id
+`
+
+const queryDeleteTicket = `
+	DELETE FROM
+		ticket
+	WHERE
+		id = $1
+`
+
+const queryCreateLookup = `
+	INSERT INTO
+		ticket_lookup
+	(
+		ticket_id,
+		kode_hash,
+		expire_time,
+		create_time
+	) VALUES (
+		:ticket_id,
+		:kode_hash,
+		:expire_time,
+		:create_time
+	) RETURNING
+		id
+`
+
+const queryGetLookup = `
+	SELECT
+		id,
+		ticket_id,
+		kode_hash,
+		percobaan,
+		expire_time,
+		token_hash,
+		token_expire_time,
+		create_time,
+		use_time
+	FROM
+		ticket_lookup
+	%s
+`
+
+const queryAddLookupAttempt = `
+	UPDATE
+		ticket_lookup
+	SET
+		percobaan = percobaan + 1
+	WHERE
+		id = :id AND
+		percobaan < :max_percobaan
+`
+
+const queryUseLookup = `
+	UPDATE
+		ticket_lookup
+	SET
+		token_hash = :token_hash,
+		token_expire_time = :token_expire_time,
+		use_time = :use_time
+	WHERE
+		id = :id AND
+		use_time IS NULL
+`
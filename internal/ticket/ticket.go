@@ -10,14 +10,74 @@ type Service interface {
 	// created ticket ID.
 	CreateTicket(ctx context.Context, ticket Ticket) (string, error)
 
-	// SendTicket sends a ticket to people who got the ticket
-	// by randomize
-	UpdateTicket(ctx context.Context) error
+	// CommitDraw commits the lottery of the free seats with
+	// the quotas of the given draw and returns the committed
+	// draw. The seed of the draw is generated and kept secret,
+	// only its hash is published until the draw is run. The
+	// registration closes once the draw is committed. Each
+	// event has one draw, it returns ErrDrawExists if the
+	// draw of the current event is already committed.
+	CommitDraw(ctx context.Context, draw Draw) (Draw, error)
+
+	// GetDraw returns the draw of the current event with its
+	// results. The seed is only returned once the draw is
+	// run.
+	GetDraw(ctx context.Context) (Draw, error)
+
+	// PreviewDraw returns the results of the given draw with
+	// the given seed over the tickets registered for the
+	// current event, without
+	// storing anything. A random seed is used if it is empty.
+	PreviewDraw(ctx context.Context, draw Draw, seed string) (Draw, error)
+
+	// RunDraw runs the committed draw of the current event
+	// over its tickets registered before the commit, stores its results and
+	// the ticket status, reveals the seed and queues the
+	// result emails. It returns ErrDrawAlreadyRun if the draw
+	// has been run.
+	RunDraw(ctx context.Context) (Draw, error)
+
+	// ConfirmAttendance confirms or declines the attendance
+	// of the winner with the given confirmation token and
+	// returns their result. The seat of a winner declining
+	// goes to the next ticket on the waitlist.
+	ConfirmAttendance(ctx context.Context, token string, hadir bool) (DrawResult, error)
+
+	// RequestLookupCode emails a one-time code to the
+	// registrant of the ticket with the given email and
+	// identity number. Nothing is sent if there is no such
+	// ticket, without telling the caller.
+	RequestLookupCode(ctx context.Context, email, nomorIdentitas string) error
+
+	// VerifyLookupCode verifies the given code of the
+	// registrant and returns the lookup with its session
+	// token.
+	VerifyLookupCode(ctx context.Context, email, nomorIdentitas, kode string) (Lookup, error)
+
+	// GetRegistration returns the registration of the given
+	// lookup session token.
+	GetRegistration(ctx context.Context, token string) (Registration, error)
+
+	// UpdateRegistration corrects the data of the ticket of
+	// the given lookup session token and returns the updated
+	// registration. The email and the identity number can not
+	// be changed. It returns ErrRegistrationClosed once the
+	// draw is committed.
+	UpdateRegistration(ctx context.Context, token string, ticket Ticket) (Registration, error)
+
+	// WithdrawRegistration deletes the ticket of the given
+	// lookup session token. It returns ErrRegistrationClosed
+	// once the draw is committed.
+	WithdrawRegistration(ctx context.Context, token string) error
 }
 
 // Ticket is a ticket.
 type Ticket struct {
-	ID             int64
+	ID int64
+
+	// EventID is the event the ticket is registered for.
+	EventID int64
+
 	Nama           string
 	JenisKelamin   string
 	NomorIdentitas string
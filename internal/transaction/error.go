@@ -83,4 +83,17 @@ var (
 	// ErrPaymentNoSettlement is returned when the given payment
 	// is not settlement.
 	ErrPaymentNotSettlement = errors.New("payment not settlement")
+
+	// ErrInvalidTransactionStatus is returned when the given
+	// transaction status is unknown.
+	ErrInvalidTransactionStatus = errors.New("invalid transaction status")
+
+	// ErrIllegalStatusTransition is returned when the
+	// transaction status may not change into the given
+	// status, e.g. from unpaid directly to settlement.
+	ErrIllegalStatusTransition = errors.New("illegal status transition")
+
+	// ErrStatusFinal is returned when the transaction status
+	// is expired or refunded and can not change anymore.
+	ErrStatusFinal = errors.New("status final")
 )
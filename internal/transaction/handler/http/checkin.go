@@ -3,7 +3,6 @@ package http
 import (
 	"context"
 	"encoding/json"
-	"log"
 	"net/http"
 	"net/url"
 	"strconv"
@@ -11,6 +10,8 @@ import (
 
 	"github.com/gorilla/mux"
 	"github.com/tedxub2023/global/helper"
+	"github.com/tedxub2023/global/logger"
+	"github.com/tedxub2023/global/metrics"
 	"github.com/tedxub2023/internal/transaction"
 )
 
@@ -22,10 +23,11 @@ func (h *checkInHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	id, err := strconv.ParseInt(vars["id"], 10, 64)
 	if err != nil {
-		log.Printf("[CheckIn HTTP][checkInHandler] Failed to parse transaction ID. ID: %s. Err: %s\n", vars["id"], err.Error())
+		logger.Error(r.Context(), "failed to parse transaction ID", err, logger.Fields{"id": vars["id"]})
 		helper.WriteErrorResponse(w, http.StatusBadRequest, []string{errInvalidTransactionID.Error()})
 		return
 	}
+	logger.AddFields(r.Context(), logger.Fields{"transaction_id": id})
 
 	switch r.Method {
 	case http.MethodPatch:
@@ -41,10 +43,8 @@ func (h *checkInHandler) handleUpdateCheckInStatus(w http.ResponseWriter, r *htt
 
 	var (
 		err        error           // stores error in this handler
-		source     string          // stores request source
 		resBody    []byte          // stores response body to write
 		statusCode = http.StatusOK // stores response status code
-
 	)
 
 	resChan := make(chan string, 1)
@@ -52,7 +52,7 @@ func (h *checkInHandler) handleUpdateCheckInStatus(w http.ResponseWriter, r *htt
 
 	defer func() {
 		if err != nil {
-			log.Printf("[CheckIn HTTP][handleUpdateCheckInStatus] Failed to update check in status. transactionID: %v. Source: %s, Err: %s\n", id, source, err.Error())
+			logger.Error(ctx, "failed to update check in status", err)
 			helper.WriteErrorResponse(w, statusCode, []string{err.Error()})
 			return
 		}
@@ -76,7 +76,11 @@ func (h *checkInHandler) handleUpdateCheckInStatus(w http.ResponseWriter, r *htt
 				statusCode = http.StatusBadRequest
 			}
 			errChan <- parsedErr
+			return
 		}
+
+		metrics.CheckIns.WithLabelValues(metrics.EventTransaction, metrics.GateLabel(r.URL.Query().Get("gate"))).Inc()
+
 		resChan <- res
 	}()
 
@@ -111,6 +111,19 @@ var (
 	// errPaymentNoSettlement is returned when the given payment
 	// is not settlement.
 	errPaymentNotSettlement = errors.New("PAYMENT_NOT_SETTLEMENT")
+
+	// errInvalidTransactionStatus is returned when the given
+	// transaction status is unknown.
+	errInvalidTransactionStatus = errors.New("INVALID_TRANSACTION_STATUS")
+
+	// errIllegalStatusTransition is returned when the
+	// transaction status may not change into the given
+	// status.
+	errIllegalStatusTransition = errors.New("ILLEGAL_STATUS_TRANSITION")
+
+	// errStatusFinal is returned when the transaction status
+	// can not change anymore.
+	errStatusFinal = errors.New("STATUS_FINAL")
 )
 
 var (
@@ -141,5 +154,8 @@ var (
 		transaction.ErrTicketNotYetPaid:                 errTicketNotYetPaid,
 		transaction.ErrAllTicketAlreadyCheckedIn:        errAllTicketAlreadyCheckedIn,
 		transaction.ErrPaymentNotSettlement:             errPaymentNotSettlement,
+		transaction.ErrInvalidTransactionStatus:         errInvalidTransactionStatus,
+		transaction.ErrIllegalStatusTransition:          errIllegalStatusTransition,
+		transaction.ErrStatusFinal:                      errStatusFinal,
 	}
 )
@@ -6,6 +6,7 @@ import "github.com/tedxub2023/internal/transaction"
 // HTTP-format object.
 func formatTransaction(t transaction.Transaction) (transactionHTTP, error) {
 	tanggal := t.Tanggal.Format(dateFormat)
+	statusPayment := t.StatusPayment.String()
 
 	return transactionHTTP{
 		ID:                &t.ID,
@@ -22,10 +23,20 @@ func formatTransaction(t transaction.Transaction) (transactionHTTP, error) {
 		TotalHarga:        &t.TotalHarga,
 		Tanggal:           &tanggal,
 		OrderID:           &t.OrderID,
-		StatusPayment:     &t.StatusPayment,
+		StatusPayment:     &statusPayment,
 		ImageURI:          &t.ImageURI,
 		NomorTiket:        &t.NomorTiket,
 		CheckInStatus:     &t.CheckInStatus,
 		CheckInNomorTiket: &t.CheckInNomorTiket,
 	}, nil
 }
+
+// parseStatus returns transaction.Status
+// from the given string.
+func parseStatus(req string) (transaction.Status, error) {
+	status := transaction.Status(req)
+	if _, ok := transaction.StatusList[status]; !ok {
+		return transaction.StatusUnknown, errInvalidTransactionStatus
+	}
+	return status, nil
+}
@@ -4,7 +4,6 @@ import (
 	"context"
 	"encoding/json"
 	"io/ioutil"
-	"log"
 	"net/http"
 	"net/url"
 	"strconv"
@@ -12,6 +11,7 @@ import (
 
 	"github.com/gorilla/mux"
 	"github.com/tedxub2023/global/helper"
+	"github.com/tedxub2023/global/logger"
 	"github.com/tedxub2023/internal/transaction"
 )
 
@@ -23,10 +23,11 @@ func (h *transactionHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	transactionID, err := strconv.ParseInt(vars["id"], 10, 64)
 	if err != nil {
-		log.Printf("[Transaction HTTP][transactionHandler] Failed to parse transaction ID. ID: %s. Err: %s\n", vars["id"], err.Error())
+		logger.Error(r.Context(), "failed to parse transaction ID", err, logger.Fields{"id": vars["id"]})
 		helper.WriteErrorResponse(w, http.StatusBadRequest, []string{errInvalidTransactionID.Error()})
 		return
 	}
+	logger.AddFields(r.Context(), logger.Fields{"transaction_id": transactionID})
 
 	switch r.Method {
 	case http.MethodGet:
@@ -45,7 +46,6 @@ func (h *transactionHandler) handleGetTransactionByID(w http.ResponseWriter, r *
 
 	var (
 		err        error           // stores error in this handler
-		source     string          // stores request source
 		resBody    []byte          // stores response body to write
 		statusCode = http.StatusOK // stores response status code
 	)
@@ -54,7 +54,7 @@ func (h *transactionHandler) handleGetTransactionByID(w http.ResponseWriter, r *
 	defer func() {
 		// error
 		if err != nil {
-			log.Printf("[Transaction HTTP][handleGetTransactionByID] Failed to get transaction. transactionID: %v. Source: %s, Err: %s\n", transactionID, source, err.Error())
+			logger.Error(ctx, "failed to get transaction", err)
 			helper.WriteErrorResponse(w, statusCode, []string{err.Error()})
 			return
 		}
@@ -88,7 +88,7 @@ func (h *transactionHandler) handleGetTransactionByID(w http.ResponseWriter, r *
 
 			// log the actual error if its internal error
 			if statusCode == http.StatusInternalServerError {
-				log.Printf("[Transaction HTTP][handleGetTransactionByID] Internal error from GetTransactionByID. transactionID: %v. Err: %s\n", transactionID, err.Error())
+				logger.Error(ctx, "internal error from GetTransactionByID", err)
 			}
 
 			errChan <- parsedErr
@@ -134,14 +134,13 @@ func (h *transactionHandler) handleUpdatePaymentStatus(w http.ResponseWriter, r
 
 	var (
 		err        error
-		source     string
 		resBody    []byte
 		statusCode = http.StatusOK
 	)
 
 	defer func() {
 		if err != nil {
-			log.Printf("[Transaction HTTP][handleUpdatePaymentStatus] Failed update transaction. transactionID: %v. Source: %s, Err: %s\n", transactionID, source, err.Error())
+			logger.Error(ctx, "failed to update transaction", err)
 			helper.WriteErrorResponse(w, statusCode, []string{err.Error()})
 			return
 		}
@@ -182,7 +181,7 @@ func (h *transactionHandler) handleUpdatePaymentStatus(w http.ResponseWriter, r
 
 			// log the actual error if its internal error
 			if statusCode == http.StatusInternalServerError {
-				log.Printf("[Transaction HTTP][handleGetTransactionByID] Internal error from GetTransactionByID. transactionID: %v. Err: %s\n", transactionID, err.Error())
+				logger.Error(ctx, "internal error from GetTransactionByID", err)
 			}
 
 			errChan <- parsedErr
@@ -206,7 +205,7 @@ func (h *transactionHandler) handleUpdatePaymentStatus(w http.ResponseWriter, r
 				statusCode = http.StatusBadRequest
 			}
 			if statusCode == http.StatusInternalServerError {
-				log.Printf("[Transaction HTTP][handleUpdatePaymentStatus] Internal error from UpdatePaymentStatus. transactionID: %v. Err: %s\n", transactionID, err.Error())
+				logger.Error(ctx, "internal error from UpdatePaymentStatus", err)
 			}
 			errChan <- parsedErr
 			return
@@ -237,7 +236,11 @@ func parseTransactionFromUpdateRequest(th transactionHTTP, current transaction.T
 	}
 
 	if th.StatusPayment != nil {
-		result.StatusPayment = *th.StatusPayment
+		status, err := parseStatus(*th.StatusPayment)
+		if err != nil {
+			return transaction.Transaction{}, err
+		}
+		result.StatusPayment = status
 	}
 
 	return result, nil
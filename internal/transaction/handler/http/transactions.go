@@ -4,12 +4,12 @@ import (
 	"context"
 	"encoding/json"
 	"io/ioutil"
-	"log"
 	"net/http"
 	"net/url"
 	"time"
 
 	"github.com/tedxub2023/global/helper"
+	"github.com/tedxub2023/global/logger"
 	"github.com/tedxub2023/internal/transaction"
 )
 
@@ -35,7 +35,6 @@ func (h *transactionsHandler) handleGetAllTransactions(w http.ResponseWriter, r
 
 	var (
 		err        error           // stores error in this handler
-		source     string          // stores request source
 		resBody    []byte          // stores response body to write
 		statusCode = http.StatusOK // stores response status code
 	)
@@ -44,7 +43,7 @@ func (h *transactionsHandler) handleGetAllTransactions(w http.ResponseWriter, r
 	defer func() {
 		// error
 		if err != nil {
-			log.Printf("[Transaction HTTP][handleGetAllTransactions] Failed to get all transaction. Source: %s, Err: %s\n", source, err.Error())
+			logger.Error(ctx, "failed to get all transactions", err)
 			helper.WriteErrorResponse(w, statusCode, []string{err.Error()})
 			return
 		}
@@ -77,7 +76,7 @@ func (h *transactionsHandler) handleGetAllTransactions(w http.ResponseWriter, r
 
 			// log the actual error if its internal error
 			if statusCode == http.StatusInternalServerError {
-				log.Printf("[Match HTTP][handleGetAllGames] Internal error from GetAllMatchs. Err: %s\n", err.Error())
+				logger.Error(ctx, "internal error from GetAllTransactions", err)
 			}
 
 			errChan <- parsedErr
@@ -112,17 +111,21 @@ func (h *transactionsHandler) handleGetAllTransactions(w http.ResponseWriter, r
 	}
 }
 
-func parseGetTransactionsFilter(request url.Values) (string, time.Time, error) {
-	var statusPayment string
+func parseGetTransactionsFilter(request url.Values) (transaction.Status, time.Time, error) {
+	var statusPayment transaction.Status
 	if query := request.Get("status_payment"); query != "" {
-		statusPayment = query
+		status, err := parseStatus(query)
+		if err != nil {
+			return transaction.StatusUnknown, time.Time{}, err
+		}
+		statusPayment = status
 	}
 
 	var tanggal time.Time
 	if queryDate := request.Get("tanggal"); queryDate != "" {
 		beforeParsed, err := time.Parse(dateFormat, queryDate)
 		if err != nil {
-			return "", time.Time{}, err
+			return transaction.StatusUnknown, time.Time{}, err
 		}
 		tanggal = beforeParsed
 	}
@@ -137,7 +140,6 @@ func (h *transactionsHandler) handleReplaceTransactionByEmail(w http.ResponseWri
 
 	var (
 		err        error           // stores error in this handler
-		source     string          // stores request source
 		resBody    []byte          // stores response body to write
 		statusCode = http.StatusOK // stores response status code
 	)
@@ -145,7 +147,7 @@ func (h *transactionsHandler) handleReplaceTransactionByEmail(w http.ResponseWri
 	// write response
 	defer func() {
 		if err != nil {
-			log.Printf("[Transaction HTTP][handleReplaceTransactionByEmail] Failed to replace transactions by email. Source: %s, Err: %s\n", source, err.Error())
+			logger.Error(ctx, "failed to replace transactions by email", err)
 			helper.WriteErrorResponse(w, statusCode, []string{err.Error()})
 			return
 		}
@@ -195,7 +197,7 @@ func (h *transactionsHandler) handleReplaceTransactionByEmail(w http.ResponseWri
 
 			// log the actual error if its internal error
 			if statusCode == http.StatusInternalServerError {
-				log.Printf("[Transaction HTTP][handleReplaceTransactionByEmail] Internal error from ReplaceTransactionByEmail. Err: %s\n", err.Error())
+				logger.Error(ctx, "internal error from ReplaceTransactionByEmail", err)
 			}
 
 			errChan <- parsedErr
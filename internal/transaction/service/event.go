@@ -0,0 +1,34 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	"github.com/tedxub2023/global/logger"
+	"github.com/tedxub2023/internal/event"
+	"github.com/tedxub2023/internal/transaction"
+)
+
+// currentEvent returns the event the transactions are sold
+// for.
+func (s *service) currentEvent(ctx context.Context) (event.Event, error) {
+	ev, err := s.event.GetEventBySlug(ctx, s.config.EventSlug)
+	if err != nil {
+		return event.Event{}, err
+	}
+	logger.AddFields(ctx, logger.Fields{"event_id": ev.ID})
+
+	return ev, nil
+}
+
+// eventOf returns the event of the given transaction.
+func (s *service) eventOf(ctx context.Context, tx transaction.Transaction) (event.Event, error) {
+	return s.event.GetEventByID(ctx, tx.EventID)
+}
+
+// ticketPrefix returns the prefix of the ticket numbers and
+// PDF files of the given event, its slug without dashes in
+// upper case, e.g. "SEMAYAMASA".
+func ticketPrefix(ev event.Event) string {
+	return strings.ToUpper(strings.ReplaceAll(ev.Slug, "-", ""))
+}
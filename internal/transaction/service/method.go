@@ -4,16 +4,18 @@ import (
 	"bytes"
 	"context"
 	"fmt"
-	"log"
 	"math/rand"
 	"net/mail"
-	"os"
 	"strings"
 	"text/template"
 	"time"
 
 	"github.com/SebastiaanKlippert/go-wkhtmltopdf"
 	"github.com/leekchan/accounting"
+	"github.com/tedxub2023/global/helper"
+	"github.com/tedxub2023/global/logger"
+	"github.com/tedxub2023/global/metrics"
+	"github.com/tedxub2023/internal/event"
 	m "github.com/tedxub2023/internal/ticket/service"
 	"github.com/tedxub2023/internal/transaction"
 )
@@ -27,6 +29,12 @@ func (s *service) ReplaceTransactionByEmail(ctx context.Context, reqTransaction
 	reqTransaction.CreateTime = s.timeNow()
 	reqTransaction.TotalHarga = 25000 * int64(reqTransaction.JumlahTiket)
 
+	ev, err := s.currentEvent(ctx)
+	if err != nil {
+		return 0, err
+	}
+	reqTransaction.EventID = ev.ID
+
 	// get pg store client with using transaction
 	pgStoreClient, err := s.pgStore.NewClient(true)
 	if err != nil {
@@ -53,14 +61,17 @@ func (s *service) ReplaceTransactionByEmail(ctx context.Context, reqTransaction
 	rand.Seed(time.Now().UnixNano())
 	randomNum := rand.Intn(1e10)
 	reqTransaction.OrderID = fmt.Sprintf("%010d", randomNum)
-	reqTransaction.StatusPayment = "unpaid"
+	reqTransaction.StatusPayment = transaction.StatusUnpaid
 
 	ticketID, err := pgStoreClient.CreateTransaction(ctx, reqTransaction)
 	if err != nil {
 		return 0, err
 	}
+	logger.AddFields(ctx, logger.Fields{"transaction_id": ticketID, "order_id": reqTransaction.OrderID})
 
-	go sendPendingMail(reqTransaction)
+	s.workers.Go(ctx, "transaction pending mail", func(ctx context.Context) error {
+		return s.sendPendingMail(ev, reqTransaction)
+	})
 
 	// commit changes
 	err = pgStoreClient.Commit()
@@ -68,10 +79,13 @@ func (s *service) ReplaceTransactionByEmail(ctx context.Context, reqTransaction
 		return 0, err
 	}
 
+	metrics.OrdersCreated.WithLabelValues(metrics.EventTransaction, transactionMetricType).Inc()
+	metrics.TicketsOrdered.WithLabelValues(metrics.EventTransaction, transactionMetricType).Add(float64(reqTransaction.JumlahTiket))
+
 	return ticketID, nil
 }
 
-func (s *service) GetAllTransactions(ctx context.Context, statusPayment string, tanggal time.Time) ([]transaction.Transaction, error) {
+func (s *service) GetAllTransactions(ctx context.Context, statusPayment transaction.Status, tanggal time.Time) ([]transaction.Transaction, error) {
 	// get pg store client without using transaction
 	pgStoreClient, err := s.pgStore.NewClient(false)
 	if err != nil {
@@ -87,6 +101,21 @@ func (s *service) GetAllTransactions(ctx context.Context, statusPayment string,
 	return result, nil
 }
 
+func (s *service) GetTransactionsByEmail(ctx context.Context, email string) ([]transaction.Transaction, error) {
+	email = strings.TrimSpace(email)
+	if email == "" {
+		return nil, transaction.ErrInvalidTransactionEmail
+	}
+
+	// get pg store client without using transaction
+	pgStoreClient, err := s.pgStore.NewClient(false)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgStoreClient.GetTransactionsByEmail(ctx, email)
+}
+
 func (s *service) GetTransactionByID(ctx context.Context, transactionID int64, nomorTiket string) (transaction.Transaction, error) {
 	// validate id
 	if transactionID <= 0 {
@@ -122,24 +151,37 @@ func (s *service) GetTransactionByID(ctx context.Context, transactionID int64, n
 	return result, nil
 }
 
-func (s *service) UpdateCheckInStatus(ctx context.Context, id int64, ticketNumber string) (string, error) {
+func (s *service) UpdateCheckInStatus(ctx context.Context, id int64, ticketNumber string) (_ string, err error) {
 	if id <= 0 {
 		return "", transaction.ErrInvalidTransactionID
 	}
 
-	pgStoreClient, err := s.pgStore.NewClient(false)
+	// the check in time is stored together with the ticket
+	pgStoreClient, err := s.pgStore.NewClient(true)
 	if err != nil {
 		return "", err
 	}
 
+	// rollback just before return if error
+	defer func() {
+		if err != nil {
+			errTx := pgStoreClient.Rollback()
+			if errTx != nil {
+				// return err from rollback
+				err = errTx
+			}
+		}
+	}()
+
 	tx, err := pgStoreClient.GetTransactionByID(ctx, id)
 	if err != nil {
 		return "", err
 	}
+	logger.AddFields(ctx, logger.Fields{"order_id": tx.OrderID, "ticket_number": ticketNumber})
 
 	if tx.CheckInStatus {
 		return "", transaction.ErrAllTicketAlreadyCheckedIn
-	} else if tx.StatusPayment != "settlement" {
+	} else if tx.StatusPayment != transaction.StatusSettlement {
 		return "", transaction.ErrTicketNotYetPaid
 	}
 
@@ -168,51 +210,124 @@ func (s *service) UpdateCheckInStatus(ctx context.Context, id int64, ticketNumbe
 		tx.CheckInStatus = true
 	}
 
-	err = pgStoreClient.UpdateTransactionByID(ctx, tx, s.timeNow())
+	now := s.timeNow()
+	err = pgStoreClient.UpdateTransactionByID(ctx, tx, now)
+	if err != nil {
+		return "", err
+	}
+
+	err = pgStoreClient.CreateCheckIn(ctx, tx.ID, ticketNumber, now)
+	if err != nil {
+		return "", err
+	}
+
+	// commit changes
+	err = pgStoreClient.Commit()
 	if err != nil {
-		log.Println(err)
 		return "", err
 	}
 
 	return ticketNumber, nil
 }
 
-func (s *service) UpdatePaymentStatus(ctx context.Context, reqTransaction transaction.Transaction) error {
+func (s *service) UpdatePaymentStatus(ctx context.Context, reqTransaction transaction.Transaction) (err error) {
 	// validate id
 	if reqTransaction.ID <= 0 {
 		return transaction.ErrInvalidTransactionID
 	}
 
-	pgStoreClient, err := s.pgStore.NewClient(false)
+	// validate status
+	if _, ok := transaction.StatusList[reqTransaction.StatusPayment]; !ok {
+		return transaction.ErrInvalidTransactionStatus
+	}
+
+	// the tickets are rendered before the settlement is
+	// stored, so that a failure leaves the status unchanged
+	// and the update can be retried
+	pgStoreClient, err := s.pgStore.NewClient(true)
 	if err != nil {
 		return err
 	}
 
-	ticketNumbers := generateNumberTicket(reqTransaction.ID, reqTransaction.Tanggal.Format("02"), reqTransaction.JumlahTiket)
-	reqTransaction.NomorTiket = ticketNumbers
+	// rollback just before return if error
+	defer func() {
+		if err != nil {
+			errTx := pgStoreClient.Rollback()
+			if errTx != nil {
+				// return err from rollback
+				err = errTx
+			}
+		}
+	}()
 
-	err = pgStoreClient.UpdateTransactionByID(ctx, reqTransaction, s.timeNow())
+	current, err := pgStoreClient.GetTransactionByID(ctx, reqTransaction.ID)
 	if err != nil {
 		return err
 	}
+	logger.AddFields(ctx, logger.Fields{"order_id": current.OrderID, "status_payment": reqTransaction.StatusPayment.String()})
+
+	// an update keeping the status, e.g. a payment proof
+	// re-upload, has no side effect
+	transition := reqTransaction.StatusPayment != current.StatusPayment
+	if transition {
+		if current.StatusPayment.IsFinal() {
+			return transaction.ErrStatusFinal
+		}
+		if !current.StatusPayment.CanTransitionTo(reqTransaction.StatusPayment) {
+			return transaction.ErrIllegalStatusTransition
+		}
+	}
+
+	// ticket numbers are issued once, when the payment is
+	// settled, in the format of the event of the transaction
+	reqTransaction.EventID = current.EventID
+	reqTransaction.NomorTiket = current.NomorTiket
 
-	if reqTransaction.StatusPayment == "settlement" {
-		if err := createPDF(reqTransaction); err != nil {
+	settled := transition && reqTransaction.StatusPayment == transaction.StatusSettlement
+
+	var ev event.Event
+	if settled {
+		ev, err = s.eventOf(ctx, current)
+		if err != nil {
+			return err
+		}
+		reqTransaction.NomorTiket = generateNumberTicket(ticketPrefix(ev), reqTransaction.ID, reqTransaction.Tanggal.Format("02"), reqTransaction.JumlahTiket)
+
+		err = s.createPDF(ev, reqTransaction)
+		if err != nil {
 			return err
 		}
-		go sendMail(reqTransaction)
+	}
+
+	err = pgStoreClient.UpdateTransactionByID(ctx, reqTransaction, s.timeNow())
+	if err != nil {
+		return err
+	}
+
+	// commit changes
+	err = pgStoreClient.Commit()
+	if err != nil {
+		return err
+	}
+
+	if settled {
+		metrics.OrderSettlements.WithLabelValues(metrics.EventTransaction, transactionMetricType).Inc()
+
+		s.workers.Go(ctx, "transaction ticket mail", func(ctx context.Context) error {
+			return s.sendMail(ev, reqTransaction)
+		})
 	}
 
 	return nil
 }
 
-func sendPendingMail(tx transaction.Transaction) error {
-	mail := m.NewMailClient()
-	mail.SetSender("tedxuniversitasbrawijaya@gmail.com")
+func (s *service) sendPendingMail(ev event.Event, tx transaction.Transaction) error {
+	mail := m.NewMailClient(s.config.Mail)
+	mail.SetSender(s.config.Mail.Sender)
 	mail.SetReciever(tx.Email)
 	mail.SetSubject("Konfirmasi Pembelian Tiket")
 
-	if err := mail.SetBodyHTMLPendingMail(); err != nil {
+	if err := mail.SetBodyHTMLPendingMail(ev); err != nil {
 		return err
 	}
 
@@ -222,61 +337,76 @@ func sendPendingMail(tx transaction.Transaction) error {
 	return nil
 }
 
-func generateNumberTicket(txID int64, date string, totalTickets int) []string {
+func generateNumberTicket(prefix string, txID int64, date string, totalTickets int) []string {
 	var ticketNumbers []string
 
 	asciiVal := 65
 	for i := 0; i < totalTickets; i++ {
-		ticketNumbers = append(ticketNumbers, fmt.Sprintf("SEMAYAMASA-%s/%c%d", date, rune(asciiVal), txID))
+		ticketNumbers = append(ticketNumbers, fmt.Sprintf("%s-%s/%c%d", prefix, date, rune(asciiVal), txID))
 		asciiVal++
 	}
 
 	return ticketNumbers
 }
 
-func createPDF(tx transaction.Transaction) error {
-	wkhtmltopdf.SetPath(os.Getenv("WKHTMLTOPDF_PATH"))
+// pdfPath returns the path of the ticket PDF of the given
+// transaction of the given event.
+func pdfPath(ev event.Event, tx transaction.Transaction) string {
+	return fmt.Sprintf("global/storage/%s-%s-%d.pdf", ticketPrefix(ev), tx.Nama, tx.ID)
+}
+
+// createPDF renders the tickets of the given transaction from
+// the ticket template of its event.
+func (s *service) createPDF(ev event.Event, tx transaction.Transaction) error {
+	defer func(start time.Time) {
+		metrics.PDFRenderDuration.WithLabelValues("wkhtmltopdf").Observe(time.Since(start).Seconds())
+	}(time.Now())
+
+	wkhtmltopdf.SetPath(s.config.PDF.WkhtmltopdfPath)
 
 	pdfg, err := wkhtmltopdf.NewPDFGenerator()
 	if err != nil {
-		log.Fatal(err)
+		return err
 	}
 
 	pdfg.PageSize.Set(wkhtmltopdf.PageSizeA4)
 	pdfg.Orientation.Set(wkhtmltopdf.OrientationPortrait)
 
-	path := "global/template/pdf.html"
+	path := helper.TemplatePath(ev.Templates.Tiket, "pdf.html")
 
 	t, err := template.ParseFiles(path)
 	if err != nil {
-		log.Fatal(err)
+		return err
 	}
 
 	body := new(bytes.Buffer)
 
-	go func() {
-		for i := 0; i < len(tx.NomorTiket); i++ {
-			t.Execute(body, struct {
-				Name           string
-				Email          string
-				NumberIdentity string
-				DateTime       string
-				QRCODE         string
-				NumberTicket   string
-			}{
-				Name:           tx.Nama,
-				Email:          tx.Email,
-				NumberIdentity: tx.NomorIdentitas,
-				DateTime:       tx.Tanggal.Format("02 January 2006"),
-				QRCODE:         fmt.Sprintf("https://api.qrserver.com/v1/create-qr-code/?size=150x150&data=%s", fmt.Sprintf(os.Getenv("URL_QRCODE"), tx.ID, tx.NomorTiket[i])),
-				NumberTicket:   tx.NomorTiket[i],
-			})
-
-			if i != (len(tx.NomorTiket) - 1) {
-				body.WriteString(`<P style="page-break-before: always">`)
-			}
+	for i := 0; i < len(tx.NomorTiket); i++ {
+		err = t.Execute(body, struct {
+			Event          helper.TemplateEvent
+			Name           string
+			Email          string
+			NumberIdentity string
+			DateTime       string
+			QRCODE         string
+			NumberTicket   string
+		}{
+			Event:          helper.NewTemplateEvent(ev),
+			Name:           tx.Nama,
+			Email:          tx.Email,
+			NumberIdentity: tx.NomorIdentitas,
+			DateTime:       tx.Tanggal.Format("02 January 2006"),
+			QRCODE:         fmt.Sprintf("https://api.qrserver.com/v1/create-qr-code/?size=150x150&data=%s", fmt.Sprintf(s.config.PDF.URLQRCode, tx.ID, tx.NomorTiket[i])),
+			NumberTicket:   tx.NomorTiket[i],
+		})
+		if err != nil {
+			return err
 		}
-	}()
+
+		if i != (len(tx.NomorTiket) - 1) {
+			body.WriteString(`<P style="page-break-before: always">`)
+		}
+	}
 
 	pdfg.AddPage(wkhtmltopdf.NewPageReader(body))
 
@@ -284,21 +414,21 @@ func createPDF(tx transaction.Transaction) error {
 		return err
 	}
 
-	go pdfg.WriteFile(fmt.Sprintf("global/storage/SEMAYAMASA-%s-%d.pdf", tx.Nama, tx.ID))
-
-	return nil
+	// the file must be written before the ticket mail
+	// attaches it
+	return pdfg.WriteFile(pdfPath(ev, tx))
 }
 
-func sendMail(tx transaction.Transaction) error {
-	mail := m.NewMailClient()
-	mail.SetSender("tedxuniversitasbrawijaya@gmail.com")
+func (s *service) sendMail(ev event.Event, tx transaction.Transaction) error {
+	mail := m.NewMailClient(s.config.Mail)
+	mail.SetSender(s.config.Mail.Sender)
 	mail.SetReciever(tx.Email)
-	mail.SetSubject("Tiket Semayam Asa")
-	mail.SetAttachFile(fmt.Sprintf("global/storage/SEMAYAMASA-%s-%d.pdf", tx.Nama, tx.ID))
+	mail.SetSubject(fmt.Sprintf("Tiket %s", ev.Nama))
+	mail.SetAttachFile(pdfPath(ev, tx))
 
 	ac := accounting.Accounting{Symbol: "Rp", Precision: 0, Thousand: ".", Decimal: ","}
 	totalPrice := ac.FormatMoney(tx.TotalHarga)
-	if err := mail.SetBodyHTMLMainEvent(tx.JumlahTiket, totalPrice); err != nil {
+	if err := mail.SetBodyHTMLMainEvent(ev, tx.Tanggal.Format("02 January 2006"), tx.JumlahTiket, totalPrice); err != nil {
 		return err
 	}
 
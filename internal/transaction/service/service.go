@@ -2,18 +2,45 @@ package service
 
 import (
 	"time"
+
+	"github.com/tedxub2023/global/helper"
+	"github.com/tedxub2023/global/worker"
+	"github.com/tedxub2023/internal/event"
+	m "github.com/tedxub2023/internal/ticket/service"
 )
 
+// transactionMetricType is the ticket type label of the
+// transaction metrics, since transactions only have a single
+// ticket type.
+const transactionMetricType = "regular"
+
+// Config holds the configuration of the service.
+type Config struct {
+	Mail m.MailConfig
+	PDF  helper.PDFConfig
+
+	// EventSlug is the slug of the event the transactions are
+	// sold for, the tickets and emails of each transaction
+	// use the name, branding and templates of its event.
+	EventSlug string
+}
+
 // New construts a new service.
 type service struct {
 	pgStore PGStore
+	workers *worker.Group
+	event   event.Service
+	config  Config
 	timeNow func() time.Time
 }
 
 // New returns a new service
-func New(pgStore PGStore) (*service, error) {
+func New(pgStore PGStore, workers *worker.Group, eventSvc event.Service, config Config) (*service, error) {
 	return &service{
 		pgStore: pgStore,
+		workers: workers,
+		event:   eventSvc,
+		config:  config,
 		timeNow: time.Now,
 	}, nil
 }
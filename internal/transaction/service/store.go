@@ -24,7 +24,11 @@ type PGStoreClient interface {
 	CreateTransaction(ctx context.Context, transaction transaction.Transaction) (int64, error)
 
 	// GetAllTransactions returns all transaction and filter by status and tanggal.
-	GetAllTransactions(ctx context.Context, statusPayment string, tanggal time.Time) ([]transaction.Transaction, error)
+	GetAllTransactions(ctx context.Context, statusPayment transaction.Status, tanggal time.Time) ([]transaction.Transaction, error)
+
+	// GetTransactionsByEmail returns the transactions with the
+	// given email, compared case-insensitively.
+	GetTransactionsByEmail(ctx context.Context, email string) ([]transaction.Transaction, error)
 
 	// GetTransactionByID returns a transaction with the given
 	// transaction ID.
@@ -37,4 +41,8 @@ type PGStoreClient interface {
 	// UpdateTransactionByID updates a transaction with the given
 	// transaction ID.
 	UpdateTransactionByID(ctx context.Context, transaction transaction.Transaction, updateTime time.Time) error
+
+	// CreateCheckIn stores the time the given ticket of the
+	// given transaction is checked in.
+	CreateCheckIn(ctx context.Context, transactionID int64, nomorTiket string, checkInTime time.Time) error
 }
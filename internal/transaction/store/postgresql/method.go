@@ -16,7 +16,7 @@ func (sc *storeClient) DeleteTransactionByEmail(ctx context.Context, email strin
 	argsKV := map[string]interface{}{
 		"email":          email,
 		"tanggal":        tanggal,
-		"status_payment": "pending",
+		"status_payment": transaction.StatusPending,
 	}
 
 	// prepare query
@@ -31,13 +31,14 @@ func (sc *storeClient) DeleteTransactionByEmail(ctx context.Context, email strin
 	query = sc.q.Rebind(query)
 
 	// execute query
-	_, err = sc.q.Exec(query, args...)
+	_, err = sc.q.ExecContext(ctx, query, args...)
 	return err
 }
 
 func (sc *storeClient) CreateTransaction(ctx context.Context, reqTransaction transaction.Transaction) (int64, error) {
 	// construct arguments filled with fields for the query
 	argsKV := map[string]interface{}{
+		"event_id":        reqTransaction.EventID,
 		"nama":            reqTransaction.Nama,
 		"jenis_kelamin":   reqTransaction.JenisKelamin,
 		"nomor_identitas": reqTransaction.NomorIdentitas,
@@ -69,7 +70,7 @@ func (sc *storeClient) CreateTransaction(ctx context.Context, reqTransaction tra
 
 	// execute query
 	var ticketID int64
-	err = sc.q.QueryRowx(query, args...).Scan(&ticketID)
+	err = sc.q.QueryRowxContext(ctx, query, args...).Scan(&ticketID)
 	if err != nil {
 		return 0, err
 	}
@@ -77,7 +78,7 @@ func (sc *storeClient) CreateTransaction(ctx context.Context, reqTransaction tra
 	return ticketID, nil
 }
 
-func (sc *storeClient) GetAllTransactions(ctx context.Context, statusPayment string, tanggal time.Time) ([]transaction.Transaction, error) {
+func (sc *storeClient) GetAllTransactions(ctx context.Context, statusPayment transaction.Status, tanggal time.Time) ([]transaction.Transaction, error) {
 	// define variables to custom query
 	argsKV := make(map[string]interface{})
 	addConditions := make([]string, 0)
@@ -114,7 +115,7 @@ func (sc *storeClient) GetAllTransactions(ctx context.Context, statusPayment str
 	query = sc.q.Rebind(query)
 
 	// query to database
-	rows, err := sc.q.Queryx(query, args...)
+	rows, err := sc.q.QueryxContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -139,12 +140,40 @@ func (sc *storeClient) GetAllTransactions(ctx context.Context, statusPayment str
 	return match, nil
 }
 
+func (sc *storeClient) GetTransactionsByEmail(ctx context.Context, email string) ([]transaction.Transaction, error) {
+	query := fmt.Sprintf(queryGetTransaction, "WHERE lower(t.email) = lower($1)")
+
+	// query to database
+	rows, err := sc.q.QueryxContext(ctx, query, email)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	// read match
+	match := make([]transaction.Transaction, 0)
+	for rows.Next() {
+		var row transactionDB
+		err = rows.StructScan(&row)
+		if err != nil {
+			return nil, err
+		}
+
+		match = append(match, row.format())
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return match, nil
+}
 func (sc *storeClient) GetTransactionByID(ctx context.Context, transactionID int64) (transaction.Transaction, error) {
 	query := fmt.Sprintf(queryGetTransaction, "WHERE t.id = $1")
 
 	// query single row
 	var tdb transactionDB
-	err := sc.q.QueryRowx(query, transactionID).StructScan(&tdb)
+	err := sc.q.QueryRowxContext(ctx, query, transactionID).StructScan(&tdb)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return transaction.Transaction{}, transaction.ErrDataNotFound
@@ -172,17 +201,23 @@ func (sc *storeClient) UpdateTransactionByID(ctx context.Context, tx transaction
 		"order_id":        tx.OrderID,
 		"status_payment":  tx.StatusPayment,
 		"image_uri":       tx.ImageURI,
-		"nomor_tiket":     tx.NomorTiket,
 		"checkin_status":  tx.CheckInStatus,
 		"update_time":     updateTime,
 		"id":              tx.ID,
 	}
-	query := fmt.Sprintf(queryUpdateTransaction, "")
 
+	// the arrays are only set when not empty, since sqlx.In
+	// rejects empty slices
+	addSets := make([]string, 0)
+	if len(tx.NomorTiket) != 0 {
+		argsKV["nomor_tiket"] = tx.NomorTiket
+		addSets = append(addSets, ", nomor_tiket = ARRAY[:nomor_tiket]")
+	}
 	if len(tx.CheckInNomorTiket) != 0 {
 		argsKV["checkin_nomor_tiket"] = tx.CheckInNomorTiket
-		query = fmt.Sprintf(queryUpdateTransaction, ", checkin_nomor_tiket = ARRAY[:checkin_nomor_tiket]")
+		addSets = append(addSets, ", checkin_nomor_tiket = ARRAY[:checkin_nomor_tiket]")
 	}
+	query := fmt.Sprintf(queryUpdateTransaction, strings.Join(addSets, ""))
 
 	query, args, err := sqlx.Named(query, argsKV)
 	if err != nil {
@@ -196,10 +231,29 @@ func (sc *storeClient) UpdateTransactionByID(ctx context.Context, tx transaction
 
 	query = sc.q.Rebind(query)
 
-	_, err = sc.q.Exec(query, args...)
+	_, err = sc.q.ExecContext(ctx, query, args...)
 	if err != nil {
 		return err
 	}
 
 	return nil
 }
+
+func (sc *storeClient) CreateCheckIn(ctx context.Context, transactionID int64, nomorTiket string, checkInTime time.Time) error {
+	argsKV := map[string]interface{}{
+		"transaction_id": transactionID,
+		"nomor_tiket":    nomorTiket,
+		"checkin_time":   checkInTime,
+	}
+
+	// prepare query
+	query, args, err := sqlx.Named(queryCreateCheckIn, argsKV)
+	if err != nil {
+		return err
+	}
+	query = sc.q.Rebind(query)
+
+	// execute query
+	_, err = sc.q.ExecContext(ctx, query, args...)
+	return err
+}
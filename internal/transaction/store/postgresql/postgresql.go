@@ -22,7 +22,7 @@ type store struct {
 
 // storeClient implements transaction/service.PGStoreClient
 type storeClient struct {
-	q sqlx.Ext
+	q sqlx.ExtContext
 }
 
 // New creates a new store.
@@ -35,7 +35,7 @@ func New(db *sqlx.DB) (*store, error) {
 }
 
 func (s *store) NewClient(useTx bool) (service.PGStoreClient, error) {
-	var q sqlx.Ext
+	var q sqlx.ExtContext
 
 	// determine what object should be use as querier
 	q = s.db
@@ -67,27 +67,28 @@ func (sc *storeClient) Rollback() error {
 }
 
 type transactionDB struct {
-	ID                int64          `db:"id"`
-	Nama              string         `db:"nama"`
-	JenisKelamin      string         `db:"jenis_kelamin"`
-	NomorIdentitas    string         `db:"nomor_identitas"`
-	AsalInstitusi     string         `db:"asal_institusi"`
-	Domisili          string         `db:"domisili"`
-	Email             string         `db:"email"`
-	NomorTelepon      string         `db:"nomor_telepon"`
-	LineID            string         `db:"line_id"`
-	Instagram         string         `db:"instagram"`
-	JumlahTiket       int            `db:"jumlah_tiket"`
-	TotalHarga        int64          `db:"total_harga"`
-	Tanggal           time.Time      `db:"tanggal"`
-	OrderID           string         `db:"order_id"`
-	StatusPayment     string         `db:"status_payment"`
-	ImageURI          *string        `db:"image_uri"`
-	NomorTiket        pq.StringArray `db:"nomor_tiket"`
-	CheckInStatus     *bool          `db:"checkin_status"`
-	CheckInNomorTiket pq.StringArray `db:"checkin_nomor_tiket"`
-	CreateTime        time.Time      `db:"create_time"`
-	UpdateTime        *time.Time     `db:"update_time"`
+	ID                int64              `db:"id"`
+	EventID           *int64             `db:"event_id"`
+	Nama              string             `db:"nama"`
+	JenisKelamin      string             `db:"jenis_kelamin"`
+	NomorIdentitas    string             `db:"nomor_identitas"`
+	AsalInstitusi     string             `db:"asal_institusi"`
+	Domisili          string             `db:"domisili"`
+	Email             string             `db:"email"`
+	NomorTelepon      string             `db:"nomor_telepon"`
+	LineID            string             `db:"line_id"`
+	Instagram         string             `db:"instagram"`
+	JumlahTiket       int                `db:"jumlah_tiket"`
+	TotalHarga        int64              `db:"total_harga"`
+	Tanggal           time.Time          `db:"tanggal"`
+	OrderID           string             `db:"order_id"`
+	StatusPayment     transaction.Status `db:"status_payment"`
+	ImageURI          *string            `db:"image_uri"`
+	NomorTiket        pq.StringArray     `db:"nomor_tiket"`
+	CheckInStatus     *bool              `db:"checkin_status"`
+	CheckInNomorTiket pq.StringArray     `db:"checkin_nomor_tiket"`
+	CreateTime        time.Time          `db:"create_time"`
+	UpdateTime        *time.Time         `db:"update_time"`
 }
 
 // format formats database struct into domain struct.
@@ -119,6 +120,10 @@ func (tdb *transactionDB) format() transaction.Transaction {
 		t.NomorTiket = ticketNumbers
 	}
 
+	if tdb.EventID != nil {
+		t.EventID = *tdb.EventID
+	}
+
 	if tdb.ImageURI != nil {
 		t.ImageURI = *tdb.ImageURI
 	}
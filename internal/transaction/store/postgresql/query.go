@@ -4,6 +4,7 @@ const queryCreateTransaction = `
 	INSERT INTO
 		transaction
 	(
+		event_id,
 		nama,
 		jenis_kelamin,
 		nomor_identitas,
@@ -20,6 +21,7 @@ const queryCreateTransaction = `
 		status_payment,
 		create_time
 	) VALUES (
+		:event_id,
 		:nama,
 		:jenis_kelamin,
 		:nomor_identitas,
@@ -42,6 +44,7 @@ const queryCreateTransaction = `
 const queryGetTransaction = `
 	SELECT
 		t.id,
+		t.event_id,
 		t.nama,
 		t.jenis_kelamin,
 		t.nomor_identitas,
@@ -98,10 +101,24 @@ const queryUpdateTransaction = `
 		order_id = :order_id,
 		status_payment = :status_payment,
 		image_uri = :image_uri,
-		nomor_tiket = ARRAY[:nomor_tiket],
 		checkin_status = :checkin_status,
 		update_time = :update_time
 		%s
 	WHERE
 		id = :id
 `
+
+const queryCreateCheckIn = `
+	INSERT INTO
+		transaction_checkin
+	(
+		transaction_id,
+		nomor_tiket,
+		checkin_time
+	) VALUES (
+		:transaction_id,
+		:nomor_tiket,
+		:checkin_time
+	)
+	ON CONFLICT (transaction_id, nomor_tiket) DO NOTHING
+`
@@ -15,21 +15,30 @@ type Service interface {
 	GetTransactionByID(ctx context.Context, transactionID int64, nomorTiket string) (Transaction, error)
 
 	// GetAllTransactions returns all transaction and filter by status and tanggal.
-	GetAllTransactions(ctx context.Context, statusPayment string, tanggal time.Time) ([]Transaction, error)
+	GetAllTransactions(ctx context.Context, statusPayment Status, tanggal time.Time) ([]Transaction, error)
+
+	// GetTransactionsByEmail returns the transactions of the
+	// buyer with the given email, newest first.
+	GetTransactionsByEmail(ctx context.Context, email string) ([]Transaction, error)
 
 	// UpdateCheckInStatus returns a ticket number and status with the giveb
 	// transaction ID and ticket number
 	UpdateCheckInStatus(ctx context.Context, id int64, nomorTiket string) (string, error)
 
 	// UpdatePaymentStatus update the payment status in DB
-	// and send a email after completed payment
+	// and send a email after completed payment. The status
+	// must follow the transitions allowed by Status.
 	UpdatePaymentStatus(ctx context.Context, reqTransaction Transaction) error
 }
 
 // Transaction is a transaction.
 
 type Transaction struct {
-	ID                int64
+	ID int64
+
+	// EventID is the event the transaction is ordered for.
+	EventID int64
+
 	Nama              string
 	JenisKelamin      string
 	NomorIdentitas    string
@@ -42,7 +51,7 @@ type Transaction struct {
 	JumlahTiket       int
 	TotalHarga        int64
 	Tanggal           time.Time
-	StatusPayment     string
+	StatusPayment     Status
 	OrderID           string
 	ImageURI          string
 	NomorTiket        []string
@@ -51,3 +60,59 @@ type Transaction struct {
 	CreateTime        time.Time
 	UpdateTime        time.Time
 }
+
+// Status denotes the payment status of a transaction.
+type Status string
+
+// Followings are the known status.
+const (
+	StatusUnknown    Status = ""
+	StatusUnpaid     Status = "unpaid"
+	StatusPending    Status = "pending"
+	StatusSettlement Status = "settlement"
+	StatusExpired    Status = "expired"
+	StatusRefunded   Status = "refunded"
+)
+
+var (
+	// StatusList is a list of valid status.
+	StatusList = map[Status]struct{}{
+		StatusUnpaid:     {},
+		StatusPending:    {},
+		StatusSettlement: {},
+		StatusExpired:    {},
+		StatusRefunded:   {},
+	}
+
+	// statusTransitions maps status to the status it may
+	// change into. The order is paid by uploading a payment
+	// proof (pending), which is then either confirmed by the
+	// committee (settlement) or not (expired). A settled order
+	// may only be refunded.
+	statusTransitions = map[Status][]Status{
+		StatusUnpaid:     {StatusPending, StatusExpired},
+		StatusPending:    {StatusSettlement, StatusExpired},
+		StatusSettlement: {StatusRefunded},
+	}
+)
+
+// String returns string representaion of a status type.
+func (s Status) String() string {
+	return string(s)
+}
+
+// CanTransitionTo returns whether the status may change into
+// the given status.
+func (s Status) CanTransitionTo(next Status) bool {
+	for _, allowed := range statusTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// IsFinal returns whether the status can not change anymore.
+func (s Status) IsFinal() bool {
+	return len(statusTransitions[s]) == 0
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"net/http"
 
+	"github.com/cloudinary/cloudinary-go/v2"
 	"github.com/gorilla/mux"
 )
 
@@ -13,7 +14,8 @@ var (
 
 // Handler contains admin HTTP-handlers.
 type Handler struct {
-	handlers map[string]*handler
+	handlers   map[string]*handler
+	cloudinary *cloudinary.Cloudinary
 }
 
 // handler is the HTTP handler wrapper.
@@ -39,9 +41,10 @@ var (
 )
 
 // New creates a new Handler.
-func New(identities []HandlerIdentity) (*Handler, error) {
+func New(cld *cloudinary.Cloudinary, identities []HandlerIdentity) (*Handler, error) {
 	h := &Handler{
-		handlers: make(map[string]*handler),
+		handlers:   make(map[string]*handler),
+		cloudinary: cld,
 	}
 
 	// apply options
@@ -71,7 +74,9 @@ func (h *Handler) createHTTPHandler(configName string) (http.Handler, error) {
 	var httpHandler http.Handler
 	switch configName {
 	case HandlerUpload.Name:
-		httpHandler = &uploadHandler{}
+		httpHandler = &uploadHandler{
+			cloudinary: h.cloudinary,
+		}
 	default:
 		return httpHandler, errUnknownConfig
 	}
@@ -3,9 +3,7 @@ package http
 import (
 	"context"
 	"encoding/json"
-	"log"
 	"net/http"
-	"os"
 	"strings"
 	"time"
 
@@ -13,6 +11,7 @@ import (
 	"github.com/cloudinary/cloudinary-go/v2/api/uploader"
 
 	"github.com/tedxub2023/global/helper"
+	"github.com/tedxub2023/global/logger"
 )
 
 var (
@@ -20,6 +19,7 @@ var (
 )
 
 type uploadHandler struct {
+	cloudinary *cloudinary.Cloudinary
 }
 
 func (h *uploadHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
@@ -39,7 +39,6 @@ func (h *uploadHandler) handleUpload(w http.ResponseWriter, r *http.Request) {
 
 	var (
 		err        error           // stores error in this handler
-		source     string          // stores request source
 		resBody    []byte          // stores response body to write
 		statusCode = http.StatusOK // stores response status code
 	)
@@ -48,7 +47,7 @@ func (h *uploadHandler) handleUpload(w http.ResponseWriter, r *http.Request) {
 	defer func() {
 		// error
 		if err != nil {
-			log.Printf("[Upload HTTP][handleUpload] Failed to upload file. Source: %s, Err: %s\n", source, err.Error())
+			logger.Error(ctx, "failed to upload file", err)
 			helper.WriteErrorResponse(w, statusCode, []string{err.Error()})
 			return
 		}
@@ -94,15 +93,8 @@ func (h *uploadHandler) handleUpload(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
-		cld, err := cloudinary.NewFromParams(os.Getenv("CLOUDINARY_API_NAME"), os.Getenv("CLOUDINARY_API_KEY"), os.Getenv("CLOUDINARY_API_SECRET"))
-		if err != nil {
-			statusCode = http.StatusBadRequest
-			errChan <- errBadRequest
-			return
-		}
-
 		// create file
-		res, err := cld.Upload.Upload(ctx, uploaded, uploader.UploadParams{Folder: folder})
+		res, err := h.cloudinary.Upload.Upload(ctx, uploaded, uploader.UploadParams{Folder: folder})
 		if err != nil {
 			// determine error and status code, by default its internal error
 			parsedErr := errInternalServerError
@@ -114,7 +106,7 @@ func (h *uploadHandler) handleUpload(w http.ResponseWriter, r *http.Request) {
 
 			// log the actual error if its internal error
 			if statusCode == http.StatusInternalServerError {
-				log.Printf("[Upload HTTP][handleUpload] Internal error from CreateFile. Err: %s\n", err.Error())
+				logger.Error(ctx, "internal error from CreateFile", err)
 			}
 
 			errChan <- parsedErr
@@ -0,0 +1,9 @@
+-- rate_limit holds the request counters of the rate limiter
+-- when RATE_LIMIT_STORE is postgres.
+CREATE TABLE IF NOT EXISTS rate_limit (
+    key        TEXT        PRIMARY KEY,
+    count      INTEGER     NOT NULL,
+    reset_time TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS rate_limit_reset_time_idx ON rate_limit (reset_time);
@@ -0,0 +1,21 @@
+-- refund_nomor_tiket holds the refunded ticket numbers of a
+-- mainevent, they are rejected at the check in.
+ALTER TABLE mainevent ADD COLUMN IF NOT EXISTS refund_nomor_tiket TEXT[];
+
+-- mainevent_refund holds the refund requests of the buyers
+-- and their approval by the committee.
+CREATE TABLE IF NOT EXISTS mainevent_refund (
+    id           BIGSERIAL   PRIMARY KEY,
+    mainevent_id BIGINT      NOT NULL REFERENCES mainevent (id) ON DELETE CASCADE,
+    nomor_tiket  TEXT[]      NOT NULL,
+    alasan       TEXT        NOT NULL DEFAULT '',
+    total_refund BIGINT      NOT NULL,
+    status       TEXT        NOT NULL,
+    metode       TEXT,
+    catatan      TEXT,
+    create_time  TIMESTAMPTZ NOT NULL,
+    update_time  TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS mainevent_refund_mainevent_id_idx ON mainevent_refund (mainevent_id);
+CREATE INDEX IF NOT EXISTS mainevent_refund_status_idx ON mainevent_refund (status, create_time);
@@ -0,0 +1,13 @@
+-- mainevent_holder holds the attendees the buyers assign
+-- their tickets to. A ticket without a holder is held by the
+-- buyer.
+CREATE TABLE IF NOT EXISTS mainevent_holder (
+    mainevent_id    BIGINT      NOT NULL REFERENCES mainevent (id) ON DELETE CASCADE,
+    nomor_tiket     TEXT        NOT NULL,
+    nama            TEXT        NOT NULL,
+    email           TEXT        NOT NULL,
+    nomor_identitas TEXT,
+    create_time     TIMESTAMPTZ NOT NULL,
+    update_time     TIMESTAMPTZ,
+    PRIMARY KEY (mainevent_id, nomor_tiket)
+);
@@ -0,0 +1,19 @@
+-- mainevent_waitlist holds the buyers waiting for normal sale
+-- tickets after they sold out, offered first in first out.
+CREATE TABLE IF NOT EXISTS mainevent_waitlist (
+    id                BIGSERIAL   PRIMARY KEY,
+    nama              TEXT        NOT NULL,
+    email             TEXT        NOT NULL,
+    jumlah_tiket      INT         NOT NULL,
+    status            TEXT        NOT NULL,
+    token             TEXT,
+    offer_expire_time TIMESTAMPTZ,
+    create_time       TIMESTAMPTZ NOT NULL,
+    update_time       TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS mainevent_waitlist_status_idx ON mainevent_waitlist (status, create_time, id);
+CREATE UNIQUE INDEX IF NOT EXISTS mainevent_waitlist_token_idx ON mainevent_waitlist (token);
+
+-- a buyer may only be in the queue once at a time
+CREATE UNIQUE INDEX IF NOT EXISTS mainevent_waitlist_email_active_idx ON mainevent_waitlist (lower(email)) WHERE status IN ('waiting', 'offered');
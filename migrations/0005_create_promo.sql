@@ -0,0 +1,40 @@
+-- promo holds the promo codes given to the partners and
+-- communities.
+CREATE TABLE IF NOT EXISTS promo (
+    id              BIGSERIAL   PRIMARY KEY,
+    kode            TEXT        NOT NULL UNIQUE,
+    jenis           TEXT        NOT NULL,
+    nilai           BIGINT      NOT NULL,
+    kuota           INT         NOT NULL DEFAULT 0,
+    kuota_per_email INT         NOT NULL DEFAULT 0,
+    types           BIGINT[]    NOT NULL DEFAULT '{}',
+    partner         TEXT        NOT NULL DEFAULT '',
+    mulai_time      TIMESTAMPTZ,
+    selesai_time    TIMESTAMPTZ,
+    create_time     TIMESTAMPTZ NOT NULL,
+    update_time     TIMESTAMPTZ
+);
+
+-- promo_redemption holds the uses of the promo codes by the
+-- orders, a redemption is cancelled when its order expires.
+CREATE TABLE IF NOT EXISTS promo_redemption (
+    id           BIGSERIAL   PRIMARY KEY,
+    promo_id     BIGINT      NOT NULL REFERENCES promo (id),
+    order_id     TEXT        NOT NULL,
+    email        TEXT        NOT NULL,
+    type         INT         NOT NULL,
+    jumlah_tiket INT         NOT NULL,
+    subtotal     BIGINT      NOT NULL,
+    diskon       BIGINT      NOT NULL,
+    status       TEXT        NOT NULL,
+    create_time  TIMESTAMPTZ NOT NULL,
+    update_time  TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS promo_redemption_promo_id_idx ON promo_redemption (promo_id, status);
+CREATE INDEX IF NOT EXISTS promo_redemption_order_id_idx ON promo_redemption (order_id);
+
+-- kode_promo and diskon are the promo code applied to a
+-- mainevent order and its discount.
+ALTER TABLE mainevent ADD COLUMN IF NOT EXISTS kode_promo TEXT;
+ALTER TABLE mainevent ADD COLUMN IF NOT EXISTS diskon BIGINT NOT NULL DEFAULT 0;
@@ -0,0 +1,47 @@
+-- mainevent_institution holds the group order policies of the
+-- institutions, the others follow the default policy.
+CREATE TABLE IF NOT EXISTS mainevent_institution (
+    id               BIGSERIAL   PRIMARY KEY,
+    nama             TEXT        NOT NULL,
+    max_tiket        INT         NOT NULL,
+    kuota_tiket      INT         NOT NULL DEFAULT 0,
+    require_approval BOOLEAN     NOT NULL DEFAULT TRUE,
+    create_time      TIMESTAMPTZ NOT NULL,
+    update_time      TIMESTAMPTZ
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS mainevent_institution_nama_idx ON mainevent_institution (lower(nama));
+
+-- mainevent_group holds the group orders submitted by the
+-- coordinators, mainevent_id is the invoice issued once the
+-- order is approved.
+CREATE TABLE IF NOT EXISTS mainevent_group (
+    id              BIGSERIAL   PRIMARY KEY,
+    mainevent_id    BIGINT      REFERENCES mainevent (id) ON DELETE SET NULL,
+    nama            TEXT        NOT NULL,
+    nomor_identitas TEXT        NOT NULL,
+    asal_institusi  TEXT        NOT NULL,
+    email           TEXT        NOT NULL,
+    nomor_telepon   TEXT        NOT NULL,
+    harga_tiket     BIGINT      NOT NULL,
+    total_harga     BIGINT      NOT NULL,
+    status          TEXT        NOT NULL,
+    catatan         TEXT,
+    create_time     TIMESTAMPTZ NOT NULL,
+    update_time     TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS mainevent_group_status_idx ON mainevent_group (status, create_time);
+CREATE INDEX IF NOT EXISTS mainevent_group_asal_institusi_idx ON mainevent_group (lower(asal_institusi));
+CREATE UNIQUE INDEX IF NOT EXISTS mainevent_group_mainevent_id_idx ON mainevent_group (mainevent_id);
+
+-- mainevent_group_attendee holds the attendees of the group
+-- orders, urutan is the order their tickets are assigned in.
+CREATE TABLE IF NOT EXISTS mainevent_group_attendee (
+    group_id        BIGINT NOT NULL REFERENCES mainevent_group (id) ON DELETE CASCADE,
+    urutan          INT    NOT NULL,
+    nama            TEXT   NOT NULL,
+    email           TEXT   NOT NULL,
+    nomor_identitas TEXT   NOT NULL,
+    PRIMARY KEY (group_id, urutan)
+);
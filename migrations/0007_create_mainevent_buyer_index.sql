@@ -0,0 +1,4 @@
+-- the purchase limits count the tickets of a buyer by email
+-- and by identity number on every order.
+CREATE INDEX IF NOT EXISTS mainevent_email_idx ON mainevent (lower(email));
+CREATE INDEX IF NOT EXISTS mainevent_nomor_identitas_idx ON mainevent (btrim(nomor_identitas));
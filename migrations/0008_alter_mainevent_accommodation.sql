@@ -0,0 +1,5 @@
+-- akomodasi holds the accessibility accommodations the buyer
+-- needs at the venue, and catatan_akomodasi the details for
+-- the committee.
+ALTER TABLE mainevent ADD COLUMN IF NOT EXISTS akomodasi TEXT[] NOT NULL DEFAULT '{}';
+ALTER TABLE mainevent ADD COLUMN IF NOT EXISTS catatan_akomodasi TEXT;
@@ -0,0 +1,19 @@
+-- mainevent_seat holds the seats of the venue. A seat is held
+-- by at most one mainevent, set back to null when the unpaid
+-- mainevent is deleted, and seats at most one of its tickets.
+CREATE TABLE IF NOT EXISTS mainevent_seat (
+    id           BIGSERIAL   PRIMARY KEY,
+    section      TEXT        NOT NULL,
+    baris        TEXT        NOT NULL,
+    nomor        INT         NOT NULL,
+    aksesibel    BOOLEAN     NOT NULL DEFAULT FALSE,
+    mainevent_id BIGINT      REFERENCES mainevent (id) ON DELETE SET NULL,
+    nomor_tiket  TEXT,
+    create_time  TIMESTAMPTZ NOT NULL,
+    update_time  TIMESTAMPTZ,
+    CHECK (nomor_tiket IS NULL OR mainevent_id IS NOT NULL)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS mainevent_seat_position_idx ON mainevent_seat (section, baris, nomor);
+CREATE UNIQUE INDEX IF NOT EXISTS mainevent_seat_nomor_tiket_idx ON mainevent_seat (nomor_tiket);
+CREATE INDEX IF NOT EXISTS mainevent_seat_mainevent_id_idx ON mainevent_seat (mainevent_id);
@@ -0,0 +1,63 @@
+-- event holds the events of the TEDxUB editions, the orders of
+-- each event reference it. tanggal are the days the event is
+-- held, the templates are file names under global/template.
+CREATE TABLE IF NOT EXISTS event (
+    id             BIGSERIAL   PRIMARY KEY,
+    slug           TEXT        NOT NULL,
+    nama           TEXT        NOT NULL,
+    venue          TEXT,
+    tanggal        DATE[]      NOT NULL DEFAULT '{}',
+    logo_uri       TEXT,
+    warna_utama    TEXT,
+    template_tiket TEXT,
+    template_email TEXT,
+    create_time    TIMESTAMPTZ NOT NULL,
+    update_time    TIMESTAMPTZ
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS event_slug_idx ON event (slug);
+
+-- event_ticket_type holds the tickets sold for the events,
+-- urutan is the order they are listed in. The mainevent sale
+-- phases use their name as kode, e.g. normal-sale.
+CREATE TABLE IF NOT EXISTS event_ticket_type (
+    event_id BIGINT NOT NULL REFERENCES event (id) ON DELETE CASCADE,
+    urutan   INT    NOT NULL,
+    kode     TEXT   NOT NULL,
+    nama     TEXT   NOT NULL,
+    harga    BIGINT NOT NULL,
+    kuota    INT    NOT NULL,
+    PRIMARY KEY (event_id, urutan)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS event_ticket_type_kode_idx ON event_ticket_type (event_id, kode);
+
+-- the 2023 events, the days of Semayam Asa are taken from its
+-- dated tickets and the others are left for the committee.
+INSERT INTO event (slug, nama, tanggal, create_time)
+SELECT 'semayam-asa', 'Semayam Asa', COALESCE((SELECT array_agg(DISTINCT tanggal::DATE ORDER BY tanggal::DATE) FROM transaction), '{}'), now()
+ON CONFLICT (slug) DO NOTHING;
+
+INSERT INTO event (slug, nama, create_time)
+VALUES
+    ('memantik-baskara', 'Memantik Baskara', now()),
+    ('panggung-swara-insan', 'Panggung Swara Insan', now())
+ON CONFLICT (slug) DO NOTHING;
+
+INSERT INTO event_ticket_type (event_id, urutan, kode, nama, harga, kuota)
+SELECT id, 0, 'normal-sale', 'Normal Sale', 79000, 100 FROM event WHERE slug = 'memantik-baskara'
+ON CONFLICT DO NOTHING;
+
+-- event_id references the event of the orders, the existing
+-- orders belong to the event of their package.
+ALTER TABLE transaction ADD COLUMN IF NOT EXISTS event_id BIGINT REFERENCES event (id);
+ALTER TABLE mainevent ADD COLUMN IF NOT EXISTS event_id BIGINT REFERENCES event (id);
+ALTER TABLE ticket ADD COLUMN IF NOT EXISTS event_id BIGINT REFERENCES event (id);
+
+UPDATE transaction SET event_id = (SELECT id FROM event WHERE slug = 'semayam-asa') WHERE event_id IS NULL;
+UPDATE mainevent SET event_id = (SELECT id FROM event WHERE slug = 'memantik-baskara') WHERE event_id IS NULL;
+UPDATE ticket SET event_id = (SELECT id FROM event WHERE slug = 'panggung-swara-insan') WHERE event_id IS NULL;
+
+CREATE INDEX IF NOT EXISTS transaction_event_id_idx ON transaction (event_id);
+CREATE INDEX IF NOT EXISTS mainevent_event_id_idx ON mainevent (event_id);
+CREATE INDEX IF NOT EXISTS ticket_event_id_idx ON ticket (event_id);
@@ -0,0 +1,38 @@
+-- ticket_draw holds the lottery of the free seats of Panggung
+-- Swara Insan. seed_hash is published when the draw is
+-- committed and seed is only revealed once it is drawn. There
+-- is a single draw, so that it is never committed twice.
+CREATE TABLE IF NOT EXISTS ticket_draw (
+    id          BIGSERIAL   PRIMARY KEY,
+    kuota       INT         NOT NULL,
+    seed        TEXT        NOT NULL,
+    seed_hash   TEXT        NOT NULL,
+    status      TEXT        NOT NULL,
+    commit_time TIMESTAMPTZ NOT NULL,
+    draw_time   TIMESTAMPTZ
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS ticket_draw_single_idx ON ticket_draw ((TRUE));
+
+-- ticket_draw_quota holds the seats of a draw reserved for the
+-- tickets with an attribute value, nilai is in lower case.
+CREATE TABLE IF NOT EXISTS ticket_draw_quota (
+    draw_id BIGINT NOT NULL REFERENCES ticket_draw (id) ON DELETE CASCADE,
+    atribut TEXT   NOT NULL,
+    nilai   TEXT   NOT NULL,
+    kuota   INT    NOT NULL,
+    PRIMARY KEY (draw_id, atribut, nilai)
+);
+
+-- ticket_draw_result holds the result of each ticket taking
+-- part in a draw, urutan is its order among the winners or its
+-- place on the waitlist.
+CREATE TABLE IF NOT EXISTS ticket_draw_result (
+    draw_id     BIGINT NOT NULL REFERENCES ticket_draw (id) ON DELETE CASCADE,
+    ticket_id   BIGINT NOT NULL REFERENCES ticket (id),
+    peringkat   INT    NOT NULL,
+    status      TEXT   NOT NULL,
+    urutan      INT    NOT NULL,
+    nomor_tiket TEXT,
+    PRIMARY KEY (draw_id, ticket_id)
+);
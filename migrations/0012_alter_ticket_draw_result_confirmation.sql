@@ -0,0 +1,9 @@
+-- the winners of the draw confirm their attendance with the
+-- link of their token before confirm_deadline, notify_time is
+-- NULL while the email of the current status is queued.
+ALTER TABLE ticket_draw_result ADD COLUMN IF NOT EXISTS token TEXT;
+ALTER TABLE ticket_draw_result ADD COLUMN IF NOT EXISTS confirm_deadline TIMESTAMPTZ;
+ALTER TABLE ticket_draw_result ADD COLUMN IF NOT EXISTS notify_time TIMESTAMPTZ;
+ALTER TABLE ticket_draw_result ADD COLUMN IF NOT EXISTS update_time TIMESTAMPTZ;
+
+CREATE UNIQUE INDEX IF NOT EXISTS ticket_draw_result_token_idx ON ticket_draw_result (token);
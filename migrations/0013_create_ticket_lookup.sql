@@ -0,0 +1,20 @@
+-- ticket_lookup holds the one-time codes emailed to the
+-- registrants of Panggung Swara Insan to look up their
+-- registration, kode_hash is the SHA-256 hash of the code and
+-- token the secret of the session it opens once verified.
+CREATE TABLE IF NOT EXISTS ticket_lookup (
+    id                BIGSERIAL   PRIMARY KEY,
+    ticket_id         BIGINT      NOT NULL REFERENCES ticket (id) ON DELETE CASCADE,
+    kode_hash         TEXT        NOT NULL,
+    percobaan         INT         NOT NULL DEFAULT 0,
+    expire_time       TIMESTAMPTZ NOT NULL,
+    token             TEXT,
+    token_expire_time TIMESTAMPTZ,
+    create_time       TIMESTAMPTZ NOT NULL,
+    use_time          TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS ticket_lookup_ticket_idx ON ticket_lookup (ticket_id, id);
+CREATE UNIQUE INDEX IF NOT EXISTS ticket_lookup_token_idx ON ticket_lookup (token);
+
+CREATE INDEX IF NOT EXISTS ticket_email_identity_idx ON ticket (lower(email), nomor_identitas);
@@ -0,0 +1,15 @@
+-- mainevent_buyer_login holds the login links emailed to the
+-- buyers and the sessions they open to manage their orders.
+CREATE TABLE IF NOT EXISTS mainevent_buyer_login (
+    id                  BIGSERIAL   PRIMARY KEY,
+    email               TEXT        NOT NULL,
+    token               TEXT        NOT NULL,
+    expire_time         TIMESTAMPTZ NOT NULL,
+    session_token       TEXT,
+    session_expire_time TIMESTAMPTZ,
+    create_time         TIMESTAMPTZ NOT NULL,
+    use_time            TIMESTAMPTZ
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS mainevent_buyer_login_token_idx ON mainevent_buyer_login (token);
+CREATE UNIQUE INDEX IF NOT EXISTS mainevent_buyer_login_session_token_idx ON mainevent_buyer_login (session_token);
@@ -0,0 +1,16 @@
+-- mainevent_checkin and transaction_checkin hold the time each
+-- ticket is checked in at the gate, the tickets checked in
+-- before have none.
+CREATE TABLE IF NOT EXISTS mainevent_checkin (
+    mainevent_id BIGINT      NOT NULL REFERENCES mainevent (id) ON DELETE CASCADE,
+    nomor_tiket  TEXT        NOT NULL,
+    checkin_time TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (mainevent_id, nomor_tiket)
+);
+
+CREATE TABLE IF NOT EXISTS transaction_checkin (
+    transaction_id BIGINT      NOT NULL REFERENCES transaction (id) ON DELETE CASCADE,
+    nomor_tiket    TEXT        NOT NULL,
+    checkin_time   TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (transaction_id, nomor_tiket)
+);
@@ -0,0 +1,15 @@
+-- the login links and sessions of the buyers are stored as the
+-- SHA-256 hash of their token in hex, like the lookup codes.
+-- The links and sessions created before are short-lived and
+-- dropped, their buyers log in again.
+ALTER TABLE mainevent_buyer_login ADD COLUMN IF NOT EXISTS token_hash TEXT;
+ALTER TABLE mainevent_buyer_login ADD COLUMN IF NOT EXISTS session_token_hash TEXT;
+
+DELETE FROM mainevent_buyer_login WHERE token_hash IS NULL;
+
+ALTER TABLE mainevent_buyer_login ALTER COLUMN token_hash SET NOT NULL;
+ALTER TABLE mainevent_buyer_login DROP COLUMN IF EXISTS token;
+ALTER TABLE mainevent_buyer_login DROP COLUMN IF EXISTS session_token;
+
+CREATE UNIQUE INDEX IF NOT EXISTS mainevent_buyer_login_token_hash_idx ON mainevent_buyer_login (token_hash);
+CREATE UNIQUE INDEX IF NOT EXISTS mainevent_buyer_login_session_token_hash_idx ON mainevent_buyer_login (session_token_hash);
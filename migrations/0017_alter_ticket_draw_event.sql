@@ -0,0 +1,11 @@
+-- event_id references the event of the draws, each event has a
+-- single draw, so that it is never committed twice. The draw
+-- committed before belongs to Panggung Swara Insan.
+ALTER TABLE ticket_draw ADD COLUMN IF NOT EXISTS event_id BIGINT REFERENCES event (id);
+
+UPDATE ticket_draw SET event_id = (SELECT id FROM event WHERE slug = 'panggung-swara-insan') WHERE event_id IS NULL;
+
+ALTER TABLE ticket_draw ALTER COLUMN event_id SET NOT NULL;
+
+DROP INDEX IF EXISTS ticket_draw_single_idx;
+CREATE UNIQUE INDEX IF NOT EXISTS ticket_draw_event_idx ON ticket_draw (event_id);
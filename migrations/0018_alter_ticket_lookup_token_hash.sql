@@ -0,0 +1,15 @@
+-- the lookup sessions are stored as the SHA-256 hash of their
+-- token in hex, like the lookup codes. The sessions opened
+-- before are hashed in place, so that they stay valid.
+ALTER TABLE ticket_lookup ADD COLUMN IF NOT EXISTS token_hash TEXT;
+
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'ticket_lookup' AND column_name = 'token') THEN
+        UPDATE ticket_lookup SET token_hash = encode(sha256(convert_to(token, 'UTF8')), 'hex') WHERE token IS NOT NULL AND token_hash IS NULL;
+    END IF;
+END $$;
+
+ALTER TABLE ticket_lookup DROP COLUMN IF EXISTS token;
+
+CREATE UNIQUE INDEX IF NOT EXISTS ticket_lookup_token_hash_idx ON ticket_lookup (token_hash);
@@ -0,0 +1,16 @@
+-- the confirmation links of the draw winners are stored as the
+-- SHA-256 hash of their token in hex, like the lookup
+-- sessions. The links sent before are hashed in place, so that
+-- they stay valid.
+ALTER TABLE ticket_draw_result ADD COLUMN IF NOT EXISTS token_hash TEXT;
+
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'ticket_draw_result' AND column_name = 'token') THEN
+        UPDATE ticket_draw_result SET token_hash = encode(sha256(convert_to(token, 'UTF8')), 'hex') WHERE token IS NOT NULL AND token_hash IS NULL;
+    END IF;
+END $$;
+
+ALTER TABLE ticket_draw_result DROP COLUMN IF EXISTS token;
+
+CREATE UNIQUE INDEX IF NOT EXISTS ticket_draw_result_token_hash_idx ON ticket_draw_result (token_hash);
@@ -1,2 +1,17 @@
 chmod +x ./run.sh
 pm2 restart backend
+
+# wait until the new process is ready to serve requests
+HEALTH_URL=${HEALTH_URL:-http://localhost:${PORT:-8080}/api/v1/health/ready}
+for i in $(seq 1 30); do
+    if curl -fsS -o /dev/null "$HEALTH_URL"; then
+        echo "backend is ready"
+        exit 0
+    fi
+    sleep 2
+done
+
+echo "backend is not ready, last readiness report:"
+curl -sS "$HEALTH_URL"
+echo
+exit 1